@@ -9,13 +9,18 @@ import (
 	"k8s.io/component-base/cli"
 
 	"github.com/openshift/library-go/pkg/operator/staticpod/certsyncpod"
-	"github.com/openshift/library-go/pkg/operator/staticpod/installerpod"
 	"github.com/openshift/library-go/pkg/operator/staticpod/prune"
 
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/cmd/controllergraph"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/cmd/inspect"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/cmd/installer"
 	operatorcmd "github.com/openshift/cluster-kube-controller-manager-operator/pkg/cmd/operator"
 	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/cmd/recoverycontroller"
 	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/cmd/render"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/cmd/replayobservation"
 	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/cmd/resourcegraph"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/cmd/status"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/cmd/validateoverrides"
 	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator"
 )
 
@@ -37,11 +42,16 @@ func NewSSCSCommand(ctx context.Context) *cobra.Command {
 
 	cmd.AddCommand(operatorcmd.NewOperator())
 	cmd.AddCommand(render.NewRenderCommand(os.Stderr))
-	cmd.AddCommand(installerpod.NewInstaller(ctx))
+	cmd.AddCommand(installer.NewInstaller(ctx))
 	cmd.AddCommand(prune.NewPrune())
 	cmd.AddCommand(resourcegraph.NewResourceChainCommand())
+	cmd.AddCommand(controllergraph.NewControllerGraphCommand(os.Stdout))
 	cmd.AddCommand(certsyncpod.NewCertSyncControllerCommand(operator.CertConfigMaps, operator.CertSecrets))
 	cmd.AddCommand(recoverycontroller.NewCertRecoveryControllerCommand(ctx))
+	cmd.AddCommand(inspect.NewInspectCommand(os.Stdout))
+	cmd.AddCommand(status.NewStatusCommand(ctx, os.Stdout))
+	cmd.AddCommand(validateoverrides.NewValidateOverridesCommand(os.Stdout))
+	cmd.AddCommand(replayobservation.NewReplayObservationCommand(os.Stdout, os.Stderr))
 
 	return cmd
 }
@@ -0,0 +1,74 @@
+package controllergraph
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/controllergraph"
+)
+
+// controllerGraphOpts holds values to drive the controller-graph command.
+type controllerGraphOpts struct {
+	output string
+
+	out io.Writer
+}
+
+// NewControllerGraphCommand creates a command printing the controllers of the operator, the resources they watch,
+// read and write and the conditions they own. The graph is generated from the source of this build.
+func NewControllerGraphCommand(out io.Writer) *cobra.Command {
+	opts := &controllerGraphOpts{output: "json", out: out}
+	cmd := &cobra.Command{
+		Use:   "controller-graph",
+		Short: "Print the controllers of the operator with their informers, the resources they read and write and the conditions they own",
+		Run: func(cmd *cobra.Command, args []string) {
+			must := func(fn func() error) {
+				if err := fn(); err != nil {
+					klog.Fatal(err)
+				}
+			}
+
+			must(opts.Validate)
+			must(opts.Run)
+		},
+	}
+
+	opts.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (o *controllerGraphOpts) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVarP(&o.output, "output", "o", o.output, "Output format, json or dot for graphviz.")
+}
+
+// Validate verifies the inputs.
+func (o *controllerGraphOpts) Validate() error {
+	switch o.output {
+	case "json", "dot":
+		return nil
+	}
+	return fmt.Errorf("--output must be json or dot, got %q", o.output)
+}
+
+// Run prints the graph.
+func (o *controllerGraphOpts) Run() error {
+	graph, err := controllergraph.Load()
+	if err != nil {
+		return err
+	}
+	if o.output == "dot" {
+		_, err := io.WriteString(o.out, graph.Dot())
+		return err
+	}
+	data, err := graph.Marshal()
+	if err != nil {
+		return err
+	}
+	_, err = o.out.Write(data)
+	return err
+}
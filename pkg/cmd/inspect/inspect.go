@@ -0,0 +1,136 @@
+package inspect
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/decisionlog"
+)
+
+// inspectOpts holds values to reach the debug endpoints of a running operator.
+type inspectOpts struct {
+	server                string
+	tokenFile             string
+	insecureSkipTLSVerify bool
+
+	out io.Writer
+}
+
+// NewInspectCommand creates a command reading the debug endpoints of the operator. It is meant to be run inside
+// the operator pod, where the service account token of the operator is authorized to read them.
+func NewInspectCommand(out io.Writer) *cobra.Command {
+	opts := &inspectOpts{
+		server:    "https://localhost:8443",
+		tokenFile: "/var/run/secrets/kubernetes.io/serviceaccount/token",
+		// the serving certificate is issued for the service name, not for localhost
+		insecureSkipTLSVerify: true,
+		out:                   out,
+	}
+	cmd := &cobra.Command{
+		Use:   "inspect",
+		Short: "Inspect the internal state of a running operator",
+		Run: func(cmd *cobra.Command, args []string) {
+			_ = cmd.Help()
+		},
+	}
+	opts.AddFlags(cmd.PersistentFlags())
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "decisions",
+		Short: "Print the rollout decisions recorded by the operator, requires DECISION_LOG_SIZE to be set on the operator",
+		Run: func(cmd *cobra.Command, args []string) {
+			must(opts.Validate)
+			must(opts.printDecisions)
+		},
+	})
+
+	return cmd
+}
+
+func must(fn func() error) {
+	if err := fn(); err != nil {
+		klog.Fatal(err)
+	}
+}
+
+func (o *inspectOpts) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.server, "server", o.server, "Base URL of the operator server.")
+	fs.StringVar(&o.tokenFile, "token-file", o.tokenFile, "File containing the bearer token to authenticate with.")
+	fs.BoolVar(&o.insecureSkipTLSVerify, "insecure-skip-tls-verify", o.insecureSkipTLSVerify, "Do not verify the serving certificate of the operator.")
+}
+
+// Validate verifies the inputs.
+func (o *inspectOpts) Validate() error {
+	if len(o.server) == 0 {
+		return errors.New("missing required flag: --server")
+	}
+	return nil
+}
+
+// get reads the given path of the operator server.
+func (o *inspectOpts) get(path string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(o.server, "/")+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(o.tokenFile) > 0 {
+		token, err := ioutil.ReadFile(o.tokenFile)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+
+	client := &http.Client{
+		Timeout: 30 * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: o.insecureSkipTLSVerify},
+		},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s: %s", path, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
+
+func (o *inspectOpts) printDecisions() error {
+	body, err := o.get("/debug/decisions")
+	if err != nil {
+		return err
+	}
+	decisions := []decisionlog.Decision{}
+	if err := json.Unmarshal(body, &decisions); err != nil {
+		return err
+	}
+	return printDecisions(o.out, decisions)
+}
+
+func printDecisions(out io.Writer, decisions []decisionlog.Decision) error {
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tCONTROLLER\tACTION\tREASONS")
+	for _, decision := range decisions {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", decision.Time.UTC().Format(time.RFC3339), decision.Controller, decision.Action, strings.Join(decision.Reasons, "; "))
+	}
+	return w.Flush()
+}
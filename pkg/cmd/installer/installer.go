@@ -0,0 +1,84 @@
+package installer
+
+import (
+	"context"
+	"os"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/library-go/pkg/config/client"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/staticpod/installerpod"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/manifestconflictcontroller"
+)
+
+// NewInstaller is the installer of the static pod library, which first reports the manifests of the static pod
+// directory which run a kube-controller-manager besides the installed one and checks the host paths of the revision,
+// and afterwards verifies the files it wrote against the payload of the revision.
+func NewInstaller(ctx context.Context) *cobra.Command {
+	cmd := installerpod.NewInstaller(ctx)
+	install := cmd.Run
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		kubeClient, err := newKubeClient(cmd)
+		if err != nil {
+			klog.Warningf("Failed to create a client for the installer checks: %v", err)
+			install(cmd, args)
+			return
+		}
+		// the check never fails the installation, a missed report is better than a blocked rollout
+		if err := reportConflicts(ctx, cmd, kubeClient); err != nil {
+			klog.Warningf("Failed to check the static pod manifests for conflicts: %v", err)
+		}
+		// a failed pre-flight fails the installer pod before the node cuts over, it stays at its revision
+		if err := preflightHostPaths(ctx, cmd, kubeClient); err != nil {
+			klog.Exit(err)
+		}
+		install(cmd, args)
+		// a mismatch fails the installer pod, the node is not marked at the revision and the installation retried
+		if err := verifyRevision(ctx, cmd, kubeClient); err != nil {
+			klog.Exit(err)
+		}
+	}
+	return cmd
+}
+
+func newKubeClient(cmd *cobra.Command) (kubernetes.Interface, error) {
+	kubeConfig, err := cmd.Flags().GetString("kubeconfig")
+	if err != nil {
+		return nil, err
+	}
+	clientConfig, err := client.GetKubeConfigOrInClusterConfig(kubeConfig, nil)
+	if err != nil {
+		return nil, err
+	}
+	return kubernetes.NewForConfig(clientConfig)
+}
+
+func reportConflicts(ctx context.Context, cmd *cobra.Command, kubeClient kubernetes.Interface) error {
+	manifestDir, err := cmd.Flags().GetString("pod-manifest-dir")
+	if err != nil {
+		return err
+	}
+	pod, err := cmd.Flags().GetString("pod")
+	if err != nil {
+		return err
+	}
+	// set via downward API
+	nodeName := os.Getenv("NODE_NAME")
+	if len(manifestDir) == 0 || len(pod) == 0 || len(nodeName) == 0 {
+		return nil
+	}
+
+	// the installer names the manifest after the pod configmap
+	conflicts, err := manifestconflictcontroller.FindConflicts(manifestDir, pod+".yaml")
+	if err != nil {
+		return err
+	}
+	for _, conflict := range conflicts {
+		klog.Warningf("Static pod manifest %q runs a kube-controller-manager besides %q", conflict, pod+".yaml")
+	}
+	return manifestconflictcontroller.ReportConflicts(ctx, kubeClient.CoreV1(), events.NewLoggingEventRecorder("static-pod-installer"), nodeName, conflicts)
+}
@@ -0,0 +1,76 @@
+package installer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/hostpathpreflightcontroller"
+)
+
+// preflightHostPaths checks the host paths the static pod of the revision mounts before the revision is installed,
+// and reports the ones which fail. It returns an error when any fail. The checks which cannot complete, like on an
+// unavailable API, are skipped rather than blocking an installation which may be fine.
+func preflightHostPaths(ctx context.Context, cmd *cobra.Command, kubeClient kubernetes.Interface) error {
+	flags := map[string]string{}
+	for _, name := range []string{"revision", "namespace", "pod", "resource-dir", "pod-manifest-dir"} {
+		value, err := cmd.Flags().GetString(name)
+		if err != nil {
+			return err
+		}
+		flags[name] = value
+	}
+	// set via downward API
+	nodeName := os.Getenv("NODE_NAME")
+	if len(nodeName) == 0 || len(flags["resource-dir"]) == 0 {
+		return nil
+	}
+
+	revision := flags["revision"]
+	podConfigMap, err := kubeClient.CoreV1().ConfigMaps(flags["namespace"]).Get(ctx, flags["pod"]+"-"+revision, metav1.GetOptions{})
+	if err != nil {
+		klog.Warningf("Skipping the host path pre-flight of revision %s: %v", revision, err)
+		return nil
+	}
+	pod := &corev1.Pod{}
+	if err := yaml.Unmarshal([]byte(strings.ReplaceAll(podConfigMap.Data["pod.yaml"], "REVISION", revision)), pod); err != nil {
+		klog.Warningf("Skipping the host path pre-flight of revision %s: %v", revision, err)
+		return nil
+	}
+
+	// a node which ran a revision before has every host path but the ones of the new revision
+	_, err = os.Stat(filepath.Join(flags["pod-manifest-dir"], flags["pod"]+".yaml"))
+	requireExisting := err == nil
+	// the installer pod mounts the parent of the resource directory of the node
+	failures, err := hostpathpreflightcontroller.CheckHostPaths(
+		hostpathpreflightcontroller.HostPaths(pod),
+		filepath.Dir(flags["resource-dir"]),
+		filepath.Join(flags["resource-dir"], flags["pod"]+"-"+revision),
+		requireExisting,
+	)
+	if err != nil {
+		klog.Warningf("Skipping the host path pre-flight of revision %s: %v", revision, err)
+		return nil
+	}
+	for _, failure := range failures {
+		klog.Warningf("Host path pre-flight of revision %s: %s", revision, failure)
+	}
+	if err := hostpathpreflightcontroller.ReportFailures(ctx, kubeClient.CoreV1(), events.NewLoggingEventRecorder("static-pod-installer"), nodeName, revision, failures); err != nil {
+		klog.Warningf("Failed to report the host path pre-flight of revision %s: %v", revision, err)
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("host paths of revision %s fail the pre-flight on node %s: %s", revision, nodeName, strings.Join(failures, ", "))
+	}
+	return nil
+}
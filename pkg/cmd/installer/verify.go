@@ -0,0 +1,117 @@
+package installer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/revisionchecksum"
+)
+
+// verifyRevision compares the files the installer wrote with the payload of the revision and reports the files which
+// differ, like the ones of a partial write. It returns an error when any differ. The checks which cannot complete,
+// like on an unavailable API, are skipped rather than failing an installation which may be fine.
+func verifyRevision(ctx context.Context, cmd *cobra.Command, kubeClient kubernetes.Interface) error {
+	flags := map[string]string{}
+	for _, name := range []string{"revision", "namespace", "pod", "resource-dir", "pod-manifest-dir"} {
+		value, err := cmd.Flags().GetString(name)
+		if err != nil {
+			return err
+		}
+		flags[name] = value
+	}
+	lists := map[string][]string{}
+	for _, name := range []string{"configmaps", "optional-configmaps", "secrets", "optional-secrets"} {
+		value, err := cmd.Flags().GetStringSlice(name)
+		if err != nil {
+			return err
+		}
+		lists[name] = value
+	}
+	// set via downward API
+	nodeName := os.Getenv("NODE_NAME")
+
+	revision, namespace := flags["revision"], flags["namespace"]
+	payload, storedChecksum, err := revisionPayload(ctx, kubeClient, namespace, revision, lists)
+	if err != nil {
+		klog.Warningf("Skipping the verification of revision %s: %v", revision, err)
+		return nil
+	}
+
+	mismatches := []string{}
+	if checksum := payload.Checksum(); len(storedChecksum) > 0 && checksum != storedChecksum {
+		mismatches = append(mismatches, fmt.Sprintf("payload checksum %s instead of %s, the revisioned resources changed", checksum, storedChecksum))
+	}
+	resourceDir := filepath.Join(flags["resource-dir"], flags["pod"]+"-"+revision)
+	files, err := payload.Substitute(revision, nodeName).VerifyFiles(resourceDir)
+	if err != nil {
+		return err
+	}
+	mismatches = append(mismatches, files...)
+
+	// the installer writes the manifest to the resource directory first, the kubelet runs the copy
+	manifest := filepath.Join(flags["pod-manifest-dir"], flags["pod"]+".yaml")
+	expected, err := os.ReadFile(filepath.Join(resourceDir, flags["pod"]+".yaml"))
+	if err != nil {
+		return err
+	}
+	if actual, err := os.ReadFile(manifest); err != nil || !bytes.Equal(actual, expected) {
+		mismatches = append(mismatches, manifest)
+	}
+
+	if err := revisionchecksum.ReportMismatches(ctx, kubeClient.CoreV1(), events.NewLoggingEventRecorder("static-pod-installer"), nodeName, revision, mismatches); err != nil {
+		klog.Warningf("Failed to report the verification of revision %s: %v", revision, err)
+	}
+	if len(mismatches) > 0 {
+		return fmt.Errorf("revision %s does not match on node %s: %s", revision, nodeName, strings.Join(mismatches, ", "))
+	}
+	klog.Infof("Verified the files of revision %s against its payload", revision)
+	return nil
+}
+
+// revisionPayload returns the payload of the revision as the installer fetched it and the checksum the operator
+// stored for it, empty when it did not yet.
+func revisionPayload(ctx context.Context, kubeClient kubernetes.Interface, namespace, revision string, lists map[string][]string) (revisionchecksum.Payload, string, error) {
+	revisionStatus, err := kubeClient.CoreV1().ConfigMaps(namespace).Get(ctx, "revision-status-"+revision, metav1.GetOptions{})
+	if err != nil {
+		return nil, "", err
+	}
+
+	payload := revisionchecksum.Payload{}
+	for _, list := range []string{"configmaps", "optional-configmaps"} {
+		for _, name := range lists[list] {
+			configMap, err := kubeClient.CoreV1().ConfigMaps(namespace).Get(ctx, name+"-"+revision, metav1.GetOptions{})
+			if apierrors.IsNotFound(err) && list == "optional-configmaps" {
+				continue
+			}
+			if err != nil {
+				return nil, "", err
+			}
+			payload.AddConfigMap(name, configMap.Data)
+		}
+	}
+	for _, list := range []string{"secrets", "optional-secrets"} {
+		for _, name := range lists[list] {
+			secret, err := kubeClient.CoreV1().Secrets(namespace).Get(ctx, name+"-"+revision, metav1.GetOptions{})
+			if apierrors.IsNotFound(err) && list == "optional-secrets" {
+				continue
+			}
+			if err != nil {
+				return nil, "", err
+			}
+			payload.AddSecret(name, secret.Data)
+		}
+	}
+	return payload, revisionStatus.Data[revisionchecksum.ChecksumKey], nil
+}
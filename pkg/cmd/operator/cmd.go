@@ -2,6 +2,7 @@ package operator
 
 import (
 	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
 
 	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator"
 	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/version"
@@ -15,5 +16,15 @@ func NewOperator() *cobra.Command {
 	cmd.Use = "operator"
 	cmd.Short = "Start the Cluster kube-controller-manager Operator"
 
+	leaderElection := &leaderElectionOptions{}
+	leaderElection.AddFlags(cmd.Flags())
+	run := cmd.Run
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		if err := applyLeaderElectionFlags(cmd, leaderElection); err != nil {
+			klog.Fatal(err)
+		}
+		run(cmd, args)
+	}
+
 	return cmd
 }
@@ -0,0 +1,131 @@
+package operator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configv1 "github.com/openshift/api/config/v1"
+	leaderelectionconverter "github.com/openshift/library-go/pkg/config/leaderelection"
+)
+
+// leaderElectionOptions override the leader election timings of the config file. Without any of them, library-go
+// applies its defaults, lengthened on single node clusters. Setting any of them opts out of the single node defaults,
+// the timings which are not set get the defaults of highly available clusters.
+type leaderElectionOptions struct {
+	leaseDuration time.Duration
+	renewDeadline time.Duration
+	retryPeriod   time.Duration
+}
+
+func (o *leaderElectionOptions) AddFlags(flags *pflag.FlagSet) {
+	flags.DurationVar(&o.leaseDuration, "leader-election-lease-duration", 0, "The duration non-leaders wait before taking over an unrenewed lease of the operator. Defaults to 137s, or 270s on single node clusters.")
+	flags.DurationVar(&o.renewDeadline, "leader-election-renew-deadline", 0, "The duration the leader retries renewing its lease before it gives up leading. Defaults to 107s, or 240s on single node clusters.")
+	flags.DurationVar(&o.retryPeriod, "leader-election-retry-period", 0, "The interval of the attempts to acquire or renew the lease. Defaults to 26s, or 60s on single node clusters.")
+}
+
+func (o *leaderElectionOptions) isSet() bool {
+	return o.leaseDuration != 0 || o.renewDeadline != 0 || o.retryPeriod != 0
+}
+
+// Validate checks the timings once defaulted, like client-go does before it runs the leader election.
+func (o *leaderElectionOptions) Validate() error {
+	if o.leaseDuration < 0 || o.renewDeadline < 0 || o.retryPeriod < 0 {
+		return fmt.Errorf("the leader election timings must not be negative")
+	}
+	defaulted := leaderelectionconverter.LeaderElectionDefaulting(o.toLeaderElection(), "", "")
+	if defaulted.LeaseDuration.Duration <= defaulted.RenewDeadline.Duration {
+		return fmt.Errorf("the leader election lease duration %v must be greater than the renew deadline %v", defaulted.LeaseDuration.Duration, defaulted.RenewDeadline.Duration)
+	}
+	// client-go jitters the retry period by up to 20%
+	if defaulted.RenewDeadline.Duration <= time.Duration(1.2*float64(defaulted.RetryPeriod.Duration)) {
+		return fmt.Errorf("the leader election renew deadline %v must be greater than 1.2 times the retry period %v", defaulted.RenewDeadline.Duration, defaulted.RetryPeriod.Duration)
+	}
+	return nil
+}
+
+func (o *leaderElectionOptions) toLeaderElection() configv1.LeaderElection {
+	return configv1.LeaderElection{
+		LeaseDuration: metav1.Duration{Duration: o.leaseDuration},
+		RenewDeadline: metav1.Duration{Duration: o.renewDeadline},
+		RetryPeriod:   metav1.Duration{Duration: o.retryPeriod},
+	}
+}
+
+// overrideConfig returns the config file content with the leader election timings which are set.
+func (o *leaderElectionOptions) overrideConfig(content []byte) ([]byte, error) {
+	config := map[string]interface{}{}
+	if len(content) > 0 {
+		if err := yaml.Unmarshal(content, &config); err != nil {
+			return nil, err
+		}
+	}
+	if config == nil {
+		config = map[string]interface{}{}
+	}
+	if _, ok := config["apiVersion"]; !ok {
+		config["apiVersion"] = "operator.openshift.io/v1alpha1"
+		config["kind"] = "GenericOperatorConfig"
+	}
+	leaderElection, ok := config["leaderElection"].(map[string]interface{})
+	if !ok {
+		leaderElection = map[string]interface{}{}
+	}
+	for field, value := range map[string]time.Duration{
+		"leaseDuration": o.leaseDuration,
+		"renewDeadline": o.renewDeadline,
+		"retryPeriod":   o.retryPeriod,
+	} {
+		if value != 0 {
+			leaderElection[field] = value.String()
+		}
+	}
+	config["leaderElection"] = leaderElection
+	return json.Marshal(config)
+}
+
+// applyLeaderElectionFlags points the --config flag at a copy of the config file with the leader election timings of
+// the flags, the operator still restarts when the original config file changes.
+func applyLeaderElectionFlags(cmd *cobra.Command, options *leaderElectionOptions) error {
+	if err := options.Validate(); err != nil {
+		return err
+	}
+	if !options.isSet() {
+		return nil
+	}
+	configFile, err := cmd.Flags().GetString("config")
+	if err != nil {
+		return err
+	}
+	var content []byte
+	if len(configFile) > 0 {
+		if content, err = os.ReadFile(configFile); err != nil {
+			return err
+		}
+	}
+	overridden, err := options.overrideConfig(content)
+	if err != nil {
+		return fmt.Errorf("failed to override the leader election of %s: %v", configFile, err)
+	}
+	dir, err := os.MkdirTemp("", "operator-config-")
+	if err != nil {
+		return err
+	}
+	overriddenFile := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(overriddenFile, overridden, 0600); err != nil {
+		return err
+	}
+	if len(configFile) > 0 {
+		if err := cmd.Flags().Set("terminate-on-files", configFile); err != nil {
+			return err
+		}
+	}
+	return cmd.Flags().Set("config", overriddenFile)
+}
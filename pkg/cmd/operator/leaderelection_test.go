@@ -0,0 +1,69 @@
+package operator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLeaderElectionOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name          string
+		options       leaderElectionOptions
+		expectedError bool
+	}{
+		{name: "defaults"},
+		{name: "single node timings", options: leaderElectionOptions{leaseDuration: 270 * time.Second, renewDeadline: 240 * time.Second, retryPeriod: 60 * time.Second}},
+		{name: "longer lease only", options: leaderElectionOptions{leaseDuration: 270 * time.Second}},
+		{name: "lease shorter than the renew deadline", options: leaderElectionOptions{leaseDuration: 60 * time.Second}, expectedError: true},
+		{name: "retry period close to the renew deadline", options: leaderElectionOptions{retryPeriod: 100 * time.Second}, expectedError: true},
+		{name: "negative", options: leaderElectionOptions{retryPeriod: -time.Second}, expectedError: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.options.Validate()
+			if tc.expectedError != (err != nil) {
+				t.Errorf("expected error %v, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestApplyLeaderElectionFlags(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	content := "apiVersion: operator.openshift.io/v1\nkind: GenericOperatorConfig\nleaderElection:\n  retryPeriod: 30s\n"
+	if err := os.WriteFile(configFile, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cmd := NewOperator()
+	if err := cmd.Flags().Set("config", configFile); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := applyLeaderElectionFlags(cmd, &leaderElectionOptions{leaseDuration: 270 * time.Second, renewDeadline: 240 * time.Second}); err != nil {
+		t.Fatal(err)
+	}
+	overriddenFile, err := cmd.Flags().GetString("config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if overriddenFile == configFile {
+		t.Fatalf("expected --config to point at the overridden config")
+	}
+	overridden, err := os.ReadFile(overriddenFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"apiVersion":"operator.openshift.io/v1","kind":"GenericOperatorConfig","leaderElection":{"leaseDuration":"4m30s","renewDeadline":"4m0s","retryPeriod":"30s"}}`
+	if string(overridden) != expected {
+		t.Errorf("expected %s, got %s", expected, overridden)
+	}
+	terminateOnFiles, err := cmd.Flags().GetStringArray("terminate-on-files")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(terminateOnFiles) != 1 || terminateOnFiles[0] != configFile {
+		t.Errorf("expected the operator to restart on changes of %s, got %v", configFile, terminateOnFiles)
+	}
+}
@@ -0,0 +1,240 @@
+package render
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/ghodss/yaml"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	kyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// renderedFile is a rendered output, split into its YAML documents.
+type renderedFile struct {
+	path      string
+	documents []*unstructured.Unstructured
+	patched   bool
+}
+
+// overlayPatch is a document of an overlay file. It selects the rendered documents to patch by apiVersion,
+// kind, and metadata name and namespace.
+type overlayPatch struct {
+	source string
+	patch  *unstructured.Unstructured
+}
+
+func (p overlayPatch) String() string {
+	return fmt.Sprintf("%s %s %q of overlay %q", p.patch.GetAPIVersion(), p.patch.GetKind(),
+		strings.TrimPrefix(p.patch.GetNamespace()+"/"+p.patch.GetName(), "/"), p.source)
+}
+
+// readOverlays reads the patches of the overlay directory. The overlay files are ordered by name and their
+// documents by their position in the file, so later patches build on earlier ones.
+func readOverlays(overlayDir string) ([]overlayPatch, error) {
+	overlayFiles, err := yamlFiles(overlayDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the overlay directory %q: %v", overlayDir, err)
+	}
+	ret := []overlayPatch{}
+	for _, overlayFile := range overlayFiles {
+		documents, err := readDocuments(overlayFile)
+		if err != nil {
+			return nil, err
+		}
+		for _, document := range documents {
+			ret = append(ret, overlayPatch{source: overlayFile, patch: document})
+		}
+	}
+	return ret, nil
+}
+
+// applyOverlays applies the patches in order to every matching document of the rendered files, and returns
+// the patches which matched none. Types known to the kube scheme are patched with strategic merge patches,
+// the others, like the bootstrap config, with JSON merge patches.
+func applyOverlays(patches []overlayPatch, renderedFiles ...*renderedFile) ([]overlayPatch, error) {
+	unmatched := []overlayPatch{}
+	for _, patch := range patches {
+		matched, err := applyPatch(renderedFiles, patch.patch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply %s: %v", patch, err)
+		}
+		if !matched {
+			unmatched = append(unmatched, patch)
+		}
+	}
+	return unmatched, nil
+}
+
+func applyPatch(renderedFiles []*renderedFile, patch *unstructured.Unstructured) (bool, error) {
+	patchJSON, err := patch.MarshalJSON()
+	if err != nil {
+		return false, err
+	}
+
+	matched := false
+	for _, file := range renderedFiles {
+		for i, document := range file.documents {
+			if document.GetAPIVersion() != patch.GetAPIVersion() || document.GetKind() != patch.GetKind() ||
+				document.GetName() != patch.GetName() || document.GetNamespace() != patch.GetNamespace() {
+				continue
+			}
+			originalJSON, err := document.MarshalJSON()
+			if err != nil {
+				return false, err
+			}
+			var patchedJSON []byte
+			if dataStruct, err := scheme.Scheme.New(document.GroupVersionKind()); err == nil {
+				patchedJSON, err = strategicpatch.StrategicMergePatch(originalJSON, patchJSON, dataStruct)
+				if err != nil {
+					return false, err
+				}
+			} else {
+				patchedJSON, err = jsonpatch.MergePatch(originalJSON, patchJSON)
+				if err != nil {
+					return false, err
+				}
+			}
+			patched := &unstructured.Unstructured{}
+			if err := patched.UnmarshalJSON(patchedJSON); err != nil {
+				return false, err
+			}
+			file.documents[i] = patched
+			file.patched = true
+			matched = true
+		}
+	}
+	return matched, nil
+}
+
+// readRenderedFiles reads the YAML files of the given directories.
+func readRenderedFiles(dirs ...string) ([]*renderedFile, error) {
+	ret := []*renderedFile{}
+	for _, dir := range dirs {
+		paths, err := yamlFiles(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range paths {
+			data, err := ioutil.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			documents, err := decodeDocuments(data)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %q: %v", path, err)
+			}
+			ret = append(ret, &renderedFile{path: path, documents: documents})
+		}
+	}
+	return ret, nil
+}
+
+// yamlFiles returns the YAML files of the given directory sorted by name, or the path itself when it is a file.
+func yamlFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+	entries, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	ret := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if ext := filepath.Ext(entry.Name()); ext == ".yaml" || ext == ".yml" {
+			ret = append(ret, filepath.Join(path, entry.Name()))
+		}
+	}
+	sort.Strings(ret)
+	return ret, nil
+}
+
+func readDocuments(path string) ([]*unstructured.Unstructured, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	documents, err := decodeDocuments(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %q: %v", path, err)
+	}
+	return documents, nil
+}
+
+func decodeDocuments(data []byte) ([]*unstructured.Unstructured, error) {
+	ret := []*unstructured.Unstructured{}
+	reader := kyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(data)))
+	for {
+		document, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(bytes.TrimSpace(document)) == 0 {
+			continue
+		}
+		documentJSON, err := yaml.YAMLToJSON(document)
+		if err != nil {
+			return nil, err
+		}
+		if string(documentJSON) == "null" {
+			continue
+		}
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(documentJSON); err != nil {
+			return nil, err
+		}
+		ret = append(ret, obj)
+	}
+	return ret, nil
+}
+
+func encodeDocuments(documents []*unstructured.Unstructured) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	for i, document := range documents {
+		data, err := yaml.Marshal(document.Object)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			buf.WriteString("---\n")
+		}
+		buf.Write(data)
+	}
+	return buf.Bytes(), nil
+}
+
+// writePatched writes back the rendered files which were patched.
+func writePatched(renderedFiles []*renderedFile) error {
+	for _, file := range renderedFiles {
+		if !file.patched {
+			continue
+		}
+		data, err := encodeDocuments(file.documents)
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(file.path, data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
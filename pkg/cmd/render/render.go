@@ -28,6 +28,7 @@ type renderOpts struct {
 	clusterConfigFile                       string
 	clusterPolicyControllerConfigOutputFile string
 	clusterPolicyControllerImage            string
+	overlayDir                              string
 	disablePhase2                           bool
 	errOut                                  io.Writer
 }
@@ -70,6 +71,7 @@ func (r *renderOpts) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&r.clusterConfigFile, "cluster-config-file", r.clusterConfigFile, "Openshift Cluster API Config file.")
 	fs.StringVar(&r.clusterPolicyControllerImage, "cluster-policy-controller-image", r.clusterPolicyControllerImage, "Image to use for the cluster-policy-controller.")
 	fs.StringVar(&r.clusterPolicyControllerConfigOutputFile, "cpc-config-output-file", r.clusterPolicyControllerConfigOutputFile, "Output path for the Openshift Cluster API Config yaml file.")
+	fs.StringVar(&r.overlayDir, "overlay-dir", r.overlayDir, "Directory of strategic merge patches applied in file name order to the rendered bootstrap manifests and configs.")
 
 	// TODO: remove when the installer has stopped using it
 	fs.BoolVar(&r.disablePhase2, "disable-phase-2", r.disablePhase2, "Disable rendering of the phase 2 daemonset and dependencies.")
@@ -238,6 +240,19 @@ func (r *renderOpts) Run() error {
 		return err
 	}
 
+	patches := []overlayPatch{}
+	if len(r.overlayDir) > 0 {
+		var err error
+		if patches, err = readOverlays(r.overlayDir); err != nil {
+			return err
+		}
+	}
+	// the configs are patched first, the kube-controller-manager arguments are derived from them
+	patches, err := patchConfigs(patches, &renderConfig.FileConfig, &renderConfig.ClusterPolicyControllerFileConfig)
+	if err != nil {
+		return err
+	}
+
 	// extendedArguments are no longer being parsed by kube-controller-mananger,
 	// we need to parse and pass them explicitly
 	var kubeControllerManagerConfig map[string]interface{}
@@ -259,6 +274,9 @@ func (r *renderOpts) Run() error {
 	if err := genericrender.WriteFiles(&r.generic, &renderConfig.FileConfig, renderConfig); err != nil {
 		return err
 	}
+	if err := r.patchManifests(patches); err != nil {
+		return err
+	}
 
 	if err := ioutil.WriteFile(
 		r.clusterPolicyControllerConfigOutputFile,
@@ -271,6 +289,56 @@ func (r *renderOpts) Run() error {
 	return nil
 }
 
+// patchConfigs applies the matching patches to the bootstrap configs and returns the remaining ones.
+func patchConfigs(patches []overlayPatch, fileConfigs ...*genericrenderoptions.FileConfig) ([]overlayPatch, error) {
+	if len(patches) == 0 {
+		return nil, nil
+	}
+	configs := []*renderedFile{}
+	for _, fileConfig := range fileConfigs {
+		documents, err := decodeDocuments(fileConfig.BootstrapConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode the bootstrap config: %v", err)
+		}
+		configs = append(configs, &renderedFile{documents: documents})
+	}
+	patches, err := applyOverlays(patches, configs...)
+	if err != nil {
+		return nil, err
+	}
+	for i, config := range configs {
+		if !config.patched {
+			continue
+		}
+		if fileConfigs[i].BootstrapConfig, err = encodeDocuments(config.documents); err != nil {
+			return nil, err
+		}
+	}
+	return patches, nil
+}
+
+// patchManifests applies the patches to the written manifests. Every patch must have matched a config or manifest.
+func (r *renderOpts) patchManifests(patches []overlayPatch) error {
+	if len(patches) == 0 {
+		return nil
+	}
+	manifests, err := readRenderedFiles(
+		filepath.Join(r.generic.AssetOutputDir, "bootstrap-manifests"),
+		filepath.Join(r.generic.AssetOutputDir, "manifests"),
+	)
+	if err != nil {
+		return err
+	}
+	unmatched, err := applyOverlays(patches, manifests...)
+	if err != nil {
+		return err
+	}
+	if len(unmatched) > 0 {
+		return fmt.Errorf("%s matches no rendered manifest or config", unmatched[0])
+	}
+	return writePatched(manifests)
+}
+
 func (r *renderOpts) readBootstrapSecretsKubeconfig() ([]byte, error) {
 	return ioutil.ReadFile(filepath.Join(r.generic.AssetInputDir, "..", "auth", "kubeconfig"))
 }
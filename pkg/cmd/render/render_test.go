@@ -346,3 +346,126 @@ func TestDiscoverRestrictedCIDRs(t *testing.T) {
 		}
 	}
 }
+
+func TestRenderOverlays(t *testing.T) {
+	overlays := map[string]string{
+		"00-pod.yaml": `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: bootstrap-kube-controller-manager
+  namespace: kube-system
+  labels:
+    lab: first
+spec:
+  containers:
+  - name: kube-controller-manager
+    env:
+    - name: LAB
+      value: "true"
+`,
+		"10-config.yaml": `
+apiVersion: kubecontrolplane.config.openshift.io/v1
+kind: KubeControllerManagerConfig
+extendedArguments:
+  node-monitor-grace-period:
+  - 50s
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: bootstrap-kube-controller-manager
+  namespace: kube-system
+  labels:
+    lab: second
+`,
+	}
+
+	tests := []struct {
+		name        string
+		overlays    map[string]string
+		expectedErr string
+	}{
+		{
+			name:     "patches",
+			overlays: overlays,
+		},
+		{
+			name: "unmatched",
+			overlays: map[string]string{"00-unknown.yaml": `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: unknown
+  namespace: kube-system
+`},
+			expectedErr: `v1 Pod "kube-system/unknown" of overlay`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			teardown, outputDir, err := setupAssetOutputDir(test.name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer teardown()
+			overlayDir := filepath.Join(outputDir, "overlays")
+			if err := os.MkdirAll(overlayDir, os.ModePerm); err != nil {
+				t.Fatal(err)
+			}
+			for name, content := range test.overlays {
+				if err := ioutil.WriteFile(filepath.Join(overlayDir, name), []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			_, err = runRender(setOutputFlags([]string{
+				"--asset-input-dir=" + filepath.Join("testdata", "tls"),
+				"--templates-input-dir=" + filepath.Join("..", "..", "..", "bindata", "bootkube"),
+				"--asset-output-dir=",
+				"--config-output-file=",
+				"--cpc-config-output-file=",
+				"--overlay-dir=" + overlayDir,
+			}, outputDir)...)
+			if len(test.expectedErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected error containing %q, got %v", test.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := ioutil.ReadFile(filepath.Join(outputDir, "manifests", "bootstrap-manifests", "kube-controller-manager-pod.yaml"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			pod := map[string]interface{}{}
+			if err := yaml.Unmarshal(data, &pod); err != nil {
+				t.Fatal(err)
+			}
+			if label, _ := readPath(pod, "metadata.labels.lab"); label != "second" {
+				t.Errorf("expected the later overlay to win, got label %v", label)
+			}
+			env, _ := readPath(pod, "spec.containers[0].env")
+			if !reflect.DeepEqual(env, []interface{}{map[string]interface{}{"name": "LAB", "value": "true"}}) {
+				t.Errorf("expected the container env to be merged, got %v", env)
+			}
+			args, _ := readPath(pod, "spec.containers[0].args")
+			found := false
+			for _, arg := range args.([]interface{}) {
+				if arg == "--node-monitor-grace-period=50s" {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("expected the patched config to reach the arguments, got %v", args)
+			}
+			if !bytes.Contains(data, []byte("openshift.io/control-plane")) {
+				t.Errorf("expected the untouched labels to be kept, got:\n%s", data)
+			}
+		})
+	}
+}
@@ -0,0 +1,280 @@
+package replayobservation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	kyaml "k8s.io/apimachinery/pkg/util/yaml"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	configlistersv1 "github.com/openshift/client-go/config/listers/config/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/configobserver"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resourcesynccontroller"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/argumentrequestcontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation/configobservercontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation/ownership"
+)
+
+// replayObservationOpts holds values to drive the replay-observation command.
+type replayObservationOpts struct {
+	mustGatherDir                string
+	ignoreExistingObservedConfig bool
+
+	out    io.Writer
+	errOut io.Writer
+}
+
+// NewReplayObservationCommand creates a replay-observation command.
+func NewReplayObservationCommand(out, errOut io.Writer) *cobra.Command {
+	opts := &replayObservationOpts{out: out, errOut: errOut}
+	cmd := &cobra.Command{
+		Use:   "replay-observation",
+		Short: "Run the config observers against the resources of a must-gather and print the observed config they produce, without a cluster",
+		Run: func(cmd *cobra.Command, args []string) {
+			must := func(fn func() error) {
+				if err := fn(); err != nil {
+					klog.Fatal(err)
+				}
+			}
+
+			must(opts.Validate)
+			must(opts.Run)
+		},
+	}
+
+	opts.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (o *replayObservationOpts) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.mustGatherDir, "must-gather-dir", o.mustGatherDir, "Directory of the must-gather to read the resources from, every YAML file below it is read.")
+	fs.BoolVar(&o.ignoreExistingObservedConfig, "ignore-existing-observed-config", o.ignoreExistingObservedConfig, "Observe from an empty observed config instead of the one of the kubecontrollermanager resource, the observers keeping previous values on errors then keep none.")
+}
+
+// Validate verifies the inputs.
+func (o *replayObservationOpts) Validate() error {
+	if len(o.mustGatherDir) == 0 {
+		return errors.New("missing required flag: --must-gather-dir")
+	}
+	return nil
+}
+
+// Run loads the resources of the must-gather and runs the config observers on them.
+func (o *replayObservationOpts) Run() error {
+	snapshot, err := loadSnapshot(o.mustGatherDir)
+	if err != nil {
+		return err
+	}
+	if o.ignoreExistingObservedConfig {
+		snapshot.operator.Spec.ObservedConfig = runtime.RawExtension{}
+	}
+	observedConfig, syncs, errs := snapshot.observe()
+	for _, sync := range syncs {
+		fmt.Fprintf(o.errOut, "The operator would sync %s\n", sync)
+	}
+	for _, err := range errs {
+		fmt.Fprintf(o.errOut, "Observation error: %v\n", err)
+	}
+	out, err := yaml.Marshal(observedConfig)
+	if err != nil {
+		return err
+	}
+	_, err = o.out.Write(out)
+	return err
+}
+
+// snapshot holds the resources of a must-gather the config observers read.
+type snapshot struct {
+	operator         *operatorv1.KubeControllerManager
+	featureGates     cache.Indexer
+	infrastructures  cache.Indexer
+	networks         cache.Indexer
+	proxies          cache.Indexer
+	apiServers       cache.Indexer
+	configMaps       cache.Indexer
+	argumentRequests cache.Indexer
+}
+
+func newSnapshot() *snapshot {
+	newIndexer := func() cache.Indexer {
+		return cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	}
+	return &snapshot{
+		operator:         &operatorv1.KubeControllerManager{},
+		featureGates:     newIndexer(),
+		infrastructures:  newIndexer(),
+		networks:         newIndexer(),
+		proxies:          newIndexer(),
+		apiServers:       newIndexer(),
+		configMaps:       newIndexer(),
+		argumentRequests: newIndexer(),
+	}
+}
+
+// loadSnapshot reads every YAML file below dir, keeping the resources the config observers read and skipping the
+// files which are not resources, like the logs.
+func loadSnapshot(dir string) (*snapshot, error) {
+	s := newSnapshot()
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || (filepath.Ext(path) != ".yaml" && filepath.Ext(path) != ".yml") {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		decoder := kyaml.NewYAMLOrJSONDecoder(bytes.NewReader(content), 4096)
+		for {
+			obj := &unstructured.Unstructured{}
+			if err := decoder.Decode(&obj.Object); err == io.EOF {
+				return nil
+			} else if err != nil {
+				klog.V(2).Infof("Skipping %s: %v", path, err)
+				return nil
+			}
+			if err := s.add(obj); err != nil {
+				return fmt.Errorf("%s: %v", path, err)
+			}
+		}
+	})
+	return s, err
+}
+
+// add adds the resource, or the items of the list, to the snapshot when the config observers read them.
+func (s *snapshot) add(obj *unstructured.Unstructured) error {
+	if obj.IsList() {
+		return obj.EachListItem(func(item runtime.Object) error {
+			return s.add(item.(*unstructured.Unstructured))
+		})
+	}
+	var indexer cache.Indexer
+	var typed interface{}
+	switch obj.GroupVersionKind() {
+	case operatorv1.GroupVersion.WithKind("KubeControllerManager"):
+		if obj.GetName() != "cluster" {
+			return nil
+		}
+		return runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, s.operator)
+	case configv1.GroupVersion.WithKind("FeatureGate"):
+		indexer, typed = s.featureGates, &configv1.FeatureGate{}
+	case configv1.GroupVersion.WithKind("Infrastructure"):
+		indexer, typed = s.infrastructures, &configv1.Infrastructure{}
+	case configv1.GroupVersion.WithKind("Network"):
+		indexer, typed = s.networks, &configv1.Network{}
+	case configv1.GroupVersion.WithKind("Proxy"):
+		indexer, typed = s.proxies, &configv1.Proxy{}
+	case configv1.GroupVersion.WithKind("APIServer"):
+		indexer, typed = s.apiServers, &configv1.APIServer{}
+	case corev1.SchemeGroupVersion.WithKind("ConfigMap"):
+		indexer, typed = s.configMaps, &corev1.ConfigMap{}
+	case argumentrequestcontroller.ArgumentRequestGVR.GroupVersion().WithKind("KubeControllerManagerArgumentRequest"):
+		return s.argumentRequests.Add(obj)
+	default:
+		return nil
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, typed); err != nil {
+		return fmt.Errorf("%s %s/%s: %v", obj.GetKind(), obj.GetNamespace(), obj.GetName(), err)
+	}
+	return indexer.Add(typed)
+}
+
+// observe runs the config observers of the operator once, like the config observer controller does, and returns the
+// observed config, the resource syncs the observers requested and their errors.
+func (s *snapshot) observe() (map[string]interface{}, []string, []error) {
+	operatorClient := v1helpers.NewFakeOperatorClientWithObjectMeta(&s.operator.ObjectMeta, &s.operator.Spec.OperatorSpec, &s.operator.Status.OperatorStatus, nil)
+	syncer := &recordingSyncer{}
+	// the break-glass freeze and the health tracking are about the running operator, only the ownership of the paths
+	// changes what is observed
+	configOwnership := ownership.New()
+	observe := func(name string, observer configobserver.ObserveConfigFunc, paths ...[]string) configobserver.ObserveConfigFunc {
+		return configOwnership.Own(name, observer, paths...)
+	}
+	listers := configobservation.Listers{
+		FeatureGateLister_:    configlistersv1.NewFeatureGateLister(s.featureGates),
+		InfrastructureLister_: configlistersv1.NewInfrastructureLister(s.infrastructures),
+		NetworkLister:         configlistersv1.NewNetworkLister(s.networks),
+		ProxyLister_:          configlistersv1.NewProxyLister(s.proxies),
+		APIServerLister_:      configlistersv1.NewAPIServerLister(s.apiServers),
+		ConfigMapLister_:      corev1listers.NewConfigMapLister(s.configMaps),
+		ResourceSync:          syncer,
+	}
+	recorder := events.NewInMemoryRecorder("replay-observation")
+	observer := configobserver.NewConfigObserver(
+		operatorClient,
+		recorder,
+		listers,
+		nil,
+		configobservercontroller.Observers(operatorClient, cache.NewGenericLister(s.argumentRequests, argumentrequestcontroller.ArgumentRequestGVR.GroupResource()), observe)...,
+	)
+
+	errs := []error{}
+	if err := observer.Sync(context.Background(), factory.NewSyncContext("replay-observation", recorder)); err != nil {
+		errs = append(errs, err)
+	}
+	spec, _, _, err := operatorClient.GetOperatorState()
+	if err != nil {
+		return nil, nil, append(errs, err)
+	}
+	// the observer sets the object of the spec it writes, which the API server would serialize
+	observedConfig := map[string]interface{}{}
+	if written, ok := spec.ObservedConfig.Object.(*unstructured.Unstructured); ok {
+		observedConfig = written.Object
+	} else if len(spec.ObservedConfig.Raw) > 0 {
+		if err := json.Unmarshal(spec.ObservedConfig.Raw, &observedConfig); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	sort.Strings(syncer.syncs)
+	return observedConfig, syncer.syncs, errs
+}
+
+// recordingSyncer records the resource syncs of the observers instead of running them.
+type recordingSyncer struct {
+	syncs []string
+}
+
+var _ resourcesynccontroller.ResourceSyncer = &recordingSyncer{}
+
+func (r *recordingSyncer) SyncConfigMap(destination, source resourcesynccontroller.ResourceLocation) error {
+	r.syncs = append(r.syncs, describeSync("configmap", destination, source))
+	return nil
+}
+
+func (r *recordingSyncer) SyncSecret(destination, source resourcesynccontroller.ResourceLocation) error {
+	r.syncs = append(r.syncs, describeSync("secret", destination, source))
+	return nil
+}
+
+func describeSync(kind string, destination, source resourcesynccontroller.ResourceLocation) string {
+	if len(source.Name) == 0 {
+		return fmt.Sprintf("the deletion of %s %s/%s", kind, destination.Namespace, destination.Name)
+	}
+	return strings.Join([]string{kind, source.Namespace + "/" + source.Name, "to", destination.Namespace + "/" + destination.Name}, " ")
+}
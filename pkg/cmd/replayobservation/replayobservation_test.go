@@ -0,0 +1,91 @@
+package replayobservation
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// mustGather lays out the resources like oc adm must-gather does
+var mustGather = map[string]string{
+	"cluster-scoped-resources/operator.openshift.io/kubecontrollermanagers/cluster.yaml": `
+apiVersion: operator.openshift.io/v1
+kind: KubeControllerManager
+metadata:
+  name: cluster
+spec:
+  managementState: Managed
+  observedConfig:
+    extendedArguments:
+      cluster-cidr:
+      - 10.128.0.0/14
+      concurrent-gc-syncs:
+      - "30"
+`,
+	"cluster-scoped-resources/config.openshift.io/networks/cluster.yaml": `
+apiVersion: config.openshift.io/v1
+kind: Network
+metadata:
+  name: cluster
+status:
+  clusterNetwork:
+  - cidr: 10.132.0.0/14
+    hostPrefix: 23
+  serviceNetwork:
+  - 172.30.0.0/16
+`,
+	"cluster-scoped-resources/config.openshift.io/infrastructures/cluster.yaml": `
+apiVersion: config.openshift.io/v1
+kind: Infrastructure
+metadata:
+  name: cluster
+status:
+  infrastructureName: replay-4qzt2
+  platformStatus:
+    type: None
+`,
+	"namespaces/openshift-monitoring/kubecontrollermanager.operator.openshift.io/kubecontrollermanagerargumentrequests.yaml": `
+apiVersion: kubecontrollermanager.operator.openshift.io/v1alpha1
+kind: KubeControllerManagerArgumentRequestList
+items:
+- apiVersion: kubecontrollermanager.operator.openshift.io/v1alpha1
+  kind: KubeControllerManagerArgumentRequest
+  metadata:
+    namespace: openshift-monitoring
+    name: gc
+  spec:
+    arguments:
+      concurrent-gc-syncs: "40"
+`,
+	"namespaces/openshift-kube-controller-manager/pods/kube-controller-manager-master-0/kube-controller-manager/logs/current.log": "not a resource\n",
+}
+
+func TestReplayObservation(t *testing.T) {
+	dir := t.TempDir()
+	for path, content := range mustGather {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, path)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, path), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	opts := &replayObservationOpts{mustGatherDir: dir, out: out, errOut: errOut}
+	if err := opts.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		"cluster-cidr:\n  - 10.132.0.0/14\n",
+		"cluster-name:\n  - replay-4qzt2\n",
+		"concurrent-gc-syncs:\n  - \"40\"\n",
+		"service-cluster-ip-range:\n  - 172.30.0.0/16\n",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("expected the observed config to contain %q, got:\n%s", expected, out.String())
+		}
+	}
+}
@@ -111,6 +111,11 @@ func Resources() resourcegraph.Resources {
 		From(operatorCSRCA).
 		Add(ret)
 
+	cpcClientCertKey := resourcegraph.NewSecret(operatorclient.TargetNamespace, "cluster-policy-controller-client-cert-key").
+		Note("Rotated").
+		From(strippedSigner).
+		Add(ret)
+
 	// serviceaccount token ca.crt
 	initialSACA := resourcegraph.NewConfigMap(operatorclient.GlobalUserSpecifiedConfigNamespace, "initial-serviceaccount-ca").
 		Note("Static").
@@ -134,6 +139,10 @@ func Resources() resourcegraph.Resources {
 		Note("Rotated").
 		From(serviceCAController).
 		Add(ret)
+	cpcServingCert := resourcegraph.NewSecret(operatorclient.TargetNamespace, "cluster-policy-controller-serving-cert").
+		Note("Rotated").
+		From(serviceCAController).
+		Add(ret)
 
 	// observedConfig
 	config := resourcegraph.NewConfigMap(operatorclient.OperatorNamespace, "config").
@@ -147,6 +156,8 @@ func Resources() resourcegraph.Resources {
 		From(clientCertKey).
 		From(saCA).
 		From(servingCert).
+		From(cpcClientCertKey).
+		From(cpcServingCert).
 		From(strippedSigner).
 		From(config).
 		Add(ret)
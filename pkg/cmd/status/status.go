@@ -0,0 +1,122 @@
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/library-go/pkg/config/client"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/argumentmatrixcontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+// statusOpts holds values to drive the status command.
+type statusOpts struct {
+	kubeConfig       string
+	inconsistentOnly bool
+
+	out io.Writer
+}
+
+// NewStatusCommand creates a command printing the kube-controller-manager arguments of every control plane node, as
+// published by the operator in the argument matrix configmap.
+func NewStatusCommand(ctx context.Context, out io.Writer) *cobra.Command {
+	opts := &statusOpts{out: out}
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Print the revision, the config checksum and the kube-controller-manager arguments of every control plane node",
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := opts.Run(ctx); err != nil {
+				klog.Fatal(err)
+			}
+		},
+	}
+
+	opts.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (o *statusOpts) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.kubeConfig, "kubeconfig", o.kubeConfig, "Kubeconfig file, the in-cluster config is used when empty.")
+	fs.BoolVar(&o.inconsistentOnly, "inconsistent-only", o.inconsistentOnly, "Print only the arguments whose values differ between the nodes.")
+}
+
+// Run reads the argument matrix and prints it.
+func (o *statusOpts) Run(ctx context.Context) error {
+	clientConfig, err := client.GetKubeConfigOrInClusterConfig(o.kubeConfig, nil)
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubernetes.NewForConfig(clientConfig)
+	if err != nil {
+		return err
+	}
+	configMap, err := kubeClient.CoreV1().ConfigMaps(operatorclient.OperatorNamespace).Get(ctx, argumentmatrixcontroller.ConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	matrix := &argumentmatrixcontroller.Matrix{}
+	if err := json.Unmarshal([]byte(configMap.Data[argumentmatrixcontroller.MatrixKey]), matrix); err != nil {
+		return fmt.Errorf("configmap %s/%s: %v", operatorclient.OperatorNamespace, argumentmatrixcontroller.ConfigMapName, err)
+	}
+	return printMatrix(o.out, matrix, o.inconsistentOnly)
+}
+
+// printMatrix prints the revision of every node and the checksum of its config, the KCM_CONFIG_CHECKSUM of its
+// kube-controller-manager, then one line per argument with its value on every node. Inconsistent
+// arguments are marked with a *, - stands for an argument a node does not set, ? for a node with unknown arguments.
+func printMatrix(out io.Writer, matrix *argumentmatrixcontroller.Matrix, inconsistentOnly bool) error {
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "NODE\tREVISION\tCONFIG CHECKSUM")
+	for _, node := range matrix.Nodes {
+		checksum := node.ConfigChecksum
+		if len(checksum) == 0 {
+			checksum = "?"
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\n", node.NodeName, node.Revision, checksum)
+	}
+	fmt.Fprintln(w)
+
+	inconsistent := map[string]bool{}
+	for _, name := range matrix.Inconsistent {
+		inconsistent[name] = true
+	}
+	header := []string{"ARGUMENT"}
+	for _, node := range matrix.Nodes {
+		header = append(header, node.NodeName)
+	}
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, name := range matrix.ArgumentNames() {
+		if inconsistentOnly && !inconsistent[name] {
+			continue
+		}
+		row := []string{name}
+		if inconsistent[name] {
+			row[0] = "* " + name
+		}
+		for _, node := range matrix.Nodes {
+			values, ok := node.Arguments[name]
+			switch {
+			case len(node.Arguments) == 0:
+				row = append(row, "?")
+			case !ok:
+				row = append(row, "-")
+			default:
+				row = append(row, strings.Join(values, ","))
+			}
+		}
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
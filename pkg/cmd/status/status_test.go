@@ -0,0 +1,62 @@
+package status
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/argumentmatrixcontroller"
+)
+
+func TestPrintMatrix(t *testing.T) {
+	matrix := &argumentmatrixcontroller.Matrix{
+		Nodes: []argumentmatrixcontroller.NodeArguments{
+			{NodeName: "master-0", Revision: 4, ConfigChecksum: "5f1c0a", Arguments: map[string][]string{"controllers": {"*"}, "v": {"2"}, "profiling": {"true"}}},
+			{NodeName: "master-1", Revision: 3, ConfigChecksum: "b72e94", Arguments: map[string][]string{"controllers": {"*", "-ttl"}, "v": {"2"}}},
+			{NodeName: "master-2", Revision: 1},
+		},
+		Inconsistent: []string{"controllers", "profiling"},
+	}
+	testCases := []struct {
+		name             string
+		inconsistentOnly bool
+		expected         string
+	}{
+		{
+			name: "all",
+			expected: `NODE      REVISION  CONFIG CHECKSUM
+master-0  4         5f1c0a
+master-1  3         b72e94
+master-2  1         ?
+
+ARGUMENT       master-0  master-1  master-2
+* controllers  *         *,-ttl    ?
+* profiling    true      -         ?
+v              2         2         ?
+`,
+		},
+		{
+			name:             "inconsistent only",
+			inconsistentOnly: true,
+			expected: `NODE      REVISION  CONFIG CHECKSUM
+master-0  4         5f1c0a
+master-1  3         b72e94
+master-2  1         ?
+
+ARGUMENT       master-0  master-1  master-2
+* controllers  *         *,-ttl    ?
+* profiling    true      -         ?
+`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			if err := printMatrix(out, matrix, tc.inconsistentOnly); err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != tc.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tc.expected, out.String())
+			}
+		})
+	}
+}
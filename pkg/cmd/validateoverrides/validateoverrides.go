@@ -0,0 +1,174 @@
+package validateoverrides
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/targetconfigcontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/upgradeablecontroller"
+)
+
+// validateOverridesOpts holds values to drive the validate-overrides command.
+type validateOverridesOpts struct {
+	overridesFile        string
+	currentOverridesFile string
+	observedConfigFile   string
+
+	overrides        []byte
+	currentOverrides []byte
+	observedConfig   []byte
+
+	out io.Writer
+}
+
+// NewValidateOverridesCommand creates a validate-overrides command.
+func NewValidateOverridesCommand(out io.Writer) *cobra.Command {
+	opts := &validateOverridesOpts{out: out}
+	cmd := &cobra.Command{
+		Use:   "validate-overrides",
+		Short: "Render the kube-controller-manager config with proposed unsupportedConfigOverrides and report the effective changes, without applying anything",
+		Run: func(cmd *cobra.Command, args []string) {
+			must := func(fn func() error) {
+				if err := fn(); err != nil {
+					klog.Fatal(err)
+				}
+			}
+
+			must(opts.Validate)
+			must(opts.Complete)
+			must(opts.Run)
+		},
+	}
+
+	opts.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (o *validateOverridesOpts) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.overridesFile, "overrides-file", o.overridesFile, "File with the proposed spec.unsupportedConfigOverrides, in JSON or YAML.")
+	fs.StringVar(&o.currentOverridesFile, "current-overrides-file", o.currentOverridesFile, "File with the spec.unsupportedConfigOverrides in effect, to compare against. Defaults to no overrides.")
+	fs.StringVar(&o.observedConfigFile, "observed-config-file", o.observedConfigFile, "File with the spec.observedConfig of the cluster, in JSON or YAML. Defaults to an empty observed config.")
+}
+
+// Validate verifies the inputs.
+func (o *validateOverridesOpts) Validate() error {
+	if len(o.overridesFile) == 0 {
+		return errors.New("missing required flag: --overrides-file")
+	}
+	return nil
+}
+
+// Complete reads the input files.
+func (o *validateOverridesOpts) Complete() error {
+	var err error
+	if o.overrides, err = readJSON(o.overridesFile); err != nil {
+		return err
+	}
+	if o.currentOverrides, err = readJSON(o.currentOverridesFile); err != nil {
+		return err
+	}
+	if o.observedConfig, err = readJSON(o.observedConfigFile); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Run merges the overrides as the operator would and reports the outcome.
+func (o *validateOverridesOpts) Run() error {
+	problems := validate(o.overrides)
+
+	currentFlags, err := renderFlags(o.observedConfig, o.currentOverrides)
+	if err != nil {
+		return fmt.Errorf("failed to render the config with the current overrides: %v", err)
+	}
+	proposedFlags, err := renderFlags(o.observedConfig, o.overrides)
+	// the rendering rejects the overrides failing the schema validation again
+	if err != nil && len(problems) == 0 {
+		problems = append(problems, fmt.Sprintf("the config cannot be rendered: %v", err))
+	}
+
+	if len(problems) > 0 {
+		fmt.Fprintln(o.out, "The proposed overrides are invalid:")
+		for _, problem := range problems {
+			fmt.Fprintf(o.out, "  %s\n", problem)
+		}
+		return errors.New("validation failed")
+	}
+
+	removed := sets.NewString(currentFlags...).Difference(sets.NewString(proposedFlags...)).List()
+	added := sets.NewString(proposedFlags...).Difference(sets.NewString(currentFlags...)).List()
+	if len(removed) == 0 && len(added) == 0 {
+		fmt.Fprintln(o.out, "The proposed overrides do not change the kube-controller-manager flags.")
+		return nil
+	}
+	fmt.Fprintln(o.out, "The proposed overrides change the kube-controller-manager flags:")
+	for _, flag := range removed {
+		fmt.Fprintf(o.out, "- %s\n", flag)
+	}
+	for _, flag := range added {
+		fmt.Fprintf(o.out, "+ %s\n", flag)
+	}
+	return nil
+}
+
+// validate checks the overrides against the KubeControllerManagerConfig schema and the flags the next
+// kube-controller-manager does not accept any more.
+func validate(overrides []byte) []string {
+	if len(overrides) == 0 {
+		return nil
+	}
+	problems := []string{}
+	if err := targetconfigcontroller.ValidateConfigOverrides(overrides); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	removedFlags, err := upgradeablecontroller.RemovedFlagsInOverrides(overrides)
+	if err != nil {
+		problems = append(problems, err.Error())
+	}
+	for _, flag := range removedFlags {
+		problems = append(problems, fmt.Sprintf("extendedArguments.%s is removed from the next kube-controller-manager", flag))
+	}
+	return problems
+}
+
+// renderFlags returns the extended arguments of the kube-controller-manager config rendered by the operator.
+func renderFlags(observedConfig, overrides []byte) ([]string, error) {
+	configMap, err := targetconfigcontroller.MergeKubeControllerManagerConfig(observedConfig, overrides)
+	if err != nil {
+		return nil, err
+	}
+	var config map[string]interface{}
+	if err := yaml.Unmarshal([]byte(configMap.Data["config.yaml"]), &config); err != nil {
+		return nil, err
+	}
+	return targetconfigcontroller.GetKubeControllerManagerArgs(config), nil
+}
+
+func readJSON(file string) ([]byte, error) {
+	if len(file) == 0 {
+		return nil, nil
+	}
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(content)) == 0 {
+		return nil, nil
+	}
+	ret, err := yaml.YAMLToJSON(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", file, err)
+	}
+	return ret, nil
+}
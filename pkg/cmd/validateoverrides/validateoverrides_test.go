@@ -0,0 +1,71 @@
+package validateoverrides
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name             string
+		overrides        string
+		expectedProblems []string
+	}{
+		{
+			name: "no overrides",
+		},
+		{
+			name:      "valid overrides",
+			overrides: `{"extendedArguments":{"node-monitor-grace-period":["50s"]}}`,
+		},
+		{
+			name:      "deprecated flags still supported",
+			overrides: `{"extendedArguments":{"pod-eviction-timeout":["1m"],"enable-taint-manager":["true"]}}`,
+		},
+		{
+			name:      "operator overrides",
+			overrides: `{"shutdown":{"preStopSleepSeconds":10},"enableDeprecatedAndRemovedServiceCAKeyUntilNextRelease_ThisMakesClusterImpossibleToUpgrade":false}`,
+		},
+		{
+			name:             "unknown field",
+			overrides:        `{"extendedArgument":{"node-monitor-grace-period":["50s"]}}`,
+			expectedProblems: []string{`unknown field "extendedArgument"`},
+		},
+		{
+			name:             "removed flag",
+			overrides:        `{"extendedArguments":{"register-retry-count":["10"]}}`,
+			expectedProblems: []string{"extendedArguments.register-retry-count is removed from the next kube-controller-manager"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			problems := validate([]byte(test.overrides))
+			if len(problems) != len(test.expectedProblems) {
+				t.Fatalf("expected %d problems, got %v", len(test.expectedProblems), problems)
+			}
+			for i := range problems {
+				if !strings.Contains(problems[i], test.expectedProblems[i]) {
+					t.Errorf("expected %q in %q", test.expectedProblems[i], problems[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	out := &bytes.Buffer{}
+	opts := &validateOverridesOpts{
+		currentOverrides: []byte(`{"extendedArguments":{"node-monitor-grace-period":["40s"]}}`),
+		overrides:        []byte(`{"extendedArguments":{"node-monitor-grace-period":["50s"]}}`),
+		observedConfig:   []byte(`{"extendedArguments":{"cluster-name":["foo"]}}`),
+		out:              out,
+	}
+	if err := opts.Run(); err != nil {
+		t.Fatal(err)
+	}
+	expected := "The proposed overrides change the kube-controller-manager flags:\n- --node-monitor-grace-period=40s\n+ --node-monitor-grace-period=50s\n"
+	if out.String() != expected {
+		t.Errorf("expected %q, got %q", expected, out.String())
+	}
+}
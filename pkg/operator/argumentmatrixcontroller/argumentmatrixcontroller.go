@@ -0,0 +1,181 @@
+package argumentmatrixcontroller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/backpressure"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/controllerpause"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operationprofile"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/revisioncache"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
+)
+
+const (
+	// ConfigMapName is the configmap in the operator namespace holding the argument matrix.
+	ConfigMapName = "kube-controller-manager-argument-matrix"
+	// MatrixKey is the key of the configmap holding the matrix as JSON.
+	MatrixKey = "matrix.json"
+)
+
+// Matrix holds the kube-controller-manager arguments every control plane node runs with.
+type Matrix struct {
+	Nodes []NodeArguments `json:"nodes"`
+	// Inconsistent lists the arguments whose values are not the same on all nodes with known arguments, including
+	// the arguments set on some of the nodes only.
+	Inconsistent []string `json:"inconsistent,omitempty"`
+}
+
+// NodeArguments are the arguments of the kube-controller-manager on a node, at the revision the node runs.
+type NodeArguments struct {
+	NodeName string `json:"nodeName"`
+	Revision int32  `json:"revision"`
+	// ConfigChecksum is the checksum of the config.yaml of the revision, the value of the KCM_CONFIG_CHECKSUM
+	// environment variable of kube-controller-manager when the configIdentity override is enabled.
+	ConfigChecksum string `json:"configChecksum,omitempty"`
+	// Arguments maps the flags, without their leading dashes, to their values. Flags given several times have
+	// several values. It is empty when the node runs no revision yet or a revision without published flags.
+	Arguments map[string][]string `json:"arguments,omitempty"`
+}
+
+// ArgumentNames returns the sorted names of the arguments set on any of the nodes.
+func (m *Matrix) ArgumentNames() []string {
+	names := map[string]bool{}
+	for _, node := range m.Nodes {
+		for name := range node.Arguments {
+			names[name] = true
+		}
+	}
+	ret := make([]string, 0, len(names))
+	for name := range names {
+		ret = append(ret, name)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// ArgumentMatrixController publishes the effective arguments of the kube-controller-manager per node, read from the
+// flags configmap of the revision each node runs. The matrix tells at a glance whether all control plane nodes run
+// with the same arguments, which they do not during a rollout or when a node is stuck at an old revision.
+type ArgumentMatrixController struct {
+	operatorClient   v1helpers.StaticPodOperatorClient
+	revisionCache    *revisioncache.RevisionCache
+	configMapsGetter corev1client.ConfigMapsGetter
+}
+
+func NewArgumentMatrixController(
+	operatorClient v1helpers.StaticPodOperatorClient,
+	revisionCache *revisioncache.RevisionCache,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	configMapsGetter corev1client.ConfigMapsGetter,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &ArgumentMatrixController{
+		operatorClient:   operatorClient,
+		revisionCache:    revisionCache,
+		configMapsGetter: configMapsGetter,
+	}
+
+	return factory.New().WithInformers(
+		operatorClient.Informer(),
+		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().ConfigMaps().Informer(),
+		kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().ConfigMaps().Informer(),
+	).ResyncEvery(operationprofile.Resync(10*time.Minute)).WithSync(syncmetrics.Timed("ArgumentMatrixController", controllerpause.Pausable("ArgumentMatrixController", backpressure.Deferrable("ArgumentMatrixController", c.sync)))).ToController("ArgumentMatrixController", eventRecorder)
+}
+
+func (c *ArgumentMatrixController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	_, status, _, err := c.operatorClient.GetStaticPodOperatorState()
+	if err != nil {
+		return err
+	}
+
+	matrix := Matrix{Nodes: []NodeArguments{}}
+	for _, nodeStatus := range status.NodeStatuses {
+		node := NodeArguments{NodeName: nodeStatus.NodeName, Revision: nodeStatus.CurrentRevision}
+		if nodeStatus.CurrentRevision > 0 {
+			// revisions created before the flags were published have none
+			flags, err := c.revisionCache.Flags(nodeStatus.CurrentRevision)
+			if err != nil {
+				return fmt.Errorf("node %s: %v", nodeStatus.NodeName, err)
+			}
+			if flags != nil {
+				node.Arguments = ParseFlags(flags)
+			}
+			config, err := c.revisionCache.Config(nodeStatus.CurrentRevision)
+			if err != nil {
+				return fmt.Errorf("node %s: %v", nodeStatus.NodeName, err)
+			}
+			if config != nil {
+				node.ConfigChecksum = config.Checksum
+			}
+		}
+		matrix.Nodes = append(matrix.Nodes, node)
+	}
+	sort.Slice(matrix.Nodes, func(i, j int) bool { return matrix.Nodes[i].NodeName < matrix.Nodes[j].NodeName })
+	matrix.Inconsistent = inconsistentArguments(matrix.Nodes)
+
+	raw, err := json.Marshal(matrix)
+	if err != nil {
+		return err
+	}
+	// the configmap is only written when the matrix changes
+	_, _, err = resourceapply.ApplyConfigMap(ctx, c.configMapsGetter, syncCtx.Recorder(), &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: ConfigMapName},
+		Data:       map[string]string{MatrixKey: string(raw)},
+	})
+	return err
+}
+
+// ParseFlags maps the command line flags to their values, flags given without a value are boolean flags set to true.
+func ParseFlags(flags []string) map[string][]string {
+	arguments := map[string][]string{}
+	for _, flag := range flags {
+		flag = strings.TrimLeft(strings.TrimSpace(flag), "-")
+		if len(flag) == 0 {
+			continue
+		}
+		value := "true"
+		if i := strings.Index(flag, "="); i >= 0 {
+			flag, value = flag[:i], flag[i+1:]
+		}
+		arguments[flag] = append(arguments[flag], value)
+	}
+	return arguments
+}
+
+func inconsistentArguments(nodes []NodeArguments) []string {
+	known := []NodeArguments{}
+	for _, node := range nodes {
+		if len(node.Arguments) > 0 {
+			known = append(known, node)
+		}
+	}
+	if len(known) < 2 {
+		return nil
+	}
+	inconsistent := []string{}
+	for _, name := range (&Matrix{Nodes: known}).ArgumentNames() {
+		for _, node := range known[1:] {
+			if !reflect.DeepEqual(node.Arguments[name], known[0].Arguments[name]) {
+				inconsistent = append(inconsistent, name)
+				break
+			}
+		}
+	}
+	return inconsistent
+}
@@ -0,0 +1,96 @@
+package argumentmatrixcontroller
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/revisioncache"
+)
+
+func TestSync(t *testing.T) {
+	flags := func(revision string, flags string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "kube-controller-manager-flags-" + revision},
+			Data:       map[string]string{"flags": flags},
+		}
+	}
+	// the checksum of the config.yaml of revision 3, revision 4 has no config
+	checksum := "835c6e868c6b8bf288bca43689610f0a06861925cd2b6e3ecb3f293a98dfe0d6"
+	testCases := []struct {
+		name         string
+		nodeStatuses []operatorv1.NodeStatus
+		expected     Matrix
+	}{
+		{
+			name: "consistent",
+			nodeStatuses: []operatorv1.NodeStatus{
+				{NodeName: "master-1", CurrentRevision: 3},
+				{NodeName: "master-0", CurrentRevision: 3},
+			},
+			expected: Matrix{Nodes: []NodeArguments{
+				{NodeName: "master-0", Revision: 3, ConfigChecksum: checksum, Arguments: map[string][]string{"controllers": {"*", "-ttl"}, "v": {"2"}}},
+				{NodeName: "master-1", Revision: 3, ConfigChecksum: checksum, Arguments: map[string][]string{"controllers": {"*", "-ttl"}, "v": {"2"}}},
+			}},
+		},
+		{
+			name: "rollout",
+			nodeStatuses: []operatorv1.NodeStatus{
+				{NodeName: "master-0", CurrentRevision: 4},
+				{NodeName: "master-1", CurrentRevision: 3},
+				// revisions without published flags and nodes without a revision are left out of the comparison
+				{NodeName: "master-2", CurrentRevision: 1},
+				{NodeName: "master-3"},
+			},
+			expected: Matrix{
+				Nodes: []NodeArguments{
+					{NodeName: "master-0", Revision: 4, Arguments: map[string][]string{"controllers": {"*"}, "v": {"2"}, "profiling": {"true"}}},
+					{NodeName: "master-1", Revision: 3, ConfigChecksum: checksum, Arguments: map[string][]string{"controllers": {"*", "-ttl"}, "v": {"2"}}},
+					{NodeName: "master-2", Revision: 1},
+					{NodeName: "master-3"},
+				},
+				Inconsistent: []string{"controllers", "profiling"},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			configMapInformer := kubeinformers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0).Core().V1().ConfigMaps()
+			require.NoError(t, configMapInformer.Informer().GetIndexer().Add(flags("3", "--controllers=*\n--controllers=-ttl\n-v=2")))
+			require.NoError(t, configMapInformer.Informer().GetIndexer().Add(flags("4", "--controllers=*\n--profiling\n-v=2")))
+			require.NoError(t, configMapInformer.Informer().GetIndexer().Add(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "config-3"},
+				Data:       map[string]string{"config.yaml": `{"extendedArguments":{"v":["2"]}}`},
+			}))
+			kubeClient := fake.NewSimpleClientset()
+			c := &ArgumentMatrixController{
+				operatorClient: v1helpers.NewFakeStaticPodOperatorClient(
+					&operatorv1.StaticPodOperatorSpec{},
+					&operatorv1.StaticPodOperatorStatus{NodeStatuses: tc.nodeStatuses},
+					nil, nil),
+				revisionCache:    revisioncache.New(configMapInformer),
+				configMapsGetter: kubeClient.CoreV1(),
+			}
+			require.NoError(t, c.sync(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))))
+
+			configMap, err := kubeClient.CoreV1().ConfigMaps(operatorclient.OperatorNamespace).Get(context.TODO(), ConfigMapName, metav1.GetOptions{})
+			require.NoError(t, err)
+			matrix := Matrix{}
+			require.NoError(t, json.Unmarshal([]byte(configMap.Data[MatrixKey]), &matrix))
+			assert.Equal(t, tc.expected, matrix)
+		})
+	}
+}
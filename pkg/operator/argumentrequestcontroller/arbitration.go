@@ -0,0 +1,145 @@
+package argumentrequestcontroller
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ArgumentRequestGVR is the resource other operators request kube-controller-manager flag values with, defined by the
+// argumentrequest CRD of the manifests.
+var ArgumentRequestGVR = schema.GroupVersionResource{Group: "kubecontrollermanager.operator.openshift.io", Version: "v1alpha1", Resource: "kubecontrollermanagerargumentrequests"}
+
+// allowedFlags are the flags which may be requested, with the validation of their values. They must not be observed
+// by another config observer.
+var allowedFlags = map[string]func(string) error{
+	"concurrent-deployment-syncs":       validatePositiveInt,
+	"concurrent-gc-syncs":               validatePositiveInt,
+	"concurrent-namespace-syncs":        validatePositiveInt,
+	"concurrent-replicaset-syncs":       validatePositiveInt,
+	"concurrent-service-endpoint-syncs": validatePositiveInt,
+	"concurrent-statefulset-syncs":      validatePositiveInt,
+}
+
+// Paths returns the extendedArguments paths the requested flags are observed into, sorted.
+func Paths() [][]string {
+	flags := make([]string, 0, len(allowedFlags))
+	for flag := range allowedFlags {
+		flags = append(flags, flag)
+	}
+	sort.Strings(flags)
+	paths := make([][]string, 0, len(flags))
+	for _, flag := range flags {
+		paths = append(paths, []string{"extendedArguments", flag})
+	}
+	return paths
+}
+
+// Arbitration is the merge of the argument requests.
+type Arbitration struct {
+	// Arguments are the flags whose requests agree on a value, with the value.
+	Arguments map[string]string
+	// Conflicts are the flags requested with different values, with the namespace/name of the requests by value.
+	Conflicts map[string]map[string][]string
+	// Rejected are the requests with a flag which is not allowed or an invalid value, by namespace/name, with the
+	// reasons. They are ignored as a whole.
+	Rejected map[string][]string
+	// requested are the flags of the requests which were not rejected, by namespace/name
+	requested map[string][]string
+}
+
+// Arbitrate merges the argument requests: a flag gets the value its requests agree on, and none when they disagree.
+func Arbitrate(requests []*unstructured.Unstructured) Arbitration {
+	ret := Arbitration{
+		Arguments: map[string]string{},
+		Conflicts: map[string]map[string][]string{},
+		Rejected:  map[string][]string{},
+		requested: map[string][]string{},
+	}
+	requesters := map[string]map[string][]string{}
+	for _, request := range requests {
+		key := request.GetNamespace() + "/" + request.GetName()
+		arguments, _, err := unstructured.NestedStringMap(request.Object, "spec", "arguments")
+		if err != nil {
+			ret.Rejected[key] = []string{fmt.Sprintf("spec.arguments: %v", err)}
+			continue
+		}
+		reasons := []string{}
+		for flag, value := range arguments {
+			validate, ok := allowedFlags[flag]
+			if !ok {
+				reasons = append(reasons, fmt.Sprintf("%q is not an allowed flag", flag))
+				continue
+			}
+			if err := validate(value); err != nil {
+				reasons = append(reasons, fmt.Sprintf("invalid value %q of %q: %v", value, flag, err))
+			}
+		}
+		if len(reasons) > 0 {
+			sort.Strings(reasons)
+			ret.Rejected[key] = reasons
+			continue
+		}
+		for flag, value := range arguments {
+			if requesters[flag] == nil {
+				requesters[flag] = map[string][]string{}
+			}
+			requesters[flag][value] = append(requesters[flag][value], key)
+			ret.requested[key] = append(ret.requested[key], flag)
+		}
+		sort.Strings(ret.requested[key])
+	}
+
+	for flag, byValue := range requesters {
+		for _, keys := range byValue {
+			sort.Strings(keys)
+		}
+		if len(byValue) > 1 {
+			ret.Conflicts[flag] = byValue
+			continue
+		}
+		for value := range byValue {
+			ret.Arguments[flag] = value
+		}
+	}
+	return ret
+}
+
+// conflictsOf returns the flags of the request which conflict with other requests, sorted.
+func (a Arbitration) conflictsOf(key string) []string {
+	conflicts := []string{}
+	for _, flag := range a.requested[key] {
+		if _, ok := a.Conflicts[flag]; ok {
+			conflicts = append(conflicts, flag)
+		}
+	}
+	return conflicts
+}
+
+// describeConflict lists the values of the conflicting flag with their requests, sorted by value.
+func (a Arbitration) describeConflict(flag string) string {
+	values := make([]string, 0, len(a.Conflicts[flag]))
+	for value := range a.Conflicts[flag] {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+	ret := flag + ":"
+	for _, value := range values {
+		ret += fmt.Sprintf(" %q by %v", value, a.Conflicts[flag][value])
+	}
+	return ret
+}
+
+func validatePositiveInt(value string) error {
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return err
+	}
+	if i <= 0 {
+		return fmt.Errorf("must be positive")
+	}
+	return nil
+}
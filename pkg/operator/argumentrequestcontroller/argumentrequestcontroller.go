@@ -0,0 +1,178 @@
+package argumentrequestcontroller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operationprofile"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
+)
+
+const (
+	conditionType = "ArgumentRequestsDegraded"
+	// acceptedConditionType is the condition of the argument requests telling whether their flags are applied
+	acceptedConditionType = "Accepted"
+)
+
+// ArgumentRequestController reports the arbitration of the argument requests, in the Accepted condition of every
+// request and in the ArgumentRequestsDegraded condition of the operator. The argumentrequests config observer applies
+// the arbitrated flags.
+type ArgumentRequestController struct {
+	lister        cache.GenericLister
+	statusUpdater statusbatcher.StatusUpdater
+	// updateRequestStatus writes the status of an argument request
+	updateRequestStatus func(ctx context.Context, request *unstructured.Unstructured) error
+	now                 func() time.Time
+}
+
+func NewArgumentRequestController(
+	operatorClient v1helpers.OperatorClient,
+	argumentRequestInformer informers.GenericInformer,
+	dynamicClient dynamic.Interface,
+	statusUpdater statusbatcher.StatusUpdater,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &ArgumentRequestController{
+		lister:        argumentRequestInformer.Lister(),
+		statusUpdater: statusUpdater,
+		updateRequestStatus: func(ctx context.Context, request *unstructured.Unstructured) error {
+			_, err := dynamicClient.Resource(ArgumentRequestGVR).Namespace(request.GetNamespace()).UpdateStatus(ctx, request, metav1.UpdateOptions{})
+			return err
+		},
+		now: time.Now,
+	}
+
+	return factory.New().WithInformers(
+		operatorClient.Informer(),
+		argumentRequestInformer.Informer(),
+	).ResyncEvery(operationprofile.Resync(5*time.Minute)).WithSync(syncmetrics.Timed("ArgumentRequestController", c.sync)).ToController("ArgumentRequestController", eventRecorder)
+}
+
+func (c *ArgumentRequestController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	objs, err := c.lister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	requests := make([]*unstructured.Unstructured, 0, len(objs))
+	for _, obj := range objs {
+		requests = append(requests, obj.(*unstructured.Unstructured))
+	}
+	sort.Slice(requests, func(i, j int) bool {
+		return requests[i].GetNamespace()+"/"+requests[i].GetName() < requests[j].GetNamespace()+"/"+requests[j].GetName()
+	})
+	arbitration := Arbitrate(requests)
+
+	errs := []error{}
+	for _, request := range requests {
+		if err := c.syncRequestStatus(ctx, request, arbitration); err != nil {
+			errs = append(errs, fmt.Errorf("%s/%s: %v", request.GetNamespace(), request.GetName(), err))
+		}
+	}
+
+	condition := operatorv1.OperatorCondition{
+		Type:   conditionType,
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+	messages := []string{}
+	flags := make([]string, 0, len(arbitration.Conflicts))
+	for flag := range arbitration.Conflicts {
+		flags = append(flags, flag)
+	}
+	sort.Strings(flags)
+	for _, flag := range flags {
+		messages = append(messages, "conflicting requests of "+arbitration.describeConflict(flag))
+	}
+	keys := make([]string, 0, len(arbitration.Rejected))
+	for key := range arbitration.Rejected {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		messages = append(messages, fmt.Sprintf("rejected request %s: %s", key, strings.Join(arbitration.Rejected[key], ", ")))
+	}
+	if len(messages) > 0 {
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "ConflictingOrRejectedRequests"
+		condition.Message = strings.Join(messages, "\n")
+	}
+	if err := c.statusUpdater.UpdateStatus(ctx, v1helpers.UpdateConditionFn(condition)); err != nil {
+		errs = append(errs, err)
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// syncRequestStatus sets the Accepted condition of the request, unless it is set already.
+func (c *ArgumentRequestController) syncRequestStatus(ctx context.Context, request *unstructured.Unstructured, arbitration Arbitration) error {
+	key := request.GetNamespace() + "/" + request.GetName()
+	status, reason, message := metav1.ConditionTrue, "AsRequested", "the requested flags are applied"
+	if reasons, ok := arbitration.Rejected[key]; ok {
+		status, reason, message = metav1.ConditionFalse, "Rejected", strings.Join(reasons, ", ")
+	} else if conflicts := arbitration.conflictsOf(key); len(conflicts) > 0 {
+		descriptions := []string{}
+		for _, flag := range conflicts {
+			descriptions = append(descriptions, arbitration.describeConflict(flag))
+		}
+		status, reason = metav1.ConditionFalse, "Conflict"
+		message = fmt.Sprintf("other requests disagree, the flags keep their current values until the requests agree: %s", strings.Join(descriptions, ", "))
+	}
+
+	existing, _, err := unstructured.NestedSlice(request.Object, "status", "conditions")
+	if err != nil {
+		return err
+	}
+	observedGeneration, _, _ := unstructured.NestedInt64(request.Object, "status", "observedGeneration")
+	lastTransitionTime := c.now().UTC().Format(time.RFC3339)
+	for _, obj := range existing {
+		condition, ok := obj.(map[string]interface{})
+		if !ok || condition["type"] != acceptedConditionType {
+			continue
+		}
+		if condition["status"] == string(status) {
+			if condition["reason"] == reason && condition["message"] == message && observedGeneration == request.GetGeneration() {
+				return nil
+			}
+			if previous, ok := condition["lastTransitionTime"].(string); ok {
+				lastTransitionTime = previous
+			}
+		}
+	}
+
+	required := request.DeepCopy()
+	conditions := []interface{}{map[string]interface{}{
+		"type":               acceptedConditionType,
+		"status":             string(status),
+		"reason":             reason,
+		"message":            message,
+		"lastTransitionTime": lastTransitionTime,
+	}}
+	for _, obj := range existing {
+		if condition, ok := obj.(map[string]interface{}); ok && condition["type"] != acceptedConditionType {
+			conditions = append(conditions, condition)
+		}
+	}
+	if err := unstructured.SetNestedSlice(required.Object, conditions, "status", "conditions"); err != nil {
+		return err
+	}
+	if err := unstructured.SetNestedField(required.Object, request.GetGeneration(), "status", "observedGeneration"); err != nil {
+		return err
+	}
+	return c.updateRequestStatus(ctx, required)
+}
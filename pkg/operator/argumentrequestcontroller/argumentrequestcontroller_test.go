@@ -0,0 +1,134 @@
+package argumentrequestcontroller
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+)
+
+func newRequest(t *testing.T, namespace, name string, arguments map[string]string) *unstructured.Unstructured {
+	request := &unstructured.Unstructured{}
+	request.SetAPIVersion(ArgumentRequestGVR.GroupVersion().String())
+	request.SetKind("KubeControllerManagerArgumentRequest")
+	request.SetNamespace(namespace)
+	request.SetName(name)
+	request.SetGeneration(1)
+	if err := unstructured.SetNestedStringMap(request.Object, arguments, "spec", "arguments"); err != nil {
+		t.Fatal(err)
+	}
+	return request
+}
+
+func TestArbitrate(t *testing.T) {
+	arbitration := Arbitrate([]*unstructured.Unstructured{
+		newRequest(t, "openshift-a", "a", map[string]string{"concurrent-gc-syncs": "30", "concurrent-deployment-syncs": "10"}),
+		newRequest(t, "openshift-b", "b", map[string]string{"concurrent-gc-syncs": "30", "concurrent-deployment-syncs": "20"}),
+		newRequest(t, "openshift-c", "c", map[string]string{"concurrent-deployment-syncs": "20", "cluster-name": "other"}),
+		newRequest(t, "openshift-d", "d", map[string]string{"concurrent-namespace-syncs": "0"}),
+	})
+
+	if expected := map[string]string{"concurrent-gc-syncs": "30"}; !reflect.DeepEqual(arbitration.Arguments, expected) {
+		t.Errorf("expected arguments %v, got %v", expected, arbitration.Arguments)
+	}
+	expectedConflicts := map[string]map[string][]string{
+		"concurrent-deployment-syncs": {"10": {"openshift-a/a"}, "20": {"openshift-b/b"}},
+	}
+	if !reflect.DeepEqual(arbitration.Conflicts, expectedConflicts) {
+		t.Errorf("expected conflicts %v, got %v", expectedConflicts, arbitration.Conflicts)
+	}
+	expectedRejected := map[string][]string{
+		"openshift-c/c": {`"cluster-name" is not an allowed flag`},
+		"openshift-d/d": {`invalid value "0" of "concurrent-namespace-syncs": must be positive`},
+	}
+	if !reflect.DeepEqual(arbitration.Rejected, expectedRejected) {
+		t.Errorf("expected rejected %v, got %v", expectedRejected, arbitration.Rejected)
+	}
+	if conflicts := arbitration.conflictsOf("openshift-b/b"); !reflect.DeepEqual(conflicts, []string{"concurrent-deployment-syncs"}) {
+		t.Errorf("expected openshift-b/b to conflict on concurrent-deployment-syncs, got %v", conflicts)
+	}
+}
+
+func TestSync(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, request := range []*unstructured.Unstructured{
+		newRequest(t, "openshift-a", "a", map[string]string{"concurrent-gc-syncs": "30"}),
+		newRequest(t, "openshift-b", "b", map[string]string{"concurrent-gc-syncs": "40"}),
+		newRequest(t, "openshift-c", "c", map[string]string{"concurrent-statefulset-syncs": "10"}),
+	} {
+		if err := indexer.Add(request); err != nil {
+			t.Fatal(err)
+		}
+	}
+	operatorClient := v1helpers.NewFakeStaticPodOperatorClient(&operatorv1.StaticPodOperatorSpec{}, &operatorv1.StaticPodOperatorStatus{}, nil, nil)
+	updated := map[string]*unstructured.Unstructured{}
+	c := &ArgumentRequestController{
+		lister:        cache.NewGenericLister(indexer, ArgumentRequestGVR.GroupResource()),
+		statusUpdater: statusbatcher.NewDirectUpdater(operatorClient),
+		updateRequestStatus: func(ctx context.Context, request *unstructured.Unstructured) error {
+			updated[request.GetNamespace()+"/"+request.GetName()] = request
+			return indexer.Update(request)
+		},
+		now: func() time.Time { return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC) },
+	}
+	syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))
+
+	if err := c.sync(context.TODO(), syncCtx); err != nil {
+		t.Fatal(err)
+	}
+	expectedReasons := map[string]string{"openshift-a/a": "Conflict", "openshift-b/b": "Conflict", "openshift-c/c": "AsRequested"}
+	for key, expectedReason := range expectedReasons {
+		conditions, _, _ := unstructured.NestedSlice(updated[key].Object, "status", "conditions")
+		if len(conditions) != 1 || conditions[0].(map[string]interface{})["reason"] != expectedReason {
+			t.Errorf("expected the Accepted condition of %s to have the reason %s, got %v", key, expectedReason, conditions)
+		}
+	}
+	_, status, _, err := operatorClient.GetOperatorState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	condition := v1helpers.FindOperatorCondition(status.Conditions, conditionType)
+	if condition == nil || condition.Status != operatorv1.ConditionTrue {
+		t.Fatalf("expected %s to be true, got %v", conditionType, condition)
+	}
+	if expected := `conflicting requests of concurrent-gc-syncs: "30" by [openshift-a/a] "40" by [openshift-b/b]`; condition.Message != expected {
+		t.Errorf("expected message %q, got %q", expected, condition.Message)
+	}
+
+	// the status is only written when it changes
+	updated = map[string]*unstructured.Unstructured{}
+	if err := c.sync(context.TODO(), syncCtx); err != nil {
+		t.Fatal(err)
+	}
+	if len(updated) != 0 {
+		t.Errorf("expected no status updates, got %v", updated)
+	}
+
+	// the conflict resolves once the requests agree
+	if err := indexer.Delete(newRequest(t, "openshift-b", "b", nil)); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.sync(context.TODO(), syncCtx); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := updated["openshift-a/a"]; !ok || len(updated) != 1 {
+		t.Errorf("expected only the status of openshift-a/a to be updated, got %v", updated)
+	}
+	_, status, _, err = operatorClient.GetOperatorState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if condition := v1helpers.FindOperatorCondition(status.Conditions, conditionType); condition == nil || condition.Status != operatorv1.ConditionFalse {
+		t.Errorf("expected %s to be false, got %v", conditionType, condition)
+	}
+}
@@ -0,0 +1,279 @@
+package backpressure
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/component-base/metrics"
+	"k8s.io/klog/v2"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operationprofile"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatormetrics"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
+)
+
+const (
+	conditionType = "BackpressureActive"
+
+	// the watermarks are apart for the backpressure not to flap around a single threshold
+	memoryHighWatermark = 0.85
+	memoryLowWatermark  = 0.75
+	// throttledHighWatermark is the share of the CFS periods since the previous sample the operator was throttled in
+	throttledHighWatermark = 0.5
+	throttledLowWatermark  = 0.25
+
+	// unlimited is above any real cgroup v1 limit, v1 reports no limit as the largest page aligned int64
+	unlimited = int64(1) << 60
+)
+
+var deferredSyncs = metrics.NewCounterVec(
+	&metrics.CounterOpts{
+		Name:           "kcm_operator_backpressure_deferred_syncs_total",
+		Help:           "Syncs of optional operator controllers skipped while the operator was close to its resource limits.",
+		StabilityLevel: metrics.ALPHA,
+	},
+	[]string{"controller"},
+)
+
+func init() {
+	operatormetrics.MustRegister(deferredSyncs)
+}
+
+var (
+	lock     sync.RWMutex
+	active   bool
+	deferred = map[string]bool{}
+)
+
+// Active tells whether the operator is close to its resource limits and defers its optional work.
+func Active() bool {
+	lock.RLock()
+	defer lock.RUnlock()
+	return active
+}
+
+func setActive(value bool) {
+	lock.Lock()
+	defer lock.Unlock()
+	active = value
+	if !value {
+		deferred = map[string]bool{}
+	}
+}
+
+// deferredControllers returns the controllers which skipped a sync since the backpressure became active.
+func deferredControllers() []string {
+	lock.RLock()
+	defer lock.RUnlock()
+	ret := []string{}
+	for controller := range deferred {
+		ret = append(ret, controller)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// Deferrable wraps the sync of an optional controller to skip it while the backpressure is active. The work is not
+// lost: the next resync after the backpressure ends does it. The core reconcile loops must not be wrapped.
+func Deferrable(controller string, sync factory.SyncFunc) factory.SyncFunc {
+	return func(ctx context.Context, syncCtx factory.SyncContext) error {
+		if Active() {
+			lock.Lock()
+			deferred[controller] = true
+			lock.Unlock()
+			deferredSyncs.WithLabelValues(controller).Inc()
+			klog.V(2).Infof("%s sync deferred, the operator is close to its resource limits", controller)
+			return nil
+		}
+		return sync(ctx, syncCtx)
+	}
+}
+
+// usage is a sample of the resources used by the cgroup of the operator.
+type usage struct {
+	// memoryBytes is the working set, the usage without the inactive page cache the kernel reclaims before an OOM kill
+	memoryBytes int64
+	// memoryLimitBytes is 0 without a limit
+	memoryLimitBytes int64
+	// periods and throttledPeriods are the CFS periods so far, and the ones the cgroup ran out of CPU quota in
+	periods          int64
+	throttledPeriods int64
+}
+
+// Controller samples the resource usage of the operator from its cgroup, and activates the backpressure when the
+// memory working set or the CPU throttling approaches the limits of the operator pod. The optional controllers
+// wrapped with Deferrable then skip their syncs, so that the core reconcile loops are not OOM killed or starved by
+// auxiliary features. Without limits on the operator pod the backpressure never activates.
+type Controller struct {
+	statusUpdater statusbatcher.StatusUpdater
+	// cgroupRoot is where the cgroup filesystem of the operator is mounted
+	cgroupRoot string
+
+	// last is the previous sample, the CPU throttling is the one between two samples
+	last *usage
+}
+
+func NewController(statusUpdater statusbatcher.StatusUpdater, eventRecorder events.Recorder) factory.Controller {
+	c := &Controller{
+		statusUpdater: statusUpdater,
+		cgroupRoot:    "/sys/fs/cgroup",
+	}
+
+	return factory.New().ResyncEvery(operationprofile.Resync(30*time.Second)).WithSync(syncmetrics.Timed("BackpressureController", c.sync)).ToController("BackpressureController", eventRecorder)
+}
+
+func (c *Controller) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	condition := operatorv1.OperatorCondition{
+		Type:   conditionType,
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+	current, err := c.sample()
+	if err != nil {
+		// the backpressure is a safeguard, the operator runs on without it
+		klog.V(2).Infof("Unable to sample the resource usage of the operator: %v", err)
+		setActive(false)
+		condition.Reason = "UsageUnknown"
+		condition.Message = fmt.Sprintf("the resource usage of the operator cannot be sampled: %v", err)
+		return c.statusUpdater.UpdateStatus(ctx, v1helpers.UpdateConditionFn(condition))
+	}
+	wasActive := Active()
+	reason, pressures := c.pressures(current, wasActive)
+	c.last = &current
+
+	switch {
+	case len(pressures) > 0 && !wasActive:
+		setActive(true)
+		syncCtx.Recorder().Warningf("BackpressureActivated", "The operator defers its optional work: %s", strings.Join(pressures, ", "))
+	case len(pressures) == 0 && wasActive:
+		setActive(false)
+		syncCtx.Recorder().Eventf("BackpressureDeactivated", "The operator resumed its optional work")
+	}
+	if len(pressures) > 0 {
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = reason
+		condition.Message = fmt.Sprintf("the operator is close to its resource limits, its optional work is deferred: %s", strings.Join(pressures, ", "))
+		if controllers := deferredControllers(); len(controllers) > 0 {
+			condition.Message += fmt.Sprintf("; deferred so far: %s", strings.Join(controllers, ", "))
+		}
+	}
+	return c.statusUpdater.UpdateStatus(ctx, v1helpers.UpdateConditionFn(condition))
+}
+
+// pressures returns the reason and the descriptions of the resources under pressure. Active backpressure is released
+// below the low watermarks only.
+func (c *Controller) pressures(current usage, active bool) (string, []string) {
+	memoryWatermark, throttledWatermark := memoryHighWatermark, throttledHighWatermark
+	if active {
+		memoryWatermark, throttledWatermark = memoryLowWatermark, throttledLowWatermark
+	}
+	reasons := []string{}
+	pressures := []string{}
+	if current.memoryLimitBytes > 0 {
+		if ratio := float64(current.memoryBytes) / float64(current.memoryLimitBytes); ratio >= memoryWatermark {
+			reasons = append(reasons, "Memory")
+			pressures = append(pressures, fmt.Sprintf("the memory working set is %dMi of the %dMi limit (%.0f%%)",
+				current.memoryBytes>>20, current.memoryLimitBytes>>20, ratio*100))
+		}
+	}
+	if c.last != nil && current.periods > c.last.periods {
+		periods := current.periods - c.last.periods
+		throttled := current.throttledPeriods - c.last.throttledPeriods
+		if ratio := float64(throttled) / float64(periods); ratio >= throttledWatermark {
+			reasons = append(reasons, "CPU")
+			pressures = append(pressures, fmt.Sprintf("the CPU was throttled in %d of the last %d scheduling periods (%.0f%%)", throttled, periods, ratio*100))
+		}
+	}
+	if len(reasons) == 0 {
+		return "", nil
+	}
+	return strings.Join(reasons, "And") + "Pressure", pressures
+}
+
+// sample reads the usage from the unified cgroup v2 hierarchy, or from the cgroup v1 controllers.
+func (c *Controller) sample() (usage, error) {
+	if _, err := os.Stat(filepath.Join(c.cgroupRoot, "cgroup.controllers")); err == nil {
+		return c.sampleFiles("memory.current", "memory.max", "memory.stat", "inactive_file", "cpu.stat")
+	}
+	cpuStat := "cpu/cpu.stat"
+	if _, err := os.Stat(filepath.Join(c.cgroupRoot, "cpu,cpuacct/cpu.stat")); err == nil {
+		cpuStat = "cpu,cpuacct/cpu.stat"
+	}
+	return c.sampleFiles("memory/memory.usage_in_bytes", "memory/memory.limit_in_bytes", "memory/memory.stat", "total_inactive_file", cpuStat)
+}
+
+func (c *Controller) sampleFiles(usageFile, limitFile, memoryStatFile, inactiveKey, cpuStatFile string) (usage, error) {
+	ret := usage{}
+	var err error
+	if ret.memoryBytes, err = readValue(filepath.Join(c.cgroupRoot, usageFile)); err != nil {
+		return usage{}, err
+	}
+	if ret.memoryLimitBytes, err = readValue(filepath.Join(c.cgroupRoot, limitFile)); err != nil {
+		return usage{}, err
+	}
+	if ret.memoryLimitBytes >= unlimited {
+		ret.memoryLimitBytes = 0
+	}
+	memoryStat, err := readStat(filepath.Join(c.cgroupRoot, memoryStatFile))
+	if err != nil {
+		return usage{}, err
+	}
+	if inactive := memoryStat[inactiveKey]; inactive < ret.memoryBytes {
+		ret.memoryBytes -= inactive
+	}
+	// without a CPU quota there are no CFS periods to be throttled in
+	if cpuStat, err := readStat(filepath.Join(c.cgroupRoot, cpuStatFile)); err == nil {
+		ret.periods = cpuStat["nr_periods"]
+		ret.throttledPeriods = cpuStat["nr_throttled"]
+	}
+	return ret, nil
+}
+
+// readValue reads a single value cgroup file, "max" is no limit.
+func readValue(path string) (int64, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	value := strings.TrimSpace(string(content))
+	if value == "max" {
+		return 0, nil
+	}
+	return strconv.ParseInt(value, 10, 64)
+}
+
+// readStat reads a flat keyed cgroup file of "key value" lines.
+func readStat(path string) (map[string]int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	ret := map[string]int64{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		if value, err := strconv.ParseInt(fields[1], 10, 64); err == nil {
+			ret[fields[0]] = value
+		}
+	}
+	return ret, scanner.Err()
+}
@@ -0,0 +1,120 @@
+package backpressure
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+)
+
+func TestSync(t *testing.T) {
+	defer setActive(false)
+
+	root := t.TempDir()
+	writeFiles := func(files map[string]string) {
+		t.Helper()
+		for name, content := range files {
+			if err := ioutil.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	writeFiles(map[string]string{
+		"cgroup.controllers": "cpu memory",
+		"memory.max":         "104857600",
+		"memory.current":     "94371840",
+		// the inactive page cache does not count
+		"memory.stat": "anon 52428800\ninactive_file 20971520\n",
+		"cpu.stat":    "usage_usec 100\nnr_periods 100\nnr_throttled 10\n",
+	})
+
+	operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil)
+	c := &Controller{statusUpdater: statusbatcher.NewDirectUpdater(operatorClient), cgroupRoot: root}
+	recorder := events.NewInMemoryRecorder("test")
+	syncs := 0
+	optional := Deferrable("OptionalController", func(ctx context.Context, syncCtx factory.SyncContext) error {
+		syncs++
+		return nil
+	})
+	expectCondition := func(status operatorv1.ConditionStatus, reason, message string) {
+		t.Helper()
+		if err := c.sync(context.TODO(), factory.NewSyncContext("test", recorder)); err != nil {
+			t.Fatal(err)
+		}
+		_, operatorStatus, _, err := operatorClient.GetOperatorState()
+		if err != nil {
+			t.Fatal(err)
+		}
+		condition := v1helpers.FindOperatorCondition(operatorStatus.Conditions, conditionType)
+		if condition == nil || condition.Status != status || condition.Reason != reason || !strings.Contains(condition.Message, message) {
+			t.Fatalf("expected %s %s with reason %s and message containing %q, got %v", conditionType, status, reason, message, condition)
+		}
+	}
+
+	// 70Mi of 100Mi
+	expectCondition(operatorv1.ConditionFalse, "AsExpected", "")
+	if err := optional(context.TODO(), nil); err != nil || syncs != 1 {
+		t.Fatalf("expected the optional sync to run, got %d syncs: %v", syncs, err)
+	}
+
+	writeFiles(map[string]string{"memory.current": "115343360", "cpu.stat": "nr_periods 200\nnr_throttled 70\n"})
+	expectCondition(operatorv1.ConditionTrue, "MemoryAndCPUPressure", "the memory working set is 90Mi of the 100Mi limit (90%), the CPU was throttled in 60 of the last 100 scheduling periods (60%)")
+	if err := optional(context.TODO(), nil); err != nil || syncs != 1 {
+		t.Fatalf("expected the optional sync deferred, got %d syncs: %v", syncs, err)
+	}
+
+	// 80Mi is below the high watermark, but not below the low one
+	writeFiles(map[string]string{"memory.current": "104857600", "cpu.stat": "nr_periods 300\nnr_throttled 70\n"})
+	expectCondition(operatorv1.ConditionTrue, "MemoryPressure", "deferred so far: OptionalController")
+
+	writeFiles(map[string]string{"memory.current": "83886080"})
+	expectCondition(operatorv1.ConditionFalse, "AsExpected", "")
+	if err := optional(context.TODO(), nil); err != nil || syncs != 2 {
+		t.Fatalf("expected the optional sync to run again, got %d syncs: %v", syncs, err)
+	}
+
+	reasons := []string{}
+	for _, event := range recorder.Events() {
+		reasons = append(reasons, event.Reason)
+	}
+	if expected := "BackpressureActivated,BackpressureDeactivated"; strings.Join(reasons, ",") != expected {
+		t.Errorf("expected the events %s, got %v", expected, reasons)
+	}
+}
+
+func TestSampleCgroupV1(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"memory", "cpu,cpuacct"} {
+		if err := os.Mkdir(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for name, content := range map[string]string{
+		"memory/memory.usage_in_bytes": "73400320\n",
+		"memory/memory.limit_in_bytes": "9223372036854771712\n",
+		"memory/memory.stat":           "cache 0\ntotal_inactive_file 10485760\n",
+		"cpu,cpuacct/cpu.stat":         "nr_periods 0\nnr_throttled 0\nthrottled_time 0\n",
+	} {
+		if err := ioutil.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := &Controller{cgroupRoot: root}
+	sample, err := c.sample()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := (usage{memoryBytes: 62914560}); sample != expected {
+		t.Errorf("expected %+v without a memory limit, got %+v", expected, sample)
+	}
+}
@@ -0,0 +1,156 @@
+package certreloadcontroller
+
+import (
+	"context"
+	"crypto/x509"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/util/cert"
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operandendpoint"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operationprofile"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
+)
+
+const (
+	// ServingCertSecretName is the serving certificate of kube-controller-manager. It is synced in place by the cert
+	// syncer rather than revisioned, kube-controller-manager reloads it from disk.
+	ServingCertSecretName = "serving-cert"
+
+	// reloadTimeout leaves the cert syncer and the file watch of kube-controller-manager time to pick a rotated
+	// certificate up, both react within seconds
+	reloadTimeout = 10 * time.Minute
+
+	forceRedeploymentReasonPrefix = "serving-cert-reload-"
+)
+
+// CertReloadController verifies the in-place reload of the rotated serving certificate of kube-controller-manager. A
+// pod still serving another certificate reloadTimeout after the controller first saw it gets the operand redeployed,
+// the new revision restarts the pods with the current certificate.
+type CertReloadController struct {
+	operatorClient v1helpers.StaticPodOperatorClient
+	secretLister   corev1listers.SecretLister
+	podLister      corev1listers.PodLister
+	probe          operandendpoint.CertificateProber
+	now            func() time.Time
+
+	lock sync.Mutex
+	// stale is when a pod was first seen serving another certificate than the current one
+	stale map[types.UID]time.Time
+}
+
+func NewCertReloadController(
+	operatorClient v1helpers.StaticPodOperatorClient,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	informers := kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1()
+	c := &CertReloadController{
+		operatorClient: operatorClient,
+		secretLister:   informers.Secrets().Lister(),
+		podLister:      informers.Pods().Lister(),
+		probe:          operandendpoint.NewCertificateProber(),
+		now:            time.Now,
+		stale:          map[types.UID]time.Time{},
+	}
+
+	return factory.New().WithInformers(
+		informers.Secrets().Informer(),
+		informers.Pods().Informer(),
+	).ResyncEvery(operationprofile.Resync(time.Minute)).WithSync(syncmetrics.Timed("CertReloadController", c.sync)).ToController("CertReloadController", eventRecorder)
+}
+
+func (c *CertReloadController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	secret, err := c.secretLister.Secrets(operatorclient.TargetNamespace).Get(ServingCertSecretName)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	certificates, err := cert.ParseCertsPEM(secret.Data[corev1.TLSCertKey])
+	if err != nil || len(certificates) == 0 {
+		// the service CA operator rewrites a broken secret, there is nothing the pods could reload
+		return nil
+	}
+	expected := certificates[0]
+
+	pods, err := c.podLister.Pods(operatorclient.TargetNamespace).List(labels.SelectorFromSet(labels.Set{"app": "kube-controller-manager"}))
+	if err != nil {
+		return err
+	}
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	now := c.now()
+	seen := map[types.UID]bool{}
+	stalePods := []string{}
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodRunning || len(pod.Status.PodIP) == 0 {
+			continue
+		}
+		seen[pod.UID] = true
+		served, err := c.probe(ctx, pod)
+		if err != nil {
+			// an unreachable pod is reported by the availability checks, it tells nothing about the reload
+			klog.V(2).Infof("Failed to probe the serving certificate of %s: %v", pod.Name, err)
+			continue
+		}
+		if served.Equal(expected) {
+			delete(c.stale, pod.UID)
+			continue
+		}
+		since, found := c.stale[pod.UID]
+		if !found {
+			c.stale[pod.UID] = now
+			continue
+		}
+		if now.Sub(since) >= reloadTimeout {
+			stalePods = append(stalePods, pod.Name)
+		}
+	}
+	for uid := range c.stale {
+		if !seen[uid] {
+			delete(c.stale, uid)
+		}
+	}
+	if len(stalePods) == 0 {
+		return nil
+	}
+	return c.redeploy(ctx, syncCtx.Recorder(), expected, stalePods)
+}
+
+// redeploy forces a new revision, once per certificate: a pod not reloading it after a restart has another problem.
+func (c *CertReloadController) redeploy(ctx context.Context, recorder events.Recorder, expected *x509.Certificate, stalePods []string) error {
+	reason := forceRedeploymentReasonPrefix + expected.SerialNumber.String()
+	spec, _, resourceVersion, err := c.operatorClient.GetStaticPodOperatorState()
+	if err != nil {
+		return err
+	}
+	if spec.ForceRedeploymentReason == reason {
+		return nil
+	}
+	required := spec.DeepCopy()
+	required.ForceRedeploymentReason = reason
+	if _, _, err := c.operatorClient.UpdateStaticPodOperatorSpec(ctx, resourceVersion, required); err != nil {
+		return err
+	}
+	recorder.Warningf("ServingCertReloadFailed", "%s served another certificate than %s/%s for %s, redeploying kube-controller-manager",
+		strings.Join(stalePods, ", "), operatorclient.TargetNamespace, ServingCertSecretName, reloadTimeout)
+	return nil
+}
@@ -0,0 +1,102 @@
+package certreloadcontroller
+
+import (
+	"context"
+	"crypto/x509"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/cert"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+func newCertificate(t *testing.T) ([]byte, *x509.Certificate) {
+	certPEM, _, err := cert.GenerateSelfSignedCertKey("kube-controller-manager.openshift-kube-controller-manager.svc", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certificates, err := cert.ParseCertsPEM(certPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return certPEM, certificates[0]
+}
+
+func TestCertReload(t *testing.T) {
+	currentPEM, current := newCertificate(t)
+	_, previous := newCertificate(t)
+
+	newIndexer := func() cache.Indexer {
+		return cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	}
+	secrets, pods := newIndexer(), newIndexer()
+	if err := secrets.Add(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: ServingCertSecretName},
+		Data:       map[string][]byte{corev1.TLSCertKey: currentPEM},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"reloaded", "stale"} {
+		if err := pods.Add(&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: name, UID: types.UID(name), Labels: map[string]string{"app": "kube-controller-manager"}},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.0.0.1"},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	operatorClient := v1helpers.NewFakeStaticPodOperatorClient(&operatorv1.StaticPodOperatorSpec{}, &operatorv1.StaticPodOperatorStatus{}, nil, nil)
+	now := time.Now()
+	c := &CertReloadController{
+		operatorClient: operatorClient,
+		secretLister:   corev1listers.NewSecretLister(secrets),
+		podLister:      corev1listers.NewPodLister(pods),
+		probe: func(ctx context.Context, pod *corev1.Pod) (*x509.Certificate, error) {
+			if pod.Name == "stale" {
+				return previous, nil
+			}
+			return current, nil
+		},
+		now:   func() time.Time { return now },
+		stale: map[types.UID]time.Time{},
+	}
+	syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))
+	forceRedeploymentReason := func() string {
+		spec, _, _, err := operatorClient.GetStaticPodOperatorState()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return spec.ForceRedeploymentReason
+	}
+
+	// the stale pod gets reloadTimeout to reload
+	if err := c.sync(context.TODO(), syncCtx); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(reloadTimeout - time.Second)
+	if err := c.sync(context.TODO(), syncCtx); err != nil {
+		t.Fatal(err)
+	}
+	if reason := forceRedeploymentReason(); len(reason) > 0 {
+		t.Fatalf("expected no redeployment within the reload timeout, got %q", reason)
+	}
+
+	now = now.Add(time.Second)
+	if err := c.sync(context.TODO(), syncCtx); err != nil {
+		t.Fatal(err)
+	}
+	if reason, expected := forceRedeploymentReason(), forceRedeploymentReasonPrefix+current.SerialNumber.String(); reason != expected {
+		t.Errorf("expected the redeployment reason %q, got %q", expected, reason)
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/openshift/library-go/pkg/operator/v1helpers"
 
 	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/teardown"
 	"github.com/openshift/library-go/pkg/controller/factory"
 )
 
@@ -119,7 +120,19 @@ func newCertRotationController(
 		eventRecorder,
 	)
 
-	ret.certRotators = append(ret.certRotators, certRotator)
+	// the rotator of library-go takes no sync wrapper, its sync runs in a controller which skips it while the cluster
+	// is torn down
+	ret.certRotators = append(ret.certRotators, factory.New().WithInformers(
+		kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().Secrets().Informer(),
+		kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().ConfigMaps().Informer(),
+	).ResyncEvery(time.Minute).WithSync(teardown.Quiesceable("CSRSigningCert", certRotator.Sync)).ToController("CSRSigningCertRotationController", eventRecorder))
+	ret.certRotators = append(ret.certRotators, NewClusterPolicyControllerClientCertController(
+		kubeInformersForNamespaces,
+		secretsGetter,
+		eventRecorder,
+		rotationDay,
+		refreshOnlyWhenExpired,
+	))
 
 	return ret, nil
 }
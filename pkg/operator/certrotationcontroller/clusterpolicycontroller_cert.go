@@ -0,0 +1,181 @@
+package certrotationcontroller
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/authentication/user"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/util/cert"
+	"k8s.io/component-base/metrics"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/crypto"
+	"github.com/openshift/library-go/pkg/operator/certrotation"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operationprofile"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatormetrics"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/teardown"
+)
+
+const (
+	clusterPolicyControllerClientCertName = "cluster-policy-controller-client-cert-key"
+	clusterPolicyControllerUser           = "system:openshift:cluster-policy-controller"
+)
+
+var (
+	clusterPolicyControllerCertExpiry = metrics.NewGauge(
+		&metrics.GaugeOpts{
+			Name:           "kcm_operator_cluster_policy_controller_client_cert_expiry_timestamp_seconds",
+			Help:           "Expiry of the client certificate of cluster-policy-controller, in seconds since the epoch.",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+	clusterPolicyControllerCertRotations = metrics.NewCounter(
+		&metrics.CounterOpts{
+			Name:           "kcm_operator_cluster_policy_controller_client_cert_rotations_total",
+			Help:           "Number of client certificates issued to cluster-policy-controller.",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+)
+
+func init() {
+	operatormetrics.MustRegister(clusterPolicyControllerCertExpiry, clusterPolicyControllerCertRotations)
+}
+
+// ClusterPolicyControllerClientCertController issues cluster-policy-controller a client certificate of its own, so
+// that it no longer shares the identity of kube-controller-manager. The certificate is signed by the csr-signer of
+// the operand namespace, which the kube-apiserver trusts through csr-controller-ca, and rotated on a schedule of its
+// own.
+type ClusterPolicyControllerClientCertController struct {
+	secretLister    corev1listers.SecretLister
+	configMapLister corev1listers.ConfigMapLister
+	secretsGetter   corev1client.SecretsGetter
+
+	validity               time.Duration
+	refresh                time.Duration
+	refreshOnlyWhenExpired bool
+	certCreator            *certrotation.ClientRotation
+}
+
+func NewClusterPolicyControllerClientCertController(
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	secretsGetter corev1client.SecretsGetter,
+	eventRecorder events.Recorder,
+	rotationDay time.Duration,
+	refreshOnlyWhenExpired bool,
+) factory.Controller {
+	c := &ClusterPolicyControllerClientCertController{
+		secretLister:           kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().Secrets().Lister(),
+		configMapLister:        kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().ConfigMaps().Lister(),
+		secretsGetter:          secretsGetter,
+		validity:               30 * rotationDay,
+		refresh:                15 * rotationDay,
+		refreshOnlyWhenExpired: refreshOnlyWhenExpired,
+		certCreator:            &certrotation.ClientRotation{UserInfo: &user.DefaultInfo{Name: clusterPolicyControllerUser}},
+	}
+
+	return factory.New().WithInformers(
+		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().Secrets().Informer(),
+		kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().ConfigMaps().Informer(),
+	).ResyncEvery(operationprofile.Resync(time.Minute)).WithSync(syncmetrics.Timed("ClusterPolicyControllerClientCertController", teardown.Quiesceable("ClusterPolicyControllerClientCertController", c.sync))).ToController("ClusterPolicyControllerClientCertController", eventRecorder)
+}
+
+func (c *ClusterPolicyControllerClientCertController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	// the csr-signer of the operand namespace is only updated once the kube-apiserver had the time to trust it
+	signerSecret, err := c.secretLister.Secrets(operatorclient.TargetNamespace).Get("csr-signer")
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	signer, err := crypto.GetCAFromBytes(signerSecret.Data["tls.crt"], signerSecret.Data["tls.key"])
+	if err != nil {
+		return fmt.Errorf("secret %s/%s: %v", signerSecret.Namespace, signerSecret.Name, err)
+	}
+	caBundleCerts := []*x509.Certificate{}
+	caBundle, err := c.configMapLister.ConfigMaps(operatorclient.OperatorNamespace).Get("csr-signer-ca")
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	if caBundle != nil && len(caBundle.Data["ca-bundle.crt"]) > 0 {
+		caBundleCerts, err = cert.ParseCertsPEM([]byte(caBundle.Data["ca-bundle.crt"]))
+		if err != nil {
+			return fmt.Errorf("configmap %s/%s: %v", caBundle.Namespace, caBundle.Name, err)
+		}
+	}
+
+	existing, err := c.secretLister.Secrets(operatorclient.TargetNamespace).Get(clusterPolicyControllerClientCertName)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: clusterPolicyControllerClientCertName}}
+	if existing != nil {
+		secret = existing.DeepCopy()
+	}
+
+	reason := c.certCreator.NeedNewTargetCertKeyPair(secret.Annotations, signer, caBundleCerts, c.refresh, c.refreshOnlyWhenExpired)
+	if len(reason) == 0 {
+		recordExpiry(secret.Annotations)
+		return nil
+	}
+	syncCtx.Recorder().Eventf("ClusterPolicyControllerClientCertRotated", "Issuing a new client certificate to cluster-policy-controller: %s", reason)
+	if err := c.setClientCert(secret, signer); err != nil {
+		return err
+	}
+	if _, _, err := resourceapply.ApplySecret(ctx, c.secretsGetter, syncCtx.Recorder(), secret); err != nil {
+		return err
+	}
+	clusterPolicyControllerCertRotations.Inc()
+	recordExpiry(secret.Annotations)
+	return nil
+}
+
+// setClientCert puts a new certificate into the secret, it never outlives its signer.
+func (c *ClusterPolicyControllerClientCertController) setClientCert(secret *corev1.Secret, signer *crypto.CA) error {
+	validity := c.validity
+	if remaining := time.Until(signer.Config.Certs[0].NotAfter); remaining < validity {
+		validity = remaining
+	}
+	certKeyPair, err := c.certCreator.NewCertificate(signer, validity)
+	if err != nil {
+		return err
+	}
+
+	secret.Type = corev1.SecretTypeTLS
+	secret.Data = map[string][]byte{}
+	secret.Data["tls.crt"], secret.Data["tls.key"], err = certKeyPair.GetPEMBytes()
+	if err != nil {
+		return err
+	}
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	secret.Annotations[certrotation.CertificateNotAfterAnnotation] = certKeyPair.Certs[0].NotAfter.Format(time.RFC3339)
+	secret.Annotations[certrotation.CertificateNotBeforeAnnotation] = certKeyPair.Certs[0].NotBefore.Format(time.RFC3339)
+	secret.Annotations[certrotation.CertificateIssuer] = certKeyPair.Certs[0].Issuer.CommonName
+	c.certCreator.SetAnnotations(certKeyPair, secret.Annotations)
+	certrotation.LabelAsManagedSecret(secret, certrotation.CertificateTypeTarget)
+	return nil
+}
+
+func recordExpiry(annotations map[string]string) {
+	notAfter, err := time.Parse(time.RFC3339, annotations[certrotation.CertificateNotAfterAnnotation])
+	if err != nil {
+		return
+	}
+	clusterPolicyControllerCertExpiry.Set(float64(notAfter.Unix()))
+}
@@ -0,0 +1,97 @@
+package certrotationcontroller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/cert"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/crypto"
+	"github.com/openshift/library-go/pkg/operator/certrotation"
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+func TestClusterPolicyControllerClientCert(t *testing.T) {
+	signerConfig, err := crypto.MakeSelfSignedCAConfigForDuration("kube-csr-signer", 10*24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signerCert, signerKey, err := signerConfig.GetPEMBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secretIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	configMapIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	kubeClient := fake.NewSimpleClientset()
+	c := &ClusterPolicyControllerClientCertController{
+		secretLister:    corev1listers.NewSecretLister(secretIndexer),
+		configMapLister: corev1listers.NewConfigMapLister(configMapIndexer),
+		secretsGetter:   kubeClient.CoreV1(),
+		validity:        30 * 24 * time.Hour,
+		refresh:         15 * 24 * time.Hour,
+		certCreator:     &certrotation.ClientRotation{UserInfo: &user.DefaultInfo{Name: clusterPolicyControllerUser}},
+	}
+	syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))
+
+	// nothing is issued before the signer is in place
+	if err := c.sync(context.TODO(), syncCtx); err != nil {
+		t.Fatal(err)
+	}
+	if len(kubeClient.Actions()) != 0 {
+		t.Fatalf("expected no actions without a signer, got %v", kubeClient.Actions())
+	}
+
+	if err := secretIndexer.Add(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "csr-signer"},
+		Data:       map[string][]byte{"tls.crt": signerCert, "tls.key": signerKey},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := configMapIndexer.Add(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: "csr-signer-ca"},
+		Data:       map[string]string{"ca-bundle.crt": string(signerCert)},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.sync(context.TODO(), syncCtx); err != nil {
+		t.Fatal(err)
+	}
+
+	secret, err := kubeClient.CoreV1().Secrets(operatorclient.TargetNamespace).Get(context.TODO(), clusterPolicyControllerClientCertName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	certs, err := cert.ParseCertsPEM(secret.Data["tls.crt"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if certs[0].Subject.CommonName != clusterPolicyControllerUser {
+		t.Errorf("expected a certificate for %s, got %s", clusterPolicyControllerUser, certs[0].Subject.CommonName)
+	}
+	if certs[0].NotAfter.After(signerConfig.Certs[0].NotAfter) {
+		t.Errorf("expected the certificate not to outlive its signer, it expires %v, the signer %v", certs[0].NotAfter, signerConfig.Certs[0].NotAfter)
+	}
+
+	// a valid certificate is kept
+	if err := secretIndexer.Add(secret); err != nil {
+		t.Fatal(err)
+	}
+	kubeClient.ClearActions()
+	if err := c.sync(context.TODO(), syncCtx); err != nil {
+		t.Fatal(err)
+	}
+	if len(kubeClient.Actions()) != 0 {
+		t.Errorf("expected the certificate to be kept, got %v", kubeClient.Actions())
+	}
+}
@@ -16,7 +16,11 @@ import (
 	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 
 	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operationprofile"
 	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/teardown"
 	"github.com/openshift/library-go/pkg/controller/factory"
 	"github.com/openshift/library-go/pkg/operator/encryption/crypto"
 	"github.com/openshift/library-go/pkg/operator/events"
@@ -30,6 +34,7 @@ const (
 
 type SATokenSignerController struct {
 	operatorClient  v1helpers.StaticPodOperatorClient
+	statusUpdater   statusbatcher.StatusUpdater
 	secretClient    corev1client.SecretsGetter
 	configMapClient corev1client.ConfigMapsGetter
 	endpointClient  corev1client.EndpointsGetter
@@ -40,12 +45,14 @@ type SATokenSignerController struct {
 
 func NewSATokenSignerController(
 	operatorClient v1helpers.StaticPodOperatorClient,
+	statusUpdater statusbatcher.StatusUpdater,
 	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
 	kubeClient kubernetes.Interface,
 	eventRecorder events.Recorder,
 ) factory.Controller {
 	c := &SATokenSignerController{
 		operatorClient:  operatorClient,
+		statusUpdater:   statusUpdater,
 		secretClient:    v1helpers.CachedSecretGetter(kubeClient.CoreV1(), kubeInformersForNamespaces),
 		configMapClient: v1helpers.CachedConfigMapGetter(kubeClient.CoreV1(), kubeInformersForNamespaces),
 		endpointClient:  kubeClient.CoreV1(),
@@ -58,7 +65,7 @@ func NewSATokenSignerController(
 		kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().Secrets().Informer(),
 		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().Secrets().Informer(),
 		operatorClient.Informer(),
-	).ResyncEvery(time.Minute).WithSync(c.sync).ToController("SATokenSignerController", eventRecorder)
+	).ResyncEvery(operationprofile.Resync(time.Minute)).WithSync(syncmetrics.Timed("SATokenSignerController", teardown.Quiesceable("SATokenSignerController", c.sync))).ToController("SATokenSignerController", eventRecorder)
 }
 
 func (c *SATokenSignerController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
@@ -72,7 +79,7 @@ func (c *SATokenSignerController) sync(ctx context.Context, syncCtx factory.Sync
 		condition.Reason = "Error"
 		condition.Message = syncErr.Error()
 	}
-	if _, _, updateErr := v1helpers.UpdateStatus(ctx, c.operatorClient, v1helpers.UpdateConditionFn(condition)); updateErr != nil {
+	if updateErr := c.statusUpdater.UpdateStatus(ctx, v1helpers.UpdateConditionFn(condition)); updateErr != nil {
 		return updateErr
 	}
 
@@ -0,0 +1,131 @@
+package clusteridentity
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configv1client "github.com/openshift/client-go/config/clientset/versioned/typed/config/v1"
+	configinformers "github.com/openshift/client-go/config/informers/externalversions"
+	configv1listers "github.com/openshift/client-go/config/listers/config/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operationprofile"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatormetrics"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
+)
+
+// eventLabelPrefix prefixes the identity labels of the events
+const eventLabelPrefix = "kube-controller-manager.openshift.io/"
+
+// Identity identifies the cluster in the metrics and events of the operator, for fleet-wide dashboards to slice
+// them by platform and topology without joining them with an info metric.
+type Identity struct {
+	InfrastructureName string
+	Platform           string
+	Topology           string
+}
+
+var (
+	lock    sync.RWMutex
+	current Identity
+)
+
+// Current returns the identity of the cluster, empty until the Infrastructure config was read.
+func Current() Identity {
+	lock.RLock()
+	defer lock.RUnlock()
+	return current
+}
+
+func set(identity Identity) {
+	lock.Lock()
+	defer lock.Unlock()
+	current = identity
+}
+
+// FromInfrastructure returns the identity of the cluster described by the Infrastructure config.
+func FromInfrastructure(infrastructure *configv1.Infrastructure) Identity {
+	identity := Identity{
+		InfrastructureName: infrastructure.Status.InfrastructureName,
+		Platform:           string(infrastructure.Status.Platform),
+		Topology:           string(infrastructure.Status.ControlPlaneTopology),
+	}
+	if infrastructure.Status.PlatformStatus != nil && len(infrastructure.Status.PlatformStatus.Type) > 0 {
+		identity.Platform = string(infrastructure.Status.PlatformStatus.Type)
+	}
+	return identity
+}
+
+// Load reads the identity from the Infrastructure config, for the metrics and events before the Controller synced.
+func Load(ctx context.Context, client configv1client.InfrastructuresGetter) error {
+	infrastructure, err := client.Infrastructures().Get(ctx, "cluster", metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	set(FromInfrastructure(infrastructure))
+	return nil
+}
+
+func init() {
+	operatormetrics.SetLabels(func() map[string]string { return Current().metricLabels() })
+}
+
+// metricLabels returns the identity as metric labels, without the unknown parts.
+func (i Identity) metricLabels() map[string]string {
+	return withoutEmpty(map[string]string{
+		"infrastructure_name": i.InfrastructureName,
+		"platform":            i.Platform,
+		"topology":            i.Topology,
+	})
+}
+
+// EventLabels returns the identity as labels of the events, without the unknown parts.
+func (i Identity) EventLabels() map[string]string {
+	return withoutEmpty(map[string]string{
+		eventLabelPrefix + "infrastructure-name": i.InfrastructureName,
+		eventLabelPrefix + "platform":            i.Platform,
+		eventLabelPrefix + "topology":            i.Topology,
+	})
+}
+
+func withoutEmpty(labels map[string]string) map[string]string {
+	for name, value := range labels {
+		if len(value) == 0 {
+			delete(labels, name)
+		}
+	}
+	return labels
+}
+
+// Controller keeps the identity up to date with the Infrastructure config.
+type Controller struct {
+	infrastructureLister configv1listers.InfrastructureLister
+}
+
+func NewController(configInformers configinformers.SharedInformerFactory, eventRecorder events.Recorder) factory.Controller {
+	c := &Controller{
+		infrastructureLister: configInformers.Config().V1().Infrastructures().Lister(),
+	}
+
+	return factory.New().WithInformers(
+		configInformers.Config().V1().Infrastructures().Informer(),
+	).ResyncEvery(operationprofile.Resync(10*time.Minute)).WithSync(syncmetrics.Timed("ClusterIdentityController", c.sync)).ToController("ClusterIdentityController", eventRecorder)
+}
+
+func (c *Controller) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	infrastructure, err := c.infrastructureLister.Get("cluster")
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	set(FromInfrastructure(infrastructure))
+	return nil
+}
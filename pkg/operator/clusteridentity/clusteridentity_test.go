@@ -0,0 +1,54 @@
+package clusteridentity
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configv1listers "github.com/openshift/client-go/config/listers/config/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+)
+
+func TestController(t *testing.T) {
+	defer set(Identity{})
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	if err := indexer.Add(&configv1.Infrastructure{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Status: configv1.InfrastructureStatus{
+			InfrastructureName:   "fleet-7xk2p",
+			Platform:             configv1.AWSPlatformType,
+			PlatformStatus:       &configv1.PlatformStatus{Type: configv1.GCPPlatformType},
+			ControlPlaneTopology: configv1.SingleReplicaTopologyMode,
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	c := &Controller{infrastructureLister: configv1listers.NewInfrastructureLister(indexer)}
+	if err := c.sync(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedMetricLabels := map[string]string{
+		"infrastructure_name": "fleet-7xk2p",
+		"platform":            "GCP",
+		"topology":            "SingleReplica",
+	}
+	if actual := Current().metricLabels(); !reflect.DeepEqual(actual, expectedMetricLabels) {
+		t.Errorf("expected the metric labels %v, got %v", expectedMetricLabels, actual)
+	}
+
+	expectedEventLabels := map[string]string{
+		"kube-controller-manager.openshift.io/infrastructure-name": "fleet-7xk2p",
+		"kube-controller-manager.openshift.io/platform":            "GCP",
+		"kube-controller-manager.openshift.io/topology":            "SingleReplica",
+	}
+	if actual := Current().EventLabels(); !reflect.DeepEqual(actual, expectedEventLabels) {
+		t.Errorf("expected the event labels %v, got %v", expectedEventLabels, actual)
+	}
+}
@@ -0,0 +1,258 @@
+package conditionaggregation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configv1client "github.com/openshift/client-go/config/clientset/versioned/typed/config/v1"
+	configv1informers "github.com/openshift/client-go/config/informers/externalversions/config/v1"
+	configv1listers "github.com/openshift/client-go/config/listers/config/v1"
+)
+
+const (
+	// maxMessageLength caps the Degraded and Progressing messages of the ClusterOperator. The causes beyond are
+	// counted rather than listed.
+	maxMessageLength = 4096
+	// maxLineLength keeps a single verbose cause from crowding out the others.
+	maxLineLength = 1024
+)
+
+// priorities orders the causes of the Degraded and Progressing conditions by the condition type without its suffix.
+// The causes about the operand itself come first, the causes which are not listed follow in the order of their types.
+var priorities = []string{
+	"TargetNamespaceRecovery",
+	"StaticPods",
+	"NodeInstaller",
+	"InstallerPodPending",
+	"InstallerPodContainerWaiting",
+	"InstallerPodNetworking",
+	"InstallerController",
+	"StaticPodFallbackRevision",
+	"RevisionController",
+	"TargetConfigController",
+	"ConfigObservation",
+	"NodeController",
+}
+
+// aggregatedConditionTypes are the conditions of the ClusterOperator which are unioned from many causes.
+var aggregatedConditionTypes = []configv1.ClusterStatusConditionType{configv1.OperatorDegraded, configv1.OperatorProgressing}
+
+// Aggregator orders the causes in the Degraded and Progressing messages of the ClusterOperator by priority and caps
+// the messages, counting the causes which do not fit. The ClusterOperator status controller unions the conditions of
+// the operator ordered by their types and writes messages of any length, which the consumers truncate wherever they
+// happen to.
+//
+// The status controller only writes the ClusterOperator when its union differs from the ClusterOperator in its
+// lister. The lister of the Aggregator returns the union in place of the aggregated message it was written as, the
+// status controller would write on every sync otherwise.
+type Aggregator struct {
+	lock sync.Mutex
+	// written is the last aggregated message written per condition type, with the union it replaced
+	written map[configv1.ClusterStatusConditionType]aggregation
+}
+
+type aggregation struct {
+	aggregated string
+	unioned    string
+}
+
+func NewAggregator() *Aggregator {
+	return &Aggregator{written: map[configv1.ClusterStatusConditionType]aggregation{}}
+}
+
+// ClusterOperatorsGetter returns the client for the ClusterOperator status controller, which aggregates the messages
+// on UpdateStatus.
+func (a *Aggregator) ClusterOperatorsGetter(getter configv1client.ClusterOperatorsGetter) configv1client.ClusterOperatorsGetter {
+	return &aggregatingGetter{ClusterOperatorsGetter: getter, aggregator: a}
+}
+
+// ClusterOperatorInformer returns the informer for the ClusterOperator status controller, whose lister returns the
+// unions of the aggregated messages.
+func (a *Aggregator) ClusterOperatorInformer(informer configv1informers.ClusterOperatorInformer) configv1informers.ClusterOperatorInformer {
+	return &restoringInformer{ClusterOperatorInformer: informer, aggregator: a}
+}
+
+func (a *Aggregator) aggregate(status *configv1.ClusterOperatorStatus) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	for i := range status.Conditions {
+		condition := &status.Conditions[i]
+		if !isAggregated(condition.Type) {
+			continue
+		}
+		aggregated := aggregateMessage(string(condition.Type), condition.Message)
+		a.written[condition.Type] = aggregation{aggregated: aggregated, unioned: condition.Message}
+		condition.Message = aggregated
+	}
+}
+
+func (a *Aggregator) restore(status *configv1.ClusterOperatorStatus) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	for i := range status.Conditions {
+		condition := &status.Conditions[i]
+		if written, ok := a.written[condition.Type]; ok && written.aggregated == condition.Message {
+			condition.Message = written.unioned
+		}
+	}
+}
+
+func isAggregated(conditionType configv1.ClusterStatusConditionType) bool {
+	for _, aggregated := range aggregatedConditionTypes {
+		if conditionType == aggregated {
+			return true
+		}
+	}
+	return false
+}
+
+type cause struct {
+	conditionType string
+	lines         []string
+}
+
+// aggregateMessage reorders the lines of a union, each of them prefixed by the type of the condition it comes from, by
+// the priority of their conditions and drops the lines beyond maxMessageLength for a count of them. Messages which are
+// no union of causes, like "All is well", are returned as they are.
+func aggregateMessage(conditionType, message string) string {
+	causes := []*cause{}
+	byType := map[string]*cause{}
+	for _, line := range strings.Split(message, "\n") {
+		separator := strings.Index(line, ": ")
+		if separator < 0 || !strings.HasSuffix(line[:separator], conditionType) || strings.Contains(line[:separator], " ") {
+			return message
+		}
+		causeType, text := line[:separator], line[separator+len(": "):]
+		if byType[causeType] == nil {
+			byType[causeType] = &cause{conditionType: causeType}
+			causes = append(causes, byType[causeType])
+		}
+		byType[causeType].lines = append(byType[causeType].lines, truncate(text, maxLineLength))
+	}
+	sort.SliceStable(causes, func(i, j int) bool {
+		iPriority, jPriority := priority(conditionType, causes[i].conditionType), priority(conditionType, causes[j].conditionType)
+		if iPriority != jPriority {
+			return iPriority < jPriority
+		}
+		return causes[i].conditionType < causes[j].conditionType
+	})
+
+	lines, lineTypes := []string{}, []string{}
+	for _, cause := range causes {
+		for _, text := range cause.lines {
+			lines = append(lines, cause.conditionType+": "+text)
+			lineTypes = append(lineTypes, cause.conditionType)
+		}
+	}
+	// drop the lines of the lowest priority until the rest fits together with the count of the dropped ones
+	for kept := len(lines); ; kept-- {
+		aggregated := lines[:kept]
+		if omitted := len(lines) - kept; omitted > 0 {
+			aggregated = append(aggregated[:kept:kept], fmt.Sprintf("... and %d more messages from %s", omitted, strings.Join(uniqueTypes(lineTypes[kept:]), ", ")))
+		}
+		if message := strings.Join(aggregated, "\n"); len(message) <= maxMessageLength || kept == 0 {
+			return message
+		}
+	}
+}
+
+// priority returns the index of the condition type in priorities, the types which are not listed come last.
+func priority(conditionType, causeType string) int {
+	prefix := strings.TrimSuffix(causeType, conditionType)
+	for i, prioritized := range priorities {
+		if prefix == prioritized {
+			return i
+		}
+	}
+	return len(priorities)
+}
+
+func uniqueTypes(types []string) []string {
+	ret := []string{}
+	for _, conditionType := range types {
+		if len(ret) == 0 || ret[len(ret)-1] != conditionType {
+			ret = append(ret, conditionType)
+		}
+	}
+	return ret
+}
+
+// truncate cuts the text to at most maxLength bytes, at a rune boundary.
+func truncate(text string, maxLength int) string {
+	if len(text) <= maxLength {
+		return text
+	}
+	const ellipsis = "..."
+	end := maxLength - len(ellipsis)
+	for end > 0 && !utf8.RuneStart(text[end]) {
+		end--
+	}
+	return text[:end] + ellipsis
+}
+
+type aggregatingGetter struct {
+	configv1client.ClusterOperatorsGetter
+	aggregator *Aggregator
+}
+
+func (g *aggregatingGetter) ClusterOperators() configv1client.ClusterOperatorInterface {
+	return &aggregatingClusterOperators{ClusterOperatorInterface: g.ClusterOperatorsGetter.ClusterOperators(), aggregator: g.aggregator}
+}
+
+type aggregatingClusterOperators struct {
+	configv1client.ClusterOperatorInterface
+	aggregator *Aggregator
+}
+
+func (c *aggregatingClusterOperators) UpdateStatus(ctx context.Context, clusterOperator *configv1.ClusterOperator, opts metav1.UpdateOptions) (*configv1.ClusterOperator, error) {
+	aggregated := clusterOperator.DeepCopy()
+	c.aggregator.aggregate(&aggregated.Status)
+	return c.ClusterOperatorInterface.UpdateStatus(ctx, aggregated, opts)
+}
+
+type restoringInformer struct {
+	configv1informers.ClusterOperatorInformer
+	aggregator *Aggregator
+}
+
+func (i *restoringInformer) Lister() configv1listers.ClusterOperatorLister {
+	return &restoringLister{ClusterOperatorLister: i.ClusterOperatorInformer.Lister(), aggregator: i.aggregator}
+}
+
+type restoringLister struct {
+	configv1listers.ClusterOperatorLister
+	aggregator *Aggregator
+}
+
+func (l *restoringLister) Get(name string) (*configv1.ClusterOperator, error) {
+	clusterOperator, err := l.ClusterOperatorLister.Get(name)
+	if err != nil {
+		return clusterOperator, err
+	}
+	// the object is shared with the cache, it must not be modified
+	restored := clusterOperator.DeepCopy()
+	l.aggregator.restore(&restored.Status)
+	return restored, nil
+}
+
+func (l *restoringLister) List(selector labels.Selector) ([]*configv1.ClusterOperator, error) {
+	clusterOperators, err := l.ClusterOperatorLister.List(selector)
+	if err != nil {
+		return clusterOperators, err
+	}
+	ret := []*configv1.ClusterOperator{}
+	for _, clusterOperator := range clusterOperators {
+		restored := clusterOperator.DeepCopy()
+		l.aggregator.restore(&restored.Status)
+		ret = append(ret, restored)
+	}
+	return ret, nil
+}
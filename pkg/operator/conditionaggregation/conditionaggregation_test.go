@@ -0,0 +1,125 @@
+package conditionaggregation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configv1client "github.com/openshift/client-go/config/clientset/versioned/typed/config/v1"
+	configv1listers "github.com/openshift/client-go/config/listers/config/v1"
+)
+
+func TestAggregateMessage(t *testing.T) {
+	union := strings.Join([]string{
+		"CSRSigningDegraded: signer expired",
+		"NodeInstallerDegraded: 1 nodes are failing on revision 7:",
+		"NodeInstallerDegraded: installer pod failed",
+		"StaticPodsDegraded: pod kube-controller-manager-master-0 crashlooping",
+	}, "\n")
+	expected := strings.Join([]string{
+		"StaticPodsDegraded: pod kube-controller-manager-master-0 crashlooping",
+		"NodeInstallerDegraded: 1 nodes are failing on revision 7:",
+		"NodeInstallerDegraded: installer pod failed",
+		"CSRSigningDegraded: signer expired",
+	}, "\n")
+	if actual := aggregateMessage("Degraded", union); actual != expected {
+		t.Errorf("expected the operand first:\n%s\ngot:\n%s", expected, actual)
+	}
+
+	for _, message := range []string{"", "All is well"} {
+		if actual := aggregateMessage("Degraded", message); actual != message {
+			t.Errorf("expected %q unchanged, got %q", message, actual)
+		}
+	}
+
+	lines := []string{"StaticPodsDegraded: " + strings.Repeat("x", 2*maxLineLength)}
+	for i := 0; i < 100; i++ {
+		lines = append(lines, fmt.Sprintf("ZDegraded: failure %d", i))
+		lines = append(lines, fmt.Sprintf("YDegraded: failure %d", i))
+	}
+	actual := aggregateMessage("Degraded", strings.Join(lines, "\n"))
+	if len(actual) > maxMessageLength {
+		t.Fatalf("expected at most %d bytes, got %d", maxMessageLength, len(actual))
+	}
+	if !strings.HasPrefix(actual, "StaticPodsDegraded: xxx") || !strings.Contains(actual, "...\nYDegraded: failure 0\n") {
+		t.Errorf("expected the truncated operand cause first, got %q", actual)
+	}
+	if !strings.HasSuffix(actual, "more messages from ZDegraded") {
+		t.Errorf("expected the lowest priority cause counted, got %q", actual)
+	}
+}
+
+type fakeClusterOperators struct {
+	configv1client.ClusterOperatorInterface
+	indexer cache.Indexer
+}
+
+func (c *fakeClusterOperators) ClusterOperators() configv1client.ClusterOperatorInterface {
+	return c
+}
+
+func (c *fakeClusterOperators) UpdateStatus(ctx context.Context, clusterOperator *configv1.ClusterOperator, opts metav1.UpdateOptions) (*configv1.ClusterOperator, error) {
+	return clusterOperator, c.indexer.Update(clusterOperator)
+}
+
+type fakeInformer struct {
+	indexer cache.Indexer
+}
+
+func (i *fakeInformer) Informer() cache.SharedIndexInformer {
+	return nil
+}
+
+func (i *fakeInformer) Lister() configv1listers.ClusterOperatorLister {
+	return configv1listers.NewClusterOperatorLister(i.indexer)
+}
+
+func TestAggregator(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	union := "ZDegraded: z failed\nStaticPodsDegraded: pod crashlooping"
+	clusterOperator := &configv1.ClusterOperator{
+		ObjectMeta: metav1.ObjectMeta{Name: "kube-controller-manager"},
+		Status: configv1.ClusterOperatorStatus{Conditions: []configv1.ClusterOperatorStatusCondition{
+			{Type: configv1.OperatorDegraded, Status: configv1.ConditionTrue, Message: union},
+			{Type: configv1.OperatorAvailable, Status: configv1.ConditionTrue, Message: "ZAvailable: a\nStaticPodsAvailable: b"},
+		}},
+	}
+	if err := indexer.Add(clusterOperator); err != nil {
+		t.Fatal(err)
+	}
+
+	aggregator := NewAggregator()
+	client := aggregator.ClusterOperatorsGetter(&fakeClusterOperators{indexer: indexer})
+	lister := aggregator.ClusterOperatorInformer(&fakeInformer{indexer: indexer}).Lister()
+	if _, err := client.ClusterOperators().UpdateStatus(context.TODO(), clusterOperator, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	stored, err := configv1listers.NewClusterOperatorLister(indexer).Get("kube-controller-manager")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "StaticPodsDegraded: pod crashlooping\nZDegraded: z failed"; stored.Status.Conditions[0].Message != expected {
+		t.Errorf("expected %q written, got %q", expected, stored.Status.Conditions[0].Message)
+	}
+	if expected := "ZAvailable: a\nStaticPodsAvailable: b"; stored.Status.Conditions[1].Message != expected {
+		t.Errorf("expected the Available message unchanged, got %q", stored.Status.Conditions[1].Message)
+	}
+
+	// the status controller compares its union with the lister, which must see no difference
+	listed, err := lister.Get("kube-controller-manager")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if listed.Status.Conditions[0].Message != union {
+		t.Errorf("expected the lister to return the union %q, got %q", union, listed.Status.Conditions[0].Message)
+	}
+	if stored.Status.Conditions[0].Message == union {
+		t.Errorf("expected the lister to leave the cached object unmodified")
+	}
+}
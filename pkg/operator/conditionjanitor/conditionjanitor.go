@@ -0,0 +1,162 @@
+package conditionjanitor
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/condition"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/controllergraph"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/controllerpause"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operationprofile"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
+)
+
+// externalConditionTypes are the conditions set by the controllers of library-go, which the controller graph does not
+// look into.
+var externalConditionTypes = []string{
+	condition.ManagementStateDegradedConditionType,
+	condition.UnsupportedConfigOverridesUpgradeableConditionType,
+	condition.MonitoringResourceControllerDegradedConditionType,
+	condition.BackingResourceControllerDegradedConditionType,
+	condition.StaticPodsDegradedConditionType,
+	condition.StaticPodsAvailableConditionType,
+	condition.ConfigObservationDegradedConditionType,
+	condition.ResourceSyncControllerDegradedConditionType,
+	condition.InstallerControllerDegradedConditionType,
+	condition.NodeInstallerDegradedConditionType,
+	condition.NodeInstallerProgressingConditionType,
+	condition.RevisionControllerDegradedConditionType,
+	condition.NodeControllerDegradedConditionType,
+	"InstallerPodPendingDegraded",
+	"InstallerPodContainerWaitingDegraded",
+	"InstallerPodNetworkingDegraded",
+	"StaticPodFallbackRevisionDegraded",
+	"StartupMonitorPodDegraded",
+	"StartupMonitorPodContainerExcessiveRestartsDegraded",
+	// the controllers syncing with WithSyncDegradedOnError report their errors as <name>Degraded
+	"GuardControllerDegraded",
+	"MissingStaticPodControllerDegraded",
+	"KubeControllerManagerStaticResourcesDegraded",
+}
+
+// externalConditionPrefixes are the prefixes of the conditions library-go names after the instance of a controller,
+// one per certificate for the cert rotation controllers.
+var externalConditionPrefixes = []string{
+	"CertRotation_",
+}
+
+// ConditionJanitor removes the operator conditions which no controller of this build owns any more. A controller
+// removed by an upgrade leaves its conditions behind, a stale Degraded=True keeps the ClusterOperator degraded for
+// good and a stale Degraded=False still shows up in the aggregated messages. The owned conditions are the ones of the
+// controller graph and the known conditions of the library-go controllers, every removed condition is recorded with
+// an event.
+//
+// A condition set again after it was removed has an owner the janitor does not know of, it is left alone from then on
+// rather than removed on every sync.
+type ConditionJanitor struct {
+	operatorClient v1helpers.OperatorClient
+	statusUpdater  statusbatcher.StatusUpdater
+	owned          map[string]bool
+
+	lock sync.Mutex
+	// removed are the conditions the janitor removed, reclaimed the ones which were set again afterwards
+	removed   map[string]bool
+	reclaimed map[string]bool
+}
+
+func NewConditionJanitor(
+	operatorClient v1helpers.OperatorClient,
+	statusUpdater statusbatcher.StatusUpdater,
+	graph *controllergraph.Graph,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &ConditionJanitor{
+		operatorClient: operatorClient,
+		statusUpdater:  statusUpdater,
+		owned:          ownedConditions(graph),
+		removed:        map[string]bool{},
+		reclaimed:      map[string]bool{},
+	}
+	return factory.New().WithInformers(
+		operatorClient.Informer(),
+	).ResyncEvery(operationprofile.Resync(10*time.Minute)).WithSync(syncmetrics.Timed("ConditionJanitor", controllerpause.Pausable("ConditionJanitor", c.sync))).ToController("ConditionJanitor", eventRecorder)
+}
+
+// ownedConditions returns the conditions set by the controllers of the graph and by the controllers of library-go.
+func ownedConditions(graph *controllergraph.Graph) map[string]bool {
+	owned := map[string]bool{}
+	for _, controller := range graph.Controllers {
+		for _, conditionType := range controller.Conditions {
+			owned[conditionType] = true
+		}
+	}
+	for _, conditionType := range externalConditionTypes {
+		owned[conditionType] = true
+	}
+	return owned
+}
+
+func (c *ConditionJanitor) isOwned(conditionType string) bool {
+	if c.owned[conditionType] {
+		return true
+	}
+	for _, prefix := range externalConditionPrefixes {
+		if strings.HasPrefix(conditionType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *ConditionJanitor) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	_, status, _, err := c.operatorClient.GetOperatorState()
+	if err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	stale := []operatorv1.OperatorCondition{}
+	for _, condition := range status.Conditions {
+		if c.isOwned(condition.Type) || c.reclaimed[condition.Type] {
+			continue
+		}
+		if c.removed[condition.Type] {
+			c.reclaimed[condition.Type] = true
+			syncCtx.Recorder().Warningf("StaleConditionReclaimed", "Condition %s was set again after it was removed as stale, it has an owner unknown to the janitor and is kept from now on", condition.Type)
+			continue
+		}
+		stale = append(stale, condition)
+	}
+	c.lock.Unlock()
+	if len(stale) == 0 {
+		return nil
+	}
+	sort.Slice(stale, func(i, j int) bool { return stale[i].Type < stale[j].Type })
+
+	removeFn := func(status *operatorv1.OperatorStatus) error {
+		for _, condition := range stale {
+			v1helpers.RemoveOperatorCondition(&status.Conditions, condition.Type)
+		}
+		return nil
+	}
+	if err := c.statusUpdater.UpdateStatus(ctx, removeFn); err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for _, condition := range stale {
+		c.removed[condition.Type] = true
+		syncCtx.Recorder().Eventf("StaleConditionRemoved", "Removed condition %s=%s, no controller owns it any more", condition.Type, condition.Status)
+	}
+	return nil
+}
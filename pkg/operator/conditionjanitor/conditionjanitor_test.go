@@ -0,0 +1,112 @@
+package conditionjanitor
+
+import (
+	"context"
+	"testing"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/controllergraph"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+)
+
+func conditionTypes(t *testing.T, operatorClient v1helpers.OperatorClient) []string {
+	_, status, _, err := operatorClient.GetOperatorState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ret := []string{}
+	for _, condition := range status.Conditions {
+		ret = append(ret, condition.Type)
+	}
+	return ret
+}
+
+func TestSync(t *testing.T) {
+	graph := &controllergraph.Graph{Controllers: []controllergraph.Controller{
+		{Name: "ExampleController", Conditions: []string{"ExampleDegraded"}},
+	}}
+	operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{
+		Conditions: []operatorv1.OperatorCondition{
+			{Type: "ExampleDegraded", Status: operatorv1.ConditionFalse},
+			{Type: "NodeInstallerProgressing", Status: operatorv1.ConditionFalse},
+			{Type: "CertRotation_CSRSigningCert_Degraded", Status: operatorv1.ConditionFalse},
+			{Type: "RemovedControllerDegraded", Status: operatorv1.ConditionTrue},
+			{Type: "LatencyProfileProgressing", Status: operatorv1.ConditionFalse},
+		},
+	}, nil)
+	c := &ConditionJanitor{
+		operatorClient: operatorClient,
+		statusUpdater:  statusbatcher.NewDirectUpdater(operatorClient),
+		owned:          ownedConditions(graph),
+		removed:        map[string]bool{},
+		reclaimed:      map[string]bool{},
+	}
+	recorder := events.NewInMemoryRecorder("test")
+	syncCtx := factory.NewSyncContext("test", recorder)
+
+	if err := c.sync(context.TODO(), syncCtx); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"ExampleDegraded", "NodeInstallerProgressing", "CertRotation_CSRSigningCert_Degraded"}
+	if got := conditionTypes(t, operatorClient); !equal(got, expected) {
+		t.Errorf("expected conditions %v, got %v", expected, got)
+	}
+	if len(recorder.Events()) != 2 {
+		t.Fatalf("expected an event per removed condition, got %v", recorder.Events())
+	}
+	if recorder.Events()[0].Reason != "StaleConditionRemoved" || recorder.Events()[0].Message != "Removed condition LatencyProfileProgressing=False, no controller owns it any more" {
+		t.Errorf("unexpected event %#v", recorder.Events()[0])
+	}
+
+	// an owner unknown to the janitor sets its condition again, it is kept from then on
+	_, status, resourceVersion, err := operatorClient.GetOperatorState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	status = status.DeepCopy()
+	v1helpers.SetOperatorCondition(&status.Conditions, operatorv1.OperatorCondition{Type: "RemovedControllerDegraded", Status: operatorv1.ConditionFalse})
+	if _, err := operatorClient.UpdateOperatorStatus(context.TODO(), resourceVersion, status); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := c.sync(context.TODO(), syncCtx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected = append(expected, "RemovedControllerDegraded")
+	if got := conditionTypes(t, operatorClient); !equal(got, expected) {
+		t.Errorf("expected conditions %v, got %v", expected, got)
+	}
+	if len(recorder.Events()) != 3 || recorder.Events()[2].Reason != "StaleConditionReclaimed" {
+		t.Errorf("expected a single StaleConditionReclaimed event, got %v", recorder.Events())
+	}
+}
+
+func TestOwnedConditionsOfThisBuild(t *testing.T) {
+	graph, err := controllergraph.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &ConditionJanitor{owned: ownedConditions(graph)}
+	for _, conditionType := range []string{"TargetConfigControllerDegraded", "GuardPodsDegraded", "StaticPodsDegraded", "CertRotation_KubeControllerManagerClient_Degraded"} {
+		if !c.isOwned(conditionType) {
+			t.Errorf("%s is not owned", conditionType)
+		}
+	}
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,196 @@
+package configbaseline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/blang/semver"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operationprofile"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/targetconfigcontroller"
+)
+
+const (
+	// ConfigMapName is the configmap in the operator namespace holding the config baseline of the operator version and
+	// its diff against the baseline of the previous minor version.
+	ConfigMapName = "kube-controller-manager-config-baseline"
+
+	versionKey         = "version"
+	configKey          = "config.yaml"
+	previousVersionKey = "previousVersion"
+	diffKey            = "diff"
+)
+
+// BaselineController records the kube-controller-manager config the operator renders without any observed config or
+// override, its defaults. On every minor version upgrade of the operator, it records what the defaults of the new
+// version changed: the config of a cluster changes with the upgrade itself, not only with what the admins set.
+type BaselineController struct {
+	operatorVersion  string
+	configMapLister  corev1listers.ConfigMapLister
+	configMapsGetter corev1client.ConfigMapsGetter
+	// defaultConfig renders the default config, replaced in tests
+	defaultConfig func() (string, error)
+}
+
+func NewBaselineController(
+	operatorVersion string,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	configMapsGetter corev1client.ConfigMapsGetter,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &BaselineController{
+		operatorVersion:  operatorVersion,
+		configMapLister:  kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().ConfigMaps().Lister(),
+		configMapsGetter: configMapsGetter,
+		defaultConfig:    renderDefaultConfig,
+	}
+
+	return factory.New().WithInformers(
+		kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().ConfigMaps().Informer(),
+	).ResyncEvery(operationprofile.Resync(time.Hour)).WithSync(syncmetrics.Timed("ConfigBaselineController", c.sync)).ToController("ConfigBaselineController", eventRecorder)
+}
+
+func renderDefaultConfig() (string, error) {
+	configMap, err := targetconfigcontroller.MergeKubeControllerManagerConfig(nil, nil)
+	if err != nil {
+		return "", err
+	}
+	return configMap.Data["config.yaml"], nil
+}
+
+func (c *BaselineController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	current, err := semver.ParseTolerant(c.operatorVersion)
+	if err != nil {
+		// operators run without OPERATOR_IMAGE_VERSION have no version to compare
+		return nil
+	}
+	existing, err := c.configMapLister.ConfigMaps(operatorclient.OperatorNamespace).Get(ConfigMapName)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	if existing != nil {
+		recorded, err := semver.ParseTolerant(existing.Data[versionKey])
+		if err == nil && recorded.Major == current.Major && recorded.Minor == current.Minor {
+			return nil
+		}
+	}
+
+	config, err := c.defaultConfig()
+	if err != nil {
+		return err
+	}
+	required := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: ConfigMapName},
+		Data: map[string]string{
+			versionKey: c.operatorVersion,
+			configKey:  config,
+		},
+	}
+	// the first baseline has nothing to compare with, the operator was installed with this version
+	var changes []string
+	if existing != nil && len(existing.Data[configKey]) > 0 {
+		changes, err = diffConfig(existing.Data[configKey], config)
+		if err != nil {
+			return err
+		}
+		required.Data[previousVersionKey] = existing.Data[versionKey]
+		required.Data[diffKey] = strings.Join(changes, "\n")
+	}
+	if _, _, err := resourceapply.ApplyConfigMap(ctx, c.configMapsGetter, syncCtx.Recorder(), required); err != nil {
+		return err
+	}
+	if existing != nil {
+		if len(changes) == 0 {
+			syncCtx.Recorder().Eventf("ConfigBaselineUnchanged", "The upgrade from %s to %s does not change the default kube-controller-manager config", existing.Data[versionKey], c.operatorVersion)
+		} else {
+			syncCtx.Recorder().Eventf("ConfigBaselineChanged", "The upgrade from %s to %s changes the default kube-controller-manager config, see configmap %s/%s:\n%s", existing.Data[versionKey], c.operatorVersion, operatorclient.OperatorNamespace, ConfigMapName, strings.Join(changes, "\n"))
+		}
+	}
+	return nil
+}
+
+// diffConfig lists the changes between two rendered configs, one line per changed value, prefixed with + for new
+// values, - for removed ones and ~ for changed ones, like ~ extendedArguments.flag: ["old"] -> ["new"].
+func diffConfig(previous, current string) ([]string, error) {
+	previousValues, err := flattenConfig(previous)
+	if err != nil {
+		return nil, fmt.Errorf("previous baseline: %v", err)
+	}
+	currentValues, err := flattenConfig(current)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := []string{}
+	for path := range previousValues {
+		paths = append(paths, path)
+	}
+	for path := range currentValues {
+		if _, ok := previousValues[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	changes := []string{}
+	for _, path := range paths {
+		previousValue, inPrevious := previousValues[path]
+		currentValue, inCurrent := currentValues[path]
+		switch {
+		case !inPrevious:
+			changes = append(changes, fmt.Sprintf("+ %s: %s", path, formatValue(currentValue)))
+		case !inCurrent:
+			changes = append(changes, fmt.Sprintf("- %s: %s", path, formatValue(previousValue)))
+		case !reflect.DeepEqual(previousValue, currentValue):
+			changes = append(changes, fmt.Sprintf("~ %s: %s -> %s", path, formatValue(previousValue), formatValue(currentValue)))
+		}
+	}
+	return changes, nil
+}
+
+// flattenConfig maps the dotted path of every leaf of the config to its value. Lists are leaves, the
+// kube-controller-manager flags are lists of values.
+func flattenConfig(config string) (map[string]interface{}, error) {
+	object := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(config), &object); err != nil {
+		return nil, err
+	}
+	values := map[string]interface{}{}
+	var flatten func(prefix string, object map[string]interface{})
+	flatten = func(prefix string, object map[string]interface{}) {
+		for key, value := range object {
+			if nested, ok := value.(map[string]interface{}); ok && len(nested) > 0 {
+				flatten(prefix+key+".", nested)
+				continue
+			}
+			values[prefix+key] = value
+		}
+	}
+	flatten("", object)
+	return values, nil
+}
+
+func formatValue(value interface{}) string {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(raw)
+}
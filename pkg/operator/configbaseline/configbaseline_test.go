@@ -0,0 +1,120 @@
+package configbaseline
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+func TestDiffConfig(t *testing.T) {
+	previous := `{"apiVersion":"v1","extendedArguments":{"removed":["a"],"changed":["1"],"same":["x"]},"serviceServingCert":{"certFile":"/a"}}`
+	current := `{"apiVersion":"v1","extendedArguments":{"added":["b"],"changed":["2"],"same":["x"]},"serviceServingCert":{"certFile":"/a"}}`
+	changes, err := diffConfig(previous, current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		`+ extendedArguments.added: ["b"]`,
+		`~ extendedArguments.changed: ["1"] -> ["2"]`,
+		`- extendedArguments.removed: ["a"]`,
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("expected %v, got %v", expected, changes)
+	}
+}
+
+func TestBaselineController(t *testing.T) {
+	previousConfig := `{"extendedArguments":{"removed":["a"]}}`
+	currentConfig := `{"extendedArguments":{"added":["b"]}}`
+
+	tests := []struct {
+		name            string
+		operatorVersion string
+		existing        map[string]string
+		expectedData    map[string]string
+		expectedEvent   string
+	}{
+		{
+			name:            "first baseline",
+			operatorVersion: "4.15.2",
+			expectedData:    map[string]string{versionKey: "4.15.2", configKey: currentConfig},
+		},
+		{
+			name:            "z-stream upgrade keeps the baseline",
+			operatorVersion: "4.15.3",
+			existing:        map[string]string{versionKey: "4.15.2", configKey: previousConfig},
+			expectedData:    map[string]string{versionKey: "4.15.2", configKey: previousConfig},
+		},
+		{
+			name:            "minor upgrade",
+			operatorVersion: "4.16.0",
+			existing:        map[string]string{versionKey: "4.15.2", configKey: previousConfig},
+			expectedData: map[string]string{
+				versionKey:         "4.16.0",
+				configKey:          currentConfig,
+				previousVersionKey: "4.15.2",
+				diffKey:            "+ extendedArguments.added: [\"b\"]\n- extendedArguments.removed: [\"a\"]",
+			},
+			expectedEvent: "ConfigBaselineChanged",
+		},
+		{
+			name:            "no operator version",
+			operatorVersion: "",
+			existing:        map[string]string{versionKey: "4.15.2", configKey: previousConfig},
+			expectedData:    map[string]string{versionKey: "4.15.2", configKey: previousConfig},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			objects := []runtime.Object{}
+			if tc.existing != nil {
+				existing := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: ConfigMapName}, Data: tc.existing}
+				if err := indexer.Add(existing); err != nil {
+					t.Fatal(err)
+				}
+				objects = append(objects, existing)
+			}
+			kubeClient := fake.NewSimpleClientset(objects...)
+			c := &BaselineController{
+				operatorVersion:  tc.operatorVersion,
+				configMapLister:  corev1listers.NewConfigMapLister(indexer),
+				configMapsGetter: kubeClient.CoreV1(),
+				defaultConfig:    func() (string, error) { return currentConfig, nil },
+			}
+			recorder := events.NewInMemoryRecorder("test")
+			if err := c.sync(context.TODO(), factory.NewSyncContext("test", recorder)); err != nil {
+				t.Fatal(err)
+			}
+
+			actual, err := kubeClient.CoreV1().ConfigMaps(operatorclient.OperatorNamespace).Get(context.TODO(), ConfigMapName, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(actual.Data, tc.expectedData) {
+				t.Errorf("expected %v, got %v", tc.expectedData, actual.Data)
+			}
+			reasons := []string{}
+			for _, event := range recorder.Events() {
+				if event.Reason == "ConfigBaselineChanged" || event.Reason == "ConfigBaselineUnchanged" {
+					reasons = append(reasons, event.Reason)
+				}
+			}
+			if len(tc.expectedEvent) == 0 && len(reasons) > 0 || len(tc.expectedEvent) > 0 && !reflect.DeepEqual(reasons, []string{tc.expectedEvent}) {
+				t.Errorf("expected event %q, got %v", tc.expectedEvent, reasons)
+			}
+		})
+	}
+}
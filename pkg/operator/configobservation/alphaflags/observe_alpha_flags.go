@@ -0,0 +1,132 @@
+package alphaflags
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/library-go/pkg/operator/configobserver"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation"
+)
+
+// AnnotationName on the kubecontrollermanager resource holds the alpha flags to pass to kube-controller-manager as a
+// JSON object of flag names to values. The operator API has no field for them, they are honoured on
+// TechPreviewNoUpgrade clusters only.
+const AnnotationName = "kube-controller-manager.openshift.io/alpha-flags"
+
+// allowedFlags are the flags kube-controller-manager documents as alpha or experimental, with the validation of their
+// values. Generally available settings do not belong here, they are either observed or left to the unsupported config
+// overrides, and so do the settings which risk the workloads, like disabling the attach-detach reconciliation.
+var allowedFlags = map[string]func(string) error{
+	// non-default logging formats are alpha
+	"logging-format":            validateOneOf("text", "json"),
+	"log-json-info-buffer-size": validateQuantity,
+	"log-json-split-stream":     validateBool,
+}
+
+// Paths returns the extendedArguments paths the alpha flags are observed into, sorted.
+func Paths() [][]string {
+	flags := make([]string, 0, len(allowedFlags))
+	for flag := range allowedFlags {
+		flags = append(flags, flag)
+	}
+	sort.Strings(flags)
+	paths := make([][]string, 0, len(flags))
+	for _, flag := range flags {
+		paths = append(paths, []string{"extendedArguments", flag})
+	}
+	return paths
+}
+
+// NewObserveAlphaFlagsFunc passes the alpha flags of the operator resource through to the extendedArguments when the
+// cluster runs the TechPreviewNoUpgrade feature set. An invalid flag keeps the previously observed ones.
+func NewObserveAlphaFlagsFunc(operatorClient v1helpers.OperatorClient) configobserver.ObserveConfigFunc {
+	return func(genericListers configobserver.Listers, recorder events.Recorder, existingConfig map[string]interface{}) (ret map[string]interface{}, errs []error) {
+		paths := Paths()
+		defer func() {
+			ret = configobserver.Pruned(ret, paths...)
+		}()
+		prevObservedConfig := configobserver.Pruned(existingConfig, paths...)
+
+		meta, err := operatorClient.GetObjectMeta()
+		if err != nil {
+			return prevObservedConfig, append(errs, err)
+		}
+		annotation := meta.Annotations[AnnotationName]
+
+		listers := genericListers.(configobservation.Listers)
+		featureGate, err := listers.FeatureGateLister().Get("cluster")
+		if err != nil && !errors.IsNotFound(err) {
+			return prevObservedConfig, append(errs, err)
+		}
+		observedConfig := map[string]interface{}{}
+		if featureGate == nil || featureGate.Spec.FeatureSet != configv1.TechPreviewNoUpgrade {
+			if len(annotation) > 0 {
+				recorder.Warningf("ObserveAlphaFlags", "The %s annotation is ignored outside of the %s feature set", AnnotationName, configv1.TechPreviewNoUpgrade)
+			}
+			return observedConfig, errs
+		}
+		if len(annotation) == 0 {
+			return observedConfig, errs
+		}
+
+		flags := map[string]string{}
+		if err := json.Unmarshal([]byte(annotation), &flags); err != nil {
+			return prevObservedConfig, append(errs, fmt.Errorf("%s: %v", AnnotationName, err))
+		}
+		for flag, value := range flags {
+			validate, ok := allowedFlags[flag]
+			if !ok {
+				errs = append(errs, fmt.Errorf("%s: %q is not an allowed alpha flag", AnnotationName, flag))
+				continue
+			}
+			if err := validate(value); err != nil {
+				errs = append(errs, fmt.Errorf("%s: invalid value %q of %q: %v", AnnotationName, value, flag, err))
+				continue
+			}
+			if err := unstructured.SetNestedStringSlice(observedConfig, []string{value}, "extendedArguments", flag); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if len(errs) > 0 {
+			return prevObservedConfig, errs
+		}
+
+		if !equality.Semantic.DeepEqual(prevObservedConfig, configobserver.Pruned(observedConfig, paths...)) {
+			recorder.Eventf("ObserveAlphaFlags", "alpha flags changed to %s", annotation)
+		}
+		return observedConfig, errs
+	}
+}
+
+func validateBool(value string) error {
+	_, err := strconv.ParseBool(value)
+	return err
+}
+
+func validateQuantity(value string) error {
+	_, err := resource.ParseQuantity(value)
+	return err
+}
+
+func validateOneOf(allowed ...string) func(string) error {
+	return func(value string) error {
+		for _, a := range allowed {
+			if value == a {
+				return nil
+			}
+		}
+		return fmt.Errorf("must be one of %s", strings.Join(allowed, ", "))
+	}
+}
@@ -0,0 +1,110 @@
+package alphaflags
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	configlistersv1 "github.com/openshift/client-go/config/listers/config/v1"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation"
+)
+
+func TestObserveAlphaFlags(t *testing.T) {
+	previous := map[string]interface{}{
+		"extendedArguments": map[string]interface{}{
+			"logging-format": []interface{}{"json"},
+			"cluster-name":   []interface{}{"cluster-1"},
+		},
+	}
+	tests := []struct {
+		name          string
+		featureSet    configv1.FeatureSet
+		annotation    string
+		expected      map[string]interface{}
+		expectedError bool
+	}{
+		{
+			name:       "tech preview",
+			featureSet: configv1.TechPreviewNoUpgrade,
+			annotation: `{"logging-format":"json","log-json-info-buffer-size":"64Ki"}`,
+			expected: map[string]interface{}{
+				"extendedArguments": map[string]interface{}{
+					"logging-format":            []interface{}{"json"},
+					"log-json-info-buffer-size": []interface{}{"64Ki"},
+				},
+			},
+		},
+		{
+			name:       "annotation removed",
+			featureSet: configv1.TechPreviewNoUpgrade,
+			expected:   map[string]interface{}{},
+		},
+		{
+			name:       "default feature set",
+			featureSet: configv1.Default,
+			annotation: `{"logging-format":"json"}`,
+			expected:   map[string]interface{}{},
+		},
+		{
+			name:          "flag not allowed",
+			featureSet:    configv1.TechPreviewNoUpgrade,
+			annotation:    `{"logging-format":"text","cluster-name":"other"}`,
+			expected:      map[string]interface{}{"extendedArguments": map[string]interface{}{"logging-format": []interface{}{"json"}}},
+			expectedError: true,
+		},
+		{
+			name:          "workload risking flag not allowed",
+			featureSet:    configv1.TechPreviewNoUpgrade,
+			annotation:    `{"disable-attach-detach-reconcile-sync":"true"}`,
+			expected:      map[string]interface{}{"extendedArguments": map[string]interface{}{"logging-format": []interface{}{"json"}}},
+			expectedError: true,
+		},
+		{
+			name:          "invalid value",
+			featureSet:    configv1.TechPreviewNoUpgrade,
+			annotation:    `{"logging-format":"yaml"}`,
+			expected:      map[string]interface{}{"extendedArguments": map[string]interface{}{"logging-format": []interface{}{"json"}}},
+			expectedError: true,
+		},
+		{
+			name:          "invalid annotation",
+			featureSet:    configv1.TechPreviewNoUpgrade,
+			annotation:    `logging-format=json`,
+			expected:      map[string]interface{}{"extendedArguments": map[string]interface{}{"logging-format": []interface{}{"json"}}},
+			expectedError: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if err := indexer.Add(&configv1.FeatureGate{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Spec:       configv1.FeatureGateSpec{FeatureGateSelection: configv1.FeatureGateSelection{FeatureSet: test.featureSet}},
+			}); err != nil {
+				t.Fatal(err)
+			}
+			listers := configobservation.Listers{FeatureGateLister_: configlistersv1.NewFeatureGateLister(indexer)}
+			operatorClient := v1helpers.NewFakeOperatorClientWithObjectMeta(
+				&metav1.ObjectMeta{Annotations: map[string]string{AnnotationName: test.annotation}},
+				&operatorv1.OperatorSpec{},
+				&operatorv1.OperatorStatus{},
+				nil,
+			)
+
+			result, errs := NewObserveAlphaFlagsFunc(operatorClient)(listers, events.NewInMemoryRecorder("test"), previous)
+			if test.expectedError != (len(errs) > 0) {
+				t.Errorf("expected error %v, got %v", test.expectedError, errs)
+			}
+			if !reflect.DeepEqual(test.expected, result) {
+				t.Errorf("expected %v, got %v", test.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package argumentrequests
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openshift/library-go/pkg/operator/configobserver"
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/argumentrequestcontroller"
+)
+
+// NewObserveArgumentRequestsFunc applies the flags other operators request with KubeControllerManagerArgumentRequests
+// to the extendedArguments. A flag the requests disagree on keeps its previously observed value, so that a conflicting
+// request does not roll out a revision, the ArgumentRequestController reports the conflict.
+func NewObserveArgumentRequestsFunc(lister cache.GenericLister) configobserver.ObserveConfigFunc {
+	return func(genericListers configobserver.Listers, recorder events.Recorder, existingConfig map[string]interface{}) (ret map[string]interface{}, errs []error) {
+		paths := argumentrequestcontroller.Paths()
+		defer func() {
+			ret = configobserver.Pruned(ret, paths...)
+		}()
+		prevObservedConfig := configobserver.Pruned(existingConfig, paths...)
+
+		objs, err := lister.List(labels.Everything())
+		if err != nil {
+			return prevObservedConfig, append(errs, err)
+		}
+		requests := make([]*unstructured.Unstructured, 0, len(objs))
+		for _, obj := range objs {
+			requests = append(requests, obj.(*unstructured.Unstructured))
+		}
+		arbitration := argumentrequestcontroller.Arbitrate(requests)
+
+		observedConfig := map[string]interface{}{}
+		for flag, value := range arbitration.Arguments {
+			if err := unstructured.SetNestedStringSlice(observedConfig, []string{value}, "extendedArguments", flag); err != nil {
+				return prevObservedConfig, append(errs, err)
+			}
+		}
+		for flag := range arbitration.Conflicts {
+			previous, found, err := unstructured.NestedStringSlice(prevObservedConfig, "extendedArguments", flag)
+			if err != nil {
+				return prevObservedConfig, append(errs, err)
+			}
+			if !found {
+				continue
+			}
+			if err := unstructured.SetNestedStringSlice(observedConfig, previous, "extendedArguments", flag); err != nil {
+				return prevObservedConfig, append(errs, err)
+			}
+		}
+
+		if !equality.Semantic.DeepEqual(prevObservedConfig, configobserver.Pruned(observedConfig, paths...)) {
+			recorder.Eventf("ObserveArgumentRequests", "requested flags changed to %s", describe(observedConfig))
+		}
+		return observedConfig, errs
+	}
+}
+
+// describe lists the requested flags of the observed config, sorted.
+func describe(observedConfig map[string]interface{}) string {
+	arguments, _, _ := unstructured.NestedMap(observedConfig, "extendedArguments")
+	if len(arguments) == 0 {
+		return "none"
+	}
+	flags := []string{}
+	for flag, values := range arguments {
+		flags = append(flags, fmt.Sprintf("%s=%v", flag, values))
+	}
+	sort.Strings(flags)
+	return strings.Join(flags, ", ")
+}
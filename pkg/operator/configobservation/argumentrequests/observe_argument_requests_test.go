@@ -0,0 +1,91 @@
+package argumentrequests
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/argumentrequestcontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation"
+)
+
+func TestObserveArgumentRequests(t *testing.T) {
+	previous := map[string]interface{}{
+		"extendedArguments": map[string]interface{}{
+			"concurrent-gc-syncs": []interface{}{"30"},
+			"cluster-name":        []interface{}{"cluster-1"},
+		},
+	}
+	tests := []struct {
+		name     string
+		requests map[string]map[string]string
+		expected map[string]interface{}
+	}{
+		{
+			name: "agreeing requests",
+			requests: map[string]map[string]string{
+				"a": {"concurrent-gc-syncs": "40", "concurrent-deployment-syncs": "10"},
+				"b": {"concurrent-gc-syncs": "40"},
+			},
+			expected: map[string]interface{}{
+				"extendedArguments": map[string]interface{}{
+					"concurrent-gc-syncs":         []interface{}{"40"},
+					"concurrent-deployment-syncs": []interface{}{"10"},
+				},
+			},
+		},
+		{
+			name: "conflicting requests keep the previous value",
+			requests: map[string]map[string]string{
+				"a": {"concurrent-gc-syncs": "40"},
+				"b": {"concurrent-gc-syncs": "50", "concurrent-deployment-syncs": "10"},
+			},
+			expected: map[string]interface{}{
+				"extendedArguments": map[string]interface{}{
+					"concurrent-gc-syncs":         []interface{}{"30"},
+					"concurrent-deployment-syncs": []interface{}{"10"},
+				},
+			},
+		},
+		{
+			name: "rejected request",
+			requests: map[string]map[string]string{
+				"a": {"concurrent-gc-syncs": "40", "cluster-name": "other"},
+			},
+			expected: map[string]interface{}{},
+		},
+		{
+			name:     "requests removed",
+			expected: map[string]interface{}{},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			for name, arguments := range tc.requests {
+				request := &unstructured.Unstructured{}
+				request.SetNamespace("openshift-" + name)
+				request.SetName(name)
+				if err := unstructured.SetNestedStringMap(request.Object, arguments, "spec", "arguments"); err != nil {
+					t.Fatal(err)
+				}
+				if err := indexer.Add(request); err != nil {
+					t.Fatal(err)
+				}
+			}
+			observe := NewObserveArgumentRequestsFunc(cache.NewGenericLister(indexer, argumentrequestcontroller.ArgumentRequestGVR.GroupResource()))
+
+			observed, errs := observe(configobservation.Listers{}, events.NewInMemoryRecorder("test"), previous)
+			if len(errs) > 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			if !reflect.DeepEqual(observed, tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, observed)
+			}
+		})
+	}
+}
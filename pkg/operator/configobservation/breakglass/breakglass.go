@@ -0,0 +1,219 @@
+package breakglass
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/component-base/metrics"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/configobserver"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operationprofile"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatormetrics"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
+)
+
+// AnnotationName on the kubecontrollermanager resource freezes the observed config at its current value, for
+// incidents where an upstream config object is being corrupted. It holds a JSON object with the time the freeze
+// expires and the reason for it:
+//
+//	kube-controller-manager.openshift.io/freeze-observed-config: '{"until":"2026-10-16T08:00:00Z","reason":"INC-1234 network config corrupted"}'
+//
+// The freeze ends on its own at the given time, which may be at most MaxFreeze ahead.
+const AnnotationName = "kube-controller-manager.openshift.io/freeze-observed-config"
+
+// MaxFreeze bounds how far ahead a freeze may expire, a forgotten freeze must not hold back config changes for long.
+const MaxFreeze = 24 * time.Hour
+
+const conditionType = "ObservedConfigFrozen"
+
+var (
+	frozen = metrics.NewGauge(
+		&metrics.GaugeOpts{
+			Name:           "kcm_operator_observed_config_frozen",
+			Help:           "1 while the observed config is frozen by the break-glass annotation, 0 otherwise.",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+	frozenDrift = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Name:           "kcm_operator_observed_config_frozen_drift",
+			Help:           "1 for the config observers which would change the observed config if it was not frozen.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"observer"},
+	)
+)
+
+func init() {
+	operatormetrics.MustRegister(frozen, frozenDrift)
+}
+
+type freeze struct {
+	Until  time.Time `json:"until"`
+	Reason string    `json:"reason"`
+}
+
+// Freezer holds the config observers back while the break-glass annotation is active, and remembers what they would
+// have observed.
+type Freezer struct {
+	operatorClient v1helpers.OperatorClient
+	now            func() time.Time
+
+	lock sync.Mutex
+	// drift maps the observers to the observed config they would have written while frozen
+	drift map[string]map[string]interface{}
+}
+
+func NewFreezer(operatorClient v1helpers.OperatorClient) *Freezer {
+	return &Freezer{
+		operatorClient: operatorClient,
+		now:            time.Now,
+		drift:          map[string]map[string]interface{}{},
+	}
+}
+
+// current returns the freeze of the annotation, nil when there is none. It may have expired already.
+func (f *Freezer) current() (*freeze, error) {
+	meta, err := f.operatorClient.GetObjectMeta()
+	if err != nil {
+		return nil, err
+	}
+	annotation, found := meta.Annotations[AnnotationName]
+	if !found {
+		return nil, nil
+	}
+	ret := &freeze{}
+	if err := json.Unmarshal([]byte(annotation), ret); err != nil {
+		return nil, fmt.Errorf("%s: %v", AnnotationName, err)
+	}
+	if len(strings.TrimSpace(ret.Reason)) == 0 {
+		return nil, fmt.Errorf("%s: a reason is required", AnnotationName)
+	}
+	if ret.Until.After(f.now().Add(MaxFreeze)) {
+		return nil, fmt.Errorf("%s: expires at %s, more than %s ahead", AnnotationName, ret.Until.UTC().Format(time.RFC3339), MaxFreeze)
+	}
+	return ret, nil
+}
+
+// active returns the freeze of the annotation, nil when there is none or it expired.
+func (f *Freezer) active() (*freeze, error) {
+	current, err := f.current()
+	if err != nil || current == nil || !current.Until.After(f.now()) {
+		return nil, err
+	}
+	return current, nil
+}
+
+// Freeze wraps the observer to keep the given paths at their existing value while the observed config is frozen. The
+// observer still runs, so that its errors are reported and what it would change is known.
+func (f *Freezer) Freeze(name string, observer configobserver.ObserveConfigFunc, paths ...[]string) configobserver.ObserveConfigFunc {
+	return func(listers configobserver.Listers, recorder events.Recorder, existingConfig map[string]interface{}) (map[string]interface{}, []error) {
+		observedConfig, errs := observer(listers, recorder, existingConfig)
+		// an invalid annotation does not freeze, it is reported by the condition
+		active, _ := f.active()
+
+		f.lock.Lock()
+		defer f.lock.Unlock()
+		if active == nil {
+			delete(f.drift, name)
+			frozenDrift.WithLabelValues(name).Set(0)
+			return observedConfig, errs
+		}
+
+		existing := configobserver.Pruned(existingConfig, paths...)
+		if equality.Semantic.DeepEqual(existing, configobserver.Pruned(observedConfig, paths...)) {
+			delete(f.drift, name)
+			frozenDrift.WithLabelValues(name).Set(0)
+			return existing, errs
+		}
+		if previous, found := f.drift[name]; !found || !equality.Semantic.DeepEqual(previous, observedConfig) {
+			held, _ := json.Marshal(observedConfig)
+			recorder.Warningf("ObservedConfigFrozen", "The observed config of %s is frozen until %s (%s), holding back %s", name, active.Until.UTC().Format(time.RFC3339), active.Reason, held)
+		}
+		f.drift[name] = observedConfig
+		frozenDrift.WithLabelValues(name).Set(1)
+		return existing, errs
+	}
+}
+
+// driftingObservers returns the observers which are held back, sorted.
+func (f *Freezer) driftingObservers() []string {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	ret := make([]string, 0, len(f.drift))
+	for name := range f.drift {
+		ret = append(ret, name)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+type breakGlassController struct {
+	freezer       *Freezer
+	statusUpdater statusbatcher.StatusUpdater
+	// wasFrozen keeps whether the last sync found the freeze active, to report its start and end once
+	wasFrozen bool
+}
+
+// NewBreakGlassController reports the freeze of the observed config in the ObservedConfigFrozen condition, and the
+// observers which are held back by it.
+func NewBreakGlassController(
+	freezer *Freezer,
+	statusUpdater statusbatcher.StatusUpdater,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &breakGlassController{
+		freezer:       freezer,
+		statusUpdater: statusUpdater,
+	}
+	return factory.New().WithInformers(freezer.operatorClient.Informer()).ResyncEvery(operationprofile.Resync(time.Minute)).WithSync(syncmetrics.Timed("BreakGlassController", c.sync)).ToController("BreakGlassController", eventRecorder)
+}
+
+func (c *breakGlassController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	condition := operatorv1.OperatorCondition{
+		Type:   conditionType,
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+	current, err := c.freezer.current()
+	var active *freeze
+	switch {
+	case err != nil:
+		condition.Reason = "InvalidFreeze"
+		condition.Message = fmt.Sprintf("the observed config is not frozen: %v", err)
+	case current != nil && !current.Until.After(c.freezer.now()):
+		condition.Reason = "FreezeExpired"
+		condition.Message = fmt.Sprintf("the freeze of the observed config expired at %s, remove the %s annotation", current.Until.UTC().Format(time.RFC3339), AnnotationName)
+	case current != nil:
+		active = current
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "BreakGlass"
+		condition.Message = fmt.Sprintf("the observed config is frozen until %s: %s", active.Until.UTC().Format(time.RFC3339), active.Reason)
+		if drifting := c.freezer.driftingObservers(); len(drifting) > 0 {
+			condition.Message += fmt.Sprintf(". Held back changes of: %s", strings.Join(drifting, ", "))
+		}
+	}
+
+	switch {
+	case active != nil && !c.wasFrozen:
+		frozen.Set(1)
+		syncCtx.Recorder().Warningf("ObservedConfigFrozen", "The observed config is frozen until %s: %s", active.Until.UTC().Format(time.RFC3339), active.Reason)
+	case active == nil && c.wasFrozen:
+		frozen.Set(0)
+		syncCtx.Recorder().Warningf("ObservedConfigUnfrozen", "The observed config is no longer frozen, held back changes are applied")
+	}
+	c.wasFrozen = active != nil
+	return c.statusUpdater.UpdateStatus(ctx, v1helpers.UpdateConditionFn(condition))
+}
@@ -0,0 +1,108 @@
+package breakglass
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/configobserver"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+)
+
+func TestFreeze(t *testing.T) {
+	now := time.Date(2026, 10, 15, 10, 0, 0, 0, time.UTC)
+	existing := map[string]interface{}{"extendedArguments": map[string]interface{}{"cluster-cidr": []interface{}{"10.128.0.0/14"}}}
+	corrupted := map[string]interface{}{"extendedArguments": map[string]interface{}{"cluster-cidr": []interface{}{"0.0.0.0/0"}}}
+	observer := func(configobserver.Listers, events.Recorder, map[string]interface{}) (map[string]interface{}, []error) {
+		return corrupted, nil
+	}
+
+	testCases := []struct {
+		name            string
+		annotation      string
+		expectedConfig  map[string]interface{}
+		expectedStatus  operatorv1.ConditionStatus
+		expectedReason  string
+		expectedMessage string
+	}{
+		{
+			name:           "no freeze",
+			expectedConfig: corrupted,
+			expectedStatus: operatorv1.ConditionFalse,
+			expectedReason: "AsExpected",
+		},
+		{
+			name:            "frozen",
+			annotation:      `{"until":"2026-10-15T12:00:00Z","reason":"INC-1234 network config corrupted"}`,
+			expectedConfig:  existing,
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedReason:  "BreakGlass",
+			expectedMessage: "the observed config is frozen until 2026-10-15T12:00:00Z: INC-1234 network config corrupted. Held back changes of: clustercidrs",
+		},
+		{
+			name:            "expired",
+			annotation:      `{"until":"2026-10-15T09:00:00Z","reason":"INC-1234 network config corrupted"}`,
+			expectedConfig:  corrupted,
+			expectedStatus:  operatorv1.ConditionFalse,
+			expectedReason:  "FreezeExpired",
+			expectedMessage: "the freeze of the observed config expired at 2026-10-15T09:00:00Z, remove the kube-controller-manager.openshift.io/freeze-observed-config annotation",
+		},
+		{
+			name:            "too long",
+			annotation:      `{"until":"2026-10-20T10:00:00Z","reason":"INC-1234 network config corrupted"}`,
+			expectedConfig:  corrupted,
+			expectedStatus:  operatorv1.ConditionFalse,
+			expectedReason:  "InvalidFreeze",
+			expectedMessage: "the observed config is not frozen: kube-controller-manager.openshift.io/freeze-observed-config: expires at 2026-10-20T10:00:00Z, more than 24h0m0s ahead",
+		},
+		{
+			name:            "no reason",
+			annotation:      `{"until":"2026-10-15T12:00:00Z"}`,
+			expectedConfig:  corrupted,
+			expectedStatus:  operatorv1.ConditionFalse,
+			expectedReason:  "InvalidFreeze",
+			expectedMessage: "the observed config is not frozen: kube-controller-manager.openshift.io/freeze-observed-config: a reason is required",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			meta := &metav1.ObjectMeta{Name: "cluster"}
+			if len(tc.annotation) > 0 {
+				meta.Annotations = map[string]string{AnnotationName: tc.annotation}
+			}
+			operatorClient := v1helpers.NewFakeOperatorClientWithObjectMeta(meta, &operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil)
+			freezer := NewFreezer(operatorClient)
+			freezer.now = func() time.Time { return now }
+			recorder := events.NewInMemoryRecorder("test")
+
+			observed, errs := freezer.Freeze("clustercidrs", observer, []string{"extendedArguments", "cluster-cidr"})(nil, recorder, existing)
+			if len(errs) > 0 {
+				t.Fatal(errs)
+			}
+			if !reflect.DeepEqual(observed, tc.expectedConfig) {
+				t.Errorf("expected %v, got %v", tc.expectedConfig, observed)
+			}
+
+			c := &breakGlassController{freezer: freezer, statusUpdater: statusbatcher.NewDirectUpdater(operatorClient)}
+			if err := c.sync(context.TODO(), factory.NewSyncContext("test", recorder)); err != nil {
+				t.Fatal(err)
+			}
+			_, status, _, _ := operatorClient.GetOperatorState()
+			condition := v1helpers.FindOperatorCondition(status.Conditions, conditionType)
+			if condition == nil {
+				t.Fatalf("missing condition %s", conditionType)
+			}
+			if condition.Status != tc.expectedStatus || condition.Reason != tc.expectedReason || condition.Message != tc.expectedMessage {
+				t.Errorf("expected %s %s %q, got %s %s %q", tc.expectedStatus, tc.expectedReason, tc.expectedMessage, condition.Status, condition.Reason, condition.Message)
+			}
+		})
+	}
+}
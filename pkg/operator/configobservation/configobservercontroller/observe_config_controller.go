@@ -1,7 +1,10 @@
 package configobservercontroller
 
 import (
+	"time"
+
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/informers"
 	"k8s.io/client-go/tools/cache"
 
 	configinformers "github.com/openshift/client-go/config/informers/externalversions"
@@ -16,12 +19,21 @@ import (
 	"github.com/openshift/library-go/pkg/operator/resourcesynccontroller"
 	"github.com/openshift/library-go/pkg/operator/v1helpers"
 
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/argumentrequestcontroller"
 	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation/alphaflags"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation/argumentrequests"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation/breakglass"
 	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation/cloud"
 	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation/clustername"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation/contenttype"
 	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation/network"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation/observerhealth"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation/ownership"
 	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation/serviceca"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/decisionlog"
 	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
 )
 
 // openShiftOnlyFeatureGates are feature gate names that are only used within
@@ -40,9 +52,20 @@ func NewConfigObserver(
 	operatorClient v1helpers.OperatorClient,
 	configinformers configinformers.SharedInformerFactory,
 	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	argumentRequestInformer informers.GenericInformer,
 	resourceSyncer resourcesynccontroller.ResourceSyncer,
+	observerTracker *observerhealth.Tracker,
+	freezer *breakglass.Freezer,
+	configOwnership *ownership.Ownership,
+	decisionLog *decisionlog.Log,
+	resyncInterval time.Duration,
 	eventRecorder events.Recorder,
 ) *ConfigObserver {
+	// every path of the observed config is written by a single observer, the break-glass freeze holds the paths
+	// back before the decision log records them
+	observe := func(name string, observer configobserver.ObserveConfigFunc, paths ...[]string) configobserver.ObserveConfigFunc {
+		return observerTracker.Track(name, decisionLog.WrapObserver(name, freezer.Freeze(name, configOwnership.Own(name, observer, paths...), paths...)))
+	}
 
 	interestingNamespaces := []string{
 		operatorclient.GlobalUserSpecifiedConfigNamespace,
@@ -61,55 +84,80 @@ func NewConfigObserver(
 		configinformers.Config().V1().Infrastructures().Informer(),
 		configinformers.Config().V1().Networks().Informer(),
 		configinformers.Config().V1().Proxies().Informer(),
+		argumentRequestInformer.Informer(),
 	}
 	for _, ns := range interestingNamespaces {
 		informers = append(informers, kubeInformersForNamespaces.InformersFor(ns).Core().V1().ConfigMaps().Informer())
 	}
 
-	c := &ConfigObserver{
-		Controller: configobserver.NewConfigObserver(
-			operatorClient,
-			eventRecorder,
-			configobservation.Listers{
-				FeatureGateLister_:    configinformers.Config().V1().FeatureGates().Lister(),
-				InfrastructureLister_: configinformers.Config().V1().Infrastructures().Lister(),
-				NetworkLister:         configinformers.Config().V1().Networks().Lister(),
-				ProxyLister_:          configinformers.Config().V1().Proxies().Lister(),
-				APIServerLister_:      configinformers.Config().V1().APIServers().Lister(),
-
-				ResourceSync:     resourceSyncer,
-				ConfigMapLister_: kubeInformersForNamespaces.ConfigMapLister(),
-				PreRunCachesSynced: append(configMapPreRunCacheSynced,
-					operatorClient.Informer().HasSynced,
-
-					kubeInformersForNamespaces.InformersFor(operatorclient.GlobalUserSpecifiedConfigNamespace).Core().V1().ConfigMaps().Informer().HasSynced,
-					kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().ConfigMaps().Informer().HasSynced,
-
-					configinformers.Config().V1().FeatureGates().Informer().HasSynced,
-					configinformers.Config().V1().Infrastructures().Informer().HasSynced,
-					configinformers.Config().V1().Networks().Informer().HasSynced,
-					configinformers.Config().V1().Proxies().Informer().HasSynced,
-				),
-			},
-			informers,
-			cloudprovider.NewCloudProviderObserver(
-				"openshift-kube-controller-manager",
-				[]string{"extendedArguments", "cloud-provider"},
-				[]string{"extendedArguments", "cloud-config"}),
-			featuregates.NewObserveFeatureFlagsFunc(
-				nil,
-				openShiftOnlyFeatureGates,
-				[]string{"extendedArguments", "feature-gates"},
+	observer := configobserver.NewConfigObserver(
+		operatorClient,
+		eventRecorder,
+		configobservation.Listers{
+			FeatureGateLister_:    configinformers.Config().V1().FeatureGates().Lister(),
+			InfrastructureLister_: configinformers.Config().V1().Infrastructures().Lister(),
+			NetworkLister:         configinformers.Config().V1().Networks().Lister(),
+			ProxyLister_:          configinformers.Config().V1().Proxies().Lister(),
+			APIServerLister_:      configinformers.Config().V1().APIServers().Lister(),
+
+			ResourceSync:     resourceSyncer,
+			ConfigMapLister_: kubeInformersForNamespaces.ConfigMapLister(),
+			PreRunCachesSynced: append(configMapPreRunCacheSynced,
+				operatorClient.Informer().HasSynced,
+
+				kubeInformersForNamespaces.InformersFor(operatorclient.GlobalUserSpecifiedConfigNamespace).Core().V1().ConfigMaps().Informer().HasSynced,
+				kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().ConfigMaps().Informer().HasSynced,
+
+				configinformers.Config().V1().FeatureGates().Informer().HasSynced,
+				configinformers.Config().V1().Infrastructures().Informer().HasSynced,
+				configinformers.Config().V1().Networks().Informer().HasSynced,
+				configinformers.Config().V1().Proxies().Informer().HasSynced,
+				argumentRequestInformer.Informer().HasSynced,
 			),
-			network.ObserveClusterCIDRs,
-			network.ObserveServiceClusterIPRanges,
-			proxy.NewProxyObserveFunc([]string{"targetconfigcontroller", "proxy"}),
-			serviceca.ObserveServiceCA,
-			clustername.ObserveInfraID,
-			libgoapiserver.ObserveTLSSecurityProfile,
-			cloud.ObserveCloudVolumePlugin,
-		),
+		},
+		informers,
+		Observers(operatorClient, argumentRequestInformer.Lister(), observe)...,
+	)
+
+	// the observation runs in its own loop, so that its resync interval can be shortened independently of the
+	// status controllers to bring config changes into revisions sooner
+	return &ConfigObserver{
+		Controller: factory.New().
+			WithInformers(informers...).
+			ResyncEvery(resyncInterval).
+			WithSync(syncmetrics.Timed("ConfigObserver", observer.Sync)).
+			ToController("ConfigObserver", eventRecorder.WithComponentSuffix("config-observer")),
 	}
+}
 
-	return c
+// ObserveFunc wraps an observer with the paths of the observed config it owns.
+type ObserveFunc func(name string, observer configobserver.ObserveConfigFunc, paths ...[]string) configobserver.ObserveConfigFunc
+
+// Observers returns the config observers of the operator wrapped by observe, the replay-observation command runs them
+// outside of the cluster.
+func Observers(operatorClient v1helpers.OperatorClient, argumentRequestLister cache.GenericLister, observe ObserveFunc) []configobserver.ObserveConfigFunc {
+	return []configobserver.ObserveConfigFunc{
+		observe("cloudprovider", cloudprovider.NewCloudProviderObserver(
+			"openshift-kube-controller-manager",
+			[]string{"extendedArguments", "cloud-provider"},
+			[]string{"extendedArguments", "cloud-config"}),
+			[]string{"extendedArguments", "cloud-provider"},
+			[]string{"extendedArguments", "cloud-config"}),
+		observe("featuregates", featuregates.NewObserveFeatureFlagsFunc(
+			nil,
+			openShiftOnlyFeatureGates,
+			[]string{"extendedArguments", "feature-gates"},
+		), []string{"extendedArguments", "feature-gates"}),
+		observe("clustercidrs", network.ObserveClusterCIDRs, []string{"extendedArguments", "cluster-cidr"}),
+		observe("serviceclusteripranges", network.ObserveServiceClusterIPRanges, []string{"extendedArguments", "service-cluster-ip-range"}),
+		observe("nodecidrallocation", network.NewObserveNodeCIDRAllocationFunc(operatorClient), network.AllocateNodeCIDRsPath, network.ConfigureCloudRoutesPath),
+		observe("proxy", proxy.NewProxyObserveFunc([]string{"targetconfigcontroller", "proxy"}), []string{"targetconfigcontroller", "proxy"}),
+		observe("serviceca", serviceca.ObserveServiceCA, []string{"serviceServingCert", "certFile"}),
+		observe("infraid", clustername.ObserveInfraID, []string{"extendedArguments", "cluster-name"}),
+		observe("tlssecurityprofile", libgoapiserver.ObserveTLSSecurityProfile, []string{"servingInfo", "minTLSVersion"}, []string{"servingInfo", "cipherSuites"}),
+		observe("cloudvolumeplugin", cloud.ObserveCloudVolumePlugin, []string{"extendedArguments", "external-cloud-volume-plugin"}),
+		observe("alphaflags", alphaflags.NewObserveAlphaFlagsFunc(operatorClient), alphaflags.Paths()...),
+		observe("argumentrequests", argumentrequests.NewObserveArgumentRequestsFunc(argumentRequestLister), argumentrequestcontroller.Paths()...),
+		observe("kubeapicontenttype", contenttype.NewObserveKubeAPIContentTypeFunc(operatorClient), contenttype.Path),
+	}
 }
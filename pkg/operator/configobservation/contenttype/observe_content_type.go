@@ -0,0 +1,68 @@
+package contenttype
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/openshift/library-go/pkg/operator/configobserver"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+const (
+	// AnnotationName on the kubecontrollermanager resource selects the encoding kube-controller-manager talks to
+	// kube-apiserver with, protobuf or json. It is meant for debugging only, JSON costs kube-apiserver noticeably
+	// more CPU on large clusters.
+	AnnotationName = "kube-controller-manager.openshift.io/kube-api-content-type"
+
+	// Protobuf is the content type kube-controller-manager uses unless told otherwise.
+	Protobuf = "application/vnd.kubernetes.protobuf"
+	// JSON is the content type kube-controller-manager falls back to.
+	JSON = "application/json"
+)
+
+// Path is the extendedArguments path the content type is observed into.
+var Path = []string{"extendedArguments", "kube-api-content-type"}
+
+var contentTypes = map[string]string{
+	"":         Protobuf,
+	"protobuf": Protobuf,
+	"json":     JSON,
+}
+
+// NewObserveKubeAPIContentTypeFunc sets the content type of the requests of kube-controller-manager explicitly, to
+// protobuf unless the annotation of the operator resource asks for JSON. An invalid annotation keeps the previously
+// observed content type.
+func NewObserveKubeAPIContentTypeFunc(operatorClient v1helpers.OperatorClient) configobserver.ObserveConfigFunc {
+	return func(genericListers configobserver.Listers, recorder events.Recorder, existingConfig map[string]interface{}) (ret map[string]interface{}, errs []error) {
+		defer func() {
+			ret = configobserver.Pruned(ret, Path)
+		}()
+		prevObservedConfig := configobserver.Pruned(existingConfig, Path)
+
+		meta, err := operatorClient.GetObjectMeta()
+		if err != nil {
+			return prevObservedConfig, append(errs, err)
+		}
+		annotation := meta.Annotations[AnnotationName]
+		contentType, ok := contentTypes[annotation]
+		if !ok {
+			return prevObservedConfig, append(errs, fmt.Errorf("%s: invalid value %q, must be protobuf or json", AnnotationName, annotation))
+		}
+
+		observedConfig := map[string]interface{}{}
+		if err := unstructured.SetNestedStringSlice(observedConfig, []string{contentType}, Path...); err != nil {
+			return prevObservedConfig, append(errs, err)
+		}
+		if !equality.Semantic.DeepEqual(prevObservedConfig, observedConfig) {
+			if contentType == JSON {
+				recorder.Warningf("ObserveKubeAPIContentType", "kube-api-content-type changed to %s as requested by the %s annotation", contentType, AnnotationName)
+			} else {
+				recorder.Eventf("ObserveKubeAPIContentType", "kube-api-content-type changed to %s", contentType)
+			}
+		}
+		return observedConfig, errs
+	}
+}
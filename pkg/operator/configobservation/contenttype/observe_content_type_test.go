@@ -0,0 +1,81 @@
+package contenttype
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation"
+)
+
+func TestObserveKubeAPIContentType(t *testing.T) {
+	previous := map[string]interface{}{
+		"extendedArguments": map[string]interface{}{
+			"kube-api-content-type": []interface{}{Protobuf},
+			"cluster-name":          []interface{}{"cluster-1"},
+		},
+	}
+	tests := []struct {
+		name          string
+		annotation    string
+		expected      map[string]interface{}
+		expectedEvent string
+		expectedError bool
+	}{
+		{
+			name:     "default",
+			expected: map[string]interface{}{"extendedArguments": map[string]interface{}{"kube-api-content-type": []interface{}{Protobuf}}},
+		},
+		{
+			name:       "protobuf",
+			annotation: "protobuf",
+			expected:   map[string]interface{}{"extendedArguments": map[string]interface{}{"kube-api-content-type": []interface{}{Protobuf}}},
+		},
+		{
+			name:          "json",
+			annotation:    "json",
+			expected:      map[string]interface{}{"extendedArguments": map[string]interface{}{"kube-api-content-type": []interface{}{JSON}}},
+			expectedEvent: "ObserveKubeAPIContentType",
+		},
+		{
+			name:          "invalid annotation",
+			annotation:    "yaml",
+			expected:      map[string]interface{}{"extendedArguments": map[string]interface{}{"kube-api-content-type": []interface{}{Protobuf}}},
+			expectedError: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			operatorClient := v1helpers.NewFakeOperatorClientWithObjectMeta(
+				&metav1.ObjectMeta{Annotations: map[string]string{AnnotationName: test.annotation}},
+				&operatorv1.OperatorSpec{},
+				&operatorv1.OperatorStatus{},
+				nil,
+			)
+			recorder := events.NewInMemoryRecorder("test")
+
+			result, errs := NewObserveKubeAPIContentTypeFunc(operatorClient)(configobservation.Listers{}, recorder, previous)
+			if test.expectedError != (len(errs) > 0) {
+				t.Errorf("expected error %v, got %v", test.expectedError, errs)
+			}
+			if !reflect.DeepEqual(test.expected, result) {
+				t.Errorf("expected %v, got %v", test.expected, result)
+			}
+			reasons := []string{}
+			for _, event := range recorder.Events() {
+				reasons = append(reasons, event.Reason)
+			}
+			if len(test.expectedEvent) > 0 && (len(reasons) != 1 || reasons[0] != test.expectedEvent) {
+				t.Errorf("expected a %s event, got %v", test.expectedEvent, reasons)
+			}
+			if len(test.expectedEvent) == 0 && len(reasons) > 0 {
+				t.Errorf("expected no events, got %v", reasons)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	configinformers "github.com/openshift/client-go/config/informers/externalversions"
+	configlistersv1 "github.com/openshift/client-go/config/listers/config/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operationprofile"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
+)
+
+const networkConfigMismatchConditionType = "NetworkConfigMismatch"
+
+var (
+	clusterCIDRPath           = []string{"extendedArguments", "cluster-cidr"}
+	serviceClusterIPRangePath = []string{"extendedArguments", "service-cluster-ip-range"}
+)
+
+// NetworkConfigMismatchController compares the cluster-cidr and service-cluster-ip-range of the observed config with
+// the cluster and service networks the network operator applied, as published in the status of the network config.
+// The observed config keeps its previous values while the observers fail or are held back, after a network
+// migration the IPAM controllers of kube-controller-manager would then allocate from the stale ranges.
+type NetworkConfigMismatchController struct {
+	operatorClient v1helpers.OperatorClient
+	statusUpdater  statusbatcher.StatusUpdater
+	networkLister  configlistersv1.NetworkLister
+}
+
+func NewNetworkConfigMismatchController(
+	operatorClient v1helpers.OperatorClient,
+	statusUpdater statusbatcher.StatusUpdater,
+	configInformers configinformers.SharedInformerFactory,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &NetworkConfigMismatchController{
+		operatorClient: operatorClient,
+		statusUpdater:  statusUpdater,
+		networkLister:  configInformers.Config().V1().Networks().Lister(),
+	}
+
+	return factory.New().WithInformers(
+		operatorClient.Informer(),
+		configInformers.Config().V1().Networks().Informer(),
+	).ResyncEvery(operationprofile.Resync(5*time.Minute)).WithSync(syncmetrics.Timed("NetworkConfigMismatchController", c.sync)).ToController("NetworkConfigMismatchController", eventRecorder)
+}
+
+func (c *NetworkConfigMismatchController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	condition := operatorv1.OperatorCondition{
+		Type:   networkConfigMismatchConditionType,
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+
+	network, err := c.networkLister.Get("cluster")
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	if network == nil || len(network.Status.ClusterNetwork) == 0 || len(network.Status.ServiceNetwork) == 0 {
+		condition.Status = operatorv1.ConditionUnknown
+		condition.Reason = "NetworkNotApplied"
+		condition.Message = "the network operator did not publish the cluster and service networks yet"
+		return c.statusUpdater.UpdateStatus(ctx, v1helpers.UpdateConditionFn(condition))
+	}
+
+	spec, _, _, err := c.operatorClient.GetOperatorState()
+	if err != nil {
+		return err
+	}
+	observedConfig := map[string]interface{}{}
+	if len(spec.ObservedConfig.Raw) > 0 {
+		if err := json.Unmarshal(spec.ObservedConfig.Raw, &observedConfig); err != nil {
+			return fmt.Errorf("invalid observed config: %v", err)
+		}
+	}
+	observedClusterCIDRs, _, _ := unstructured.NestedStringSlice(observedConfig, clusterCIDRPath...)
+	observedServiceClusterIPRanges, _, _ := unstructured.NestedStringSlice(observedConfig, serviceClusterIPRangePath...)
+
+	appliedClusterCIDRs := []string{}
+	for _, entry := range network.Status.ClusterNetwork {
+		appliedClusterCIDRs = append(appliedClusterCIDRs, entry.CIDR)
+	}
+
+	mismatches := []string{}
+	if mismatch := cidrMismatch("cluster-cidr", observedClusterCIDRs, appliedClusterCIDRs); len(mismatch) > 0 {
+		mismatches = append(mismatches, mismatch)
+	}
+	if mismatch := cidrMismatch("service-cluster-ip-range", observedServiceClusterIPRanges, network.Status.ServiceNetwork); len(mismatch) > 0 {
+		mismatches = append(mismatches, mismatch)
+	}
+	if len(mismatches) > 0 {
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "NetworkConfigMismatch"
+		condition.Message = strings.Join(mismatches, "; ")
+		if network.Status.Migration != nil {
+			condition.Message += "; a network migration is in progress"
+		}
+	}
+	return c.statusUpdater.UpdateStatus(ctx, v1helpers.UpdateConditionFn(condition))
+}
+
+// cidrMismatch describes the mismatch of the observed and applied CIDRs of an argument, nothing when they match.
+// service-cluster-ip-range holds its CIDRs comma separated in a single value, the order does not matter.
+func cidrMismatch(argument string, observed, applied []string) string {
+	observedCIDRs := sets.NewString()
+	for _, value := range observed {
+		for _, cidr := range strings.Split(value, ",") {
+			if cidr = strings.TrimSpace(cidr); len(cidr) > 0 {
+				observedCIDRs.Insert(cidr)
+			}
+		}
+	}
+	appliedCIDRs := sets.NewString(applied...)
+	if observedCIDRs.Equal(appliedCIDRs) {
+		return ""
+	}
+	return fmt.Sprintf("%s is %s in the observed config, the network operator applied %s", argument, formatCIDRs(observedCIDRs.List()), formatCIDRs(appliedCIDRs.List()))
+}
+
+func formatCIDRs(cidrs []string) string {
+	if len(cidrs) == 0 {
+		return "<none>"
+	}
+	return strings.Join(cidrs, ",")
+}
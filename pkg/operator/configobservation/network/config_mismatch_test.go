@@ -0,0 +1,97 @@
+package network
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	configlistersv1 "github.com/openshift/client-go/config/listers/config/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+)
+
+func TestNetworkConfigMismatch(t *testing.T) {
+	network := func(clusterCIDRs []string, serviceNetwork []string, migration bool) *configv1.Network {
+		network := &configv1.Network{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}
+		for _, cidr := range clusterCIDRs {
+			network.Status.ClusterNetwork = append(network.Status.ClusterNetwork, configv1.ClusterNetworkEntry{CIDR: cidr, HostPrefix: 23})
+		}
+		network.Status.ServiceNetwork = serviceNetwork
+		if migration {
+			network.Status.Migration = &configv1.NetworkMigration{NetworkType: "OVNKubernetes"}
+		}
+		return network
+	}
+	tests := []struct {
+		name            string
+		network         *configv1.Network
+		observedConfig  string
+		expectedStatus  operatorv1.ConditionStatus
+		expectedReason  string
+		expectedMessage string
+	}{
+		{
+			name:            "network not applied",
+			expectedStatus:  operatorv1.ConditionUnknown,
+			expectedReason:  "NetworkNotApplied",
+			expectedMessage: "the network operator did not publish the cluster and service networks yet",
+		},
+		{
+			name:           "match",
+			network:        network([]string{"10.128.0.0/14", "fd01::/48"}, []string{"172.30.0.0/16", "fd02::/112"}, false),
+			observedConfig: `{"extendedArguments":{"cluster-cidr":["fd01::/48","10.128.0.0/14"],"service-cluster-ip-range":["172.30.0.0/16,fd02::/112"]}}`,
+			expectedStatus: operatorv1.ConditionFalse,
+			expectedReason: "AsExpected",
+		},
+		{
+			name:            "stale cluster cidr after a migration",
+			network:         network([]string{"10.132.0.0/14"}, []string{"172.30.0.0/16"}, true),
+			observedConfig:  `{"extendedArguments":{"cluster-cidr":["10.128.0.0/14"],"service-cluster-ip-range":["172.30.0.0/16"]}}`,
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedReason:  "NetworkConfigMismatch",
+			expectedMessage: "cluster-cidr is 10.128.0.0/14 in the observed config, the network operator applied 10.132.0.0/14; a network migration is in progress",
+		},
+		{
+			name:            "nothing observed",
+			network:         network([]string{"10.128.0.0/14"}, []string{"172.30.0.0/16"}, false),
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedReason:  "NetworkConfigMismatch",
+			expectedMessage: "cluster-cidr is <none> in the observed config, the network operator applied 10.128.0.0/14; service-cluster-ip-range is <none> in the observed config, the network operator applied 172.30.0.0/16",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if test.network != nil {
+				if err := indexer.Add(test.network); err != nil {
+					t.Fatal(err)
+				}
+			}
+			operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{ObservedConfig: runtime.RawExtension{Raw: []byte(test.observedConfig)}}, &operatorv1.OperatorStatus{}, nil)
+			c := &NetworkConfigMismatchController{
+				operatorClient: operatorClient,
+				statusUpdater:  statusbatcher.NewDirectUpdater(operatorClient),
+				networkLister:  configlistersv1.NewNetworkLister(indexer),
+			}
+			if err := c.sync(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))); err != nil {
+				t.Fatal(err)
+			}
+			_, status, _, err := operatorClient.GetOperatorState()
+			if err != nil {
+				t.Fatal(err)
+			}
+			condition := v1helpers.FindOperatorCondition(status.Conditions, networkConfigMismatchConditionType)
+			if condition == nil || condition.Status != test.expectedStatus || condition.Reason != test.expectedReason || condition.Message != test.expectedMessage {
+				t.Errorf("expected %s %s with message %q, got %v", test.expectedStatus, test.expectedReason, test.expectedMessage, condition)
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package network
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	configinformers "github.com/openshift/client-go/config/informers/externalversions"
+	configlistersv1 "github.com/openshift/client-go/config/listers/config/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/configobserver"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operationprofile"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
+)
+
+// AllocateNodeCIDRsAnnotationName set to true on the kubecontrollermanager resource makes kube-controller-manager
+// assign the node pod CIDRs for the networks which do not assign them themselves. Without it the node CIDR
+// allocation and the cloud routes stay disabled, the way existing clusters run.
+const AllocateNodeCIDRsAnnotationName = "kube-controller-manager.openshift.io/allocate-node-cidrs"
+
+var (
+	AllocateNodeCIDRsPath    = []string{"extendedArguments", "allocate-node-cidrs"}
+	ConfigureCloudRoutesPath = []string{"extendedArguments", "configure-cloud-routes"}
+)
+
+// networkTypesWithOwnIPAM assign the pod CIDRs of the nodes themselves, kube-controller-manager must not.
+var networkTypesWithOwnIPAM = sets.NewString(
+	string(operatorv1.NetworkTypeOpenShiftSDN),
+	string(operatorv1.NetworkTypeOVNKubernetes),
+	string(operatorv1.NetworkTypeKuryr),
+)
+
+// platformsWithCloudRoutes have an in-tree route controller programming the node pod CIDRs into the cloud network.
+var platformsWithCloudRoutes = sets.NewString(
+	string(configv1.AWSPlatformType),
+	string(configv1.AzurePlatformType),
+	string(configv1.GCPPlatformType),
+)
+
+// NodeCIDRTopology is how pod CIDRs get assigned to nodes and routed for a network type on a platform.
+type NodeCIDRTopology struct {
+	AllocateNodeCIDRs    bool
+	ConfigureCloudRoutes bool
+	Reason               string
+	Message              string
+}
+
+// NodeCIDRTopologyFor decides whether kube-controller-manager allocates the node pod CIDRs and configures the
+// cloud routes for them. Both stay disabled unless the allocation is requested, switching the IPAM and the routing
+// of an existing cluster behind its back is not safe.
+func NodeCIDRTopologyFor(networkType string, platform configv1.PlatformType, requested bool) NodeCIDRTopology {
+	if networkTypesWithOwnIPAM.Has(networkType) {
+		return NodeCIDRTopology{
+			Reason:  "NetworkAllocatesNodeCIDRs",
+			Message: fmt.Sprintf("the %s network assigns the node pod CIDRs", networkType),
+		}
+	}
+	if !requested {
+		return NodeCIDRTopology{
+			Reason:  "NodeCIDRAllocationNotRequested",
+			Message: fmt.Sprintf("kube-controller-manager does not assign the node pod CIDRs for the %s network unless the kubecontrollermanager resource is annotated with %s=true", networkType, AllocateNodeCIDRsAnnotationName),
+		}
+	}
+	if platformsWithCloudRoutes.Has(string(platform)) {
+		return NodeCIDRTopology{
+			AllocateNodeCIDRs:    true,
+			ConfigureCloudRoutes: true,
+			Reason:               "ControllerManagerAllocatesNodeCIDRsWithCloudRoutes",
+			Message:              fmt.Sprintf("kube-controller-manager assigns the node pod CIDRs for the %s network and routes them in the %s cloud", networkType, platform),
+		}
+	}
+	return NodeCIDRTopology{
+		AllocateNodeCIDRs: true,
+		Reason:            "ControllerManagerAllocatesNodeCIDRs",
+		Message:           fmt.Sprintf("kube-controller-manager assigns the node pod CIDRs for the %s network, the %s platform has no cloud routes", networkType, platform),
+	}
+}
+
+// nodeCIDRAllocationRequested reads the annotation of the operator resource requesting the node CIDR allocation.
+func nodeCIDRAllocationRequested(operatorClient v1helpers.OperatorClient) (bool, error) {
+	meta, err := operatorClient.GetObjectMeta()
+	if err != nil {
+		return false, err
+	}
+	annotation, ok := meta.Annotations[AllocateNodeCIDRsAnnotationName]
+	if !ok {
+		return false, nil
+	}
+	requested, err := strconv.ParseBool(annotation)
+	if err != nil {
+		return false, fmt.Errorf("%s: invalid value %q, must be true or false", AllocateNodeCIDRsAnnotationName, annotation)
+	}
+	return requested, nil
+}
+
+// nodeCIDRTopology reads the topology of the cluster, it returns nil while the network type is not known yet.
+func nodeCIDRTopology(operatorClient v1helpers.OperatorClient, networkLister configlistersv1.NetworkLister, infrastructureLister configlistersv1.InfrastructureLister) (*NodeCIDRTopology, error) {
+	requested, err := nodeCIDRAllocationRequested(operatorClient)
+	if err != nil {
+		return nil, err
+	}
+	network, err := networkLister.Get("cluster")
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(network.Status.NetworkType) == 0 {
+		return nil, nil
+	}
+
+	platform := configv1.NonePlatformType
+	infrastructure, err := infrastructureLister.Get("cluster")
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+	if infrastructure != nil && infrastructure.Status.PlatformStatus != nil {
+		platform = infrastructure.Status.PlatformStatus.Type
+	}
+	topology := NodeCIDRTopologyFor(network.Status.NetworkType, platform, requested)
+	return &topology, nil
+}
+
+// NewObserveNodeCIDRAllocationFunc sets allocate-node-cidrs and configure-cloud-routes for the network type and
+// platform, once the allocation is requested through the annotation of the operator resource.
+func NewObserveNodeCIDRAllocationFunc(operatorClient v1helpers.OperatorClient) configobserver.ObserveConfigFunc {
+	return func(genericListers configobserver.Listers, recorder events.Recorder, existingConfig map[string]interface{}) (map[string]interface{}, []error) {
+		return observeNodeCIDRAllocation(operatorClient, genericListers, recorder, existingConfig)
+	}
+}
+
+func observeNodeCIDRAllocation(operatorClient v1helpers.OperatorClient, genericListers configobserver.Listers, recorder events.Recorder, existingConfig map[string]interface{}) (ret map[string]interface{}, _ []error) {
+	defer func() {
+		ret = configobserver.Pruned(ret, AllocateNodeCIDRsPath, ConfigureCloudRoutesPath)
+	}()
+	listers := genericListers.(configobservation.Listers)
+
+	topology, err := nodeCIDRTopology(operatorClient, listers.NetworkLister, listers.InfrastructureLister_)
+	if err != nil {
+		return existingConfig, []error{err}
+	}
+	if topology == nil {
+		// keep the defaults until the network is deployed
+		return existingConfig, nil
+	}
+
+	observedConfig := map[string]interface{}{}
+	if err := unstructured.SetNestedStringSlice(observedConfig, []string{strconv.FormatBool(topology.AllocateNodeCIDRs)}, AllocateNodeCIDRsPath...); err != nil {
+		return existingConfig, []error{err}
+	}
+	if err := unstructured.SetNestedStringSlice(observedConfig, []string{strconv.FormatBool(topology.ConfigureCloudRoutes)}, ConfigureCloudRoutesPath...); err != nil {
+		return existingConfig, []error{err}
+	}
+
+	if !equality.Semantic.DeepEqual(configobserver.Pruned(existingConfig, AllocateNodeCIDRsPath, ConfigureCloudRoutesPath), observedConfig) {
+		recorder.Eventf("ObserveNodeCIDRAllocation", "allocate-node-cidrs=%t, configure-cloud-routes=%t: %s", topology.AllocateNodeCIDRs, topology.ConfigureCloudRoutes, topology.Message)
+	}
+	return observedConfig, nil
+}
+
+// NodeCIDRTopologyController reports the node CIDR topology chosen by the node CIDR allocation observer in the
+// NodeCIDRAllocation condition.
+type NodeCIDRTopologyController struct {
+	operatorClient       v1helpers.OperatorClient
+	statusUpdater        statusbatcher.StatusUpdater
+	networkLister        configlistersv1.NetworkLister
+	infrastructureLister configlistersv1.InfrastructureLister
+}
+
+func NewNodeCIDRTopologyController(
+	operatorClient v1helpers.OperatorClient,
+	statusUpdater statusbatcher.StatusUpdater,
+	configInformers configinformers.SharedInformerFactory,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &NodeCIDRTopologyController{
+		operatorClient:       operatorClient,
+		statusUpdater:        statusUpdater,
+		networkLister:        configInformers.Config().V1().Networks().Lister(),
+		infrastructureLister: configInformers.Config().V1().Infrastructures().Lister(),
+	}
+
+	return factory.New().WithInformers(
+		operatorClient.Informer(),
+		configInformers.Config().V1().Networks().Informer(),
+		configInformers.Config().V1().Infrastructures().Informer(),
+	).ResyncEvery(operationprofile.Resync(time.Minute)).WithSync(syncmetrics.Timed("NodeCIDRTopologyController", c.sync)).ToController("NodeCIDRTopologyController", eventRecorder)
+}
+
+func (c *NodeCIDRTopologyController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	topology, err := nodeCIDRTopology(c.operatorClient, c.networkLister, c.infrastructureLister)
+	if err != nil {
+		return err
+	}
+
+	condition := operatorv1.OperatorCondition{
+		Type:    "NodeCIDRAllocation",
+		Status:  operatorv1.ConditionUnknown,
+		Reason:  "NetworkTypeUnknown",
+		Message: "the network type of the cluster is not known yet",
+	}
+	if topology != nil {
+		condition.Status = operatorv1.ConditionFalse
+		if topology.AllocateNodeCIDRs {
+			condition.Status = operatorv1.ConditionTrue
+		}
+		condition.Reason = topology.Reason
+		condition.Message = topology.Message
+	}
+	return c.statusUpdater.UpdateStatus(ctx, v1helpers.UpdateConditionFn(condition))
+}
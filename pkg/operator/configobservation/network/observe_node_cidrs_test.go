@@ -0,0 +1,130 @@
+package network
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	configlistersv1 "github.com/openshift/client-go/config/listers/config/v1"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation"
+)
+
+func nodeCIDRConfig(allocate, routes string) map[string]interface{} {
+	return map[string]interface{}{
+		"extendedArguments": map[string]interface{}{
+			"allocate-node-cidrs":    []interface{}{allocate},
+			"configure-cloud-routes": []interface{}{routes},
+		},
+	}
+}
+
+func TestObserveNodeCIDRAllocation(t *testing.T) {
+	tests := []struct {
+		name        string
+		networkType string
+		platform    configv1.PlatformType
+		annotation  string
+		input       map[string]interface{}
+		expected    map[string]interface{}
+		expectedErr bool
+	}{
+		{
+			name:        "OVNKubernetes on AWS",
+			networkType: "OVNKubernetes",
+			platform:    configv1.AWSPlatformType,
+			expected:    nodeCIDRConfig("false", "false"),
+		},
+		{
+			name:        "OVNKubernetes on AWS requested",
+			networkType: "OVNKubernetes",
+			platform:    configv1.AWSPlatformType,
+			annotation:  "true",
+			expected:    nodeCIDRConfig("false", "false"),
+		},
+		{
+			name:        "third party network on GCP not requested",
+			networkType: "Calico",
+			platform:    configv1.GCPPlatformType,
+			expected:    nodeCIDRConfig("false", "false"),
+		},
+		{
+			name:        "third party network on GCP",
+			networkType: "Calico",
+			platform:    configv1.GCPPlatformType,
+			annotation:  "true",
+			expected:    nodeCIDRConfig("true", "true"),
+		},
+		{
+			name:        "third party network on bare metal",
+			networkType: "Cilium",
+			platform:    configv1.BareMetalPlatformType,
+			annotation:  "true",
+			expected:    nodeCIDRConfig("true", "false"),
+		},
+		{
+			name:        "third party network on bare metal turned off",
+			networkType: "Cilium",
+			platform:    configv1.BareMetalPlatformType,
+			annotation:  "false",
+			input:       nodeCIDRConfig("true", "false"),
+			expected:    nodeCIDRConfig("false", "false"),
+		},
+		{
+			name:        "invalid annotation",
+			networkType: "Cilium",
+			platform:    configv1.BareMetalPlatformType,
+			annotation:  "yes please",
+			input:       nodeCIDRConfig("true", "false"),
+			expected:    nodeCIDRConfig("true", "false"),
+			expectedErr: true,
+		},
+		{
+			name:     "network type not known yet",
+			input:    nodeCIDRConfig("true", "false"),
+			expected: nodeCIDRConfig("true", "false"),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			networkIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if err := networkIndexer.Add(&configv1.Network{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Status:     configv1.NetworkStatus{NetworkType: test.networkType},
+			}); err != nil {
+				t.Fatal(err)
+			}
+			infrastructureIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if err := infrastructureIndexer.Add(&configv1.Infrastructure{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Status:     configv1.InfrastructureStatus{PlatformStatus: &configv1.PlatformStatus{Type: test.platform}},
+			}); err != nil {
+				t.Fatal(err)
+			}
+			listers := configobservation.Listers{
+				NetworkLister:         configlistersv1.NewNetworkLister(networkIndexer),
+				InfrastructureLister_: configlistersv1.NewInfrastructureLister(infrastructureIndexer),
+			}
+
+			meta := &metav1.ObjectMeta{Name: "cluster"}
+			if len(test.annotation) > 0 {
+				meta.Annotations = map[string]string{AllocateNodeCIDRsAnnotationName: test.annotation}
+			}
+			operatorClient := v1helpers.NewFakeOperatorClientWithObjectMeta(meta, &operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil)
+
+			result, errs := NewObserveNodeCIDRAllocationFunc(operatorClient)(listers, events.NewInMemoryRecorder("network"), test.input)
+			if test.expectedErr != (len(errs) > 0) {
+				t.Fatalf("expected errors %v, got %v", test.expectedErr, errs)
+			}
+			if !reflect.DeepEqual(result, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package observerhealth
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/component-base/metrics"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/configobserver"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operationprofile"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatormetrics"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
+)
+
+const conditionType = "StaleObservation"
+
+var lastSuccess = metrics.NewGaugeVec(
+	&metrics.GaugeOpts{
+		Name:           "kcm_operator_observer_last_success_timestamp",
+		Help:           "Unix timestamp of the last run of a config observer which finished without errors.",
+		StabilityLevel: metrics.ALPHA,
+	},
+	[]string{"observer"},
+)
+
+func init() {
+	operatormetrics.MustRegister(lastSuccess)
+}
+
+// Tracker remembers when every config observer last finished without errors.
+type Tracker struct {
+	lock        sync.Mutex
+	started     time.Time
+	lastSuccess map[string]time.Time
+	now         func() time.Time
+}
+
+func NewTracker() *Tracker {
+	return &Tracker{
+		started:     time.Now(),
+		lastSuccess: map[string]time.Time{},
+		now:         time.Now,
+	}
+}
+
+// Track wraps the observer to record its successful runs under the given name.
+func (t *Tracker) Track(name string, observer configobserver.ObserveConfigFunc) configobserver.ObserveConfigFunc {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	// observers which never succeeded count from the time they were registered
+	t.lastSuccess[name] = time.Time{}
+
+	return func(listers configobserver.Listers, recorder events.Recorder, existingConfig map[string]interface{}) (map[string]interface{}, []error) {
+		observedConfig, errs := observer(listers, recorder, existingConfig)
+		if len(errs) == 0 {
+			t.lock.Lock()
+			now := t.now()
+			t.lastSuccess[name] = now
+			t.lock.Unlock()
+			lastSuccess.WithLabelValues(name).Set(float64(now.Unix()))
+		}
+		return observedConfig, errs
+	}
+}
+
+// Stale returns the observers which did not succeed within the threshold, with the time of their last success.
+func (t *Tracker) Stale(threshold time.Duration) []string {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	stale := []string{}
+	for name, last := range t.lastSuccess {
+		switch {
+		case last.IsZero() && t.now().Sub(t.started) > threshold:
+			stale = append(stale, fmt.Sprintf("%s has not succeeded since the operator started at %s", name, t.started.UTC().Format(time.RFC3339)))
+		case !last.IsZero() && t.now().Sub(last) > threshold:
+			stale = append(stale, fmt.Sprintf("%s last succeeded at %s", name, last.UTC().Format(time.RFC3339)))
+		}
+	}
+	sort.Strings(stale)
+	return stale
+}
+
+type staleObservationController struct {
+	tracker       *Tracker
+	threshold     time.Duration
+	statusUpdater statusbatcher.StatusUpdater
+}
+
+// NewStaleObservationController sets the StaleObservation condition when any tracked observer has not succeeded
+// within the threshold. A wedged observer otherwise goes unnoticed as long as it keeps returning its previous result.
+func NewStaleObservationController(
+	tracker *Tracker,
+	threshold time.Duration,
+	statusUpdater statusbatcher.StatusUpdater,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &staleObservationController{
+		tracker:       tracker,
+		threshold:     threshold,
+		statusUpdater: statusUpdater,
+	}
+	return factory.New().ResyncEvery(operationprofile.Resync(time.Minute)).WithSync(syncmetrics.Timed("StaleObservationController", c.sync)).ToController("StaleObservationController", eventRecorder)
+}
+
+func (c *staleObservationController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	condition := operatorv1.OperatorCondition{
+		Type:   conditionType,
+		Status: operatorv1.ConditionFalse,
+	}
+	if stale := c.tracker.Stale(c.threshold); len(stale) > 0 {
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "ObserversNotSucceeding"
+		condition.Message = strings.Join(stale, "\n")
+	}
+	return c.statusUpdater.UpdateStatus(ctx, v1helpers.UpdateConditionFn(condition))
+}
@@ -0,0 +1,56 @@
+package observerhealth
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/openshift/library-go/pkg/operator/configobserver"
+	"github.com/openshift/library-go/pkg/operator/events"
+)
+
+func TestTracker(t *testing.T) {
+	started := time.Date(2022, 2, 1, 10, 0, 0, 0, time.UTC)
+	now := started
+	tracker := &Tracker{
+		started:     started,
+		lastSuccess: map[string]time.Time{},
+		now:         func() time.Time { return now },
+	}
+
+	failing := true
+	ok := tracker.Track("ok", func(configobserver.Listers, events.Recorder, map[string]interface{}) (map[string]interface{}, []error) {
+		return nil, nil
+	})
+	flaky := tracker.Track("flaky", func(configobserver.Listers, events.Recorder, map[string]interface{}) (map[string]interface{}, []error) {
+		if failing {
+			return nil, []error{fmt.Errorf("failed")}
+		}
+		return nil, nil
+	})
+	recorder := events.NewInMemoryRecorder("test")
+
+	now = now.Add(time.Minute)
+	ok(nil, recorder, nil)
+	flaky(nil, recorder, nil)
+	if stale := tracker.Stale(10 * time.Minute); len(stale) != 0 {
+		t.Errorf("expected nothing stale yet, got %v", stale)
+	}
+
+	now = now.Add(15 * time.Minute)
+	ok(nil, recorder, nil)
+	flaky(nil, recorder, nil)
+	expected := []string{"flaky has not succeeded since the operator started at 2022-02-01T10:00:00Z"}
+	if stale := tracker.Stale(10 * time.Minute); !reflect.DeepEqual(stale, expected) {
+		t.Errorf("expected %v, got %v", expected, stale)
+	}
+
+	failing = false
+	flaky(nil, recorder, nil)
+	now = now.Add(15 * time.Minute)
+	expected = []string{"flaky last succeeded at 2022-02-01T10:16:00Z", "ok last succeeded at 2022-02-01T10:16:00Z"}
+	if stale := tracker.Stale(10 * time.Minute); !reflect.DeepEqual(stale, expected) {
+		t.Errorf("expected %v, got %v", expected, stale)
+	}
+}
@@ -0,0 +1,145 @@
+package ownership
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/openshift/library-go/pkg/operator/configobserver"
+	"github.com/openshift/library-go/pkg/operator/events"
+)
+
+// Ownership assigns every path of the observed config to a single observer. The observed configs of all
+// observers are merged in random order, so two observers writing the same path would make the result depend
+// on the ordering. Writes outside of the owned paths are dropped and reported as errors of the observer instead.
+type Ownership struct {
+	lock sync.Mutex
+	// owners maps the dot separated paths to the name of their observer
+	owners map[string]string
+}
+
+func New() *Ownership {
+	return &Ownership{owners: map[string]string{}}
+}
+
+// Own registers the observer as the owner of the given paths and wraps it to only write below them.
+// Paths claimed by an earlier observer are not granted, the conflict is reported on every run of the observer.
+func (o *Ownership) Own(name string, observer configobserver.ObserveConfigFunc, paths ...[]string) configobserver.ObserveConfigFunc {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	owned := [][]string{}
+	conflicts := []error{}
+	for _, path := range paths {
+		if owners := o.ownersLocked(path, name); len(owners) > 0 {
+			for _, owner := range owners {
+				conflicts = append(conflicts, fmt.Errorf("observed config path %s is owned by both %s and %s", strings.Join(path, "."), owner, name))
+			}
+			continue
+		}
+		o.owners[strings.Join(path, ".")] = name
+		owned = append(owned, path)
+	}
+
+	return func(listers configobserver.Listers, recorder events.Recorder, existingConfig map[string]interface{}) (map[string]interface{}, []error) {
+		observedConfig, errs := observer(listers, recorder, existingConfig)
+		errs = append(errs, conflicts...)
+
+		for _, path := range leafPaths(observedConfig, nil) {
+			if isBelow(path, owned) {
+				continue
+			}
+			if owners := o.ownersOf(path, name); len(owners) > 0 {
+				errs = append(errs, fmt.Errorf("observed config path %s is owned by %s, dropping the value observed by %s", strings.Join(path, "."), strings.Join(owners, " and "), name))
+			} else {
+				errs = append(errs, fmt.Errorf("observed config path %s is not owned by %s, dropping the observed value", strings.Join(path, "."), name))
+			}
+		}
+		if len(owned) == 0 {
+			return map[string]interface{}{}, errs
+		}
+		return configobserver.Pruned(observedConfig, owned...), errs
+	}
+}
+
+// Owner returns the observer owning the path or one of its parents, empty when the path is not observed.
+func (o *Ownership) Owner(path []string) string {
+	if o == nil {
+		return ""
+	}
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	for i := len(path); i > 0; i-- {
+		if owner, ok := o.owners[strings.Join(path[:i], ".")]; ok {
+			return owner
+		}
+	}
+	return ""
+}
+
+func (o *Ownership) ownersOf(path []string, except string) []string {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	return o.ownersLocked(path, except)
+}
+
+// ownersLocked returns the observers other than the given one owning the path, a parent or a child of it, sorted.
+func (o *Ownership) ownersLocked(path []string, except string) []string {
+	key := strings.Join(path, ".")
+	owners := map[string]bool{}
+	for ownedPath, owner := range o.owners {
+		if owner == except {
+			continue
+		}
+		if ownedPath == key || strings.HasPrefix(key, ownedPath+".") || strings.HasPrefix(ownedPath, key+".") {
+			owners[owner] = true
+		}
+	}
+	ret := make([]string, 0, len(owners))
+	for owner := range owners {
+		ret = append(ret, owner)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// isBelow tells whether the path is one of the given paths or below one of them.
+func isBelow(path []string, paths [][]string) bool {
+	for _, parent := range paths {
+		if len(path) < len(parent) {
+			continue
+		}
+		below := true
+		for i := range parent {
+			if path[i] != parent[i] {
+				below = false
+				break
+			}
+		}
+		if below {
+			return true
+		}
+	}
+	return false
+}
+
+// leafPaths returns the paths of all values of the config which are not maps, sorted.
+func leafPaths(config map[string]interface{}, prefix []string) [][]string {
+	keys := make([]string, 0, len(config))
+	for key := range config {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	ret := [][]string{}
+	for _, key := range keys {
+		path := append(append([]string{}, prefix...), key)
+		if nested, ok := config[key].(map[string]interface{}); ok && len(nested) > 0 {
+			ret = append(ret, leafPaths(nested, path)...)
+			continue
+		}
+		ret = append(ret, path)
+	}
+	return ret
+}
@@ -0,0 +1,75 @@
+package ownership
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/openshift/library-go/pkg/operator/configobserver"
+	"github.com/openshift/library-go/pkg/operator/events"
+)
+
+func observing(config map[string]interface{}) configobserver.ObserveConfigFunc {
+	return func(configobserver.Listers, events.Recorder, map[string]interface{}) (map[string]interface{}, []error) {
+		return config, nil
+	}
+}
+
+func errorStrings(errs []error) []string {
+	ret := []string{}
+	for _, err := range errs {
+		ret = append(ret, err.Error())
+	}
+	return ret
+}
+
+func TestOwn(t *testing.T) {
+	ownership := New()
+	clusterName := ownership.Own("infraid", observing(map[string]interface{}{
+		"extendedArguments": map[string]interface{}{"cluster-name": []interface{}{"foo"}},
+	}), []string{"extendedArguments", "cluster-name"})
+	// writes the path it owns and one owned by infraid
+	cidrs := ownership.Own("clustercidrs", observing(map[string]interface{}{
+		"extendedArguments": map[string]interface{}{
+			"cluster-cidr": []interface{}{"10.128.0.0/14"},
+			"cluster-name": []interface{}{"bar"},
+		},
+	}), []string{"extendedArguments", "cluster-cidr"})
+	// claims a parent of a path owned by infraid
+	greedy := ownership.Own("greedy", observing(map[string]interface{}{
+		"extendedArguments": map[string]interface{}{"cluster-name": []interface{}{"baz"}},
+		"servingInfo":       map[string]interface{}{"minTLSVersion": "VersionTLS12"},
+	}), []string{"extendedArguments"})
+
+	config, errs := clusterName(nil, nil, nil)
+	if len(errs) > 0 {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+	if expected := map[string]interface{}{"extendedArguments": map[string]interface{}{"cluster-name": []interface{}{"foo"}}}; !reflect.DeepEqual(config, expected) {
+		t.Errorf("expected %v, got %v", expected, config)
+	}
+
+	config, errs = cidrs(nil, nil, nil)
+	if expected := map[string]interface{}{"extendedArguments": map[string]interface{}{"cluster-cidr": []interface{}{"10.128.0.0/14"}}}; !reflect.DeepEqual(config, expected) {
+		t.Errorf("expected %v, got %v", expected, config)
+	}
+	if expected := []string{"observed config path extendedArguments.cluster-name is owned by infraid, dropping the value observed by clustercidrs"}; !reflect.DeepEqual(errorStrings(errs), expected) {
+		t.Errorf("expected %v, got %v", expected, errorStrings(errs))
+	}
+
+	config, errs = greedy(nil, nil, nil)
+	if len(config) > 0 {
+		t.Errorf("expected nothing to be observed, got %v", config)
+	}
+	got := strings.Join(errorStrings(errs), "\n")
+	for _, expected := range []string{
+		"observed config path extendedArguments is owned by both clustercidrs and greedy",
+		"observed config path extendedArguments is owned by both infraid and greedy",
+		"observed config path extendedArguments.cluster-name is owned by infraid, dropping the value observed by greedy",
+		"observed config path servingInfo.minTLSVersion is not owned by greedy, dropping the observed value",
+	} {
+		if !strings.Contains(got, expected) {
+			t.Errorf("expected %q in errors, got %v", expected, got)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package contentionprofilecontroller
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/rest"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/controllerpause"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operandendpoint"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/targetconfigcontroller"
+)
+
+const (
+	conditionType = "ContentionProfiling"
+
+	// ConfigMapName is the configmap of the operator namespace holding the last collected profiles, retrieved with
+	// oc extract -n openshift-kube-controller-manager-operator configmap/contention-profiles
+	ConfigMapName = "contention-profiles"
+	// ProfilesKey is the binary key of the configmap holding the profiles as a gzipped tarball of
+	// <pod>/<profile>.pb.gz files.
+	ProfilesKey           = "profiles.tar.gz"
+	collectedAtAnnotation = "kube-controller-manager.openshift.io/collected-at"
+
+	// maxProfilesBytes keeps the configmap below the object size limit of etcd
+	maxProfilesBytes = 900 * 1024
+)
+
+// profiles are the pprof endpoints collected, block profiling is turned on by --contention-profiling
+var profiles = []string{"block", "mutex"}
+
+// ContentionProfileController collects the block and mutex profiles of the kube-controller-manager pods while the
+// contentionProfiling stanza of the unsupported config overrides turns on contention profiling. The profiles are
+// cumulative, every collection replaces the previous one and the last before the window closes is kept for retrieval.
+type ContentionProfileController struct {
+	operatorClient   v1helpers.StaticPodOperatorClient
+	statusUpdater    statusbatcher.StatusUpdater
+	podLister        corev1listers.PodLister
+	configMapsGetter corev1client.ConfigMapsGetter
+	fetch            operandendpoint.Fetcher
+	interval         time.Duration
+	now              func() time.Time
+
+	lastCollected time.Time
+	// lastFailures are the pods and profiles the last collection failed for
+	lastFailures []string
+}
+
+// NewContentionProfileController collects the profiles every interval, through the serving endpoint of every
+// kube-controller-manager pod with the credentials of the operator.
+func NewContentionProfileController(
+	operatorClient v1helpers.StaticPodOperatorClient,
+	statusUpdater statusbatcher.StatusUpdater,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	configMapsGetter corev1client.ConfigMapsGetter,
+	kubeConfig *rest.Config,
+	interval time.Duration,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	targetInformers := kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace)
+	c := &ContentionProfileController{
+		operatorClient:   operatorClient,
+		statusUpdater:    statusUpdater,
+		podLister:        targetInformers.Core().V1().Pods().Lister(),
+		configMapsGetter: configMapsGetter,
+		fetch:            operandendpoint.NewFetcher(kubeConfig, targetInformers.Core().V1().ConfigMaps().Lister()),
+		interval:         interval,
+		now:              time.Now,
+	}
+
+	return factory.New().WithInformers(
+		operatorClient.Informer(),
+	).ResyncEvery(interval).WithSync(syncmetrics.Timed("ContentionProfileController", controllerpause.Pausable("ContentionProfileController", c.sync))).ToController("ContentionProfileController", eventRecorder)
+}
+
+func (c *ContentionProfileController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	spec, _, _, err := c.operatorClient.GetStaticPodOperatorState()
+	if err != nil {
+		return err
+	}
+	now := c.now()
+	condition := operatorv1.OperatorCondition{
+		Type:   conditionType,
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+	until, err := targetconfigcontroller.ContentionProfilingUntil(spec.UnsupportedConfigOverrides.Raw, now)
+	switch {
+	case err != nil:
+		condition.Reason = "InvalidContentionProfiling"
+		condition.Message = err.Error()
+	case until == nil:
+	case !now.Before(*until):
+		condition.Reason = "ProfilingWindowClosed"
+		condition.Message = fmt.Sprintf("contention profiling ended at %s, the last collected profiles are in the %s key of configmap %s/%s. Remove the contentionProfiling stanza of unsupportedConfigOverrides",
+			until.UTC().Format(time.RFC3339), ProfilesKey, operatorclient.OperatorNamespace, ConfigMapName)
+	default:
+		// status updates trigger syncs as well, the profiles are collected once per interval only
+		if now.Sub(c.lastCollected) >= c.interval {
+			c.lastFailures, err = c.collect(ctx, syncCtx.Recorder(), now)
+			if err != nil {
+				return err
+			}
+			c.lastCollected = now
+		}
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "Profiling"
+		condition.Message = fmt.Sprintf("kube-controller-manager runs with contention profiling until %s, its block and mutex profiles are collected every %s into the %s key of configmap %s/%s",
+			until.UTC().Format(time.RFC3339), c.interval, ProfilesKey, operatorclient.OperatorNamespace, ConfigMapName)
+		if len(c.lastFailures) > 0 {
+			condition.Message += fmt.Sprintf(". Failed to collect: %s", strings.Join(c.lastFailures, ", "))
+		}
+	}
+	return c.statusUpdater.UpdateStatus(ctx, v1helpers.UpdateConditionFn(condition))
+}
+
+// collect stores the profiles of the running kube-controller-manager pods in the configmap, and returns the ones which
+// failed. The previous profiles are kept when none could be collected.
+func (c *ContentionProfileController) collect(ctx context.Context, recorder events.Recorder, now time.Time) ([]string, error) {
+	pods, err := c.podLister.Pods(operatorclient.TargetNamespace).List(labels.SelectorFromSet(labels.Set{"app": "kube-controller-manager"}))
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+
+	collected := map[string][]byte{}
+	failures := []string{}
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodRunning || len(pod.Status.PodIP) == 0 {
+			continue
+		}
+		for _, profile := range profiles {
+			data, err := c.fetch(ctx, pod, "/debug/pprof/"+profile)
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("%s %s profile: %v", pod.Name, profile, err))
+				continue
+			}
+			collected[fmt.Sprintf("%s/%s.pb.gz", pod.Name, profile)] = data
+		}
+	}
+	if len(collected) == 0 {
+		return failures, nil
+	}
+
+	tarball, err := archive(collected, now)
+	if err != nil {
+		return nil, err
+	}
+	if len(tarball) > maxProfilesBytes {
+		return append(failures, fmt.Sprintf("the profiles take %d bytes, more than the %d a configmap holds", len(tarball), maxProfilesBytes)), nil
+	}
+	_, _, err = resourceapply.ApplyConfigMap(ctx, c.configMapsGetter, recorder, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   operatorclient.OperatorNamespace,
+			Name:        ConfigMapName,
+			Annotations: map[string]string{collectedAtAnnotation: now.UTC().Format(time.RFC3339)},
+		},
+		BinaryData: map[string][]byte{ProfilesKey: tarball},
+	})
+	return failures, err
+}
+
+// archive returns the files as a gzipped tarball, in the order of their names.
+func archive(files map[string][]byte, modTime time.Time) ([]byte, error) {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	buf := &bytes.Buffer{}
+	gzipWriter := gzip.NewWriter(buf)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for _, name := range names {
+		if err := tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(files[name])), ModTime: modTime}); err != nil {
+			return nil, err
+		}
+		if _, err := tarWriter.Write(files[name]); err != nil {
+			return nil, err
+		}
+	}
+	if err := tarWriter.Close(); err != nil {
+		return nil, err
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
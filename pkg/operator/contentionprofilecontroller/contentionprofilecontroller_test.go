@@ -0,0 +1,155 @@
+package contentionprofilecontroller
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+)
+
+func TestSync(t *testing.T) {
+	now := time.Date(2026, 10, 15, 10, 0, 0, 0, time.UTC)
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, pod := range []*corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "kube-controller-manager-master-0", Labels: map[string]string{"app": "kube-controller-manager"}},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.0.0.1"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "kube-controller-manager-master-1", Labels: map[string]string{"app": "kube-controller-manager"}},
+			Status:     corev1.PodStatus{Phase: corev1.PodPending},
+		},
+	} {
+		if err := indexer.Add(pod); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	testCases := []struct {
+		name            string
+		overrides       string
+		expectedStatus  operatorv1.ConditionStatus
+		expectedReason  string
+		expectedMessage string
+		expectedFiles   []string
+	}{
+		{
+			name:           "no stanza",
+			expectedStatus: operatorv1.ConditionFalse,
+			expectedReason: "AsExpected",
+		},
+		{
+			name:            "profiling",
+			overrides:       `{"contentionProfiling":{"until":"2026-10-15T11:00:00Z"}}`,
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedReason:  "Profiling",
+			expectedMessage: "kube-controller-manager runs with contention profiling until 2026-10-15T11:00:00Z, its block and mutex profiles are collected every 5m0s into the profiles.tar.gz key of configmap openshift-kube-controller-manager-operator/contention-profiles. Failed to collect: kube-controller-manager-master-0 mutex profile: connection refused",
+			expectedFiles:   []string{"kube-controller-manager-master-0/block.pb.gz"},
+		},
+		{
+			name:            "window closed",
+			overrides:       `{"contentionProfiling":{"until":"2026-10-15T09:00:00Z"}}`,
+			expectedStatus:  operatorv1.ConditionFalse,
+			expectedReason:  "ProfilingWindowClosed",
+			expectedMessage: "contention profiling ended at 2026-10-15T09:00:00Z, the last collected profiles are in the profiles.tar.gz key of configmap openshift-kube-controller-manager-operator/contention-profiles. Remove the contentionProfiling stanza of unsupportedConfigOverrides",
+		},
+		{
+			name:            "invalid",
+			overrides:       `{"contentionProfiling":{"until":"2026-10-16T10:00:00Z"}}`,
+			expectedStatus:  operatorv1.ConditionFalse,
+			expectedReason:  "InvalidContentionProfiling",
+			expectedMessage: "contentionProfiling.until must be at most 2h0m0s ahead, got 2026-10-16T10:00:00Z",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			operatorClient := v1helpers.NewFakeStaticPodOperatorClient(
+				&operatorv1.StaticPodOperatorSpec{OperatorSpec: operatorv1.OperatorSpec{UnsupportedConfigOverrides: runtime.RawExtension{Raw: []byte(tc.overrides)}}},
+				&operatorv1.StaticPodOperatorStatus{},
+				nil,
+				nil,
+			)
+			kubeClient := fake.NewSimpleClientset()
+			c := &ContentionProfileController{
+				operatorClient:   operatorClient,
+				statusUpdater:    statusbatcher.NewDirectUpdater(operatorClient),
+				podLister:        corev1listers.NewPodLister(indexer),
+				configMapsGetter: kubeClient.CoreV1(),
+				fetch: func(_ context.Context, pod *corev1.Pod, path string) ([]byte, error) {
+					if path == "/debug/pprof/mutex" {
+						return nil, fmt.Errorf("connection refused")
+					}
+					return []byte(pod.Name + " " + path), nil
+				},
+				interval: 5 * time.Minute,
+				now:      func() time.Time { return now },
+			}
+			if err := c.sync(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))); err != nil {
+				t.Fatal(err)
+			}
+
+			_, status, _, _ := operatorClient.GetOperatorState()
+			condition := v1helpers.FindOperatorCondition(status.Conditions, conditionType)
+			if condition == nil {
+				t.Fatalf("missing condition %s", conditionType)
+			}
+			if condition.Status != tc.expectedStatus || condition.Reason != tc.expectedReason || condition.Message != tc.expectedMessage {
+				t.Errorf("expected %s %s %q, got %s %s %q", tc.expectedStatus, tc.expectedReason, tc.expectedMessage, condition.Status, condition.Reason, condition.Message)
+			}
+
+			configMap, err := kubeClient.CoreV1().ConfigMaps(operatorclient.OperatorNamespace).Get(context.TODO(), ConfigMapName, metav1.GetOptions{})
+			if len(tc.expectedFiles) == 0 {
+				if err == nil {
+					t.Errorf("expected no profiles, got %v", configMap)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			files := readTarball(t, configMap.BinaryData[ProfilesKey])
+			if !reflect.DeepEqual(files, tc.expectedFiles) {
+				t.Errorf("expected %v, got %v", tc.expectedFiles, files)
+			}
+		})
+	}
+}
+
+func readTarball(t *testing.T, tarball []byte) []string {
+	gzipReader, err := gzip.NewReader(bytes.NewReader(tarball))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tarReader := tar.NewReader(gzipReader)
+	names := []string{}
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return names
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, header.Name)
+	}
+}
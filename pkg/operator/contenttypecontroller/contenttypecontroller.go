@@ -0,0 +1,135 @@
+package contenttypecontroller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/component-base/metrics"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/argumentmatrixcontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation/contenttype"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/controllerpause"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operationprofile"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatormetrics"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/revisioncache"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
+)
+
+const (
+	conditionType = "KubeAPIContentTypeJSON"
+	flagName      = "kube-api-content-type"
+)
+
+var jsonContentType = metrics.NewGaugeVec(
+	&metrics.GaugeOpts{
+		Name:           "kcm_operator_kube_api_json_content_type",
+		Help:           "Set to 1 for the nodes whose kube-controller-manager talks JSON instead of protobuf to kube-apiserver.",
+		StabilityLevel: metrics.ALPHA,
+	},
+	[]string{"node"},
+)
+
+func init() {
+	operatormetrics.MustRegister(jsonContentType)
+}
+
+// ContentTypeController verifies after every rollout that the kube-controller-manager of every node talks protobuf to
+// kube-apiserver. The content type is read from the flags of the revision a node runs, a kube-controller-manager
+// encoding its requests as JSON measurably increases the CPU usage of kube-apiserver on large clusters.
+type ContentTypeController struct {
+	operatorClient v1helpers.StaticPodOperatorClient
+	statusUpdater  statusbatcher.StatusUpdater
+	revisionCache  *revisioncache.RevisionCache
+}
+
+func NewContentTypeController(
+	operatorClient v1helpers.StaticPodOperatorClient,
+	statusUpdater statusbatcher.StatusUpdater,
+	revisionCache *revisioncache.RevisionCache,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &ContentTypeController{
+		operatorClient: operatorClient,
+		statusUpdater:  statusUpdater,
+		revisionCache:  revisionCache,
+	}
+
+	return factory.New().WithInformers(
+		operatorClient.Informer(),
+		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().ConfigMaps().Informer(),
+	).ResyncEvery(operationprofile.Resync(5*time.Minute)).WithSync(syncmetrics.Timed("KubeAPIContentTypeController", controllerpause.Pausable("KubeAPIContentTypeController", c.sync))).ToController("KubeAPIContentTypeController", eventRecorder)
+}
+
+func (c *ContentTypeController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	_, status, _, err := c.operatorClient.GetStaticPodOperatorState()
+	if err != nil {
+		return err
+	}
+
+	// the content type the rollout converges to
+	configured := contenttype.Protobuf
+	if status.LatestAvailableRevision > 0 {
+		config, err := c.revisionCache.Config(status.LatestAvailableRevision)
+		if err != nil {
+			return err
+		}
+		if config != nil {
+			if values := config.Typed.ExtendedArguments[flagName]; len(values) > 0 {
+				configured = values[len(values)-1]
+			}
+		}
+	}
+
+	jsonNodes := []string{}
+	for _, nodeStatus := range status.NodeStatuses {
+		if nodeStatus.CurrentRevision == 0 {
+			continue
+		}
+		flags, err := c.revisionCache.Flags(nodeStatus.CurrentRevision)
+		if err != nil {
+			return err
+		}
+		// the revisions created before the flags were published are not verified
+		if flags == nil {
+			continue
+		}
+		running := contenttype.Protobuf
+		if values := argumentmatrixcontroller.ParseFlags(flags)[flagName]; len(values) > 0 {
+			running = values[len(values)-1]
+		}
+		if running == contenttype.Protobuf {
+			jsonContentType.WithLabelValues(nodeStatus.NodeName).Set(0)
+			continue
+		}
+		jsonContentType.WithLabelValues(nodeStatus.NodeName).Set(1)
+		jsonNodes = append(jsonNodes, fmt.Sprintf("%s (revision %d, %s)", nodeStatus.NodeName, nodeStatus.CurrentRevision, running))
+	}
+
+	condition := operatorv1.OperatorCondition{
+		Type:   conditionType,
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+	switch {
+	case len(jsonNodes) == 0:
+	case configured != contenttype.Protobuf:
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "NotProtobufRequested"
+		condition.Message = fmt.Sprintf("kube-controller-manager does not talk protobuf to kube-apiserver as configured by %s: %s", flagName, strings.Join(jsonNodes, ", "))
+	default:
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "NotProtobuf"
+		condition.Message = fmt.Sprintf("kube-controller-manager does not talk protobuf to kube-apiserver although revision %d configures it: %s", status.LatestAvailableRevision, strings.Join(jsonNodes, ", "))
+	}
+	return c.statusUpdater.UpdateStatus(ctx, v1helpers.UpdateConditionFn(condition))
+}
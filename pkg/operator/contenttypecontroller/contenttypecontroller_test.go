@@ -0,0 +1,127 @@
+package contenttypecontroller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/revisioncache"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+)
+
+func TestSync(t *testing.T) {
+	configOf := func(revision int32, contentType string) *corev1.ConfigMap {
+		config := `{"apiVersion":"kubecontrolplane.config.openshift.io/v1","kind":"KubeControllerManagerConfig"}`
+		if len(contentType) > 0 {
+			config = fmt.Sprintf(`{"apiVersion":"kubecontrolplane.config.openshift.io/v1","kind":"KubeControllerManagerConfig","extendedArguments":{"kube-api-content-type":[%q]}}`, contentType)
+		}
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: fmt.Sprintf("config-%d", revision)},
+			Data:       map[string]string{"config.yaml": config},
+		}
+	}
+	flagsOf := func(revision int32, flags string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: fmt.Sprintf("kube-controller-manager-flags-%d", revision)},
+			Data:       map[string]string{"flags": flags},
+		}
+	}
+
+	testCases := []struct {
+		name            string
+		configMaps      []*corev1.ConfigMap
+		expectedStatus  operatorv1.ConditionStatus
+		expectedReason  string
+		expectedMessage string
+	}{
+		{
+			name: "protobuf",
+			configMaps: []*corev1.ConfigMap{
+				configOf(2, "application/vnd.kubernetes.protobuf"),
+				flagsOf(1, "--v=2"),
+				flagsOf(2, "--kube-api-content-type=application/vnd.kubernetes.protobuf\n--v=2"),
+			},
+			expectedStatus: operatorv1.ConditionFalse,
+			expectedReason: "AsExpected",
+		},
+		{
+			name: "node not rolled out yet",
+			configMaps: []*corev1.ConfigMap{
+				configOf(2, ""),
+				flagsOf(1, "--kube-api-content-type=application/json"),
+				flagsOf(2, "--v=2"),
+			},
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedReason:  "NotProtobuf",
+			expectedMessage: "kube-controller-manager does not talk protobuf to kube-apiserver although revision 2 configures it: master-1 (revision 1, application/json)",
+		},
+		{
+			name: "json requested",
+			configMaps: []*corev1.ConfigMap{
+				configOf(2, "application/json"),
+				flagsOf(1, "--kube-api-content-type=application/json"),
+				flagsOf(2, "--kube-api-content-type=application/json"),
+			},
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedReason:  "NotProtobufRequested",
+			expectedMessage: "kube-controller-manager does not talk protobuf to kube-apiserver as configured by kube-api-content-type: master-0 (revision 2, application/json), master-1 (revision 1, application/json)",
+		},
+		{
+			name:           "flags not published",
+			configMaps:     []*corev1.ConfigMap{configOf(2, "")},
+			expectedStatus: operatorv1.ConditionFalse,
+			expectedReason: "AsExpected",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			configMapInformer := kubeinformers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0).Core().V1().ConfigMaps()
+			for _, configMap := range tc.configMaps {
+				if err := configMapInformer.Informer().GetIndexer().Add(configMap); err != nil {
+					t.Fatal(err)
+				}
+			}
+			operatorClient := v1helpers.NewFakeStaticPodOperatorClient(
+				&operatorv1.StaticPodOperatorSpec{},
+				&operatorv1.StaticPodOperatorStatus{
+					LatestAvailableRevision: 2,
+					NodeStatuses: []operatorv1.NodeStatus{
+						{NodeName: "master-0", CurrentRevision: 2},
+						{NodeName: "master-1", CurrentRevision: 1},
+						{NodeName: "master-2"},
+					},
+				},
+				nil,
+				nil,
+			)
+			c := &ContentTypeController{
+				operatorClient: operatorClient,
+				statusUpdater:  statusbatcher.NewDirectUpdater(operatorClient),
+				revisionCache:  revisioncache.New(configMapInformer),
+			}
+			if err := c.sync(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))); err != nil {
+				t.Fatal(err)
+			}
+
+			_, status, _, _ := operatorClient.GetOperatorState()
+			condition := v1helpers.FindOperatorCondition(status.Conditions, conditionType)
+			if condition == nil {
+				t.Fatalf("missing condition %s", conditionType)
+			}
+			if condition.Status != tc.expectedStatus || condition.Reason != tc.expectedReason || condition.Message != tc.expectedMessage {
+				t.Errorf("expected %s %s %q, got %s %s %q", tc.expectedStatus, tc.expectedReason, tc.expectedMessage, condition.Status, condition.Reason, condition.Message)
+			}
+		})
+	}
+}
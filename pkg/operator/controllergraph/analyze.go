@@ -0,0 +1,987 @@
+package controllergraph
+
+import (
+	"bufio"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// operatorResource is the kubecontrollermanager resource behind the operator clients and the status updaters.
+var operatorResource = Resource{Group: "operator.openshift.io", Resource: "kubecontrollermanagers"}
+
+// stateStoreResource stands for the documents of the statestore, configmaps or OperatorState resources depending
+// on the STATE_STORAGE of the operator.
+var stateStoreResource = Resource{Resource: "statestore"}
+
+var (
+	// version matches the version accessors of the informer factories, V1() of Core().V1()
+	version = regexp.MustCompile(`^V[0-9]+((alpha|beta)[0-9]+)?$`)
+	// clientGroupVersion matches the group version accessors of the clientsets, CoreV1() of kubeClient.CoreV1()
+	clientGroupVersion = regexp.MustCompile(`^([A-Z][a-z]+)V[0-9]+((alpha|beta)[0-9]+)?$`)
+)
+
+var groupNames = map[string]string{
+	"core":                  "",
+	"apps":                  "apps",
+	"batch":                 "batch",
+	"policy":                "policy",
+	"admissionregistration": "admissionregistration.k8s.io",
+	"certificates":          "certificates.k8s.io",
+	"coordination":          "coordination.k8s.io",
+	"rbac":                  "rbac.authorization.k8s.io",
+	"config":                "config.openshift.io",
+	"operator":              "operator.openshift.io",
+}
+
+// wellKnownStrings are the string constants of other modules used for namespaces and condition types.
+var wellKnownStrings = map[string]string{
+	"NamespaceAll":     "",
+	"NamespaceDefault": "default",
+	"NamespaceSystem":  "kube-system",
+}
+
+var writeVerbs = map[string]bool{"Create": true, "Update": true, "UpdateStatus": true, "Patch": true, "Delete": true, "DeleteCollection": true}
+
+type goPackage struct {
+	path string
+	rel  string
+	name string
+	// values are the top level constants and variables
+	values map[string]value
+	// funcs are the top level functions by name and the methods by receiver type and name, "Type.name"
+	funcs map[string][]*funcDecl
+	// methods are the methods by name
+	methods map[string][]*funcDecl
+}
+
+type goFile struct {
+	pkg     *goPackage
+	imports map[string]string
+}
+
+type value struct {
+	file *goFile
+	expr ast.Expr
+}
+
+type funcDecl struct {
+	file *goFile
+	decl *ast.FuncDecl
+}
+
+// scope resolves the identifiers of a function.
+type scope struct {
+	a    *analyzer
+	file *goFile
+	// env maps the local variables to the expression last assigned to them
+	env map[string]ast.Expr
+	// assignments maps the local variables to all expressions assigned to them, for slices built with append
+	assignments map[string][]ast.Expr
+	// ranges maps the values of range loops to the expression ranged over
+	ranges map[string]ast.Expr
+	// types maps the receiver and the parameters to their type
+	types map[string]ast.Expr
+}
+
+type segment struct {
+	name string
+	call bool
+	args []ast.Expr
+	// root is set for the identifier a selector chain starts with
+	root bool
+}
+
+type analyzer struct {
+	modulePath string
+	packages   map[string]*goPackage
+}
+
+// Analyze builds the graph from the source of the operator in operatorDir, pkg/operator of the module. It finds
+// the constructors of the controllers by their factory.New()...ToController call, and follows the functions and
+// methods of the package the constructor refers to. Informers are taken from the arguments of WithInformers and
+// WithNamespaceInformer, reads and writes from the calls on the clientsets, dynamic clients, resourceapply,
+// operator clients and status updaters, conditions from the OperatorCondition literals. Controllers built by
+// library-go are listed from starter.go without their dataflow.
+func Analyze(operatorDir string) (*Graph, error) {
+	operatorDir, err := filepath.Abs(operatorDir)
+	if err != nil {
+		return nil, err
+	}
+	moduleDir, modulePath, err := findModule(operatorDir)
+	if err != nil {
+		return nil, err
+	}
+	a := &analyzer{modulePath: modulePath, packages: map[string]*goPackage{}}
+
+	fset := token.NewFileSet()
+	err = filepath.Walk(operatorDir, func(filename string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == "testdata" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		dir, name := filepath.Split(filename)
+		if !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			return nil
+		}
+		// the files of the default build only, the faultinjection hooks are stubs there
+		if match, err := build.Default.MatchFile(dir, name); err != nil || !match {
+			return err
+		}
+		parsed, err := parser.ParseFile(fset, filename, nil, 0)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(moduleDir, filepath.Dir(filename))
+		if err != nil {
+			return err
+		}
+		a.addFile(filepath.ToSlash(rel), parsed)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	controllers := map[string]*Controller{}
+	// constructors maps the constructors to the controllers they build, by "path.Func"
+	constructors := map[string][]*Controller{}
+	for _, pkg := range a.sortedPackages() {
+		for _, fns := range pkg.funcs {
+			for _, fn := range fns {
+				if fn.decl.Recv != nil || fn.decl.Body == nil {
+					continue
+				}
+				for _, name := range a.controllerNames(fn) {
+					if _, exists := controllers[name]; exists {
+						return nil, fmt.Errorf("controller %s is built by more than one constructor", name)
+					}
+					controller := a.analyzeController(fn, name)
+					controllers[name] = controller
+					constructors[pkg.path+"."+fn.decl.Name.Name] = append(constructors[pkg.path+"."+fn.decl.Name.Name], controller)
+				}
+			}
+		}
+	}
+
+	starter, found := a.packages[modulePath+"/pkg/operator"]
+	if !found {
+		return nil, fmt.Errorf("%s is not the operator package of %s", operatorDir, modulePath)
+	}
+	for _, started := range a.startedControllers(starter) {
+		if built, found := constructors[started.constructor]; found {
+			for _, controller := range built {
+				controller.Started = true
+			}
+			continue
+		}
+		name := strings.ToUpper(started.variable[:1]) + started.variable[1:]
+		if _, exists := controllers[name]; exists {
+			return nil, fmt.Errorf("the started controller %s clashes with a controller of the same name", name)
+		}
+		controllers[name] = &Controller{Name: name, Constructor: a.shortPath(started.constructor), External: true, Started: true}
+	}
+
+	graph := &Graph{}
+	for _, controller := range controllers {
+		graph.Controllers = append(graph.Controllers, *controller)
+	}
+	sort.Slice(graph.Controllers, func(i, j int) bool { return graph.Controllers[i].Name < graph.Controllers[j].Name })
+	return graph, nil
+}
+
+func findModule(dir string) (string, string, error) {
+	for current := dir; ; current = filepath.Dir(current) {
+		f, err := os.Open(filepath.Join(current, "go.mod"))
+		if err == nil {
+			defer f.Close()
+			scanner := bufio.NewScanner(f)
+			for scanner.Scan() {
+				if fields := strings.Fields(scanner.Text()); len(fields) == 2 && fields[0] == "module" {
+					return current, fields[1], nil
+				}
+			}
+			return "", "", fmt.Errorf("no module path in %s", filepath.Join(current, "go.mod"))
+		}
+		if filepath.Dir(current) == current {
+			return "", "", fmt.Errorf("%s is not in a module", dir)
+		}
+	}
+}
+
+func (a *analyzer) addFile(rel string, parsed *ast.File) {
+	importPath := a.modulePath + "/" + rel
+	pkg, found := a.packages[importPath]
+	if !found {
+		pkg = &goPackage{
+			path:    importPath,
+			rel:     rel,
+			name:    parsed.Name.Name,
+			values:  map[string]value{},
+			funcs:   map[string][]*funcDecl{},
+			methods: map[string][]*funcDecl{},
+		}
+		a.packages[importPath] = pkg
+	}
+	file := &goFile{pkg: pkg, imports: map[string]string{}}
+	for _, spec := range parsed.Imports {
+		importPath, _ := strconv.Unquote(spec.Path.Value)
+		name := path.Base(importPath)
+		if version.MatchString(strings.ToUpper(name[:1])+name[1:]) || name == "v2" {
+			name = path.Base(path.Dir(importPath))
+		}
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		file.imports[name] = importPath
+	}
+	for _, decl := range parsed.Decls {
+		switch decl := decl.(type) {
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				if spec, ok := spec.(*ast.ValueSpec); ok {
+					for i, name := range spec.Names {
+						if i < len(spec.Values) {
+							pkg.values[name.Name] = value{file: file, expr: spec.Values[i]}
+						}
+					}
+				}
+			}
+		case *ast.FuncDecl:
+			fn := &funcDecl{file: file, decl: decl}
+			if decl.Recv == nil {
+				pkg.funcs[decl.Name.Name] = append(pkg.funcs[decl.Name.Name], fn)
+				continue
+			}
+			if receiver := typeName(decl.Recv.List[0].Type); len(receiver) > 0 {
+				pkg.funcs[receiver+"."+decl.Name.Name] = append(pkg.funcs[receiver+"."+decl.Name.Name], fn)
+			}
+			pkg.methods[decl.Name.Name] = append(pkg.methods[decl.Name.Name], fn)
+		}
+	}
+}
+
+func (a *analyzer) sortedPackages() []*goPackage {
+	ret := make([]*goPackage, 0, len(a.packages))
+	for _, pkg := range a.packages {
+		ret = append(ret, pkg)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].path < ret[j].path })
+	return ret
+}
+
+func (a *analyzer) shortPath(qualified string) string {
+	if strings.HasPrefix(qualified, a.modulePath+"/") {
+		return strings.TrimPrefix(qualified, a.modulePath+"/")
+	}
+	return strings.TrimPrefix(qualified, "github.com/openshift/")
+}
+
+// typeName returns the name of a type of the package, "" for types of other packages.
+func typeName(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.StarExpr:
+		return typeName(expr.X)
+	case *ast.Ident:
+		return expr.Name
+	}
+	return ""
+}
+
+func (a *analyzer) newScope(fn *funcDecl) *scope {
+	s := &scope{a: a, file: fn.file, env: map[string]ast.Expr{}, assignments: map[string][]ast.Expr{}, ranges: map[string]ast.Expr{}, types: map[string]ast.Expr{}}
+	assign := func(name string, expr ast.Expr) {
+		s.env[name] = expr
+		s.assignments[name] = append(s.assignments[name], expr)
+	}
+	fields := []*ast.Field{}
+	if fn.decl.Recv != nil {
+		fields = append(fields, fn.decl.Recv.List...)
+	}
+	fields = append(fields, fn.decl.Type.Params.List...)
+	for _, field := range fields {
+		for _, name := range field.Names {
+			s.types[name.Name] = field.Type
+		}
+	}
+	if fn.decl.Body == nil {
+		return s
+	}
+	ast.Inspect(fn.decl.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			switch {
+			case len(n.Lhs) == len(n.Rhs):
+				for i, lhs := range n.Lhs {
+					if ident, ok := lhs.(*ast.Ident); ok && ident.Name != "_" {
+						assign(ident.Name, n.Rhs[i])
+					}
+				}
+			case len(n.Rhs) == 1:
+				// x, err := f()
+				if ident, ok := n.Lhs[0].(*ast.Ident); ok && ident.Name != "_" {
+					assign(ident.Name, n.Rhs[0])
+				}
+			}
+		case *ast.RangeStmt:
+			if value, ok := n.Value.(*ast.Ident); ok {
+				s.ranges[value.Name] = n.X
+			}
+		case *ast.ValueSpec:
+			for i, name := range n.Names {
+				if i < len(n.Values) {
+					assign(name.Name, n.Values[i])
+				} else if n.Type != nil {
+					s.types[name.Name] = n.Type
+				}
+			}
+		}
+		return true
+	})
+	return s
+}
+
+// localType returns the package local type of a variable, from its declaration or from the literal assigned to it.
+func (s *scope) localType(name string) string {
+	if t, found := s.types[name]; found {
+		return typeName(t)
+	}
+	expr := s.env[name]
+	if unary, ok := expr.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+		expr = unary.X
+	}
+	if literal, ok := expr.(*ast.CompositeLit); ok {
+		return typeName(literal.Type)
+	}
+	return ""
+}
+
+// importPath returns the package an identifier refers to, "" for identifiers which are not imports.
+func (s *scope) importPath(name string) string {
+	if _, local := s.env[name]; local {
+		return ""
+	}
+	if _, local := s.types[name]; local {
+		return ""
+	}
+	return s.file.imports[name]
+}
+
+// flatten returns the selectors and calls of a chain like kubeClient.CoreV1().ConfigMaps(ns).Get, with the local
+// variables replaced by the expression assigned to them.
+func (s *scope) flatten(expr ast.Expr, depth int) []segment {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		if assigned, found := s.env[expr.Name]; found && depth < 10 {
+			return s.flatten(assigned, depth+1)
+		}
+		return []segment{{name: expr.Name, root: true}}
+	case *ast.SelectorExpr:
+		return append(s.flatten(expr.X, depth), segment{name: expr.Sel.Name})
+	case *ast.CallExpr:
+		switch fun := expr.Fun.(type) {
+		case *ast.SelectorExpr:
+			return append(s.flatten(fun.X, depth), segment{name: fun.Sel.Name, call: true, args: expr.Args})
+		case *ast.Ident:
+			return []segment{{name: fun.Name, call: true, args: expr.Args, root: true}}
+		}
+	case *ast.ParenExpr:
+		return s.flatten(expr.X, depth)
+	case *ast.StarExpr:
+		return s.flatten(expr.X, depth)
+	case *ast.UnaryExpr:
+		return s.flatten(expr.X, depth)
+	}
+	return []segment{{name: types.ExprString(expr), root: true}}
+}
+
+// stringValue resolves string literals and constants.
+func (s *scope) stringValue(expr ast.Expr, depth int) (string, bool) {
+	if depth > 10 {
+		return "", false
+	}
+	switch expr := expr.(type) {
+	case *ast.BasicLit:
+		if expr.Kind == token.STRING {
+			ret, err := strconv.Unquote(expr.Value)
+			return ret, err == nil
+		}
+	case *ast.ParenExpr:
+		return s.stringValue(expr.X, depth+1)
+	case *ast.BinaryExpr:
+		if expr.Op == token.ADD {
+			x, xFound := s.stringValue(expr.X, depth+1)
+			y, yFound := s.stringValue(expr.Y, depth+1)
+			return x + y, xFound && yFound
+		}
+	case *ast.Ident:
+		if assigned, found := s.env[expr.Name]; found {
+			return s.stringValue(assigned, depth+1)
+		}
+		if _, found := s.types[expr.Name]; found {
+			return "", false
+		}
+		if v, found := s.file.pkg.values[expr.Name]; found {
+			return (&scope{a: s.a, file: v.file}).stringValue(v.expr, depth+1)
+		}
+	case *ast.SelectorExpr:
+		base, ok := expr.X.(*ast.Ident)
+		if !ok {
+			return "", false
+		}
+		importPath := s.importPath(base.Name)
+		if len(importPath) == 0 {
+			return "", false
+		}
+		if value, found := wellKnownStrings[expr.Sel.Name]; found {
+			return value, true
+		}
+		if strings.HasPrefix(expr.Sel.Name, "OperatorStatusType") {
+			return strings.TrimPrefix(expr.Sel.Name, "OperatorStatusType"), true
+		}
+		if pkg, found := s.a.packages[importPath]; found {
+			if v, found := pkg.values[expr.Sel.Name]; found {
+				return (&scope{a: s.a, file: v.file}).stringValue(v.expr, depth+1)
+			}
+		}
+	}
+	return "", false
+}
+
+// controllerNames returns the names of the controllers the function builds.
+func (a *analyzer) controllerNames(fn *funcDecl) []string {
+	s := a.newScope(fn)
+	names := []string{}
+	ast.Inspect(fn.decl.Body, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok && len(call.Args) > 0 {
+			if selector, ok := call.Fun.(*ast.SelectorExpr); ok && selector.Sel.Name == "ToController" {
+				if name, found := s.stringValue(call.Args[0], 0); found {
+					names = append(names, name)
+				}
+			}
+		}
+		return true
+	})
+	return names
+}
+
+func (a *analyzer) analyzeController(constructor *funcDecl, name string) *Controller {
+	s := a.newScope(constructor)
+	informers := map[string]Resource{}
+	ast.Inspect(constructor.decl.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		selector, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		args := []ast.Expr{}
+		switch selector.Sel.Name {
+		case "WithInformers", "WithBareInformers":
+			args = call.Args
+		case "WithFilteredEventsInformers":
+			if len(call.Args) > 0 {
+				args = call.Args[1:]
+			}
+		case "WithNamespaceInformer":
+			if len(call.Args) > 0 {
+				args = call.Args[:1]
+			}
+		}
+		if call.Ellipsis.IsValid() && len(args) > 0 {
+			args = append(args[:len(args)-1], s.elements(args[len(args)-1])...)
+		}
+		for _, arg := range args {
+			for _, resource := range s.informerResources(arg) {
+				informers[resource.String()] = resource
+			}
+		}
+		return true
+	})
+
+	reads, writes, conditions := map[string]Resource{}, map[string]Resource{}, map[string]bool{}
+	for _, fn := range a.reachable(constructor) {
+		a.collect(fn, reads, writes, conditions)
+	}
+	return &Controller{
+		Name:        name,
+		Constructor: constructor.file.pkg.rel + "." + constructor.decl.Name.Name,
+		Informers:   sortedResources(informers),
+		Reads:       sortedResources(reads),
+		Writes:      sortedResources(writes),
+		Conditions:  sortedKeys(conditions),
+	}
+}
+
+// reachable returns the functions and methods of the package the function refers to, directly or through others.
+// Methods are resolved by the type of their receiver where it is known, by their name otherwise.
+func (a *analyzer) reachable(root *funcDecl) []*funcDecl {
+	pkg := root.file.pkg
+	seen := map[*funcDecl]bool{root: true}
+	ret := []*funcDecl{root}
+	for i := 0; i < len(ret); i++ {
+		fn := ret[i]
+		if fn.decl.Body == nil {
+			continue
+		}
+		s := a.newScope(fn)
+		add := func(candidates []*funcDecl) {
+			for _, candidate := range candidates {
+				if !seen[candidate] {
+					seen[candidate] = true
+					ret = append(ret, candidate)
+				}
+			}
+		}
+		var visit func(n ast.Node) bool
+		visit = func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.SelectorExpr:
+				if base, ok := n.X.(*ast.Ident); ok {
+					switch {
+					case len(s.importPath(base.Name)) > 0:
+					case len(s.localType(base.Name)) > 0:
+						add(pkg.funcs[s.localType(base.Name)+"."+n.Sel.Name])
+					default:
+						add(pkg.methods[n.Sel.Name])
+					}
+					return false
+				}
+				ast.Inspect(n.X, visit)
+				return false
+			case *ast.Ident:
+				if _, local := s.env[n.Name]; !local {
+					add(pkg.funcs[n.Name])
+				}
+			}
+			return true
+		}
+		ast.Inspect(fn.decl.Body, visit)
+	}
+	return ret
+}
+
+// collect adds the live reads, the writes and the conditions of the function.
+func (a *analyzer) collect(fn *funcDecl, reads, writes map[string]Resource, conditions map[string]bool) {
+	if fn.decl.Body == nil {
+		return
+	}
+	s := a.newScope(fn)
+	addCondition := func(expr ast.Expr) {
+		if condition, found := s.stringValue(expr, 0); found && len(condition) > 0 {
+			conditions[condition] = true
+		}
+	}
+	conditionLiteral := func(literal *ast.CompositeLit) {
+		for _, elt := range literal.Elts {
+			if kv, ok := elt.(*ast.KeyValueExpr); ok {
+				if key, ok := kv.Key.(*ast.Ident); ok && key.Name == "Type" {
+					addCondition(kv.Value)
+				}
+			}
+		}
+	}
+	ast.Inspect(fn.decl.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.CompositeLit:
+			switch t := n.Type.(type) {
+			case *ast.SelectorExpr:
+				if t.Sel.Name == "OperatorCondition" {
+					conditionLiteral(n)
+				}
+			case *ast.ArrayType:
+				if elt, ok := t.Elt.(*ast.SelectorExpr); ok && elt.Sel.Name == "OperatorCondition" {
+					for _, element := range n.Elts {
+						if literal, ok := element.(*ast.CompositeLit); ok && literal.Type == nil {
+							conditionLiteral(literal)
+						}
+					}
+				}
+			}
+			// the condition types of tables like the error budgets
+			for _, elt := range n.Elts {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					if key, ok := kv.Key.(*ast.Ident); ok && key.Name == "conditionType" {
+						addCondition(kv.Value)
+					}
+				}
+			}
+		case *ast.CallExpr:
+			segments := s.flatten(n, 0)
+			verb := segments[len(segments)-1]
+			receiver := segments[:len(segments)-1]
+			importPath := ""
+			if len(receiver) == 1 && receiver[0].root {
+				importPath = s.importPath(receiver[0].name)
+			}
+			switch {
+			case strings.HasSuffix(importPath, "/resourceapply"):
+				for _, prefix := range []string{"Apply", "Delete", "Sync"} {
+					if kind := strings.TrimPrefix(verb.name, prefix); kind != verb.name && len(kind) > 0 {
+						resource := Resource{Group: groupNames["core"], Resource: plural(kind)}
+						writes[resource.String()] = resource
+					}
+				}
+			case strings.HasSuffix(importPath, "/v1helpers"):
+				switch verb.name {
+				case "UpdateStatus", "UpdateStaticPodStatus":
+					resource := operatorResource
+					resource.Subresource = "status"
+					writes[resource.String()] = resource
+				case "UpdateSpec", "UpdateStaticPodSpec":
+					writes[operatorResource.String()] = operatorResource
+				}
+			case len(importPath) > 0 || len(receiver) == 0:
+			case writeVerbs[verb.name]:
+				resources, found := s.resourceOfChain(receiver)
+				if !found && verb.name == "UpdateStatus" {
+					// the status updaters of the operator
+					resources = []Resource{operatorResource}
+				}
+				for _, resource := range resources {
+					if verb.name == "UpdateStatus" {
+						resource.Subresource = "status"
+					}
+					writes[resource.String()] = resource
+				}
+			case verb.name == "UpdateOperatorSpec" || verb.name == "UpdateStaticPodOperatorSpec":
+				writes[operatorResource.String()] = operatorResource
+			case verb.name == "UpdateOperatorStatus" || verb.name == "UpdateStaticPodOperatorStatus":
+				resource := operatorResource
+				resource.Subresource = "status"
+				writes[resource.String()] = resource
+			case verb.name == "Put" && isStateStore(receiver[len(receiver)-1].name):
+				writes[stateStoreResource.String()] = stateStoreResource
+			case (verb.name == "Get" || verb.name == "List") && len(verb.args) >= 2 && !fromLister(receiver):
+				// the clients take a context and options, the listers do not
+				resources, _ := s.resourceOfChain(receiver)
+				for _, resource := range resources {
+					reads[resource.String()] = resource
+				}
+			}
+		}
+		return true
+	})
+}
+
+// elements returns the elements of a slice variable, from the literals and appends assigned to it.
+func (s *scope) elements(slice ast.Expr) []ast.Expr {
+	ident, ok := slice.(*ast.Ident)
+	if !ok {
+		return []ast.Expr{slice}
+	}
+	ret := []ast.Expr{}
+	for _, assigned := range s.assignments[ident.Name] {
+		switch assigned := assigned.(type) {
+		case *ast.CompositeLit:
+			ret = append(ret, assigned.Elts...)
+		case *ast.CallExpr:
+			if fun, ok := assigned.Fun.(*ast.Ident); ok && fun.Name == "append" && len(assigned.Args) > 1 && !assigned.Ellipsis.IsValid() {
+				ret = append(ret, assigned.Args[1:]...)
+			}
+		}
+	}
+	return ret
+}
+
+// informerResources returns the resources behind an informer, informers built in a loop over namespaces have one
+// per namespace.
+func (s *scope) informerResources(expr ast.Expr) []Resource {
+	segments := s.flatten(expr, 0)
+	if last := segments[len(segments)-1]; len(segments) > 1 && last.call && last.name == "Informer" {
+		segments = segments[:len(segments)-1]
+		if call, ok := expr.(*ast.CallExpr); ok {
+			expr = call.Fun.(*ast.SelectorExpr).X
+		}
+	}
+	if resources, found := s.resourceOfChain(segments); found {
+		return resources
+	}
+	last := segments[len(segments)-1]
+	switch {
+	case isOperatorClient(last.name):
+		return []Resource{operatorResource}
+	case isStateStore(last.name):
+		return []Resource{stateStoreResource}
+	}
+	if t, found := s.types[last.name]; found && last.root {
+		if resource, found := s.resourceOfType(t); found {
+			return []Resource{resource}
+		}
+	}
+	return []Resource{{Resource: types.ExprString(expr)}}
+}
+
+// resourceOfChain returns the resource of an informer factory or client chain, like
+// kubeInformersForNamespaces.InformersFor(ns).Core().V1().ConfigMaps() or kubeClient.CoreV1().ConfigMaps(ns), once
+// for every namespace the chain is built for.
+func (s *scope) resourceOfChain(segments []segment) ([]Resource, bool) {
+	resource := Resource{}
+	var namespaces []string
+	for i, segment := range segments {
+		switch {
+		case segment.call && segment.name == "InformersFor" && len(segment.args) == 1:
+			namespaces = s.namespaces(segment.args[0])
+		case segment.call && segment.name == "Namespace" && len(segment.args) == 1 && i > 0:
+			namespaces = s.namespaces(segment.args[0])
+		case segment.call && segment.name == "Resource" && len(segment.args) == 1 && i > 0:
+			// dynamic clients
+			if gvr, found := s.groupVersionResource(segment.args[0]); found {
+				resource.Group, resource.Resource = gvr.Group, gvr.Resource
+			} else {
+				resource.Resource = types.ExprString(segment.args[0])
+			}
+		}
+	}
+	if len(resource.Resource) == 0 {
+		n := len(segments)
+		last := segments[n-1]
+		if !last.call || last.root || !ast.IsExported(last.name) || !strings.HasSuffix(last.name, "s") || len(last.args) > 1 {
+			return nil, false
+		}
+		resource.Resource = strings.ToLower(last.name)
+		if len(last.args) == 1 {
+			namespaces = s.namespaces(last.args[0])
+		}
+		switch {
+		case n >= 2 && clientGroupVersion.MatchString(segments[n-2].name):
+			resource.Group = groupName(clientGroupVersion.FindStringSubmatch(segments[n-2].name)[1])
+		case n >= 3 && version.MatchString(segments[n-2].name):
+			resource.Group = groupName(segments[n-3].name)
+		}
+	}
+
+	if namespaces == nil {
+		return []Resource{resource}, true
+	}
+	ret := []Resource{}
+	for _, namespace := range namespaces {
+		resource.Namespace = namespace
+		ret = append(ret, resource)
+	}
+	return ret, true
+}
+
+// resourceOfType returns the resource of a typed informer or operator client parameter.
+func (s *scope) resourceOfType(t ast.Expr) (Resource, bool) {
+	if star, ok := t.(*ast.StarExpr); ok {
+		t = star.X
+	}
+	selector, ok := t.(*ast.SelectorExpr)
+	if !ok {
+		return Resource{}, false
+	}
+	base, ok := selector.X.(*ast.Ident)
+	if !ok {
+		return Resource{}, false
+	}
+	switch name := selector.Sel.Name; {
+	case strings.HasSuffix(name, "OperatorClient"):
+		return operatorResource, true
+	case name == "Store" && strings.HasSuffix(s.file.imports[base.Name], "/statestore"):
+		return stateStoreResource, true
+	case strings.HasSuffix(name, "Informer") && name != "Informer" && name != "GenericInformer" && name != "SharedIndexInformer":
+		// the typed informers are in packages named by their group and version, like informers/core/v1
+		importPath := s.file.imports[base.Name]
+		return Resource{
+			Group:    groupName(path.Base(path.Dir(importPath))),
+			Resource: plural(strings.TrimSuffix(name, "Informer")),
+		}, true
+	}
+	return Resource{}, false
+}
+
+// groupVersionResource resolves a schema.GroupVersionResource variable of the package.
+func (s *scope) groupVersionResource(expr ast.Expr) (Resource, bool) {
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return Resource{}, false
+	}
+	v, found := s.file.pkg.values[ident.Name]
+	if !found {
+		return Resource{}, false
+	}
+	literal, ok := v.expr.(*ast.CompositeLit)
+	if !ok {
+		return Resource{}, false
+	}
+	resolver := &scope{a: s.a, file: v.file}
+	ret := Resource{}
+	for _, elt := range literal.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		key, _ := kv.Key.(*ast.Ident)
+		value, _ := resolver.stringValue(kv.Value, 0)
+		switch {
+		case key == nil:
+		case key.Name == "Group":
+			ret.Group = value
+		case key.Name == "Resource":
+			ret.Resource = value
+		}
+	}
+	return ret, len(ret.Resource) > 0
+}
+
+// namespaces resolves a namespace argument, the values of loops over constant lists to all of the list. Arguments
+// which are not constants are kept as they are written.
+func (s *scope) namespaces(expr ast.Expr) []string {
+	if value, found := s.stringValue(expr, 0); found {
+		return []string{value}
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		if list, found := s.stringList(s.ranges[ident.Name]); found {
+			return list
+		}
+	}
+	return []string{types.ExprString(expr)}
+}
+
+// stringList resolves a slice literal of constants, local or of a package.
+func (s *scope) stringList(expr ast.Expr) ([]string, bool) {
+	resolver := s
+	switch e := expr.(type) {
+	case *ast.Ident:
+		switch v, found := s.file.pkg.values[e.Name]; {
+		case s.env[e.Name] != nil:
+			expr = s.env[e.Name]
+		case found:
+			resolver, expr = &scope{a: s.a, file: v.file}, v.expr
+		}
+	case *ast.SelectorExpr:
+		if base, ok := e.X.(*ast.Ident); ok {
+			if pkg, found := s.a.packages[s.importPath(base.Name)]; found {
+				if v, found := pkg.values[e.Sel.Name]; found {
+					resolver, expr = &scope{a: s.a, file: v.file}, v.expr
+				}
+			}
+		}
+	}
+	literal, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return nil, false
+	}
+	ret := []string{}
+	for _, elt := range literal.Elts {
+		value, found := resolver.stringValue(elt, 0)
+		if !found {
+			return nil, false
+		}
+		ret = append(ret, value)
+	}
+	return ret, true
+}
+
+type startedController struct {
+	variable    string
+	constructor string
+}
+
+// startedControllers returns the controllers the operator starts with go controller.Run or go controller.Start, and
+// the constructors they are built with.
+func (a *analyzer) startedControllers(pkg *goPackage) []startedController {
+	ret := []startedController{}
+	for _, fns := range pkg.funcs {
+		for _, fn := range fns {
+			if fn.decl.Body == nil {
+				continue
+			}
+			s := a.newScope(fn)
+			ast.Inspect(fn.decl.Body, func(n ast.Node) bool {
+				statement, ok := n.(*ast.GoStmt)
+				if !ok {
+					return true
+				}
+				selector, ok := statement.Call.Fun.(*ast.SelectorExpr)
+				if !ok || (selector.Sel.Name != "Run" && selector.Sel.Name != "Start") {
+					return true
+				}
+				variable, ok := selector.X.(*ast.Ident)
+				if !ok {
+					return true
+				}
+				// the constructor is the call the chain assigned to the variable starts with
+				segments := s.flatten(variable, 0)
+				if len(segments) < 2 || !segments[1].call {
+					return true
+				}
+				if importPath := s.importPath(segments[0].name); len(importPath) > 0 {
+					ret = append(ret, startedController{variable: variable.Name, constructor: importPath + "." + segments[1].name})
+				}
+				return true
+			})
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].variable < ret[j].variable })
+	return ret
+}
+
+func groupName(name string) string {
+	if group, found := groupNames[strings.ToLower(name)]; found {
+		return group
+	}
+	return strings.ToLower(name)
+}
+
+func plural(kind string) string {
+	lower := strings.ToLower(kind)
+	switch {
+	case strings.HasSuffix(lower, "s"):
+		return lower
+	case strings.HasSuffix(lower, "y"):
+		return strings.TrimSuffix(lower, "y") + "ies"
+	}
+	return lower + "s"
+}
+
+func isOperatorClient(name string) bool {
+	return strings.HasSuffix(name, "operatorClient") || strings.HasSuffix(name, "OperatorClient")
+}
+
+func isStateStore(name string) bool {
+	return strings.HasSuffix(strings.ToLower(name), "store")
+}
+
+func fromLister(segments []segment) bool {
+	for _, segment := range segments {
+		if strings.Contains(strings.ToLower(segment.name), "lister") {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedResources(resources map[string]Resource) []Resource {
+	ret := []Resource{}
+	for _, key := range sortedKeys(resourceKeys(resources)) {
+		ret = append(ret, resources[key])
+	}
+	return ret
+}
+
+func resourceKeys(resources map[string]Resource) map[string]bool {
+	ret := map[string]bool{}
+	for key := range resources {
+		ret[key] = true
+	}
+	return ret
+}
@@ -0,0 +1,134 @@
+package controllergraph
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// graphJSON is the output of Analyze for this tree, regenerated with go generate and checked by the unit tests.
+//
+//go:generate go run -mod=vendor ./generate
+//go:embed controllergraph.json
+var graphJSON []byte
+
+// Graph describes the controllers of the operator, the resources they watch, read and write, and the operator
+// conditions they own.
+type Graph struct {
+	Controllers []Controller `json:"controllers"`
+}
+
+// Controller is one controller of the operator.
+type Controller struct {
+	Name string `json:"name"`
+	// Constructor is the function building the controller, relative to the module for the controllers of this
+	// repository.
+	Constructor string `json:"constructor"`
+	// External controllers are implemented in library-go, the graph does not look into them.
+	External bool `json:"external,omitempty"`
+	// Started is true for the controllers the operator starts, the others are started by another controller.
+	Started bool `json:"started"`
+
+	// Informers are the resources whose changes queue the controller.
+	Informers []Resource `json:"informers,omitempty"`
+	// Reads are the resources the controller gets or lists from the API server directly, not from an informer.
+	Reads []Resource `json:"reads,omitempty"`
+	// Writes are the resources the controller creates, updates, patches or deletes.
+	Writes []Resource `json:"writes,omitempty"`
+	// Conditions are the operator conditions the controller sets.
+	Conditions []string `json:"conditions,omitempty"`
+}
+
+// Resource is a kind of API resource, the namespace is empty for cluster scoped resources and for the ones the
+// controller uses in all namespaces. Resources the analysis could not resolve keep the expression they were built
+// from.
+type Resource struct {
+	Group       string `json:"group,omitempty"`
+	Resource    string `json:"resource"`
+	Subresource string `json:"subresource,omitempty"`
+	Namespace   string `json:"namespace,omitempty"`
+}
+
+func (r Resource) String() string {
+	ret := r.Resource
+	if len(r.Group) > 0 {
+		ret += "." + r.Group
+	}
+	if len(r.Subresource) > 0 {
+		ret += "/" + r.Subresource
+	}
+	if len(r.Namespace) > 0 {
+		ret += " in " + r.Namespace
+	}
+	return ret
+}
+
+// Load returns the graph of this build of the operator.
+func Load() (*Graph, error) {
+	graph := &Graph{}
+	if err := json.Unmarshal(graphJSON, graph); err != nil {
+		return nil, fmt.Errorf("invalid embedded controller graph: %v", err)
+	}
+	return graph, nil
+}
+
+// Marshal returns the graph as indented JSON.
+func (g *Graph) Marshal() ([]byte, error) {
+	data, err := json.MarshalIndent(g, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// Dot returns the graph in the graphviz dot format, with the resources flowing into the controllers watching them
+// and out of the controllers writing them.
+func (g *Graph) Dot() string {
+	b := &strings.Builder{}
+	b.WriteString("digraph \"kube-controller-manager-operator\" {\n  rankdir=LR;\n")
+
+	resources := map[string]bool{}
+	conditions := map[string]bool{}
+	for _, controller := range g.Controllers {
+		style := "solid"
+		if controller.External {
+			style = "dashed"
+		}
+		fmt.Fprintf(b, "  %q [shape=box, style=%s];\n", controller.Name, style)
+		for _, resource := range controller.Informers {
+			resources[resource.String()] = true
+			fmt.Fprintf(b, "  %q -> %q [label=\"watch\"];\n", resource.String(), controller.Name)
+		}
+		for _, resource := range controller.Reads {
+			resources[resource.String()] = true
+			fmt.Fprintf(b, "  %q -> %q [label=\"read\", style=dashed];\n", resource.String(), controller.Name)
+		}
+		for _, resource := range controller.Writes {
+			resources[resource.String()] = true
+			fmt.Fprintf(b, "  %q -> %q [label=\"write\"];\n", controller.Name, resource.String())
+		}
+		for _, condition := range controller.Conditions {
+			conditions[condition] = true
+			fmt.Fprintf(b, "  %q -> %q [label=\"condition\", style=dotted];\n", controller.Name, "condition "+condition)
+		}
+	}
+	for _, name := range sortedKeys(resources) {
+		fmt.Fprintf(b, "  %q [shape=ellipse];\n", name)
+	}
+	for _, name := range sortedKeys(conditions) {
+		fmt.Fprintf(b, "  %q [shape=note];\n", "condition "+name)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func sortedKeys(m map[string]bool) []string {
+	ret := make([]string, 0, len(m))
+	for key := range m {
+		ret = append(ret, key)
+	}
+	sort.Strings(ret)
+	return ret
+}
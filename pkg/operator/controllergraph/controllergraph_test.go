@@ -0,0 +1,135 @@
+package controllergraph
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestGraphUpToDate(t *testing.T) {
+	graph, err := Analyze("..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := graph.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, graphJSON) {
+		t.Errorf("controllergraph.json is out of date, run go generate ./pkg/operator/controllergraph")
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/operator\n",
+		"pkg/operator/operatorclient/names.go": `package operatorclient
+
+const TargetNamespace = "openshift-kube-controller-manager"
+`,
+		"pkg/operator/starter.go": `package operator
+
+import (
+	"example.com/operator/pkg/operator/examplecontroller"
+	"github.com/openshift/library-go/pkg/operator/staleconditions"
+)
+
+func RunOperator() {
+	exampleController := examplecontroller.NewExampleController(operatorClient, statusUpdater, kubeInformersForNamespaces, kubeClient, recorder)
+	staleConditions := staleconditions.NewRemoveStaleConditionsController(nil, operatorClient, recorder)
+	go exampleController.Run(ctx, 1)
+	go staleConditions.Run(ctx, 1)
+}
+`,
+		"pkg/operator/examplecontroller/examplecontroller.go": `package examplecontroller
+
+import (
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"example.com/operator/pkg/operator/operatorclient"
+)
+
+const conditionType = "ExampleDegraded"
+
+var namespaces = []string{operatorclient.TargetNamespace, "openshift-config"}
+
+type ExampleController struct{}
+
+func NewExampleController(operatorClient v1helpers.OperatorClient, statusUpdater StatusUpdater, kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces, kubeClient kubernetes.Interface, recorder events.Recorder) factory.Controller {
+	c := &ExampleController{}
+	informers := []factory.Informer{operatorClient.Informer()}
+	for _, ns := range namespaces {
+		informers = append(informers, kubeInformersForNamespaces.InformersFor(ns).Core().V1().Secrets().Informer())
+	}
+	return factory.New().WithInformers(informers...).WithSync(c.sync).ToController("ExampleController", recorder)
+}
+
+func (c *ExampleController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	if _, err := c.kubeClient.CoreV1().Pods(operatorclient.TargetNamespace).List(ctx, metav1.ListOptions{}); err != nil {
+		return err
+	}
+	if err := c.cleanup(ctx); err != nil {
+		return err
+	}
+	return c.statusUpdater.UpdateStatus(ctx, v1helpers.UpdateConditionFn(operatorv1.OperatorCondition{Type: conditionType}))
+}
+
+func (c *ExampleController) cleanup(ctx context.Context) error {
+	return c.kubeClient.CoreV1().ConfigMaps(operatorclient.TargetNamespace).Delete(ctx, "stale", metav1.DeleteOptions{})
+}
+
+// unused is not reachable from the constructor
+func unused(ctx context.Context, kubeClient kubernetes.Interface) error {
+	return kubeClient.CoreV1().Secrets("other").Delete(ctx, "unused", metav1.DeleteOptions{})
+}
+`,
+	}
+	for name, content := range files {
+		filename := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filename, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	graph, err := Analyze(filepath.Join(root, "pkg", "operator"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &Graph{Controllers: []Controller{
+		{
+			Name:        "ExampleController",
+			Constructor: "pkg/operator/examplecontroller.NewExampleController",
+			Started:     true,
+			Informers: []Resource{
+				{Group: "operator.openshift.io", Resource: "kubecontrollermanagers"},
+				{Resource: "secrets", Namespace: "openshift-config"},
+				{Resource: "secrets", Namespace: "openshift-kube-controller-manager"},
+			},
+			Reads: []Resource{{Resource: "pods", Namespace: "openshift-kube-controller-manager"}},
+			Writes: []Resource{
+				{Resource: "configmaps", Namespace: "openshift-kube-controller-manager"},
+				{Group: "operator.openshift.io", Resource: "kubecontrollermanagers", Subresource: "status"},
+			},
+			Conditions: []string{"ExampleDegraded"},
+		},
+		{
+			Name:        "StaleConditions",
+			Constructor: "library-go/pkg/operator/staleconditions.NewRemoveStaleConditionsController",
+			External:    true,
+			Started:     true,
+		},
+	}}
+	if !reflect.DeepEqual(graph, expected) {
+		expectedData, _ := expected.Marshal()
+		data, _ := graph.Marshal()
+		t.Errorf("expected\n%s\ngot\n%s", expectedData, data)
+	}
+}
@@ -0,0 +1,25 @@
+// generate writes the graph of the controllers of the operator to controllergraph.json, it runs in the
+// controllergraph package through go generate.
+package main
+
+import (
+	"io/ioutil"
+
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/controllergraph"
+)
+
+func main() {
+	graph, err := controllergraph.Analyze("..")
+	if err != nil {
+		klog.Fatal(err)
+	}
+	data, err := graph.Marshal()
+	if err != nil {
+		klog.Fatal(err)
+	}
+	if err := ioutil.WriteFile("controllergraph.json", data, 0644); err != nil {
+		klog.Fatal(err)
+	}
+}
@@ -0,0 +1,211 @@
+package controllerpause
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/component-base/metrics"
+	"k8s.io/klog/v2"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operationprofile"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatormetrics"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
+)
+
+// AnnotationName on the kubecontrollermanager resource pauses individual controllers of the operator, for incidents
+// where one of them misbehaves and setting the whole operator Unmanaged would stop the rollouts too. It holds a JSON
+// object mapping the controllers to the time their pause expires and the reason for it:
+//
+//	kube-controller-manager.openshift.io/pause-controllers: '{"ErrorBudgetController":{"until":"2026-10-16T08:00:00Z","reason":"OCPBUGS-1234 scrapes time out"}}'
+//
+// Only the controllers whose sync is wrapped with Pausable can be paused. A pause ends on its own at the given time,
+// which may be at most MaxPause ahead.
+const AnnotationName = "kube-controller-manager.openshift.io/pause-controllers"
+
+// MaxPause bounds how far ahead a pause may expire, a forgotten pause must not leave a controller off for long.
+const MaxPause = 24 * time.Hour
+
+const conditionType = "ControllersPaused"
+
+var controllerPaused = metrics.NewGaugeVec(
+	&metrics.GaugeOpts{
+		Name:           "kcm_operator_controller_paused",
+		Help:           "1 for the operator controllers paused by the pause annotation, 0 otherwise.",
+		StabilityLevel: metrics.ALPHA,
+	},
+	[]string{"controller"},
+)
+
+func init() {
+	operatormetrics.MustRegister(controllerPaused)
+}
+
+type pause struct {
+	Until  time.Time `json:"until"`
+	Reason string    `json:"reason"`
+}
+
+var (
+	lock sync.RWMutex
+	// pausable is the allow list of the controllers which may be paused
+	pausable = map[string]bool{}
+	// paused maps the paused controllers to their pause
+	paused = map[string]pause{}
+
+	now = time.Now
+)
+
+// Pausable wraps the sync of the controller to skip it while the controller is paused, and allows it to be paused.
+// Controllers the rollout of kube-controller-manager depends on must not be wrapped.
+func Pausable(controller string, sync factory.SyncFunc) factory.SyncFunc {
+	lock.Lock()
+	pausable[controller] = true
+	lock.Unlock()
+	return func(ctx context.Context, syncCtx factory.SyncContext) error {
+		lock.RLock()
+		current, found := paused[controller]
+		lock.RUnlock()
+		if found && current.Until.After(now()) {
+			klog.V(2).Infof("%s sync skipped, the controller is paused until %s: %s", controller, current.Until.UTC().Format(time.RFC3339), current.Reason)
+			return nil
+		}
+		return sync(ctx, syncCtx)
+	}
+}
+
+// pausableControllers returns the allow list, sorted.
+func pausableControllers() []string {
+	lock.RLock()
+	defer lock.RUnlock()
+	ret := make([]string, 0, len(pausable))
+	for controller := range pausable {
+		ret = append(ret, controller)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// parse returns the pauses of the annotation. An annotation with an invalid entry pauses nothing.
+func parse(annotation string) (map[string]pause, error) {
+	ret := map[string]pause{}
+	if err := json.Unmarshal([]byte(annotation), &ret); err != nil {
+		return nil, fmt.Errorf("%s: %v", AnnotationName, err)
+	}
+	controllers := make([]string, 0, len(ret))
+	for controller := range ret {
+		controllers = append(controllers, controller)
+	}
+	sort.Strings(controllers)
+	for _, controller := range controllers {
+		current := ret[controller]
+		lock.RLock()
+		allowed := pausable[controller]
+		lock.RUnlock()
+		switch {
+		case !allowed:
+			return nil, fmt.Errorf("%s: %s cannot be paused, the controllers which can are %s", AnnotationName, controller, strings.Join(pausableControllers(), ", "))
+		case len(strings.TrimSpace(current.Reason)) == 0:
+			return nil, fmt.Errorf("%s: a reason is required to pause %s", AnnotationName, controller)
+		case current.Until.After(now().Add(MaxPause)):
+			return nil, fmt.Errorf("%s: the pause of %s expires at %s, more than %s ahead", AnnotationName, controller, current.Until.UTC().Format(time.RFC3339), MaxPause)
+		}
+	}
+	return ret, nil
+}
+
+type pauseController struct {
+	operatorClient v1helpers.OperatorClient
+	statusUpdater  statusbatcher.StatusUpdater
+}
+
+// NewPauseController applies the pause annotation to the pausable controllers, and reports the paused ones in the
+// ControllersPaused condition, with events when they are paused and resumed.
+func NewPauseController(
+	operatorClient v1helpers.OperatorClient,
+	statusUpdater statusbatcher.StatusUpdater,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &pauseController{
+		operatorClient: operatorClient,
+		statusUpdater:  statusUpdater,
+	}
+	return factory.New().WithInformers(operatorClient.Informer()).ResyncEvery(operationprofile.Resync(time.Minute)).WithSync(syncmetrics.Timed("PauseController", c.sync)).ToController("PauseController", eventRecorder)
+}
+
+func (c *pauseController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	condition := operatorv1.OperatorCondition{
+		Type:   conditionType,
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+	meta, err := c.operatorClient.GetObjectMeta()
+	if err != nil {
+		return err
+	}
+
+	active := map[string]pause{}
+	expired := []string{}
+	if annotation, found := meta.Annotations[AnnotationName]; found {
+		pauses, err := parse(annotation)
+		if err != nil {
+			condition.Reason = "InvalidPause"
+			condition.Message = fmt.Sprintf("no controller is paused: %v", err)
+		}
+		for controller, current := range pauses {
+			if current.Until.After(now()) {
+				active[controller] = current
+			} else {
+				expired = append(expired, controller)
+			}
+		}
+	}
+
+	messages := []string{}
+	for _, controller := range pausableControllers() {
+		current, found := active[controller]
+		lock.RLock()
+		_, wasPaused := paused[controller]
+		lock.RUnlock()
+		switch {
+		case found && !wasPaused:
+			syncCtx.Recorder().Warningf("ControllerPaused", "%s is paused until %s: %s", controller, current.Until.UTC().Format(time.RFC3339), current.Reason)
+		case !found && wasPaused:
+			syncCtx.Recorder().Warningf("ControllerResumed", "%s is no longer paused", controller)
+		}
+		if found {
+			controllerPaused.WithLabelValues(controller).Set(1)
+			messages = append(messages, fmt.Sprintf("%s is paused until %s: %s", controller, current.Until.UTC().Format(time.RFC3339), current.Reason))
+		} else {
+			controllerPaused.WithLabelValues(controller).Set(0)
+		}
+	}
+	lock.Lock()
+	paused = active
+	lock.Unlock()
+
+	sort.Strings(expired)
+	switch {
+	case len(messages) > 0:
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "Paused"
+		condition.Message = strings.Join(messages, "; ")
+		if len(expired) > 0 {
+			condition.Message += fmt.Sprintf(". The pause of %s expired", strings.Join(expired, ", "))
+		}
+	case len(expired) > 0:
+		condition.Reason = "PauseExpired"
+		condition.Message = fmt.Sprintf("the pause of %s expired, remove the %s annotation", strings.Join(expired, ", "), AnnotationName)
+	}
+	return c.statusUpdater.UpdateStatus(ctx, v1helpers.UpdateConditionFn(condition))
+}
@@ -0,0 +1,116 @@
+package controllerpause
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+)
+
+func TestPause(t *testing.T) {
+	defer func() {
+		now = time.Now
+		paused = map[string]pause{}
+	}()
+	now = func() time.Time { return time.Date(2026, 10, 15, 10, 0, 0, 0, time.UTC) }
+
+	syncs := 0
+	sync := Pausable("ErrorBudgetController", func(context.Context, factory.SyncContext) error {
+		syncs++
+		return nil
+	})
+	Pausable("LogAnomalyController", nil)
+
+	testCases := []struct {
+		name            string
+		annotation      string
+		expectedSyncs   int
+		expectedStatus  operatorv1.ConditionStatus
+		expectedReason  string
+		expectedMessage string
+	}{
+		{
+			name:           "no pause",
+			expectedSyncs:  1,
+			expectedStatus: operatorv1.ConditionFalse,
+			expectedReason: "AsExpected",
+		},
+		{
+			name:            "paused",
+			annotation:      `{"ErrorBudgetController":{"until":"2026-10-15T12:00:00Z","reason":"OCPBUGS-1234 scrapes time out"}}`,
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedReason:  "Paused",
+			expectedMessage: "ErrorBudgetController is paused until 2026-10-15T12:00:00Z: OCPBUGS-1234 scrapes time out",
+		},
+		{
+			name:            "expired",
+			annotation:      `{"ErrorBudgetController":{"until":"2026-10-15T09:00:00Z","reason":"OCPBUGS-1234 scrapes time out"}}`,
+			expectedSyncs:   1,
+			expectedStatus:  operatorv1.ConditionFalse,
+			expectedReason:  "PauseExpired",
+			expectedMessage: "the pause of ErrorBudgetController expired, remove the kube-controller-manager.openshift.io/pause-controllers annotation",
+		},
+		{
+			name:            "not pausable",
+			annotation:      `{"ErrorBudgetController":{"until":"2026-10-15T12:00:00Z","reason":"OCPBUGS-1234"},"TargetConfigController":{"until":"2026-10-15T12:00:00Z","reason":"OCPBUGS-1234"}}`,
+			expectedSyncs:   1,
+			expectedStatus:  operatorv1.ConditionFalse,
+			expectedReason:  "InvalidPause",
+			expectedMessage: "no controller is paused: kube-controller-manager.openshift.io/pause-controllers: TargetConfigController cannot be paused, the controllers which can are ErrorBudgetController, LogAnomalyController",
+		},
+		{
+			name:            "too long",
+			annotation:      `{"ErrorBudgetController":{"until":"2026-10-20T10:00:00Z","reason":"OCPBUGS-1234"}}`,
+			expectedSyncs:   1,
+			expectedStatus:  operatorv1.ConditionFalse,
+			expectedReason:  "InvalidPause",
+			expectedMessage: "no controller is paused: kube-controller-manager.openshift.io/pause-controllers: the pause of ErrorBudgetController expires at 2026-10-20T10:00:00Z, more than 24h0m0s ahead",
+		},
+		{
+			name:            "no reason",
+			annotation:      `{"ErrorBudgetController":{"until":"2026-10-15T12:00:00Z"}}`,
+			expectedSyncs:   1,
+			expectedStatus:  operatorv1.ConditionFalse,
+			expectedReason:  "InvalidPause",
+			expectedMessage: "no controller is paused: kube-controller-manager.openshift.io/pause-controllers: a reason is required to pause ErrorBudgetController",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			meta := &metav1.ObjectMeta{Name: "cluster"}
+			if len(tc.annotation) > 0 {
+				meta.Annotations = map[string]string{AnnotationName: tc.annotation}
+			}
+			operatorClient := v1helpers.NewFakeOperatorClientWithObjectMeta(meta, &operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil)
+			c := &pauseController{operatorClient: operatorClient, statusUpdater: statusbatcher.NewDirectUpdater(operatorClient)}
+			if err := c.sync(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))); err != nil {
+				t.Fatal(err)
+			}
+
+			syncs = 0
+			if err := sync(context.TODO(), nil); err != nil {
+				t.Fatal(err)
+			}
+			if syncs != tc.expectedSyncs {
+				t.Errorf("expected %d syncs, got %d", tc.expectedSyncs, syncs)
+			}
+
+			_, status, _, _ := operatorClient.GetOperatorState()
+			condition := v1helpers.FindOperatorCondition(status.Conditions, conditionType)
+			if condition == nil {
+				t.Fatalf("missing condition %s", conditionType)
+			}
+			if condition.Status != tc.expectedStatus || condition.Reason != tc.expectedReason || condition.Message != tc.expectedMessage {
+				t.Errorf("expected %s %s %q, got %s %s %q", tc.expectedStatus, tc.expectedReason, tc.expectedMessage, condition.Status, condition.Reason, condition.Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,257 @@
+package controlplanecapacitycontroller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/component-base/metrics"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/controllerpause"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operationprofile"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatormetrics"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
+)
+
+const (
+	// conditionType is True while the control plane nodes and the operand namespace leave room for a rollout
+	conditionType = "ControlPlaneCapacity"
+
+	operandPodPrefix = "kube-controller-manager-"
+)
+
+// installerRequests are the requests of the installer and pruner pods of library-go, every rollout schedules them
+// to the control plane nodes.
+var installerRequests = corev1.ResourceList{
+	corev1.ResourceCPU:    resource.MustParse("150m"),
+	corev1.ResourceMemory: resource.MustParse("200M"),
+}
+
+var (
+	freeRequests = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Name:           "kcm_operator_control_plane_node_free_requests",
+			Help:           "Allocatable capacity of a control plane node not yet requested by its pods, in cores and bytes.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"node", "resource"},
+	)
+	operandRequests = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Name:           "kcm_operator_control_plane_node_operand_requests",
+			Help:           "Requests of the kube-controller-manager static pod on a control plane node, in cores and bytes.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"node", "resource"},
+	)
+)
+
+func init() {
+	operatormetrics.MustRegister(freeRequests, operandRequests)
+}
+
+// ControlPlaneCapacityController accounts the requests of the pods on the control plane nodes against their
+// allocatable capacity and the resource quotas of the operand namespace against the installer and pruner pods.
+// A node without room for an installer pod, or a quota without room for one, blocks the next rollout.
+type ControlPlaneCapacityController struct {
+	operatorClient v1helpers.StaticPodOperatorClient
+	statusUpdater  statusbatcher.StatusUpdater
+	nodeLister     corev1listers.NodeLister
+	quotaLister    corev1listers.ResourceQuotaLister
+	podsGetter     corev1client.PodsGetter
+}
+
+func NewControlPlaneCapacityController(
+	operatorClient v1helpers.StaticPodOperatorClient,
+	statusUpdater statusbatcher.StatusUpdater,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	podsGetter corev1client.PodsGetter,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &ControlPlaneCapacityController{
+		operatorClient: operatorClient,
+		statusUpdater:  statusUpdater,
+		nodeLister:     kubeInformersForNamespaces.InformersFor("").Core().V1().Nodes().Lister(),
+		quotaLister:    kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().ResourceQuotas().Lister(),
+		podsGetter:     podsGetter,
+	}
+
+	// the pods of the control plane nodes are listed on every sync rather than caching every pod of the cluster,
+	// hence the long resync
+	return factory.New().WithInformers(
+		operatorClient.Informer(),
+		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().ResourceQuotas().Informer(),
+	).ResyncEvery(operationprofile.Resync(5*time.Minute)).WithSync(syncmetrics.Timed("ControlPlaneCapacityController", controllerpause.Pausable("ControlPlaneCapacityController", c.sync))).ToController("ControlPlaneCapacityController", eventRecorder)
+}
+
+func (c *ControlPlaneCapacityController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	_, status, _, err := c.operatorClient.GetStaticPodOperatorState()
+	if err != nil {
+		return err
+	}
+
+	reasons := []string{}
+	messages := []string{}
+
+	oversubscribed := []string{}
+	for _, nodeStatus := range status.NodeStatuses {
+		node, err := c.nodeLister.Get(nodeStatus.NodeName)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		pods, err := c.podsGetter.Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+			FieldSelector: fields.OneTermEqualSelector("spec.nodeName", node.Name).String(),
+		})
+		if err != nil {
+			return err
+		}
+		if message := c.accountNode(node, pods.Items); len(message) > 0 {
+			oversubscribed = append(oversubscribed, message)
+		}
+	}
+	if len(oversubscribed) > 0 {
+		reasons = append(reasons, "NodesOversubscribed")
+		messages = append(messages, oversubscribed...)
+	}
+
+	quotas, err := c.quotaLister.ResourceQuotas(operatorclient.TargetNamespace).List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	exhausted := []string{}
+	for _, quota := range quotas {
+		if message := quotaShortage(quota); len(message) > 0 {
+			exhausted = append(exhausted, message)
+		}
+	}
+	if len(exhausted) > 0 {
+		sort.Strings(exhausted)
+		reasons = append(reasons, "QuotaExhausted")
+		messages = append(messages, exhausted...)
+	}
+
+	condition := operatorv1.OperatorCondition{
+		Type:   conditionType,
+		Status: operatorv1.ConditionTrue,
+		Reason: "AsExpected",
+	}
+	if len(reasons) > 0 {
+		condition.Status = operatorv1.ConditionFalse
+		condition.Reason = strings.Join(reasons, "And")
+		condition.Message = strings.Join(messages, "\n")
+	}
+	return c.statusUpdater.UpdateStatus(ctx, v1helpers.UpdateConditionFn(condition))
+}
+
+// accountNode records the free and operand requests of the node and describes why an installer pod does not fit.
+func (c *ControlPlaneCapacityController) accountNode(node *corev1.Node, pods []corev1.Pod) string {
+	requested := corev1.ResourceList{}
+	operand := corev1.ResourceList{}
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		addResources(requested, podRequests(pod))
+		if pod.Namespace == operatorclient.TargetNamespace && strings.HasPrefix(pod.Name, operandPodPrefix) {
+			addResources(operand, podRequests(pod))
+		}
+	}
+
+	shortages := []string{}
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		free := node.Status.Allocatable[name].DeepCopy()
+		free.Sub(requested[name])
+		freeRequests.WithLabelValues(node.Name, string(name)).Set(free.AsApproximateFloat64())
+		operandQuantity := operand[name]
+		operandRequests.WithLabelValues(node.Name, string(name)).Set(operandQuantity.AsApproximateFloat64())
+
+		if need := installerRequests[name]; free.Cmp(need) < 0 {
+			allocatable := node.Status.Allocatable[name]
+			requestedQuantity := requested[name]
+			shortages = append(shortages, fmt.Sprintf("%s %s of %s allocatable requested (kube-controller-manager %s), an installer pod needs %s",
+				name, requestedQuantity.String(), allocatable.String(), operandQuantity.String(), need.String()))
+		}
+	}
+	if len(shortages) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("node %s: %s", node.Name, strings.Join(shortages, ", "))
+}
+
+// quotaShortage describes why the quota does not admit another installer or pruner pod.
+func quotaShortage(quota *corev1.ResourceQuota) string {
+	needed := corev1.ResourceList{
+		corev1.ResourcePods:           resource.MustParse("1"),
+		corev1.ResourceCPU:            installerRequests[corev1.ResourceCPU],
+		corev1.ResourceMemory:         installerRequests[corev1.ResourceMemory],
+		corev1.ResourceRequestsCPU:    installerRequests[corev1.ResourceCPU],
+		corev1.ResourceRequestsMemory: installerRequests[corev1.ResourceMemory],
+		corev1.ResourceLimitsCPU:      installerRequests[corev1.ResourceCPU],
+		corev1.ResourceLimitsMemory:   installerRequests[corev1.ResourceMemory],
+	}
+
+	shortages := []string{}
+	for name, need := range needed {
+		hard, ok := quota.Status.Hard[name]
+		if !ok {
+			continue
+		}
+		remaining := hard.DeepCopy()
+		remaining.Sub(quota.Status.Used[name])
+		if remaining.Cmp(need) < 0 {
+			shortages = append(shortages, fmt.Sprintf("%s has %s left, an installer pod needs %s", name, remaining.String(), need.String()))
+		}
+	}
+	if len(shortages) == 0 {
+		return ""
+	}
+	sort.Strings(shortages)
+	return fmt.Sprintf("resourcequota %s/%s: %s", quota.Namespace, quota.Name, strings.Join(shortages, ", "))
+}
+
+// podRequests are the requests the scheduler accounts for the pod: the sum of its containers, or its largest init
+// container if that is more, plus the pod overhead.
+func podRequests(pod *corev1.Pod) corev1.ResourceList {
+	ret := corev1.ResourceList{}
+	for _, container := range pod.Spec.Containers {
+		addResources(ret, container.Resources.Requests)
+	}
+	for _, container := range pod.Spec.InitContainers {
+		for name, quantity := range container.Resources.Requests {
+			if current, ok := ret[name]; !ok || quantity.Cmp(current) > 0 {
+				ret[name] = quantity.DeepCopy()
+			}
+		}
+	}
+	addResources(ret, pod.Spec.Overhead)
+	return ret
+}
+
+func addResources(total, add corev1.ResourceList) {
+	for name, quantity := range add {
+		current := total[name]
+		current.Add(quantity)
+		total[name] = current
+	}
+}
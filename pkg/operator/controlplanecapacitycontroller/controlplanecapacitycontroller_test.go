@@ -0,0 +1,149 @@
+package controlplanecapacitycontroller
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+)
+
+func TestSync(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "master-0"},
+		Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("1"),
+			corev1.ResourceMemory: resource.MustParse("4Gi"),
+		}},
+	}
+	pod := func(namespace, name, cpu string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec: corev1.PodSpec{
+				NodeName: node.Name,
+				Containers: []corev1.Container{{Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse(cpu),
+					corev1.ResourceMemory: resource.MustParse("1Gi"),
+				}}}},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+	}
+	quota := func(hardPods string) *corev1.ResourceQuota {
+		return &corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "quota"},
+			Status: corev1.ResourceQuotaStatus{
+				Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse(hardPods)},
+				Used: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("4")},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name           string
+		pods           []*corev1.Pod
+		quotas         []*corev1.ResourceQuota
+		expectedStatus operatorv1.ConditionStatus
+		expectedReason string
+	}{
+		{
+			name:           "room for an installer pod",
+			pods:           []*corev1.Pod{pod(operatorclient.TargetNamespace, "kube-controller-manager-master-0", "100m"), pod("other", "workload", "500m")},
+			quotas:         []*corev1.ResourceQuota{quota("10")},
+			expectedStatus: operatorv1.ConditionTrue,
+			expectedReason: "AsExpected",
+		},
+		{
+			name:           "node oversubscribed",
+			pods:           []*corev1.Pod{pod(operatorclient.TargetNamespace, "kube-controller-manager-master-0", "100m"), pod("other", "workload", "800m")},
+			expectedStatus: operatorv1.ConditionFalse,
+			expectedReason: "NodesOversubscribed",
+		},
+		{
+			name: "completed pods are not accounted",
+			pods: []*corev1.Pod{
+				pod(operatorclient.TargetNamespace, "kube-controller-manager-master-0", "100m"),
+				func() *corev1.Pod {
+					p := pod(operatorclient.TargetNamespace, "installer-3-master-0", "800m")
+					p.Status.Phase = corev1.PodSucceeded
+					return p
+				}(),
+			},
+			expectedStatus: operatorv1.ConditionTrue,
+			expectedReason: "AsExpected",
+		},
+		{
+			name:           "quota exhausted",
+			pods:           []*corev1.Pod{pod(operatorclient.TargetNamespace, "kube-controller-manager-master-0", "100m")},
+			quotas:         []*corev1.ResourceQuota{quota("4")},
+			expectedStatus: operatorv1.ConditionFalse,
+			expectedReason: "QuotaExhausted",
+		},
+		{
+			name:           "both",
+			pods:           []*corev1.Pod{pod("other", "workload", "900m")},
+			quotas:         []*corev1.ResourceQuota{quota("4")},
+			expectedStatus: operatorv1.ConditionFalse,
+			expectedReason: "NodesOversubscribedAndQuotaExhausted",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			nodeIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if err := nodeIndexer.Add(node); err != nil {
+				t.Fatal(err)
+			}
+			quotaIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			for _, quota := range tc.quotas {
+				if err := quotaIndexer.Add(quota); err != nil {
+					t.Fatal(err)
+				}
+			}
+			objects := []runtime.Object{}
+			for _, pod := range tc.pods {
+				objects = append(objects, pod)
+			}
+
+			operatorClient := v1helpers.NewFakeStaticPodOperatorClient(
+				&operatorv1.StaticPodOperatorSpec{},
+				&operatorv1.StaticPodOperatorStatus{
+					NodeStatuses: []operatorv1.NodeStatus{{NodeName: node.Name}, {NodeName: "deleted"}},
+				},
+				nil,
+				nil,
+			)
+			c := &ControlPlaneCapacityController{
+				operatorClient: operatorClient,
+				statusUpdater:  statusbatcher.NewDirectUpdater(operatorClient),
+				nodeLister:     corev1listers.NewNodeLister(nodeIndexer),
+				quotaLister:    corev1listers.NewResourceQuotaLister(quotaIndexer),
+				podsGetter:     fake.NewSimpleClientset(objects...).CoreV1(),
+			}
+			if err := c.sync(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))); err != nil {
+				t.Fatal(err)
+			}
+
+			_, status, _, _ := operatorClient.GetOperatorState()
+			condition := v1helpers.FindOperatorCondition(status.Conditions, conditionType)
+			if condition == nil {
+				t.Fatalf("missing condition %s", conditionType)
+			}
+			if condition.Status != tc.expectedStatus || condition.Reason != tc.expectedReason {
+				t.Errorf("expected %s %s, got %s %s: %s", tc.expectedStatus, tc.expectedReason, condition.Status, condition.Reason, condition.Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package decisionlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/diff"
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/library-go/pkg/operator/configobserver"
+	"github.com/openshift/library-go/pkg/operator/events"
+)
+
+// Decision is a single rollout relevant decision taken by a controller.
+type Decision struct {
+	Time       time.Time `json:"time"`
+	Controller string    `json:"controller"`
+	Action     string    `json:"action"`
+	Reasons    []string  `json:"reasons,omitempty"`
+}
+
+// Log keeps the most recent decisions in a ring buffer, so that "why did (or didn't) we roll out" can be answered
+// after the fact without raising the log level of the whole operator.
+// A nil *Log is valid and records nothing, which is what the operator runs with unless the log is enabled.
+type Log struct {
+	lock    sync.Mutex
+	entries []Decision
+	next    int
+	full    bool
+	now     func() time.Time
+
+	// outcomes holds the reasons of the outcomes last recorded by RecordOutcome, by controller and action
+	outcomes map[string]map[string][]string
+}
+
+// New returns a log keeping the last size decisions, or nil when size is not positive.
+func New(size int) *Log {
+	if size <= 0 {
+		return nil
+	}
+	return &Log{
+		entries:  make([]Decision, size),
+		now:      time.Now,
+		outcomes: map[string]map[string][]string{},
+	}
+}
+
+// Record adds a decision to the log.
+func (l *Log) Record(controller, action string, reasons ...string) {
+	if l == nil {
+		return
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.add(controller, action, reasons)
+}
+
+// RecordOutcome adds the outcome of a sync to the log unless the controller already recorded the same outcome, so
+// that a controller resyncing in the same state does not push the other decisions out of the log.
+func (l *Log) RecordOutcome(controller, action string, reasons ...string) {
+	if l == nil {
+		return
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if previous, ok := l.outcomes[controller][action]; ok && equality.Semantic.DeepEqual(previous, reasons) {
+		return
+	}
+	if l.outcomes[controller] == nil {
+		l.outcomes[controller] = map[string][]string{}
+	}
+	l.outcomes[controller][action] = reasons
+	l.add(controller, action, reasons)
+}
+
+// Proceed records that the controller synced without being held back any more, once after the outcomes recorded by
+// RecordOutcome. Every outcome is recorded again when it comes back.
+func (l *Log) Proceed(controller string) {
+	if l == nil {
+		return
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if len(l.outcomes[controller]) == 0 {
+		return
+	}
+	delete(l.outcomes, controller)
+	l.add(controller, "Proceed", nil)
+}
+
+func (l *Log) add(controller, action string, reasons []string) {
+	l.entries[l.next] = Decision{Time: l.now(), Controller: controller, Action: action, Reasons: reasons}
+	l.next = (l.next + 1) % len(l.entries)
+	if l.next == 0 {
+		l.full = true
+	}
+}
+
+// Decisions returns the recorded decisions, oldest first.
+func (l *Log) Decisions() []Decision {
+	if l == nil {
+		return []Decision{}
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	ret := []Decision{}
+	if l.full {
+		ret = append(ret, l.entries[l.next:]...)
+	}
+	return append(ret, l.entries[:l.next]...)
+}
+
+// ServeHTTP writes the recorded decisions as JSON.
+func (l *Log) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	data, err := json.MarshalIndent(l.Decisions(), "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(data); err != nil {
+		klog.Warningf("failed to write decisions: %v", err)
+	}
+}
+
+// WrapObserver records every change of the config produced by the observer, together with its errors.
+func (l *Log) WrapObserver(name string, observer configobserver.ObserveConfigFunc) configobserver.ObserveConfigFunc {
+	if l == nil {
+		return observer
+	}
+	var lock sync.Mutex
+	var previous map[string]interface{}
+	return func(listers configobserver.Listers, recorder events.Recorder, existingConfig map[string]interface{}) (map[string]interface{}, []error) {
+		observedConfig, errs := observer(listers, recorder, existingConfig)
+
+		lock.Lock()
+		defer lock.Unlock()
+		reasons := []string{}
+		for _, err := range errs {
+			reasons = append(reasons, fmt.Sprintf("error: %v", err))
+		}
+		if !equality.Semantic.DeepEqual(previous, observedConfig) {
+			reasons = append(reasons, fmt.Sprintf("observed config changed: %s", diff.ObjectDiff(previous, observedConfig)))
+			previous = observedConfig
+		}
+		if len(reasons) > 0 {
+			l.Record("ConfigObserver/"+name, "Observe", reasons...)
+		}
+		return observedConfig, errs
+	}
+}
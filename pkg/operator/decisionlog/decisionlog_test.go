@@ -0,0 +1,78 @@
+package decisionlog
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLog(t *testing.T) {
+	var disabled *Log
+	disabled.Record("Controller", "Ignored")
+	if decisions := disabled.Decisions(); len(decisions) != 0 {
+		t.Errorf("expected no decisions from a disabled log, got %v", decisions)
+	}
+
+	now := time.Date(2022, 2, 1, 10, 0, 0, 0, time.UTC)
+	log := New(3)
+	log.now = func() time.Time { return now }
+
+	actions := func() []string {
+		ret := []string{}
+		for _, decision := range log.Decisions() {
+			ret = append(ret, decision.Action)
+		}
+		return ret
+	}
+
+	log.Record("Controller", "A")
+	log.Record("Controller", "B", "because")
+	if expected := []string{"A", "B"}; !reflect.DeepEqual(actions(), expected) {
+		t.Errorf("expected %v, got %v", expected, actions())
+	}
+
+	log.Record("Controller", "C")
+	log.Record("Controller", "D")
+	log.Record("Controller", "E")
+	if expected := []string{"C", "D", "E"}; !reflect.DeepEqual(actions(), expected) {
+		t.Errorf("expected %v, got %v", expected, actions())
+	}
+}
+
+func TestRecordOutcome(t *testing.T) {
+	var disabled *Log
+	disabled.RecordOutcome("Controller", "Ignored")
+	disabled.Proceed("Controller")
+
+	log := New(10)
+	decisions := func() []string {
+		ret := []string{}
+		for _, decision := range log.Decisions() {
+			ret = append(ret, fmt.Sprintf("%s %v", decision.Action, decision.Reasons))
+		}
+		return ret
+	}
+
+	// nothing held the controller back yet
+	log.Proceed("Controller")
+	// every resync while unmanaged
+	log.RecordOutcome("Controller", "Skip", "managementState is Unmanaged")
+	log.RecordOutcome("Controller", "Skip", "managementState is Unmanaged")
+	log.RecordOutcome("Controller", "Skip", "managementState is Removed")
+	log.RecordOutcome("Other", "Skip", "managementState is Removed")
+	log.Proceed("Controller")
+	log.Proceed("Controller")
+	log.RecordOutcome("Controller", "Skip", "managementState is Removed")
+
+	expected := []string{
+		"Skip [managementState is Unmanaged]",
+		"Skip [managementState is Removed]",
+		"Skip [managementState is Removed]",
+		"Proceed []",
+		"Skip [managementState is Removed]",
+	}
+	if !reflect.DeepEqual(decisions(), expected) {
+		t.Errorf("expected %v, got %v", expected, decisions())
+	}
+}
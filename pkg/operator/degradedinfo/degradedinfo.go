@@ -0,0 +1,195 @@
+package degradedinfo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/component-base/metrics"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operationprofile"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatormetrics"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statestore"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
+)
+
+const (
+	// ConfigMapName is the state document in the operator namespace holding the last degraded transition.
+	ConfigMapName = "kube-controller-manager-operator-degraded-info"
+	configMapKey  = "degraded.json"
+
+	// events up to eventsBefore ahead of a transition and eventsAfter after it are attached to it
+	eventsBefore = 5 * time.Minute
+	eventsAfter  = time.Minute
+	maxEvents    = 10
+)
+
+var degradedEpisodes = metrics.NewCounterVec(
+	&metrics.CounterOpts{
+		Name:           "kcm_operator_degraded_episodes_total",
+		Help:           "Number of times a controller of the operator went Degraded.",
+		StabilityLevel: metrics.ALPHA,
+	},
+	[]string{"controller"},
+)
+
+func init() {
+	operatormetrics.MustRegister(degradedEpisodes)
+}
+
+// LastDegradedInfo explains the latest transition of a controller of the operator to Degraded.
+type LastDegradedInfo struct {
+	Time       metav1.Time `json:"time"`
+	Controller string      `json:"controller"`
+	Reason     string      `json:"reason,omitempty"`
+	Message    string      `json:"message,omitempty"`
+	// Events are the warning events of the operator around the time of the transition.
+	Events []string `json:"events,omitempty"`
+}
+
+// record is what is persisted, so that the information survives restarts of the operator.
+type record struct {
+	LastDegradedInfo *LastDegradedInfo `json:"lastDegradedInfo,omitempty"`
+	// Episodes is the start of the latest degraded episode of each controller, to count every episode once.
+	Episodes map[string]metav1.Time `json:"episodes,omitempty"`
+}
+
+// DegradedInfoController records every transition of a <controller>Degraded condition to True.
+// Degraded conditions clear once the controller recovers, the record keeps what went wrong.
+type DegradedInfoController struct {
+	operatorClient v1helpers.OperatorClient
+	stateStore     statestore.Store
+	eventsGetter   corev1client.EventsGetter
+}
+
+func NewDegradedInfoController(
+	operatorClient v1helpers.OperatorClient,
+	stateStore statestore.Store,
+	kubeClient corev1client.CoreV1Interface,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &DegradedInfoController{
+		operatorClient: operatorClient,
+		stateStore:     stateStore,
+		eventsGetter:   kubeClient,
+	}
+
+	return factory.New().WithInformers(
+		operatorClient.Informer(),
+		stateStore.Informer(),
+	).ResyncEvery(operationprofile.Resync(time.Minute)).WithSync(syncmetrics.Timed("DegradedInfoController", c.sync)).ToController("DegradedInfoController", eventRecorder)
+}
+
+func (c *DegradedInfoController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	_, status, _, err := c.operatorClient.GetOperatorState()
+	if err != nil {
+		return err
+	}
+	record, err := c.record()
+	if err != nil {
+		return err
+	}
+
+	changed := false
+	for _, condition := range status.Conditions {
+		controller := strings.TrimSuffix(condition.Type, operatorv1.OperatorStatusTypeDegraded)
+		if len(controller) == 0 || controller == condition.Type || condition.Status != operatorv1.ConditionTrue {
+			continue
+		}
+		if last, ok := record.Episodes[controller]; ok && !last.Before(&condition.LastTransitionTime) {
+			continue
+		}
+
+		degradedEpisodes.WithLabelValues(controller).Inc()
+		record.Episodes[controller] = condition.LastTransitionTime
+		changed = true
+		if record.LastDegradedInfo != nil && condition.LastTransitionTime.Before(&record.LastDegradedInfo.Time) {
+			continue
+		}
+		events, err := c.eventsAround(ctx, condition.LastTransitionTime.Time)
+		if err != nil {
+			return err
+		}
+		record.LastDegradedInfo = &LastDegradedInfo{
+			Time:       condition.LastTransitionTime,
+			Controller: controller,
+			Reason:     condition.Reason,
+			Message:    condition.Message,
+			Events:     events,
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return c.stateStore.Put(ctx, syncCtx.Recorder(), ConfigMapName, map[string]string{configMapKey: string(raw)})
+}
+
+func (c *DegradedInfoController) record() (*record, error) {
+	ret := &record{}
+	data, err := c.stateStore.Get(ConfigMapName)
+	if err != nil {
+		return nil, err
+	}
+	if data != nil {
+		// start over rather than getting stuck on a hand-edited document
+		if err := json.Unmarshal([]byte(data[configMapKey]), ret); err != nil {
+			ret = &record{}
+		}
+	}
+	if ret.Episodes == nil {
+		ret.Episodes = map[string]metav1.Time{}
+	}
+	return ret, nil
+}
+
+// eventsAround returns the latest warning events of the operator namespace around the given time, oldest first.
+func (c *DegradedInfoController) eventsAround(ctx context.Context, transition time.Time) ([]string, error) {
+	eventList, err := c.eventsGetter.Events(operatorclient.OperatorNamespace).List(ctx, metav1.ListOptions{FieldSelector: "type=" + corev1.EventTypeWarning})
+	if err != nil {
+		return nil, err
+	}
+
+	matching := []corev1.Event{}
+	for _, event := range eventList.Items {
+		if last := eventTime(event); !last.Before(transition.Add(-eventsBefore)) && !last.After(transition.Add(eventsAfter)) {
+			matching = append(matching, event)
+		}
+	}
+	sort.SliceStable(matching, func(i, j int) bool { return eventTime(matching[i]).Before(eventTime(matching[j])) })
+	if len(matching) > maxEvents {
+		matching = matching[len(matching)-maxEvents:]
+	}
+
+	ret := []string{}
+	for _, event := range matching {
+		ret = append(ret, fmt.Sprintf("%s %s: %s", eventTime(event).UTC().Format(time.RFC3339), event.Reason, event.Message))
+	}
+	return ret, nil
+}
+
+func eventTime(event corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	}
+	return event.CreationTimestamp.Time
+}
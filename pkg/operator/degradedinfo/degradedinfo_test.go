@@ -0,0 +1,98 @@
+package degradedinfo
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statestore"
+)
+
+func warning(name string, at time.Time) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta:    metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: name},
+		Type:          corev1.EventTypeWarning,
+		Reason:        name,
+		Message:       name + " happened",
+		LastTimestamp: metav1.NewTime(at),
+	}
+}
+
+func TestSync(t *testing.T) {
+	transition := time.Date(2022, 3, 1, 3, 0, 0, 0, time.UTC)
+	earlier := transition.Add(-time.Hour)
+
+	kubeClient := fake.NewSimpleClientset(
+		warning("long-before", transition.Add(-time.Hour)),
+		warning("just-before", transition.Add(-time.Minute)),
+		warning("just-after", transition.Add(30*time.Second)),
+	)
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	operatorClient := v1helpers.NewFakeOperatorClient(
+		&operatorv1.OperatorSpec{ManagementState: operatorv1.Managed},
+		&operatorv1.OperatorStatus{
+			Conditions: []operatorv1.OperatorCondition{
+				{Type: "TargetConfigControllerDegraded", Status: operatorv1.ConditionTrue, Reason: "SynchronizationError", Message: "configmap/config: boom", LastTransitionTime: metav1.NewTime(transition)},
+				{Type: "NodeControllerDegraded", Status: operatorv1.ConditionTrue, LastTransitionTime: metav1.NewTime(earlier)},
+				{Type: "GuardPodsDegraded", Status: operatorv1.ConditionFalse, LastTransitionTime: metav1.NewTime(transition.Add(time.Minute))},
+			},
+		},
+		nil,
+	)
+	c := &DegradedInfoController{
+		operatorClient: operatorClient,
+		stateStore:     statestore.NewConfigMapStoreFromLister(corev1listers.NewConfigMapLister(indexer), kubeClient.CoreV1()),
+		eventsGetter:   kubeClient.CoreV1(),
+	}
+	syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))
+
+	if err := c.sync(context.TODO(), syncCtx); err != nil {
+		t.Fatal(err)
+	}
+	configMap, err := kubeClient.CoreV1().ConfigMaps(operatorclient.OperatorNamespace).Get(context.TODO(), ConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := &record{}
+	if err := json.Unmarshal([]byte(configMap.Data[configMapKey]), got); err != nil {
+		t.Fatal(err)
+	}
+
+	info := got.LastDegradedInfo
+	if info == nil || info.Controller != "TargetConfigController" || info.Reason != "SynchronizationError" || !info.Time.Time.Equal(transition) {
+		t.Fatalf("expected the TargetConfigController transition to be recorded, got %#v", info)
+	}
+	if len(info.Events) != 2 {
+		t.Errorf("expected the two events around the transition, got %v", info.Events)
+	}
+	if len(got.Episodes) != 2 {
+		t.Errorf("expected two episodes, got %v", got.Episodes)
+	}
+
+	// the episodes survive a restart through the configmap and are not recorded twice
+	if err := indexer.Add(configMap); err != nil {
+		t.Fatal(err)
+	}
+	if err := kubeClient.CoreV1().ConfigMaps(operatorclient.OperatorNamespace).Delete(context.TODO(), ConfigMapName, metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.sync(context.TODO(), syncCtx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := kubeClient.CoreV1().ConfigMaps(operatorclient.OperatorNamespace).Get(context.TODO(), ConfigMapName, metav1.GetOptions{}); err == nil {
+		t.Errorf("expected no update without a new degraded transition")
+	}
+}
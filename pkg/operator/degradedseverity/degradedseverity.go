@@ -0,0 +1,169 @@
+package degradedseverity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operationprofile"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
+)
+
+// Severity tells whether a Degraded condition of an inner controller pages.
+type Severity string
+
+const (
+	// Critical conditions are unioned into the Degraded condition of the ClusterOperator.
+	Critical Severity = "Critical"
+	// Warning conditions are only listed in the DegradedWarnings condition of the operator.
+	Warning Severity = "Warning"
+
+	conditionType = "DegradedWarnings"
+)
+
+// defaultSeverities are the conditions which report a risk rather than a broken operand. The conditions which are not
+// listed are Critical.
+var defaultSeverities = map[string]Severity{
+	"ArgumentRequestsDegraded":  Warning,
+	"GuardPodsDegraded":         Warning,
+	"NamespaceMetadataDegraded": Warning,
+}
+
+// severities returns the severity of the Degraded conditions, the defaults amended by the degradedSeverity stanza of
+// the operator's UnsupportedConfigOverrides:
+//
+//	unsupportedConfigOverrides:
+//	  degradedSeverity:
+//	    GuardPodsDegraded: Critical
+//	    ResourceSyncControllerDegraded: Warning
+func severities(unsupportedConfigOverrides []byte) (map[string]Severity, error) {
+	ret := map[string]Severity{}
+	for conditionType, severity := range defaultSeverities {
+		ret[conditionType] = severity
+	}
+	if len(unsupportedConfigOverrides) == 0 {
+		return ret, nil
+	}
+	overrides := struct {
+		DegradedSeverity map[string]Severity `json:"degradedSeverity"`
+	}{}
+	if err := json.Unmarshal(unsupportedConfigOverrides, &overrides); err != nil {
+		return nil, fmt.Errorf("failed to load degradedSeverity from UnsupportedConfigOverride: %v", err)
+	}
+	for conditionType, severity := range overrides.DegradedSeverity {
+		if !strings.HasSuffix(conditionType, "Degraded") {
+			return nil, fmt.Errorf("degradedSeverity.%s: only the severity of Degraded conditions can be set", conditionType)
+		}
+		if severity != Critical && severity != Warning {
+			return nil, fmt.Errorf("degradedSeverity.%s: must be %s or %s, not %q", conditionType, Critical, Warning, severity)
+		}
+		ret[conditionType] = severity
+	}
+	return ret, nil
+}
+
+// severitiesOrDefaults returns the severities of the overrides, or the defaults when they are invalid. The
+// DegradedWarnings condition reports the invalid overrides.
+func severitiesOrDefaults(unsupportedConfigOverrides []byte) map[string]Severity {
+	ret, err := severities(unsupportedConfigOverrides)
+	if err != nil {
+		return defaultSeverities
+	}
+	return ret
+}
+
+// criticalOnlyClient hides the Warning conditions from the ClusterOperator status controller.
+type criticalOnlyClient struct {
+	v1helpers.OperatorClient
+}
+
+// NewCriticalOnlyClient returns the operator client for the ClusterOperator status controller, whose status lacks the
+// Degraded conditions of Warning severity, so that they do not escalate into the Degraded condition of the
+// ClusterOperator. Every transient hiccup of a controller would page otherwise.
+func NewCriticalOnlyClient(operatorClient v1helpers.OperatorClient) v1helpers.OperatorClient {
+	return &criticalOnlyClient{OperatorClient: operatorClient}
+}
+
+func (c *criticalOnlyClient) GetOperatorState() (*operatorv1.OperatorSpec, *operatorv1.OperatorStatus, string, error) {
+	spec, status, resourceVersion, err := c.OperatorClient.GetOperatorState()
+	if err != nil {
+		return spec, status, resourceVersion, err
+	}
+	severities := severitiesOrDefaults(spec.UnsupportedConfigOverrides.Raw)
+	// the status may come from the lister, it must not be modified
+	filtered := status.DeepCopy()
+	filtered.Conditions = nil
+	for _, condition := range status.Conditions {
+		if severities[condition.Type] != Warning {
+			filtered.Conditions = append(filtered.Conditions, condition)
+		}
+	}
+	return spec, filtered, resourceVersion, nil
+}
+
+// WarningsController lists the Degraded conditions of Warning severity which are true in the DegradedWarnings
+// condition, so that they stay visible without paging.
+type WarningsController struct {
+	operatorClient v1helpers.OperatorClient
+	statusUpdater  statusbatcher.StatusUpdater
+}
+
+func NewWarningsController(operatorClient v1helpers.OperatorClient, statusUpdater statusbatcher.StatusUpdater, eventRecorder events.Recorder) factory.Controller {
+	c := &WarningsController{
+		operatorClient: operatorClient,
+		statusUpdater:  statusUpdater,
+	}
+
+	return factory.New().WithInformers(
+		operatorClient.Informer(),
+	).ResyncEvery(operationprofile.Resync(time.Minute)).WithSync(syncmetrics.Timed("DegradedWarningsController", c.sync)).ToController("DegradedWarningsController", eventRecorder)
+}
+
+func (c *WarningsController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	spec, status, _, err := c.operatorClient.GetOperatorState()
+	if err != nil {
+		return err
+	}
+	condition := operatorv1.OperatorCondition{
+		Type:   conditionType,
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+	severities, err := severities(spec.UnsupportedConfigOverrides.Raw)
+	if err != nil {
+		condition.Reason = "InvalidDegradedSeverity"
+		condition.Message = fmt.Sprintf("%v, the default severities apply", err)
+		severities = defaultSeverities
+	}
+
+	warnings := []operatorv1.OperatorCondition{}
+	for _, degraded := range status.Conditions {
+		if severities[degraded.Type] == Warning && degraded.Status == operatorv1.ConditionTrue {
+			warnings = append(warnings, degraded)
+		}
+	}
+	sort.Slice(warnings, func(i, j int) bool { return warnings[i].Type < warnings[j].Type })
+	if len(warnings) > 0 {
+		reasons, messages := []string{}, []string{}
+		for _, warning := range warnings {
+			reasons = append(reasons, strings.TrimSuffix(warning.Type, "Degraded"))
+			messages = append(messages, fmt.Sprintf("%s: %s", warning.Type, warning.Message))
+		}
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = strings.Join(reasons, "And")
+		condition.Message = strings.Join(messages, "\n")
+		if err != nil {
+			condition.Message += fmt.Sprintf("\n%v, the default severities apply", err)
+		}
+	}
+	return c.statusUpdater.UpdateStatus(ctx, v1helpers.UpdateConditionFn(condition))
+}
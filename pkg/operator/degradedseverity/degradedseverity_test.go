@@ -0,0 +1,118 @@
+package degradedseverity
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/status"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+)
+
+func TestSeverities(t *testing.T) {
+	got, err := severities([]byte(`{"degradedSeverity":{"GuardPodsDegraded":"Critical","ResourceSyncControllerDegraded":"Warning"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for conditionType, expected := range map[string]Severity{
+		"GuardPodsDegraded":              Critical,
+		"ResourceSyncControllerDegraded": Warning,
+		"NamespaceMetadataDegraded":      Warning,
+	} {
+		if got[conditionType] != expected {
+			t.Errorf("expected %s to be %s, got %s", conditionType, expected, got[conditionType])
+		}
+	}
+
+	for _, invalid := range []string{
+		`{"degradedSeverity":{"GuardPodsDegraded":"Info"}}`,
+		`{"degradedSeverity":{"Available":"Warning"}}`,
+		`{"degradedSeverity":["GuardPodsDegraded"]}`,
+	} {
+		if _, err := severities([]byte(invalid)); err == nil {
+			t.Errorf("expected %s to be rejected", invalid)
+		}
+	}
+}
+
+func TestWarningsDoNotEscalate(t *testing.T) {
+	conditions := []operatorv1.OperatorCondition{
+		{Type: "GuardPodsDegraded", Status: operatorv1.ConditionTrue, Reason: "Unready", Message: "guard pod on master-0 is not ready"},
+		{Type: "NamespaceMetadataDegraded", Status: operatorv1.ConditionTrue, Reason: "Drift", Message: "label removed"},
+		{Type: "NodeInstallerDegraded", Status: operatorv1.ConditionFalse, Reason: "AsExpected"},
+	}
+	testCases := []struct {
+		name                    string
+		overrides               string
+		expectedDegraded        operatorv1.ConditionStatus
+		expectedWarnings        operatorv1.ConditionStatus
+		expectedWarningsReason  string
+		expectedWarningsMessage string
+	}{
+		{
+			name:                    "warnings only",
+			expectedDegraded:        operatorv1.ConditionFalse,
+			expectedWarnings:        operatorv1.ConditionTrue,
+			expectedWarningsReason:  "GuardPodsAndNamespaceMetadata",
+			expectedWarningsMessage: "GuardPodsDegraded: guard pod on master-0 is not ready\nNamespaceMetadataDegraded: label removed",
+		},
+		{
+			name:                    "raised to critical",
+			overrides:               `{"degradedSeverity":{"GuardPodsDegraded":"Critical"}}`,
+			expectedDegraded:        operatorv1.ConditionTrue,
+			expectedWarnings:        operatorv1.ConditionTrue,
+			expectedWarningsReason:  "NamespaceMetadata",
+			expectedWarningsMessage: "NamespaceMetadataDegraded: label removed",
+		},
+		{
+			name:                    "invalid overrides",
+			overrides:               `{"degradedSeverity":{"GuardPodsDegraded":"Info"}}`,
+			expectedDegraded:        operatorv1.ConditionFalse,
+			expectedWarnings:        operatorv1.ConditionTrue,
+			expectedWarningsReason:  "GuardPodsAndNamespaceMetadata",
+			expectedWarningsMessage: "GuardPodsDegraded: guard pod on master-0 is not ready\nNamespaceMetadataDegraded: label removed\ndegradedSeverity.GuardPodsDegraded: must be Critical or Warning, not \"Info\", the default severities apply",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			spec := &operatorv1.StaticPodOperatorSpec{}
+			if len(tc.overrides) > 0 {
+				spec.UnsupportedConfigOverrides = runtime.RawExtension{Raw: []byte(tc.overrides)}
+			}
+			operatorClient := v1helpers.NewFakeStaticPodOperatorClient(
+				spec,
+				&operatorv1.StaticPodOperatorStatus{OperatorStatus: operatorv1.OperatorStatus{Conditions: append([]operatorv1.OperatorCondition{}, conditions...)}},
+				nil,
+				nil,
+			)
+
+			_, criticalStatus, _, err := NewCriticalOnlyClient(operatorClient).GetOperatorState()
+			if err != nil {
+				t.Fatal(err)
+			}
+			degraded := status.UnionCondition("Degraded", operatorv1.ConditionFalse, nil, criticalStatus.Conditions...)
+			if degraded.Status != tc.expectedDegraded {
+				t.Errorf("expected Degraded %s, got %s: %s", tc.expectedDegraded, degraded.Status, degraded.Message)
+			}
+
+			c := &WarningsController{operatorClient: operatorClient, statusUpdater: statusbatcher.NewDirectUpdater(operatorClient)}
+			if err := c.sync(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))); err != nil {
+				t.Fatal(err)
+			}
+			_, operatorStatus, _, _ := operatorClient.GetOperatorState()
+			warnings := v1helpers.FindOperatorCondition(operatorStatus.Conditions, conditionType)
+			if warnings == nil {
+				t.Fatalf("missing condition %s", conditionType)
+			}
+			if warnings.Status != tc.expectedWarnings || warnings.Reason != tc.expectedWarningsReason || warnings.Message != tc.expectedWarningsMessage {
+				t.Errorf("expected %s %s %q, got %s %s %q", tc.expectedWarnings, tc.expectedWarningsReason, tc.expectedWarningsMessage, warnings.Status, warnings.Reason, warnings.Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package dependencycontroller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resourcesynccontroller"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operationprofile"
+	operatorresourcesync "github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/resourcesynccontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
+)
+
+const conditionType = "MissingDependencies"
+
+// DependencyController reports the sources of the resource sync controller which do not exist, except for the
+// optional ones. Without it a missing source, e.g. csr-controller-ca during install, only shows up as sync errors in
+// the logs.
+type DependencyController struct {
+	statusUpdater   statusbatcher.StatusUpdater
+	configMapLister corev1listers.ConfigMapLister
+	secretLister    corev1listers.SecretLister
+
+	configMaps []operatorresourcesync.ResourceSync
+	secrets    []operatorresourcesync.ResourceSync
+
+	// missingSince remembers when a source was first noticed missing, keyed by "<resource> <namespace>/<name>"
+	missingSince map[string]time.Time
+	now          func() time.Time
+}
+
+func NewDependencyController(
+	operatorClient v1helpers.OperatorClient,
+	statusUpdater statusbatcher.StatusUpdater,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	configMapSyncs, secretSyncs []operatorresourcesync.ResourceSync,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &DependencyController{
+		statusUpdater:   statusUpdater,
+		configMapLister: kubeInformersForNamespaces.ConfigMapLister(),
+		secretLister:    kubeInformersForNamespaces.SecretLister(),
+		missingSince:    map[string]time.Time{},
+		now:             time.Now,
+	}
+
+	informers := []factory.Informer{operatorClient.Informer()}
+	for _, sync := range configMapSyncs {
+		c.configMaps = append(c.configMaps, sync)
+		informers = append(informers, kubeInformersForNamespaces.InformersFor(sync.Source.Namespace).Core().V1().ConfigMaps().Informer())
+	}
+	for _, sync := range secretSyncs {
+		c.secrets = append(c.secrets, sync)
+		informers = append(informers, kubeInformersForNamespaces.InformersFor(sync.Source.Namespace).Core().V1().Secrets().Informer())
+	}
+
+	return factory.New().WithInformers(informers...).ResyncEvery(operationprofile.Resync(time.Minute)).WithSync(syncmetrics.Timed("DependencyController", c.sync)).ToController("DependencyController", eventRecorder)
+}
+
+func (c *DependencyController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	missing := []string{}
+	for _, sync := range c.configMaps {
+		if sync.Optional {
+			continue
+		}
+		_, err := c.configMapLister.ConfigMaps(sync.Source.Namespace).Get(sync.Source.Name)
+		key, err := c.track("configmap", sync.Source, err)
+		if err != nil {
+			return err
+		}
+		if len(key) > 0 {
+			missing = append(missing, key)
+		}
+	}
+	for _, sync := range c.secrets {
+		if sync.Optional {
+			continue
+		}
+		_, err := c.secretLister.Secrets(sync.Source.Namespace).Get(sync.Source.Name)
+		key, err := c.track("secret", sync.Source, err)
+		if err != nil {
+			return err
+		}
+		if len(key) > 0 {
+			missing = append(missing, key)
+		}
+	}
+	sort.Strings(missing)
+
+	condition := operatorv1.OperatorCondition{
+		Type:   conditionType,
+		Status: operatorv1.ConditionFalse,
+	}
+	if len(missing) > 0 {
+		messages := []string{}
+		for _, key := range missing {
+			messages = append(messages, fmt.Sprintf("%s missing since %s", key, c.missingSince[key].UTC().Format(time.RFC3339)))
+		}
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "SourcesMissing"
+		condition.Message = strings.Join(messages, "\n")
+	}
+	return c.statusUpdater.UpdateStatus(ctx, v1helpers.UpdateConditionFn(condition))
+}
+
+// track records when the source was first noticed missing and returns its key in that case.
+func (c *DependencyController) track(resource string, location resourcesynccontroller.ResourceLocation, getErr error) (string, error) {
+	key := fmt.Sprintf("%s %s/%s", resource, location.Namespace, location.Name)
+	switch {
+	case getErr == nil:
+		delete(c.missingSince, key)
+		return "", nil
+	case apierrors.IsNotFound(getErr):
+		if _, ok := c.missingSince[key]; !ok {
+			c.missingSince[key] = c.now()
+		}
+		return key, nil
+	default:
+		return "", getErr
+	}
+}
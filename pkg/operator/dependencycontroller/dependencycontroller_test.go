@@ -0,0 +1,79 @@
+package dependencycontroller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resourcesynccontroller"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	operatorresourcesync "github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/resourcesynccontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+)
+
+func TestSync(t *testing.T) {
+	configMapIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	secretIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	if err := secretIndexer.Add(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "present"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	firstMissing := time.Date(2022, 2, 1, 10, 0, 0, 0, time.UTC)
+	now := firstMissing
+	operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil)
+	c := &DependencyController{
+		statusUpdater:   statusbatcher.NewDirectUpdater(operatorClient),
+		configMapLister: corev1listers.NewConfigMapLister(configMapIndexer),
+		secretLister:    corev1listers.NewSecretLister(secretIndexer),
+		configMaps: []operatorresourcesync.ResourceSync{
+			{Source: resourcesynccontroller.ResourceLocation{Namespace: "ns", Name: "csr-controller-ca"}},
+			// never created, it is optional
+			{Source: resourcesynccontroller.ResourceLocation{Namespace: "ns", Name: "node-files"}, Optional: true},
+		},
+		secrets: []operatorresourcesync.ResourceSync{
+			{Source: resourcesynccontroller.ResourceLocation{Namespace: "ns", Name: "present"}},
+		},
+		missingSince: map[string]time.Time{},
+		now:          func() time.Time { return now },
+	}
+	syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))
+
+	if err := c.sync(context.TODO(), syncCtx); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(time.Hour)
+	if err := c.sync(context.TODO(), syncCtx); err != nil {
+		t.Fatal(err)
+	}
+	_, status, _, _ := operatorClient.GetOperatorState()
+	condition := v1helpers.FindOperatorCondition(status.Conditions, conditionType)
+	if condition == nil || condition.Status != operatorv1.ConditionTrue {
+		t.Fatalf("expected %s to be true, got %v", conditionType, condition)
+	}
+	if expected := "configmap ns/csr-controller-ca missing since 2022-02-01T10:00:00Z"; condition.Message != expected {
+		t.Errorf("expected message %q, got %q", expected, condition.Message)
+	}
+
+	if err := configMapIndexer.Add(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "csr-controller-ca"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.sync(context.TODO(), syncCtx); err != nil {
+		t.Fatal(err)
+	}
+	_, status, _, _ = operatorClient.GetOperatorState()
+	if !v1helpers.IsOperatorConditionFalse(status.Conditions, conditionType) {
+		t.Errorf("expected %s to be false, got %v", conditionType, status.Conditions)
+	}
+	if len(c.missingSince) != 0 {
+		t.Errorf("expected no tracked sources, got %v", c.missingSince)
+	}
+}
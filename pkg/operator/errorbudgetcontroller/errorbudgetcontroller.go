@@ -0,0 +1,299 @@
+package errorbudgetcontroller
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/expfmt"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/rest"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/backpressure"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/controllerpause"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operandendpoint"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operationprofile"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
+)
+
+const (
+	// retriesMetric counts the items a controller requeued after failing to process them
+	retriesMetric = "workqueue_retries_total"
+	// window is how long the error rate has to stay above the budget
+	window = 10 * time.Minute
+	// stuckAfter is how long an object waits for a controller before it counts as affected by the failures
+	stuckAfter = 5 * time.Minute
+	// maxAffected bounds the objects named in a condition
+	maxAffected = 10
+)
+
+// budget is the error rate a subsystem of kube-controller-manager may sustain, measured by the retries of the work
+// queues of its controllers.
+type budget struct {
+	conditionType string
+	queues        []string
+	// perMinute is the retries per minute allowed over the window
+	perMinute float64
+	// affected returns the objects the failing controllers leave behind
+	affected func(ctx context.Context, now time.Time) ([]string, error)
+}
+
+// sample is the retries per budget since the previous scrape.
+type sample struct {
+	time    time.Time
+	retries map[string]float64
+}
+
+// ErrorBudgetController scrapes the work queue metrics of the kube-controller-manager pods and turns retry rates
+// sustained above the budget of a subsystem into its condition, naming the objects it left behind. Failures to sign
+// certificates or to create service account tokens otherwise go unnoticed until their consumers break.
+type ErrorBudgetController struct {
+	statusUpdater statusbatcher.StatusUpdater
+	podLister     corev1listers.PodLister
+	fetch         operandendpoint.Fetcher
+	budgets       []budget
+	now           func() time.Time
+
+	// lastRetries maps the pods to the retries of their queues at the previous scrape
+	lastRetries map[string]map[string]float64
+	samples     []sample
+}
+
+func NewErrorBudgetController(
+	statusUpdater statusbatcher.StatusUpdater,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	kubeClient kubernetes.Interface,
+	kubeConfig *rest.Config,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	targetInformers := kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace)
+	c := &ErrorBudgetController{
+		statusUpdater: statusUpdater,
+		podLister:     targetInformers.Core().V1().Pods().Lister(),
+		fetch:         operandendpoint.NewFetcher(kubeConfig, targetInformers.Core().V1().ConfigMaps().Lister()),
+		budgets:       newBudgets(kubeClient),
+		now:           time.Now,
+		lastRetries:   map[string]map[string]float64{},
+	}
+
+	return factory.New().ResyncEvery(operationprofile.Resync(time.Minute)).WithSync(syncmetrics.Timed("ErrorBudgetController", controllerpause.Pausable("ErrorBudgetController", backpressure.Deferrable("ErrorBudgetController", c.sync)))).ToController("ErrorBudgetController", eventRecorder)
+}
+
+func newBudgets(kubeClient kubernetes.Interface) []budget {
+	return []budget{
+		{
+			conditionType: "CSRSigningDegraded",
+			queues:        []string{"csrsigning-kubelet-serving", "csrsigning-kubelet-client", "csrsigning-kube-apiserver-client", "csrsigning-legacy-unknown"},
+			perMinute:     1,
+			affected: func(ctx context.Context, now time.Time) ([]string, error) {
+				return unsignedCSRs(ctx, kubeClient, now)
+			},
+		},
+		{
+			conditionType: "TokenControllerDegraded",
+			queues:        []string{"serviceaccount_tokens_service", "serviceaccount_tokens_secret", "serviceaccount"},
+			perMinute:     1,
+			affected: func(ctx context.Context, now time.Time) ([]string, error) {
+				return namespacesWithoutTokens(ctx, kubeClient, now)
+			},
+		},
+	}
+}
+
+func (c *ErrorBudgetController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	now := c.now()
+	if current, err := c.scrape(ctx); err != nil {
+		syncCtx.Recorder().Warningf("OperandMetricsScrapeFailed", "Failed to scrape the metrics of kube-controller-manager: %v", err)
+	} else {
+		c.addSample(now, current)
+	}
+
+	conditions := []v1helpers.UpdateStatusFunc{}
+	for _, budget := range c.budgets {
+		condition := operatorv1.OperatorCondition{
+			Type:   budget.conditionType,
+			Status: operatorv1.ConditionFalse,
+			Reason: "AsExpected",
+		}
+		if perMinute, covered := c.rate(now, budget.conditionType); covered && perMinute > budget.perMinute {
+			condition.Status = operatorv1.ConditionTrue
+			condition.Reason = "ErrorBudgetExceeded"
+			condition.Message = fmt.Sprintf("the %s queues of kube-controller-manager retried %.1f items per minute over the last %s, more than the budget of %v",
+				strings.Join(budget.queues, ", "), perMinute, window, budget.perMinute)
+			affected, err := budget.affected(ctx, now)
+			switch {
+			case err != nil:
+				condition.Message += fmt.Sprintf(". Failed to find the affected objects: %v", err)
+			case len(affected) > maxAffected:
+				condition.Message += fmt.Sprintf(". Affected: %s and %d more", strings.Join(affected[:maxAffected], ", "), len(affected)-maxAffected)
+			case len(affected) > 0:
+				condition.Message += fmt.Sprintf(". Affected: %s", strings.Join(affected, ", "))
+			}
+		}
+		conditions = append(conditions, v1helpers.UpdateConditionFn(condition))
+	}
+	return c.statusUpdater.UpdateStatus(ctx, conditions...)
+}
+
+// scrape returns the retries of the queues of every running kube-controller-manager pod. Only the leader runs the
+// controllers, the queues of the others stay empty.
+func (c *ErrorBudgetController) scrape(ctx context.Context) (map[string]map[string]float64, error) {
+	pods, err := c.podLister.Pods(operatorclient.TargetNamespace).List(labels.SelectorFromSet(labels.Set{"app": "kube-controller-manager"}))
+	if err != nil {
+		return nil, err
+	}
+	current := map[string]map[string]float64{}
+	failures := []string{}
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodRunning || len(pod.Status.PodIP) == 0 {
+			continue
+		}
+		data, err := c.fetch(ctx, pod, "/metrics")
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", pod.Name, err))
+			continue
+		}
+		retries, err := parseRetries(data)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", pod.Name, err))
+			continue
+		}
+		current[pod.Name] = retries
+	}
+	if len(current) == 0 && len(failures) > 0 {
+		return nil, fmt.Errorf("%s", strings.Join(failures, "; "))
+	}
+	return current, nil
+}
+
+// parseRetries returns the retries per queue of the metrics in the text exposition format.
+func parseRetries(data []byte) (map[string]float64, error) {
+	families, err := (&expfmt.TextParser{}).TextToMetricFamilies(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	retries := map[string]float64{}
+	family, ok := families[retriesMetric]
+	if !ok {
+		return retries, nil
+	}
+	for _, metric := range family.GetMetric() {
+		for _, label := range metric.GetLabel() {
+			if label.GetName() == "name" {
+				retries[label.GetValue()] += metric.GetCounter().GetValue()
+			}
+		}
+	}
+	return retries, nil
+}
+
+// addSample records the retries per budget since the previous scrape. A counter lower than before belongs to a
+// restarted process and counts from zero.
+func (c *ErrorBudgetController) addSample(now time.Time, current map[string]map[string]float64) {
+	added := sample{time: now, retries: map[string]float64{}}
+	for pod, retries := range current {
+		last, scraped := c.lastRetries[pod]
+		for _, budget := range c.budgets {
+			for _, queue := range budget.queues {
+				if !scraped {
+					continue
+				}
+				delta := retries[queue] - last[queue]
+				if delta < 0 {
+					delta = retries[queue]
+				}
+				added.retries[budget.conditionType] += delta
+			}
+		}
+	}
+	// the counters of pods which were not scraped this time are dropped, their restart is not noticed otherwise
+	c.lastRetries = current
+
+	// the last sample before the window is kept as its start
+	c.samples = append(c.samples, added)
+	for len(c.samples) > 1 && now.Sub(c.samples[1].time) >= window {
+		c.samples = c.samples[1:]
+	}
+}
+
+// rate returns the retries per minute of the budget over the window, and whether the samples cover all of it.
+func (c *ErrorBudgetController) rate(now time.Time, conditionType string) (float64, bool) {
+	if len(c.samples) < 2 || now.Sub(c.samples[0].time) < window {
+		return 0, false
+	}
+	total := 0.0
+	// the first sample holds the retries before the window
+	for _, sample := range c.samples[1:] {
+		total += sample.retries[conditionType]
+	}
+	return total / now.Sub(c.samples[0].time).Minutes(), true
+}
+
+// unsignedCSRs returns the approved certificate signing requests which are still waiting for their certificate.
+func unsignedCSRs(ctx context.Context, kubeClient kubernetes.Interface, now time.Time) ([]string, error) {
+	csrs, err := kubeClient.CertificatesV1().CertificateSigningRequests().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	affected := []string{}
+	for _, csr := range csrs.Items {
+		if len(csr.Status.Certificate) > 0 || now.Sub(csr.CreationTimestamp.Time) < stuckAfter {
+			continue
+		}
+		approved, failed := false, false
+		for _, condition := range csr.Status.Conditions {
+			switch condition.Type {
+			case certificatesv1.CertificateApproved:
+				approved = true
+			case certificatesv1.CertificateDenied, certificatesv1.CertificateFailed:
+				failed = true
+			}
+		}
+		if approved && !failed {
+			affected = append(affected, fmt.Sprintf("certificatesigningrequest/%s (%s)", csr.Name, csr.Spec.SignerName))
+		}
+	}
+	sort.Strings(affected)
+	return affected, nil
+}
+
+// namespacesWithoutTokens returns the namespaces with service accounts which are still waiting for their token secret.
+func namespacesWithoutTokens(ctx context.Context, kubeClient kubernetes.Interface, now time.Time) ([]string, error) {
+	serviceAccounts, err := kubeClient.CoreV1().ServiceAccounts(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := kubeClient.CoreV1().Secrets(metav1.NamespaceAll).List(ctx, metav1.ListOptions{FieldSelector: "type=" + string(corev1.SecretTypeServiceAccountToken)})
+	if err != nil {
+		return nil, err
+	}
+	withToken := sets.NewString()
+	for _, token := range tokens.Items {
+		withToken.Insert(token.Namespace + "/" + token.Annotations[corev1.ServiceAccountNameKey])
+	}
+	namespaces := sets.NewString()
+	for _, serviceAccount := range serviceAccounts.Items {
+		if now.Sub(serviceAccount.CreationTimestamp.Time) < stuckAfter || withToken.Has(serviceAccount.Namespace+"/"+serviceAccount.Name) {
+			continue
+		}
+		namespaces.Insert("namespace/" + serviceAccount.Namespace)
+	}
+	return namespaces.List(), nil
+}
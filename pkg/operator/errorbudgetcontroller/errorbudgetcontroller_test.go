@@ -0,0 +1,114 @@
+package errorbudgetcontroller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+)
+
+func TestParseRetries(t *testing.T) {
+	metrics := `# HELP workqueue_retries_total [ALPHA] Total number of retries handled by workqueue
+# TYPE workqueue_retries_total counter
+workqueue_retries_total{name="csrsigning-kubelet-serving"} 12
+workqueue_retries_total{name="serviceaccount_tokens_secret"} 3
+# HELP workqueue_adds_total [ALPHA] Total number of adds handled by workqueue
+# TYPE workqueue_adds_total counter
+workqueue_adds_total{name="csrsigning-kubelet-serving"} 40
+`
+	retries, err := parseRetries([]byte(metrics))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if retries["csrsigning-kubelet-serving"] != 12 || retries["serviceaccount_tokens_secret"] != 3 || len(retries) != 2 {
+		t.Errorf("unexpected retries %v", retries)
+	}
+}
+
+func TestSync(t *testing.T) {
+	start := time.Date(2026, 10, 15, 10, 0, 0, 0, time.UTC)
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	if err := indexer.Add(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "kube-controller-manager-master-0", Labels: map[string]string{"app": "kube-controller-manager"}},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.0.0.1"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	kubeClient := fake.NewSimpleClientset(
+		&certificatesv1.CertificateSigningRequest{
+			ObjectMeta: metav1.ObjectMeta{Name: "csr-stuck", CreationTimestamp: metav1.NewTime(start)},
+			Spec:       certificatesv1.CertificateSigningRequestSpec{SignerName: certificatesv1.KubeletServingSignerName},
+			Status:     certificatesv1.CertificateSigningRequestStatus{Conditions: []certificatesv1.CertificateSigningRequestCondition{{Type: certificatesv1.CertificateApproved}}},
+		},
+		&certificatesv1.CertificateSigningRequest{
+			ObjectMeta: metav1.ObjectMeta{Name: "csr-issued", CreationTimestamp: metav1.NewTime(start)},
+			Status:     certificatesv1.CertificateSigningRequestStatus{Conditions: []certificatesv1.CertificateSigningRequestCondition{{Type: certificatesv1.CertificateApproved}}, Certificate: []byte("cert")},
+		},
+	)
+
+	// the signing queues retry 5 items per minute, the token queues none
+	now := start
+	signingRetries := 0
+	operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil)
+	c := &ErrorBudgetController{
+		statusUpdater: statusbatcher.NewDirectUpdater(operatorClient),
+		podLister:     corev1listers.NewPodLister(indexer),
+		fetch: func(context.Context, *corev1.Pod, string) ([]byte, error) {
+			return []byte(fmt.Sprintf("# TYPE workqueue_retries_total counter\nworkqueue_retries_total{name=\"csrsigning-kubelet-serving\"} %d\nworkqueue_retries_total{name=\"serviceaccount\"} 7\n", signingRetries)), nil
+		},
+		budgets:     newBudgets(kubeClient),
+		now:         func() time.Time { return now },
+		lastRetries: map[string]map[string]float64{},
+	}
+
+	expectConditions := func(expectedSigning, expectedToken operatorv1.ConditionStatus, expectedMessage string) {
+		t.Helper()
+		if err := c.sync(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))); err != nil {
+			t.Fatal(err)
+		}
+		_, status, _, _ := operatorClient.GetOperatorState()
+		signing := v1helpers.FindOperatorCondition(status.Conditions, "CSRSigningDegraded")
+		token := v1helpers.FindOperatorCondition(status.Conditions, "TokenControllerDegraded")
+		if signing == nil || token == nil {
+			t.Fatalf("missing conditions in %v", status.Conditions)
+		}
+		if signing.Status != expectedSigning || signing.Message != expectedMessage {
+			t.Errorf("expected CSRSigningDegraded %s %q, got %s %q", expectedSigning, expectedMessage, signing.Status, signing.Message)
+		}
+		if token.Status != expectedToken {
+			t.Errorf("expected TokenControllerDegraded %s, got %s %q", expectedToken, token.Status, token.Message)
+		}
+	}
+
+	for i := 0; i < 10; i++ {
+		expectConditions(operatorv1.ConditionFalse, operatorv1.ConditionFalse, "")
+		now = now.Add(time.Minute)
+		signingRetries += 5
+	}
+	expectConditions(operatorv1.ConditionTrue, operatorv1.ConditionFalse, "the csrsigning-kubelet-serving, csrsigning-kubelet-client, csrsigning-kube-apiserver-client, csrsigning-legacy-unknown queues of kube-controller-manager retried 5.0 items per minute over the last 10m0s, more than the budget of 1. Affected: certificatesigningrequest/csr-stuck (kubernetes.io/kubelet-serving)")
+
+	// the condition clears once the retries stopped for the window
+	for i := 0; i < 10; i++ {
+		now = now.Add(time.Minute)
+		if err := c.sync(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	now = now.Add(time.Minute)
+	expectConditions(operatorv1.ConditionFalse, operatorv1.ConditionFalse, "")
+}
@@ -0,0 +1,201 @@
+package eventcategory
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/util/flowcontrol"
+	"k8s.io/component-base/metrics"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/clusteridentity"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatormetrics"
+)
+
+// Category groups the events of the operator by what they are about, for automation to select them without matching
+// on reasons.
+type Category string
+
+const (
+	// Rollout is about revisions and their installation on the nodes.
+	Rollout Category = "Rollout"
+	// CertRotation is about the signers and certificates the operator rotates.
+	CertRotation Category = "CertRotation"
+	// Observation is about the config observed from the cluster.
+	Observation Category = "Observation"
+	// Degradation is about conditions of the operator turning bad or recovering.
+	Degradation Category = "Degradation"
+
+	// annotationPrefix prefixes the category and the fields of the categorized events
+	annotationPrefix = "events.kube-controller-manager.openshift.io/"
+	// CategoryAnnotation holds the category of an event.
+	CategoryAnnotation = annotationPrefix + "category"
+)
+
+// Limit is the rate of events a category may emit. Events beyond it are dropped, so that a category flooding during an
+// incident does not drown the others.
+type Limit struct {
+	PerMinute float32
+	Burst     int
+}
+
+// DefaultLimits allow a rollout across the control plane nodes to emit its events in one burst.
+var DefaultLimits = map[Category]Limit{
+	Rollout:      {PerMinute: 30, Burst: 60},
+	CertRotation: {PerMinute: 10, Burst: 20},
+	Observation:  {PerMinute: 10, Burst: 30},
+	Degradation:  {PerMinute: 20, Burst: 30},
+}
+
+var droppedEvents = metrics.NewCounterVec(
+	&metrics.CounterOpts{
+		Name:           "kcm_operator_events_dropped_total",
+		Help:           "Number of events dropped because their category exceeded its rate limit.",
+		StabilityLevel: metrics.ALPHA,
+	},
+	[]string{"category"},
+)
+
+func init() {
+	operatormetrics.MustRegister(droppedEvents)
+}
+
+// Event is an event of a category, with structured fields added as annotations.
+type Event struct {
+	Category Category
+	// Type is corev1.EventTypeNormal or corev1.EventTypeWarning
+	Type    string
+	Reason  string
+	Message string
+	// Fields are added as events.kube-controller-manager.openshift.io/<name> annotations, the names have to be valid
+	// annotation names.
+	Fields map[string]string
+}
+
+// Recorder emits categorized events about the involved object, rate limited per category.
+type Recorder struct {
+	client            corev1client.EventInterface
+	involvedObjectRef *corev1.ObjectReference
+	component         string
+	limiters          map[Category]flowcontrol.PassiveRateLimiter
+	clock             clock.PassiveClock
+}
+
+// NewRecorder returns a recorder emitting the events of component about the involved object, like the one of the
+// events.Recorder of the operator. Categories without a limit are not limited.
+func NewRecorder(client corev1client.EventInterface, involvedObjectRef *corev1.ObjectReference, component string, limits map[Category]Limit) *Recorder {
+	return newRecorder(client, involvedObjectRef, component, limits, clock.RealClock{})
+}
+
+func newRecorder(client corev1client.EventInterface, involvedObjectRef *corev1.ObjectReference, component string, limits map[Category]Limit, clock clock.PassiveClock) *Recorder {
+	limiters := map[Category]flowcontrol.PassiveRateLimiter{}
+	for category, limit := range limits {
+		limiters[category] = flowcontrol.NewTokenBucketPassiveRateLimiterWithClock(limit.PerMinute/60, limit.Burst, clock)
+	}
+	return &Recorder{
+		client:            client,
+		involvedObjectRef: involvedObjectRef,
+		component:         component,
+		limiters:          limiters,
+		clock:             clock,
+	}
+}
+
+// Record emits the event from the component, unless its category exceeded its limit.
+func (r *Recorder) Record(ctx context.Context, component string, event Event) {
+	if limiter, ok := r.limiters[event.Category]; ok && !limiter.TryAccept() {
+		droppedEvents.WithLabelValues(string(event.Category)).Inc()
+		klog.Infof("Dropped %s event %s(%s): %s, the category exceeded its rate limit", event.Category, event.Type, event.Reason, event.Message)
+		return
+	}
+
+	annotations := map[string]string{CategoryAnnotation: string(event.Category)}
+	for name, value := range event.Fields {
+		annotations[annotationPrefix+name] = value
+	}
+	now := metav1.Time{Time: r.clock.Now()}
+	created := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%v.%x", r.involvedObjectRef.Name, now.UnixNano()),
+			Namespace: r.involvedObjectRef.Namespace,
+			// fleet-wide tooling selects the events of the clusters of a platform or topology by these labels
+			Labels:      clusteridentity.Current().EventLabels(),
+			Annotations: annotations,
+		},
+		InvolvedObject: *r.involvedObjectRef,
+		Reason:         event.Reason,
+		Message:        event.Message,
+		Type:           event.Type,
+		Count:          1,
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Source:         corev1.EventSource{Component: component},
+	}
+	if _, err := r.client.Create(ctx, created, metav1.CreateOptions{}); err != nil {
+		klog.Warningf("Error creating event %+v: %v", created, err)
+	}
+}
+
+// For returns an events.Recorder emitting the events of the category, for the controllers built on events.Recorder.
+func (r *Recorder) For(category Category) events.Recorder {
+	return &categoryRecorder{recorder: r, category: category, component: r.component}
+}
+
+type categoryRecorder struct {
+	recorder  *Recorder
+	category  Category
+	component string
+	ctx       context.Context
+}
+
+func (r *categoryRecorder) record(eventType, reason, message string) {
+	ctx := context.Background()
+	if r.ctx != nil {
+		ctx = r.ctx
+	}
+	r.recorder.Record(ctx, r.component, Event{Category: r.category, Type: eventType, Reason: reason, Message: message})
+}
+
+func (r *categoryRecorder) Event(reason, message string) {
+	r.record(corev1.EventTypeNormal, reason, message)
+}
+
+func (r *categoryRecorder) Eventf(reason, messageFmt string, args ...interface{}) {
+	r.Event(reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r *categoryRecorder) Warning(reason, message string) {
+	r.record(corev1.EventTypeWarning, reason, message)
+}
+
+func (r *categoryRecorder) Warningf(reason, messageFmt string, args ...interface{}) {
+	r.Warning(reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r *categoryRecorder) ForComponent(componentName string) events.Recorder {
+	ret := *r
+	ret.component = componentName
+	return &ret
+}
+
+func (r *categoryRecorder) WithComponentSuffix(componentNameSuffix string) events.Recorder {
+	return r.ForComponent(fmt.Sprintf("%s-%s", r.component, componentNameSuffix))
+}
+
+func (r *categoryRecorder) WithContext(ctx context.Context) events.Recorder {
+	ret := *r
+	ret.ctx = ctx
+	return &ret
+}
+
+func (r *categoryRecorder) ComponentName() string {
+	return r.component
+}
+
+func (r *categoryRecorder) Shutdown() {}
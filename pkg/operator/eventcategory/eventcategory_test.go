@@ -0,0 +1,73 @@
+package eventcategory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	clocktesting "k8s.io/utils/clock/testing"
+)
+
+func TestRecorder(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	clock := clocktesting.NewFakePassiveClock(time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC))
+	involvedObjectRef := &corev1.ObjectReference{Kind: "Deployment", Namespace: "openshift-kube-controller-manager-operator", Name: "kube-controller-manager-operator"}
+	recorder := newRecorder(kubeClient.CoreV1().Events(involvedObjectRef.Namespace), involvedObjectRef, "kube-controller-manager-operator", map[Category]Limit{
+		Rollout: {PerMinute: 1, Burst: 2},
+	}, clock)
+
+	recorder.Record(context.TODO(), "kube-controller-manager-operator", Event{
+		Category: Rollout,
+		Type:     corev1.EventTypeNormal,
+		Reason:   "RevisionTriggered",
+		Message:  "new revision 4 triggered",
+		Fields:   map[string]string{"revision": "4"},
+	})
+	// the second event of the burst passes, the third exceeds the limit
+	rollout := recorder.For(Rollout).WithComponentSuffix("installer")
+	for i := 0; i < 2; i++ {
+		clock.SetTime(clock.Now().Add(time.Second))
+		rollout.Warningf("InstallerPodFailed", "installer pod %d failed", i)
+	}
+	// categories without a limit are not limited
+	for i := 0; i < 5; i++ {
+		clock.SetTime(clock.Now().Add(time.Second))
+		recorder.For(Degradation).Warning("OperatorDegraded", "degraded")
+	}
+	// the limit refills with time
+	clock.SetTime(clock.Now().Add(time.Minute))
+	rollout.Event("RevisionCreated", "revision 5 created")
+
+	events, err := kubeClient.CoreV1().Events(involvedObjectRef.Namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	byCategory := map[string][]corev1.Event{}
+	for _, event := range events.Items {
+		byCategory[event.Annotations[CategoryAnnotation]] = append(byCategory[event.Annotations[CategoryAnnotation]], event)
+	}
+	if len(byCategory[string(Rollout)]) != 3 || len(byCategory[string(Degradation)]) != 5 {
+		t.Fatalf("expected 3 rollout and 5 degradation events, got %d and %d", len(byCategory[string(Rollout)]), len(byCategory[string(Degradation)]))
+	}
+	for _, event := range byCategory[string(Rollout)] {
+		switch event.Reason {
+		case "RevisionTriggered":
+			if event.Annotations["events.kube-controller-manager.openshift.io/revision"] != "4" || event.Source.Component != "kube-controller-manager-operator" {
+				t.Errorf("unexpected event %v", event)
+			}
+		case "InstallerPodFailed":
+			if event.Message != "installer pod 0 failed" || event.Type != corev1.EventTypeWarning || event.Source.Component != "kube-controller-manager-operator-installer" {
+				t.Errorf("unexpected event %v", event)
+			}
+		case "RevisionCreated":
+		default:
+			t.Errorf("unexpected event %v", event)
+		}
+		if event.InvolvedObject != *involvedObjectRef {
+			t.Errorf("unexpected involved object %v", event.InvolvedObject)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package externalmetrics
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+)
+
+const (
+	listenAddressEnv = "EXTERNAL_METRICS_LISTEN_ADDRESS"
+	clientCAFileEnv  = "EXTERNAL_METRICS_CLIENT_CA_FILE"
+	certFileEnv      = "EXTERNAL_METRICS_TLS_CERT_FILE"
+	keyFileEnv       = "EXTERNAL_METRICS_TLS_KEY_FILE"
+
+	// the serving certificate of the operator is used unless another one is configured
+	defaultCertFile = "/var/run/secrets/serving-cert/tls.crt"
+	defaultKeyFile  = "/var/run/secrets/serving-cert/tls.key"
+)
+
+// Options configure the additional metrics listener for scrapers outside of the cluster network. Clients are
+// authenticated by a certificate of the client CA only, independent of the in-cluster authentication.
+type Options struct {
+	ListenAddress string
+	ClientCAFile  string
+	CertFile      string
+	KeyFile       string
+}
+
+// OptionsFromEnv reads the listener options from EXTERNAL_METRICS_LISTEN_ADDRESS, EXTERNAL_METRICS_CLIENT_CA_FILE,
+// EXTERNAL_METRICS_TLS_CERT_FILE and EXTERNAL_METRICS_TLS_KEY_FILE. It returns nil when no listen address is set.
+func OptionsFromEnv() (*Options, error) {
+	opts := &Options{
+		ListenAddress: os.Getenv(listenAddressEnv),
+		ClientCAFile:  os.Getenv(clientCAFileEnv),
+		CertFile:      os.Getenv(certFileEnv),
+		KeyFile:       os.Getenv(keyFileEnv),
+	}
+	if len(opts.ListenAddress) == 0 {
+		return nil, nil
+	}
+	if len(opts.ClientCAFile) == 0 {
+		return nil, fmt.Errorf("%s is required with %s", clientCAFileEnv, listenAddressEnv)
+	}
+	if len(opts.CertFile) == 0 && len(opts.KeyFile) == 0 {
+		opts.CertFile, opts.KeyFile = defaultCertFile, defaultKeyFile
+	}
+	if len(opts.CertFile) == 0 || len(opts.KeyFile) == 0 {
+		return nil, fmt.Errorf("%s and %s must be set together", certFileEnv, keyFileEnv)
+	}
+	return opts, nil
+}
+
+// Serve exposes the metrics on the listen address until the context is done.
+func Serve(ctx context.Context, opts *Options) error {
+	listener, err := net.Listen("tcp", opts.ListenAddress)
+	if err != nil {
+		return err
+	}
+	return serve(ctx, listener, opts)
+}
+
+func serve(ctx context.Context, listener net.Listener, opts *Options) error {
+	files := &tlsFiles{opts: opts}
+	// load once upfront, so that a broken configuration fails early
+	if _, err := files.config(); err != nil {
+		listener.Close()
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(legacyregistry.DefaultGatherer, promhttp.HandlerOpts{}))
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 30 * time.Second,
+		TLSConfig: &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			// the files are re-read on rotation
+			GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+				return files.config()
+			},
+		},
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	klog.Infof("Serving metrics to external scrapers on %s", listener.Addr())
+	if err := server.ServeTLS(listener, "", ""); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// tlsFiles caches the TLS config built from the certificate files, until one of them is modified.
+type tlsFiles struct {
+	opts *Options
+
+	lock     sync.Mutex
+	modTimes [3]time.Time
+	cached   *tls.Config
+}
+
+func (f *tlsFiles) config() (*tls.Config, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	modTimes := [3]time.Time{}
+	for i, path := range []string{f.opts.ClientCAFile, f.opts.CertFile, f.opts.KeyFile} {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		modTimes[i] = info.ModTime()
+	}
+	if f.cached != nil && modTimes == f.modTimes {
+		return f.cached, nil
+	}
+
+	certificate, err := tls.LoadX509KeyPair(f.opts.CertFile, f.opts.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the serving certificate: %v", err)
+	}
+	caBundle, err := ioutil.ReadFile(f.opts.ClientCAFile)
+	if err != nil {
+		return nil, err
+	}
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(caBundle) {
+		return nil, fmt.Errorf("no certificates found in %s", f.opts.ClientCAFile)
+	}
+
+	f.modTimes = modTimes
+	f.cached = &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{certificate},
+		ClientCAs:    clientCAs,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	}
+	return f.cached, nil
+}
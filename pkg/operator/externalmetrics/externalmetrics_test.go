@@ -0,0 +1,138 @@
+package externalmetrics
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/authentication/user"
+
+	"github.com/openshift/library-go/pkg/crypto"
+)
+
+func makeCA(t *testing.T, name string) *crypto.CA {
+	config, err := crypto.MakeSelfSignedCAConfigForDuration(name, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &crypto.CA{Config: config, SerialGenerator: &crypto.RandomSerialGenerator{}}
+}
+
+func writePEM(t *testing.T, dir, name string, config *crypto.TLSCertificateConfig) (string, string) {
+	certPEM, keyPEM, err := config.GetPEMBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile, keyFile := filepath.Join(dir, name+".crt"), filepath.Join(dir, name+".key")
+	if err := ioutil.WriteFile(certFile, certPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(keyFile, keyPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+func TestServe(t *testing.T) {
+	dir, err := ioutil.TempDir("", "externalmetrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	servingCA, clientCA, otherCA := makeCA(t, "serving"), makeCA(t, "client"), makeCA(t, "other")
+	servingCert, err := servingCA.MakeServerCertForDuration(sets.NewString("127.0.0.1"), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile, keyFile := writePEM(t, dir, "serving", servingCert)
+	clientCAFile, _ := writePEM(t, dir, "client-ca", clientCA.Config)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- serve(ctx, listener, &Options{ClientCAFile: clientCAFile, CertFile: certFile, KeyFile: keyFile})
+	}()
+
+	get := func(signer *crypto.CA, path string) (int, error) {
+		roots := x509.NewCertPool()
+		roots.AddCert(servingCA.Config.Certs[0])
+		tlsConfig := &tls.Config{RootCAs: roots}
+		if signer != nil {
+			clientCert, err := signer.MakeClientCertificateForDuration(&user.DefaultInfo{Name: "scraper"}, time.Hour)
+			if err != nil {
+				t.Fatal(err)
+			}
+			certPEM, keyPEM, err := clientCert.GetPEMBytes()
+			if err != nil {
+				t.Fatal(err)
+			}
+			pair, err := tls.X509KeyPair(certPEM, keyPEM)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tlsConfig.Certificates = []tls.Certificate{pair}
+		}
+		client := &http.Client{Timeout: 5 * time.Second, Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+		resp, err := client.Get("https://" + listener.Addr().String() + path)
+		if err != nil {
+			return 0, err
+		}
+		defer resp.Body.Close()
+		return resp.StatusCode, nil
+	}
+
+	if code, err := get(clientCA, "/metrics"); err != nil || code != http.StatusOK {
+		t.Errorf("expected the metrics to be served to trusted clients, got %d, %v", code, err)
+	}
+	if code, err := get(clientCA, "/debug/decisions"); err != nil || code != http.StatusNotFound {
+		t.Errorf("expected only the metrics to be served, got %d, %v", code, err)
+	}
+	if _, err := get(otherCA, "/metrics"); err == nil {
+		t.Errorf("expected clients of another CA to be rejected")
+	}
+	if _, err := get(nil, "/metrics"); err == nil {
+		t.Errorf("expected clients without certificate to be rejected")
+	}
+
+	cancel()
+	if err := <-errCh; err != nil {
+		t.Errorf("unexpected error on shutdown: %v", err)
+	}
+}
+
+func TestOptionsFromEnv(t *testing.T) {
+	os.Unsetenv(listenAddressEnv)
+	if opts, err := OptionsFromEnv(); opts != nil || err != nil {
+		t.Errorf("expected no listener without address, got %v, %v", opts, err)
+	}
+
+	os.Setenv(listenAddressEnv, ":9443")
+	defer os.Unsetenv(listenAddressEnv)
+	if _, err := OptionsFromEnv(); err == nil {
+		t.Errorf("expected the client CA to be required")
+	}
+
+	os.Setenv(clientCAFileEnv, "/etc/ca.crt")
+	defer os.Unsetenv(clientCAFileEnv)
+	opts, err := OptionsFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.CertFile != defaultCertFile || opts.KeyFile != defaultKeyFile {
+		t.Errorf("expected the operator serving certificate by default, got %#v", opts)
+	}
+}
@@ -0,0 +1,8 @@
+// Package faultinjection breaks the operator on request so that e2e suites can exercise the Degraded paths and the
+// recovery from them without breaking the cluster itself. It is compiled in with the faultinjection build tag only,
+// release builds get the no-op WrapKubeClient.
+package faultinjection
+
+// AnnotationName on the kubecontrollermanager resource lists the faults to inject, it is ignored unless the operator
+// is built with the faultinjection tag.
+const AnnotationName = "kube-controller-manager.openshift.io/inject-faults"
@@ -0,0 +1,15 @@
+//go:build !faultinjection
+// +build !faultinjection
+
+package faultinjection
+
+import (
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+// WrapKubeClient returns the client unchanged, faults are injected in builds with the faultinjection tag only.
+func WrapKubeClient(kubeClient kubernetes.Interface, _ v1helpers.OperatorClient) kubernetes.Interface {
+	return kubeClient
+}
@@ -0,0 +1,176 @@
+//go:build faultinjection
+// +build faultinjection
+
+package faultinjection
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+const (
+	// FailNextRevision fails the creation of the next revision once per token, e.g. fail-next-revision=1.
+	FailNextRevision = "fail-next-revision"
+	// CorruptNextRevision replaces the config of the next revision with an unparsable one once per token.
+	CorruptNextRevision = "corrupt-next-revision"
+	// DelayConfigMapReads delays every read of the configmaps in the target namespace, e.g. delay-configmap-reads=5s.
+	DelayConfigMapReads = "delay-configmap-reads"
+
+	corruptRevisionData = "{ corrupted by fault injection"
+)
+
+var (
+	revisionStatusName = regexp.MustCompile(`^revision-status-\d+$`)
+	revisionConfigName = regexp.MustCompile(`^config-\d+$`)
+)
+
+// faults reads the faults to inject from the annotation of the operator resource and remembers which one-shot faults
+// fired already.
+type faults struct {
+	operatorClient v1helpers.OperatorClient
+
+	lock  sync.Mutex
+	fired map[string]bool
+}
+
+// WrapKubeClient returns a client which injects the faults the operator resource asks for in its annotation, as a
+// comma separated list of <fault>[=<value>].
+func WrapKubeClient(kubeClient kubernetes.Interface, operatorClient v1helpers.OperatorClient) kubernetes.Interface {
+	klog.Warningf("Fault injection is compiled in, faults are read from the %s annotation", AnnotationName)
+	return &faultyKubeClient{
+		Interface: kubeClient,
+		faults:    &faults{operatorClient: operatorClient, fired: map[string]bool{}},
+	}
+}
+
+// requested returns the value of the fault and whether it is requested at all.
+func (f *faults) requested(fault string) (string, bool) {
+	meta, err := f.operatorClient.GetObjectMeta()
+	if err != nil {
+		return "", false
+	}
+	value, ok := parse(meta.Annotations[AnnotationName])[fault]
+	return value, ok
+}
+
+// fire reports whether a one-shot fault is requested with a token it did not fire for yet, and marks it fired.
+func (f *faults) fire(fault string) bool {
+	token, ok := f.requested(fault)
+	if !ok {
+		return false
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	key := fault + "=" + token
+	if f.fired[key] {
+		return false
+	}
+	f.fired[key] = true
+	klog.Warningf("Injecting fault %s", key)
+	return true
+}
+
+func (f *faults) delayConfigMapRead(ctx context.Context, namespace string) error {
+	if namespace != operatorclient.TargetNamespace {
+		return nil
+	}
+	value, ok := f.requested(DelayConfigMapReads)
+	if !ok {
+		return nil
+	}
+	delay, err := time.ParseDuration(value)
+	if err != nil {
+		klog.Warningf("Ignoring fault %s=%s: %v", DelayConfigMapReads, value, err)
+		return nil
+	}
+	select {
+	case <-time.After(delay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func parse(annotation string) map[string]string {
+	ret := map[string]string{}
+	for _, fault := range strings.Split(annotation, ",") {
+		fault = strings.TrimSpace(fault)
+		if len(fault) == 0 {
+			continue
+		}
+		name, value := fault, ""
+		if i := strings.Index(fault, "="); i >= 0 {
+			name, value = fault[:i], fault[i+1:]
+		}
+		ret[name] = value
+	}
+	return ret
+}
+
+type faultyKubeClient struct {
+	kubernetes.Interface
+	faults *faults
+}
+
+func (c *faultyKubeClient) CoreV1() corev1client.CoreV1Interface {
+	return &faultyCoreV1{CoreV1Interface: c.Interface.CoreV1(), faults: c.faults}
+}
+
+type faultyCoreV1 struct {
+	corev1client.CoreV1Interface
+	faults *faults
+}
+
+func (c *faultyCoreV1) ConfigMaps(namespace string) corev1client.ConfigMapInterface {
+	return &faultyConfigMaps{ConfigMapInterface: c.CoreV1Interface.ConfigMaps(namespace), namespace: namespace, faults: c.faults}
+}
+
+type faultyConfigMaps struct {
+	corev1client.ConfigMapInterface
+	namespace string
+	faults    *faults
+}
+
+func (c *faultyConfigMaps) Create(ctx context.Context, configMap *corev1.ConfigMap, opts metav1.CreateOptions) (*corev1.ConfigMap, error) {
+	if c.namespace == operatorclient.TargetNamespace {
+		switch {
+		case revisionStatusName.MatchString(configMap.Name) && c.faults.fire(FailNextRevision):
+			return nil, fmt.Errorf("fault injected: %s", FailNextRevision)
+		case revisionConfigName.MatchString(configMap.Name) && c.faults.fire(CorruptNextRevision):
+			configMap = configMap.DeepCopy()
+			if configMap.Data == nil {
+				configMap.Data = map[string]string{}
+			}
+			configMap.Data["config.yaml"] = corruptRevisionData
+		}
+	}
+	return c.ConfigMapInterface.Create(ctx, configMap, opts)
+}
+
+func (c *faultyConfigMaps) Get(ctx context.Context, name string, opts metav1.GetOptions) (*corev1.ConfigMap, error) {
+	if err := c.faults.delayConfigMapRead(ctx, c.namespace); err != nil {
+		return nil, err
+	}
+	return c.ConfigMapInterface.Get(ctx, name, opts)
+}
+
+func (c *faultyConfigMaps) List(ctx context.Context, opts metav1.ListOptions) (*corev1.ConfigMapList, error) {
+	if err := c.faults.delayConfigMapRead(ctx, c.namespace); err != nil {
+		return nil, err
+	}
+	return c.ConfigMapInterface.List(ctx, opts)
+}
@@ -0,0 +1,82 @@
+//go:build faultinjection
+// +build faultinjection
+
+package faultinjection
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+type annotatedOperatorClient struct {
+	v1helpers.OperatorClient
+	annotation string
+}
+
+func (c *annotatedOperatorClient) GetObjectMeta() (*metav1.ObjectMeta, error) {
+	return &metav1.ObjectMeta{Annotations: map[string]string{AnnotationName: c.annotation}}, nil
+}
+
+func TestRevisionFaults(t *testing.T) {
+	operatorClient := &annotatedOperatorClient{
+		OperatorClient: v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+		annotation:     "fail-next-revision=a, corrupt-next-revision=a",
+	}
+	configMaps := WrapKubeClient(fake.NewSimpleClientset(), operatorClient).CoreV1().ConfigMaps(operatorclient.TargetNamespace)
+	create := func(name string) (*corev1.ConfigMap, error) {
+		return configMaps.Create(context.TODO(), &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: name},
+			Data:       map[string]string{"config.yaml": "{}"},
+		}, metav1.CreateOptions{})
+	}
+
+	if _, err := create("revision-status-2"); err == nil {
+		t.Error("expected the first revision to fail")
+	}
+	if _, err := create("revision-status-2"); err != nil {
+		t.Errorf("expected the fault to fire once per token, got %v", err)
+	}
+	if configMap, err := create("config-2"); err != nil || configMap.Data["config.yaml"] != corruptRevisionData {
+		t.Errorf("expected the revision to be corrupted, got %v, %v", configMap, err)
+	}
+	if configMap, err := create("config-3"); err != nil || configMap.Data["config.yaml"] != "{}" {
+		t.Errorf("expected the next revision to be left alone, got %v, %v", configMap, err)
+	}
+
+	operatorClient.annotation = "fail-next-revision=b"
+	if _, err := create("revision-status-3"); err == nil {
+		t.Error("expected a new token to fail the next revision again")
+	}
+}
+
+func TestDelayConfigMapReads(t *testing.T) {
+	operatorClient := &annotatedOperatorClient{
+		OperatorClient: v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+		annotation:     "delay-configmap-reads=50ms",
+	}
+	kubeClient := WrapKubeClient(fake.NewSimpleClientset(), operatorClient)
+
+	start := time.Now()
+	if _, err := kubeClient.CoreV1().ConfigMaps(operatorclient.TargetNamespace).List(context.TODO(), metav1.ListOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("expected the read to be delayed, took %s", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	if _, err := kubeClient.CoreV1().ConfigMaps(operatorclient.TargetNamespace).Get(ctx, "config", metav1.GetOptions{}); err != context.Canceled {
+		t.Errorf("expected a cancelled read to give up, got %v", err)
+	}
+}
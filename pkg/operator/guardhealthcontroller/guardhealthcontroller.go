@@ -0,0 +1,101 @@
+package guardhealthcontroller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/controllerpause"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operationprofile"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
+)
+
+const (
+	conditionType = "GuardPodsDegraded"
+
+	// unreadyThreshold tolerates the guard pods going unready while the operand restarts during a rollout
+	unreadyThreshold = 5 * time.Minute
+)
+
+// GuardHealthController reports guard pods which have not been ready for a while.
+// The guards back the PodDisruptionBudget protecting kube-controller-manager from being drained on all nodes at once,
+// an unready guard means the PodDisruptionBudget blocks drains of the other nodes.
+type GuardHealthController struct {
+	statusUpdater statusbatcher.StatusUpdater
+	podLister     corev1listers.PodLister
+	now           func() time.Time
+}
+
+func NewGuardHealthController(
+	operatorClient v1helpers.OperatorClient,
+	statusUpdater statusbatcher.StatusUpdater,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &GuardHealthController{
+		statusUpdater: statusUpdater,
+		podLister:     kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().Pods().Lister(),
+		now:           time.Now,
+	}
+
+	return factory.New().WithInformers(
+		operatorClient.Informer(),
+		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().Pods().Informer(),
+	).ResyncEvery(operationprofile.Resync(time.Minute)).WithSync(syncmetrics.Timed("GuardHealthController", controllerpause.Pausable("GuardHealthController", c.sync))).ToController("GuardHealthController", eventRecorder)
+}
+
+func (c *GuardHealthController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	pods, err := c.podLister.Pods(operatorclient.TargetNamespace).List(labels.SelectorFromSet(labels.Set{"app": "guard"}))
+	if err != nil {
+		return err
+	}
+
+	unready := []string{}
+	for _, pod := range pods {
+		if since, ok := unreadySince(pod); ok && c.now().Sub(since) > unreadyThreshold {
+			unready = append(unready, fmt.Sprintf("%s on node %s not ready since %s", pod.Name, pod.Spec.NodeName, since.UTC().Format(time.RFC3339)))
+		}
+	}
+	sort.Strings(unready)
+
+	condition := operatorv1.OperatorCondition{
+		Type:   conditionType,
+		Status: operatorv1.ConditionFalse,
+	}
+	if len(unready) > 0 {
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "GuardPodsNotReady"
+		condition.Message = strings.Join(unready, "\n")
+	}
+	return c.statusUpdater.UpdateStatus(ctx, v1helpers.UpdateConditionFn(condition))
+}
+
+// unreadySince returns when the pod became unready, pods which never reported readiness count from their creation.
+func unreadySince(pod *corev1.Pod) (time.Time, bool) {
+	if pod.DeletionTimestamp != nil {
+		return time.Time{}, false
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type != corev1.PodReady {
+			continue
+		}
+		if condition.Status == corev1.ConditionTrue {
+			return time.Time{}, false
+		}
+		return condition.LastTransitionTime.Time, true
+	}
+	return pod.CreationTimestamp.Time, true
+}
@@ -0,0 +1,98 @@
+package guardhealthcontroller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+)
+
+func guardPod(name string, created time.Time, ready *corev1.PodCondition) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         operatorclient.TargetNamespace,
+			Name:              name,
+			Labels:            map[string]string{"app": "guard"},
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Spec: corev1.PodSpec{NodeName: name + "-node"},
+	}
+	if ready != nil {
+		pod.Status.Conditions = []corev1.PodCondition{*ready}
+	}
+	return pod
+}
+
+func TestSync(t *testing.T) {
+	now := time.Date(2022, 2, 1, 10, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name            string
+		pods            []*corev1.Pod
+		expectedStatus  operatorv1.ConditionStatus
+		expectedMessage string
+	}{
+		{
+			name:           "no guards",
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+		{
+			name: "ready",
+			pods: []*corev1.Pod{
+				guardPod("a", now.Add(-time.Hour), &corev1.PodCondition{Type: corev1.PodReady, Status: corev1.ConditionTrue}),
+			},
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+		{
+			name: "recently unready",
+			pods: []*corev1.Pod{
+				guardPod("a", now.Add(-time.Hour), &corev1.PodCondition{Type: corev1.PodReady, Status: corev1.ConditionFalse, LastTransitionTime: metav1.NewTime(now.Add(-time.Minute))}),
+			},
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+		{
+			name: "unready for long",
+			pods: []*corev1.Pod{
+				guardPod("a", now.Add(-time.Hour), &corev1.PodCondition{Type: corev1.PodReady, Status: corev1.ConditionFalse, LastTransitionTime: metav1.NewTime(now.Add(-10 * time.Minute))}),
+				guardPod("b", now.Add(-time.Hour), nil),
+			},
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedMessage: "a on node a-node not ready since 2022-02-01T09:50:00Z\nb on node b-node not ready since 2022-02-01T09:00:00Z",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			for _, pod := range tc.pods {
+				if err := indexer.Add(pod); err != nil {
+					t.Fatal(err)
+				}
+			}
+			operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil)
+			c := &GuardHealthController{
+				statusUpdater: statusbatcher.NewDirectUpdater(operatorClient),
+				podLister:     corev1listers.NewPodLister(indexer),
+				now:           func() time.Time { return now },
+			}
+			if err := c.sync(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))); err != nil {
+				t.Fatal(err)
+			}
+			_, status, _, _ := operatorClient.GetOperatorState()
+			condition := v1helpers.FindOperatorCondition(status.Conditions, conditionType)
+			if condition == nil || condition.Status != tc.expectedStatus || condition.Message != tc.expectedMessage {
+				t.Errorf("expected %s %q, got %v", tc.expectedStatus, tc.expectedMessage, condition)
+			}
+		})
+	}
+}
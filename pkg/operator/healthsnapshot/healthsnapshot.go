@@ -0,0 +1,238 @@
+package healthsnapshot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/backpressure"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/controllerpause"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operationprofile"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statestore"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/upgradeablecontroller"
+)
+
+const (
+	// ConfigMapName is the state document in the operator namespace holding the health snapshot collected by Insights.
+	// Insights collects the configmap, with the CustomResource state storage the snapshot is not collected.
+	ConfigMapName = "kube-controller-manager-operator-health-snapshot"
+	snapshotKey   = "snapshot.json"
+
+	// maxConvergences keeps the document small, the fleet analysis only needs the recent rollouts
+	maxConvergences = 10
+
+	revisionStatusPrefix = "revision-status-"
+)
+
+// Snapshot is the anonymized health of the operator. It carries neither node names nor condition messages,
+// which may name hosts and addresses of the cluster.
+type Snapshot struct {
+	Conditions              []Condition `json:"conditions,omitempty"`
+	LatestAvailableRevision int32       `json:"latestAvailableRevision"`
+	Nodes                   []Node      `json:"nodes,omitempty"`
+	// Convergences are the latest revisions that reached every node, oldest first.
+	Convergences []Convergence `json:"convergences,omitempty"`
+	// MinTLSVersion is the observed TLS security profile of kube-controller-manager.
+	MinTLSVersion string `json:"minTLSVersion,omitempty"`
+	// CertificateExpiry is the expiry bucket of the certificates the operand depends on, keyed by their secret.
+	CertificateExpiry map[string]string `json:"certificateExpiry,omitempty"`
+}
+
+// Condition is an operator condition without its message.
+type Condition struct {
+	Type   string                     `json:"type"`
+	Status operatorv1.ConditionStatus `json:"status"`
+	Reason string                     `json:"reason,omitempty"`
+}
+
+// Node is the revision state of a master node, in the order of the operator status.
+type Node struct {
+	CurrentRevision int32 `json:"currentRevision"`
+	TargetRevision  int32 `json:"targetRevision,omitempty"`
+	Failed          bool  `json:"failed,omitempty"`
+}
+
+// Convergence is the time a revision took from its creation until every node ran it.
+type Convergence struct {
+	Revision int32 `json:"revision"`
+	Seconds  int64 `json:"seconds"`
+}
+
+// HealthSnapshotController keeps the health snapshot up to date for the Insights operator to collect it.
+type HealthSnapshotController struct {
+	operatorClient  v1helpers.StaticPodOperatorClient
+	configMapLister corev1listers.ConfigMapLister
+	secretLister    corev1listers.SecretLister
+	stateStore      statestore.Store
+	now             func() time.Time
+}
+
+func NewHealthSnapshotController(
+	operatorClient v1helpers.StaticPodOperatorClient,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	stateStore statestore.Store,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &HealthSnapshotController{
+		operatorClient:  operatorClient,
+		configMapLister: kubeInformersForNamespaces.ConfigMapLister(),
+		secretLister:    kubeInformersForNamespaces.SecretLister(),
+		stateStore:      stateStore,
+		now:             time.Now,
+	}
+
+	// certificate expiry buckets only change with time, a slow resync is enough to refresh them
+	return factory.New().WithInformers(
+		operatorClient.Informer(),
+		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().ConfigMaps().Informer(),
+		stateStore.Informer(),
+	).ResyncEvery(operationprofile.Resync(10*time.Minute)).WithSync(syncmetrics.Timed("HealthSnapshotController", controllerpause.Pausable("HealthSnapshotController", backpressure.Deferrable("HealthSnapshotController", c.sync)))).ToController("HealthSnapshotController", eventRecorder)
+}
+
+func (c *HealthSnapshotController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	spec, status, _, err := c.operatorClient.GetStaticPodOperatorState()
+	if err != nil {
+		return err
+	}
+	previous, err := c.previousSnapshot()
+	if err != nil {
+		return err
+	}
+
+	snapshot := &Snapshot{
+		LatestAvailableRevision: status.LatestAvailableRevision,
+		Convergences:            previous.Convergences,
+		MinTLSVersion:           minTLSVersion(spec.ObservedConfig.Raw),
+	}
+	for _, condition := range status.Conditions {
+		snapshot.Conditions = append(snapshot.Conditions, Condition{Type: condition.Type, Status: condition.Status, Reason: condition.Reason})
+	}
+	sort.Slice(snapshot.Conditions, func(i, j int) bool { return snapshot.Conditions[i].Type < snapshot.Conditions[j].Type })
+	for _, nodeStatus := range status.NodeStatuses {
+		snapshot.Nodes = append(snapshot.Nodes, Node{
+			CurrentRevision: nodeStatus.CurrentRevision,
+			TargetRevision:  nodeStatus.TargetRevision,
+			Failed:          nodeStatus.LastFailedRevision != 0 && nodeStatus.LastFailedRevision >= nodeStatus.CurrentRevision,
+		})
+	}
+	if convergence, ok, err := c.convergence(status); err != nil {
+		return err
+	} else if ok {
+		snapshot.Convergences = appendConvergence(snapshot.Convergences, convergence)
+	}
+	expiries, err := upgradeablecontroller.CertificateExpiries(c.secretLister)
+	if err != nil {
+		return err
+	}
+	if len(expiries) > 0 {
+		snapshot.CertificateExpiry = map[string]string{}
+		for secret, notAfter := range expiries {
+			snapshot.CertificateExpiry[secret] = expiryBucket(notAfter.Sub(c.now()))
+		}
+	}
+
+	raw, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	return c.stateStore.Put(ctx, syncCtx.Recorder(), ConfigMapName, map[string]string{snapshotKey: string(raw)})
+}
+
+func (c *HealthSnapshotController) previousSnapshot() (*Snapshot, error) {
+	ret := &Snapshot{}
+	data, err := c.stateStore.Get(ConfigMapName)
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return ret, nil
+	}
+	if err := json.Unmarshal([]byte(data[snapshotKey]), ret); err != nil {
+		// start over rather than getting stuck on a hand-edited document
+		return &Snapshot{}, nil
+	}
+	return ret, nil
+}
+
+// convergence returns how long the latest available revision took to reach every node, once it did.
+func (c *HealthSnapshotController) convergence(status *operatorv1.StaticPodOperatorStatus) (Convergence, bool, error) {
+	revision := status.LatestAvailableRevision
+	if revision == 0 || len(status.NodeStatuses) == 0 {
+		return Convergence{}, false, nil
+	}
+	for _, nodeStatus := range status.NodeStatuses {
+		if nodeStatus.CurrentRevision != revision {
+			return Convergence{}, false, nil
+		}
+	}
+	revisionStatus, err := c.configMapLister.ConfigMaps(operatorclient.TargetNamespace).Get(fmt.Sprintf("%s%d", revisionStatusPrefix, revision))
+	if apierrors.IsNotFound(err) {
+		// pruned already, its creation time is lost
+		return Convergence{}, false, nil
+	}
+	if err != nil {
+		return Convergence{}, false, err
+	}
+	return Convergence{
+		Revision: revision,
+		Seconds:  int64(c.now().Sub(revisionStatus.CreationTimestamp.Time).Seconds()),
+	}, true, nil
+}
+
+// appendConvergence records the convergence of a revision newer than the recorded ones, keeping the latest
+// maxConvergences. The first observation of a converged revision is the one closest to its convergence.
+func appendConvergence(convergences []Convergence, convergence Convergence) []Convergence {
+	if len(convergences) > 0 && convergences[len(convergences)-1].Revision >= convergence.Revision {
+		return convergences
+	}
+	convergences = append(convergences, convergence)
+	if len(convergences) > maxConvergences {
+		convergences = convergences[len(convergences)-maxConvergences:]
+	}
+	return convergences
+}
+
+// expiryBucket coarsens the remaining validity of a certificate, exact expiry times are not needed to spot
+// stuck rotations across the fleet.
+func expiryBucket(remaining time.Duration) string {
+	switch {
+	case remaining <= 0:
+		return "Expired"
+	case remaining < 24*time.Hour:
+		return "LessThan1d"
+	case remaining < 72*time.Hour:
+		return "LessThan3d"
+	case remaining < 7*24*time.Hour:
+		return "LessThan7d"
+	case remaining < 30*24*time.Hour:
+		return "LessThan30d"
+	}
+	return "MoreThan30d"
+}
+
+func minTLSVersion(observedConfig []byte) string {
+	if len(observedConfig) == 0 {
+		return ""
+	}
+	config := struct {
+		ServingInfo struct {
+			MinTLSVersion string `json:"minTLSVersion"`
+		} `json:"servingInfo"`
+	}{}
+	if err := json.Unmarshal(observedConfig, &config); err != nil {
+		return ""
+	}
+	return config.ServingInfo.MinTLSVersion
+}
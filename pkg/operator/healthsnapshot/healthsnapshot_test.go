@@ -0,0 +1,129 @@
+package healthsnapshot
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statestore"
+)
+
+func TestSync(t *testing.T) {
+	now := time.Date(2022, 3, 1, 3, 0, 0, 0, time.UTC)
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	if err := indexer.Add(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+		Namespace:         operatorclient.TargetNamespace,
+		Name:              "revision-status-4",
+		CreationTimestamp: metav1.NewTime(now.Add(-90 * time.Second)),
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	kubeClient := fake.NewSimpleClientset()
+	operatorClient := v1helpers.NewFakeStaticPodOperatorClient(
+		&operatorv1.StaticPodOperatorSpec{OperatorSpec: operatorv1.OperatorSpec{
+			ManagementState: operatorv1.Managed,
+			ObservedConfig:  runtime.RawExtension{Raw: []byte(`{"servingInfo":{"minTLSVersion":"VersionTLS12"}}`)},
+		}},
+		&operatorv1.StaticPodOperatorStatus{
+			LatestAvailableRevision: 4,
+			OperatorStatus: operatorv1.OperatorStatus{
+				Conditions: []operatorv1.OperatorCondition{
+					{Type: "NodeInstallerDegraded", Status: operatorv1.ConditionFalse},
+					{Type: "GuardPodsDegraded", Status: operatorv1.ConditionTrue, Reason: "Unready", Message: "guard pod on master-0.example.com is not ready"},
+				},
+			},
+			NodeStatuses: []operatorv1.NodeStatus{
+				{NodeName: "master-0.example.com", CurrentRevision: 4},
+				{NodeName: "master-1.example.com", CurrentRevision: 4, LastFailedRevision: 3},
+			},
+		},
+		nil,
+		nil,
+	)
+	c := &HealthSnapshotController{
+		operatorClient:  operatorClient,
+		configMapLister: corev1listers.NewConfigMapLister(indexer),
+		secretLister:    corev1listers.NewSecretLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})),
+		stateStore:      statestore.NewConfigMapStoreFromLister(corev1listers.NewConfigMapLister(indexer), kubeClient.CoreV1()),
+		now:             func() time.Time { return now },
+	}
+
+	if err := c.sync(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))); err != nil {
+		t.Fatal(err)
+	}
+	configMap, err := kubeClient.CoreV1().ConfigMaps(operatorclient.OperatorNamespace).Get(context.TODO(), ConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw := configMap.Data[snapshotKey]
+	if strings.Contains(raw, "example.com") {
+		t.Errorf("expected node names and messages to be dropped, got %s", raw)
+	}
+	got := &Snapshot{}
+	if err := json.Unmarshal([]byte(raw), got); err != nil {
+		t.Fatal(err)
+	}
+	expected := &Snapshot{
+		Conditions: []Condition{
+			{Type: "GuardPodsDegraded", Status: operatorv1.ConditionTrue, Reason: "Unready"},
+			{Type: "NodeInstallerDegraded", Status: operatorv1.ConditionFalse},
+		},
+		LatestAvailableRevision: 4,
+		Nodes:                   []Node{{CurrentRevision: 4}, {CurrentRevision: 4}},
+		Convergences:            []Convergence{{Revision: 4, Seconds: 90}},
+		MinTLSVersion:           "VersionTLS12",
+	}
+	if !reflect.DeepEqual(expected, got) {
+		t.Errorf("unexpected snapshot:\n%#v\nexpected:\n%#v", got, expected)
+	}
+}
+
+func TestAppendConvergence(t *testing.T) {
+	convergences := []Convergence{}
+	for revision := int32(1); revision <= maxConvergences+2; revision++ {
+		convergences = appendConvergence(convergences, Convergence{Revision: revision, Seconds: 60})
+	}
+	convergences = appendConvergence(convergences, Convergence{Revision: maxConvergences + 2, Seconds: 600})
+
+	if len(convergences) != maxConvergences {
+		t.Fatalf("expected %d convergences, got %d", maxConvergences, len(convergences))
+	}
+	if first := convergences[0].Revision; first != 3 {
+		t.Errorf("expected the oldest convergences to be dropped, first is revision %d", first)
+	}
+	if last := convergences[len(convergences)-1]; last.Seconds != 60 {
+		t.Errorf("expected the first observation of a converged revision to be kept, got %#v", last)
+	}
+}
+
+func TestExpiryBucket(t *testing.T) {
+	for remaining, expected := range map[time.Duration]string{
+		-time.Minute:        "Expired",
+		time.Hour:           "LessThan1d",
+		48 * time.Hour:      "LessThan3d",
+		5 * 24 * time.Hour:  "LessThan7d",
+		20 * 24 * time.Hour: "LessThan30d",
+		90 * 24 * time.Hour: "MoreThan30d",
+	} {
+		if got := expiryBucket(remaining); got != expected {
+			t.Errorf("%v: expected %s, got %s", remaining, expected, got)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package hostpathpreflightcontroller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/labels"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/controllerpause"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operationprofile"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
+)
+
+const conditionType = "HostPathPreflight"
+
+// HostPathPreflightController reports the host paths which failed the pre-flight of the installer pods. The installer
+// does not cut a node over to a revision whose host paths are missing or carry the wrong owner or SELinux label, the
+// operand would otherwise crash loop without saying which path it could not use.
+type HostPathPreflightController struct {
+	operatorClient  v1helpers.StaticPodOperatorClient
+	statusUpdater   statusbatcher.StatusUpdater
+	configMapLister corev1listers.ConfigMapLister
+}
+
+func NewHostPathPreflightController(
+	operatorClient v1helpers.StaticPodOperatorClient,
+	statusUpdater statusbatcher.StatusUpdater,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &HostPathPreflightController{
+		operatorClient:  operatorClient,
+		statusUpdater:   statusUpdater,
+		configMapLister: kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().ConfigMaps().Lister(),
+	}
+
+	return factory.New().WithInformers(
+		operatorClient.Informer(),
+		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().ConfigMaps().Informer(),
+	).ResyncEvery(operationprofile.Resync(time.Minute)).WithSync(syncmetrics.Timed("HostPathPreflightController", controllerpause.Pausable("HostPathPreflightController", c.sync))).ToController("HostPathPreflightController", eventRecorder)
+}
+
+func (c *HostPathPreflightController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	_, status, _, err := c.operatorClient.GetStaticPodOperatorState()
+	if err != nil {
+		return err
+	}
+	configMaps, err := c.configMapLister.ConfigMaps(operatorclient.TargetNamespace).List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	reports := map[string]string{}
+	for _, configMap := range configMaps {
+		if strings.HasPrefix(configMap.Name, ConfigMapPrefix) && len(configMap.Data[failuresKey]) > 0 {
+			reports[strings.TrimPrefix(configMap.Name, ConfigMapPrefix)] = fmt.Sprintf("node %s revision %s: %s",
+				strings.TrimPrefix(configMap.Name, ConfigMapPrefix), configMap.Data[revisionKey], strings.ReplaceAll(configMap.Data[failuresKey], "\n", ", "))
+		}
+	}
+	// the reports of removed nodes stay behind, only the nodes the operand runs on count
+	failures := []string{}
+	for _, nodeStatus := range status.NodeStatuses {
+		if report, found := reports[nodeStatus.NodeName]; found {
+			failures = append(failures, report)
+		}
+	}
+	sort.Strings(failures)
+
+	condition := operatorv1.OperatorCondition{
+		Type:   conditionType,
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+	if len(failures) > 0 {
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "PreflightFailed"
+		condition.Message = fmt.Sprintf("the nodes stay at their revision until their host paths are fixed, the installation is retried: %s", strings.Join(failures, "; "))
+	}
+	return c.statusUpdater.UpdateStatus(ctx, v1helpers.UpdateConditionFn(condition))
+}
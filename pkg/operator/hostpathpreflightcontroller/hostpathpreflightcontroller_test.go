@@ -0,0 +1,162 @@
+package hostpathpreflightcontroller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+)
+
+func TestCheckHostPaths(t *testing.T) {
+	root := t.TempDir()
+	resourceDir := filepath.Join(root, "static-pod-resources")
+	for _, dir := range []string{"kube-controller-manager-certs", "relabeled"} {
+		if err := os.MkdirAll(filepath.Join(resourceDir, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pod := &corev1.Pod{Spec: corev1.PodSpec{Volumes: []corev1.Volume{
+		{Name: "resource-dir", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: filepath.Join(resourceDir, "kube-controller-manager-pod-3")}}},
+		{Name: "cert-dir", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: filepath.Join(resourceDir, "kube-controller-manager-certs")}}},
+		{Name: "relabeled", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: filepath.Join(resourceDir, "relabeled")}}},
+		{Name: "removed", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: filepath.Join(resourceDir, "removed") + "/"}}},
+		{Name: "outside", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/var/log/missing"}}},
+		{Name: "tmp", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+	}}}
+	paths := HostPaths(pod)
+	labelOf := func(path string) (string, error) {
+		if filepath.Base(path) == "relabeled" {
+			return "container_file_t", nil
+		}
+		return expectedSELinuxType, nil
+	}
+	current := owner{uid: uint32(os.Getuid()), gid: uint32(os.Getgid())}
+	created := filepath.Join(resourceDir, "kube-controller-manager-pod-3")
+
+	failures, err := checkHostPaths(paths, root, created, true, current, labelOf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		filepath.Join(resourceDir, "relabeled") + " has the SELinux type container_file_t instead of kubernetes_file_t",
+		filepath.Join(resourceDir, "removed") + " does not exist",
+	}
+	if !reflect.DeepEqual(failures, expected) {
+		t.Errorf("expected %v, got %v", expected, failures)
+	}
+
+	// the first installation on a node creates the missing paths
+	failures, err = checkHostPaths(paths, root, created, false, owner{uid: current.uid + 1, gid: current.gid}, func(string) (string, error) { return "", nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = []string{}
+	for _, dir := range []string{"kube-controller-manager-certs", "relabeled"} {
+		expected = append(expected, fmt.Sprintf("%s is owned by %d:%d instead of %d:%d", filepath.Join(resourceDir, dir), current.uid, current.gid, current.uid+1, current.gid))
+	}
+	if !reflect.DeepEqual(failures, expected) {
+		t.Errorf("expected %v, got %v", expected, failures)
+	}
+}
+
+func TestReportFailures(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	recorder := events.NewInMemoryRecorder("test")
+	if err := ReportFailures(context.TODO(), kubeClient.CoreV1(), recorder, "master-0", "3", []string{"/a does not exist", "/b is owned by 1000:0 instead of 0:0"}); err != nil {
+		t.Fatal(err)
+	}
+	configMap, err := kubeClient.CoreV1().ConfigMaps(operatorclient.TargetNamespace).Get(context.TODO(), ConfigMapPrefix+"master-0", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if configMap.Data[revisionKey] != "3" || configMap.Data[failuresKey] != "/a does not exist\n/b is owned by 1000:0 instead of 0:0" {
+		t.Errorf("unexpected report %v", configMap.Data)
+	}
+
+	if err := ReportFailures(context.TODO(), kubeClient.CoreV1(), recorder, "master-0", "3", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := kubeClient.CoreV1().ConfigMaps(operatorclient.TargetNamespace).Get(context.TODO(), ConfigMapPrefix+"master-0", metav1.GetOptions{}); err == nil {
+		t.Errorf("expected the report removed once the host paths pass")
+	}
+}
+
+func TestSync(t *testing.T) {
+	testCases := []struct {
+		name            string
+		reports         map[string]string
+		expectedStatus  operatorv1.ConditionStatus
+		expectedMessage string
+	}{
+		{
+			name:           "no failures",
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+		{
+			name: "failures",
+			reports: map[string]string{
+				"master-1": "/etc/kubernetes/static-pod-resources/kube-controller-manager-certs is owned by 1000:0 instead of 0:0",
+				"master-0": "/etc/kubernetes/static-pod-resources/kube-controller-manager-certs does not exist\n/etc/kubernetes/static-pod-resources/extra has the SELinux type container_file_t instead of kubernetes_file_t",
+			},
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedMessage: "the nodes stay at their revision until their host paths are fixed, the installation is retried: node master-0 revision 4: /etc/kubernetes/static-pod-resources/kube-controller-manager-certs does not exist, /etc/kubernetes/static-pod-resources/extra has the SELinux type container_file_t instead of kubernetes_file_t; node master-1 revision 4: /etc/kubernetes/static-pod-resources/kube-controller-manager-certs is owned by 1000:0 instead of 0:0",
+		},
+		{
+			name:           "removed node",
+			reports:        map[string]string{"master-3": "/etc/kubernetes/static-pod-resources/kube-controller-manager-certs does not exist"},
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			for node, failures := range tc.reports {
+				if err := indexer.Add(&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: ConfigMapPrefix + node},
+					Data:       map[string]string{revisionKey: "4", failuresKey: failures},
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			operatorClient := v1helpers.NewFakeStaticPodOperatorClient(
+				&operatorv1.StaticPodOperatorSpec{},
+				&operatorv1.StaticPodOperatorStatus{NodeStatuses: []operatorv1.NodeStatus{{NodeName: "master-0"}, {NodeName: "master-1"}}},
+				nil,
+				nil,
+			)
+			c := &HostPathPreflightController{
+				operatorClient:  operatorClient,
+				statusUpdater:   statusbatcher.NewDirectUpdater(operatorClient),
+				configMapLister: corev1listers.NewConfigMapLister(indexer),
+			}
+			if err := c.sync(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))); err != nil {
+				t.Fatal(err)
+			}
+
+			_, status, _, _ := operatorClient.GetOperatorState()
+			condition := v1helpers.FindOperatorCondition(status.Conditions, conditionType)
+			if condition == nil {
+				t.Fatalf("missing condition %s", conditionType)
+			}
+			if condition.Status != tc.expectedStatus || condition.Message != tc.expectedMessage {
+				t.Errorf("expected %s %q, got %s %q", tc.expectedStatus, tc.expectedMessage, condition.Status, condition.Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package hostpathpreflightcontroller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+const (
+	// ConfigMapPrefix names the configmaps of the target namespace where the installer pods report the host paths of
+	// a revision which failed the pre-flight, one per node.
+	ConfigMapPrefix = "host-path-preflight-"
+	revisionKey     = "revision"
+	failuresKey     = "failures"
+
+	// expectedSELinuxType is the type of the static pod resources of the node, the operand cannot read other types
+	expectedSELinuxType = "kubernetes_file_t"
+)
+
+// HostPaths returns the host paths the pod mounts, sorted.
+func HostPaths(pod *corev1.Pod) []string {
+	paths := []string{}
+	for _, volume := range pod.Spec.Volumes {
+		if volume.HostPath != nil {
+			paths = append(paths, filepath.Clean(volume.HostPath.Path))
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// owner is the owner the operand, running as root, expects of its host paths.
+type owner struct {
+	uid, gid uint32
+}
+
+// CheckHostPaths returns the host paths which would break the static pod, like the ones left behind by a manual
+// modification of the node with another owner or SELinux label. Only the paths under root, the directory of the node
+// the installer pod mounts, are checked, and the paths under created, the directory the installer creates for the
+// revision, are not. Missing paths fail only when requireExisting, on a node which ran a revision before.
+func CheckHostPaths(paths []string, root, created string, requireExisting bool) ([]string, error) {
+	return checkHostPaths(paths, root, created, requireExisting, owner{uid: 0, gid: 0}, selinuxType)
+}
+
+func checkHostPaths(paths []string, root, created string, requireExisting bool, expectedOwner owner, labelOf func(string) (string, error)) ([]string, error) {
+	failures := []string{}
+	for _, path := range paths {
+		if !isUnder(path, root) || isUnder(path, created) {
+			continue
+		}
+		// the kubelet follows symbolic links, so does the check
+		info, err := os.Stat(path)
+		if os.IsNotExist(err) {
+			if requireExisting {
+				failures = append(failures, fmt.Sprintf("%s does not exist", path))
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if stat, ok := info.Sys().(*syscall.Stat_t); ok && (stat.Uid != expectedOwner.uid || stat.Gid != expectedOwner.gid) {
+			failures = append(failures, fmt.Sprintf("%s is owned by %d:%d instead of %d:%d", path, stat.Uid, stat.Gid, expectedOwner.uid, expectedOwner.gid))
+		}
+		label, err := labelOf(path)
+		if err != nil {
+			return nil, err
+		}
+		// nodes without SELinux have no label
+		if len(label) > 0 && label != expectedSELinuxType {
+			failures = append(failures, fmt.Sprintf("%s has the SELinux type %s instead of %s", path, label, expectedSELinuxType))
+		}
+	}
+	return failures, nil
+}
+
+func isUnder(path, dir string) bool {
+	relative, err := filepath.Rel(dir, path)
+	return err == nil && relative != ".." && !strings.HasPrefix(relative, "../")
+}
+
+// selinuxType returns the type of the SELinux context of the path, empty when it has none.
+func selinuxType(path string) (string, error) {
+	buf := make([]byte, 256)
+	size, err := unix.Getxattr(path, "security.selinux", buf)
+	if errors.Is(err, unix.ENODATA) || errors.Is(err, unix.ENOTSUP) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read the SELinux context of %s: %w", path, err)
+	}
+	// user:role:type:level
+	fields := strings.Split(strings.TrimRight(string(buf[:size]), "\x00"), ":")
+	if len(fields) < 3 {
+		return "", fmt.Errorf("invalid SELinux context %q of %s", string(buf[:size]), path)
+	}
+	return fields[2], nil
+}
+
+// ReportFailures records the host paths of the revision which failed the pre-flight on the node for the operator to
+// report them, and removes the record once they pass.
+func ReportFailures(ctx context.Context, client corev1client.ConfigMapsGetter, recorder events.Recorder, nodeName, revision string, failures []string) error {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: ConfigMapPrefix + nodeName},
+	}
+	if len(failures) == 0 {
+		_, _, err := resourceapply.DeleteConfigMap(ctx, client, recorder, configMap)
+		return err
+	}
+	configMap.Data = map[string]string{
+		revisionKey: revision,
+		failuresKey: strings.Join(failures, "\n"),
+	}
+	_, _, err := resourceapply.ApplyConfigMap(ctx, client, recorder, configMap)
+	return err
+}
@@ -0,0 +1,172 @@
+package leadertakeovercontroller
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	coordinationv1listers "k8s.io/client-go/listers/coordination/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/component-base/metrics"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatormetrics"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
+)
+
+const (
+	leaseNamespace = "kube-system"
+	leaseName      = "kube-controller-manager"
+
+	// a takeover is overdue once the lease stayed unrenewed for overdueLeaseDurations lease durations
+	overdueLeaseDurations = 3
+)
+
+// stage is how far a takeover from a leader on a failed node progressed, every stage is reported once.
+type stage int
+
+const (
+	stageNone stage = iota
+	stageNodeNotReady
+	stageLeaseExpired
+	stageTakeoverOverdue
+)
+
+var leaseStaleSeconds = metrics.NewGauge(
+	&metrics.GaugeOpts{
+		Name:           "kcm_operator_leader_lease_stale_seconds",
+		Help:           "Seconds since the kube-controller-manager leader lease expired while the node of its holder is not ready, 0 otherwise.",
+		StabilityLevel: metrics.ALPHA,
+	},
+)
+
+func init() {
+	operatormetrics.MustRegister(leaseStaleSeconds)
+}
+
+// LeaderTakeoverController watches the kube-controller-manager leader lease when the node of its holder goes
+// not ready. A leader on a failed node keeps its lease until it expires, the controller reports when the other
+// instances are expected to take over and when they are overdue. It only reports, the other instances acquire an
+// expired lease on their own about a lease duration after its last renewal.
+type LeaderTakeoverController struct {
+	leaseLister coordinationv1listers.LeaseLister
+	nodeLister  corev1listers.NodeLister
+	now         func() time.Time
+
+	// reportedTerm is the holder and renew time of the last lease reported, reportedStage how far it got
+	reportedTerm  string
+	reportedStage stage
+}
+
+func NewLeaderTakeoverController(
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &LeaderTakeoverController{
+		leaseLister: kubeInformersForNamespaces.InformersFor(leaseNamespace).Coordination().V1().Leases().Lister(),
+		nodeLister:  kubeInformersForNamespaces.InformersFor("").Core().V1().Nodes().Lister(),
+		now:         time.Now,
+	}
+
+	// the lease is not renewed while the leader is down, the resync drives the escalation
+	return factory.New().WithInformers(
+		kubeInformersForNamespaces.InformersFor(leaseNamespace).Coordination().V1().Leases().Informer(),
+		kubeInformersForNamespaces.InformersFor("").Core().V1().Nodes().Informer(),
+	).ResyncEvery(10*time.Second).WithSync(syncmetrics.Timed("LeaderTakeoverController", c.sync)).ToController("LeaderTakeoverController", eventRecorder)
+}
+
+func (c *LeaderTakeoverController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	lease, err := c.leaseLister.Leases(leaseNamespace).Get(leaseName)
+	if apierrors.IsNotFound(err) {
+		leaseStaleSeconds.Set(0)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if lease.Spec.HolderIdentity == nil || lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		leaseStaleSeconds.Set(0)
+		return nil
+	}
+	holder := *lease.Spec.HolderIdentity
+	renewTime := lease.Spec.RenewTime.Time
+	leaseDuration := time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second
+	expiry := renewTime.Add(leaseDuration)
+
+	// the holder identity is <hostname>_<uuid>, the hostname of the static pods is the node name
+	nodeName := strings.SplitN(holder, "_", 2)[0]
+	node, err := c.nodeLister.Get(nodeName)
+	if apierrors.IsNotFound(err) {
+		leaseStaleSeconds.Set(0)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	ready := nodeReadyCondition(node)
+	if ready == nil || ready.Status == corev1.ConditionTrue {
+		leaseStaleSeconds.Set(0)
+		return nil
+	}
+
+	now := c.now()
+	if now.After(expiry) {
+		leaseStaleSeconds.Set(now.Sub(expiry).Seconds())
+	} else {
+		leaseStaleSeconds.Set(0)
+	}
+
+	term := holder + "@" + renewTime.UTC().Format(time.RFC3339Nano)
+	if term != c.reportedTerm {
+		c.reportedTerm = term
+		c.reportedStage = stageNone
+	}
+	report := func(s stage, fn func()) {
+		if c.reportedStage < s {
+			c.reportedStage = s
+			fn()
+		}
+	}
+
+	report(stageNodeNotReady, func() {
+		syncCtx.Recorder().Warningf("LeaderNodeNotReady", "The kube-controller-manager leader %s runs on node %s which is %s, another instance is expected to take over after the lease expires at %s",
+			holder, nodeName, readyState(ready), expiry.UTC().Format(time.RFC3339))
+	})
+	if !now.After(expiry) {
+		return nil
+	}
+	report(stageLeaseExpired, func() {
+		syncCtx.Recorder().Warningf("LeaderLeaseExpired", "The kube-controller-manager leader lease of %s on node %s expired at %s and was not taken over yet",
+			holder, nodeName, expiry.UTC().Format(time.RFC3339))
+	})
+
+	if now.Sub(renewTime) < overdueLeaseDurations*leaseDuration {
+		return nil
+	}
+	report(stageTakeoverOverdue, func() {
+		syncCtx.Recorder().Warningf("LeaderTakeoverOverdue", "The kube-controller-manager leader lease of %s on node %s was not renewed since %s, no other instance took over",
+			holder, nodeName, renewTime.UTC().Format(time.RFC3339))
+	})
+	return nil
+}
+
+func nodeReadyCondition(node *corev1.Node) *corev1.NodeCondition {
+	for i := range node.Status.Conditions {
+		if node.Status.Conditions[i].Type == corev1.NodeReady {
+			return &node.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+func readyState(ready *corev1.NodeCondition) string {
+	if ready.Status == corev1.ConditionUnknown {
+		return "no longer reported by its kubelet"
+	}
+	return "not ready"
+}
@@ -0,0 +1,104 @@
+package leadertakeovercontroller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	coordinationv1listers "k8s.io/client-go/listers/coordination/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+)
+
+func TestSync(t *testing.T) {
+	renewTime := time.Date(2022, 3, 1, 3, 0, 0, 0, time.UTC)
+	holder := "master-0_0a2ba5d1-1b0c-4c4c-9b8b-2a5e7b6d7e41"
+	leaseDuration := int32(15)
+	lease := &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{Namespace: leaseNamespace, Name: leaseName},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       &holder,
+			LeaseDurationSeconds: &leaseDuration,
+			RenewTime:            &metav1.MicroTime{Time: renewTime},
+		},
+	}
+	node := func(status corev1.ConditionStatus) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "master-0"},
+			Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: status, LastTransitionTime: metav1.NewTime(renewTime.Add(-10 * time.Second))},
+			}},
+		}
+	}
+
+	testCases := []struct {
+		name            string
+		node            *corev1.Node
+		syncs           []time.Duration
+		expectedReasons []string
+	}{
+		{
+			name:  "ready leader",
+			node:  node(corev1.ConditionTrue),
+			syncs: []time.Duration{time.Minute},
+		},
+		{
+			name:            "escalation is reported once per stage",
+			node:            node(corev1.ConditionFalse),
+			syncs:           []time.Duration{5 * time.Second, 10 * time.Second, 20 * time.Second, 30 * time.Second, time.Minute, 2 * time.Minute},
+			expectedReasons: []string{"LeaderNodeNotReady", "LeaderLeaseExpired", "LeaderTakeoverOverdue"},
+		},
+		{
+			name:            "dead holder",
+			node:            node(corev1.ConditionUnknown),
+			syncs:           []time.Duration{20 * time.Second, 31 * time.Second, time.Minute},
+			expectedReasons: []string{"LeaderNodeNotReady", "LeaderLeaseExpired", "LeaderTakeoverOverdue"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			leaseIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			if err := leaseIndexer.Add(lease); err != nil {
+				t.Fatal(err)
+			}
+			nodeIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if err := nodeIndexer.Add(tc.node); err != nil {
+				t.Fatal(err)
+			}
+			recorder := events.NewInMemoryRecorder("test")
+			now := renewTime
+			c := &LeaderTakeoverController{
+				leaseLister: coordinationv1listers.NewLeaseLister(leaseIndexer),
+				nodeLister:  corev1listers.NewNodeLister(nodeIndexer),
+				now:         func() time.Time { return now },
+			}
+
+			for _, after := range tc.syncs {
+				now = renewTime.Add(after)
+				if err := c.sync(context.TODO(), factory.NewSyncContext("test", recorder)); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			reasons := []string{}
+			for _, event := range recorder.Events() {
+				reasons = append(reasons, event.Reason)
+			}
+			if len(reasons) != len(tc.expectedReasons) {
+				t.Fatalf("expected events %v, got %v", tc.expectedReasons, reasons)
+			}
+			for i := range reasons {
+				if reasons[i] != tc.expectedReasons[i] {
+					t.Errorf("expected events %v, got %v", tc.expectedReasons, reasons)
+					break
+				}
+			}
+		})
+	}
+}
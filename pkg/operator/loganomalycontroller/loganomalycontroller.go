@@ -0,0 +1,221 @@
+package loganomalycontroller
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/component-base/metrics"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/backpressure"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/controllerpause"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatormetrics"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
+)
+
+const (
+	conditionType = "OperandLogAnomalies"
+
+	containerName = "kube-controller-manager"
+	// limitBytes bounds the logs read per pod and scan, a chatty operand must not load the operator
+	limitBytes = 4 * 1024 * 1024
+	// maxSampleLength bounds the log line quoted in events and the condition
+	maxSampleLength = 256
+)
+
+// pattern is a high-signal log line of kube-controller-manager and the cause it points to.
+type pattern struct {
+	name   string
+	regexp *regexp.Regexp
+	cause  string
+}
+
+var patterns = []pattern{
+	{
+		name:   "LeaderElectionLost",
+		regexp: regexp.MustCompile(`leaderelection lost|failed to renew lease|error retrieving resource lock`),
+		cause:  "the instance lost or failed to renew the leader lease, check the kube-apiserver availability and the node clock",
+	},
+	{
+		name:   "APIServerThrottling",
+		regexp: regexp.MustCompile(`Waited for .* due to client-side throttling|Throttling request took|the server has received too many requests`),
+		cause:  "requests to kube-apiserver are throttled, controllers fall behind",
+	},
+	{
+		name:   "WebhookTimeout",
+		regexp: regexp.MustCompile(`failed calling webhook .*(context deadline exceeded|timeout|Timeout)`),
+		cause:  "an admission webhook times out, the writes of the controllers it intercepts fail",
+	},
+}
+
+var anomalies = metrics.NewCounterVec(
+	&metrics.CounterOpts{
+		Name:           "kcm_operator_operand_log_anomalies_total",
+		Help:           "Number of kube-controller-manager log lines matching a known anomaly pattern.",
+		StabilityLevel: metrics.ALPHA,
+	},
+	[]string{"pattern"},
+)
+
+func init() {
+	operatormetrics.MustRegister(anomalies)
+}
+
+// finding is how often a pattern matched in a scan, with the last matching line.
+type finding struct {
+	count  int
+	sample string
+}
+
+// LogAnomalyController scans the recent logs of the kube-controller-manager pods for lines pointing to a known cause,
+// like a lost leader lease or webhook timeouts, and summarizes them in events and a condition. Every pod and pattern
+// is reported by an event once per reportInterval at most, the condition reflects the last scan.
+type LogAnomalyController struct {
+	statusUpdater statusbatcher.StatusUpdater
+	podLister     corev1listers.PodLister
+	podsGetter    corev1client.PodsGetter
+	interval      time.Duration
+	now           func() time.Time
+
+	// lastReported maps <pod>/<pattern> to the time of its last event
+	lastReported   map[string]time.Time
+	reportInterval time.Duration
+}
+
+// NewLogAnomalyController scans the logs written within every interval.
+func NewLogAnomalyController(
+	statusUpdater statusbatcher.StatusUpdater,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	podsGetter corev1client.PodsGetter,
+	interval time.Duration,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &LogAnomalyController{
+		statusUpdater:  statusUpdater,
+		podLister:      kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().Pods().Lister(),
+		podsGetter:     podsGetter,
+		interval:       interval,
+		now:            time.Now,
+		lastReported:   map[string]time.Time{},
+		reportInterval: time.Hour,
+	}
+
+	// the scan is driven by the resync, the pod informer keeps the lister synced and picks up restarted pods
+	return factory.New().WithInformers(kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().Pods().Informer()).ResyncEvery(interval).WithSync(syncmetrics.Timed("LogAnomalyController", controllerpause.Pausable("LogAnomalyController", backpressure.Deferrable("LogAnomalyController", c.sync)))).ToController("LogAnomalyController", eventRecorder)
+}
+
+func (c *LogAnomalyController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	pods, err := c.podLister.Pods(operatorclient.TargetNamespace).List(labels.SelectorFromSet(labels.Set{"app": "kube-controller-manager"}))
+	if err != nil {
+		return err
+	}
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+
+	now := c.now()
+	summaries := []string{}
+	failures := []string{}
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		findings, err := c.scanPod(ctx, pod.Name)
+		if err != nil {
+			// the logs of a single pod being unavailable is no reason to skip the others
+			failures = append(failures, fmt.Sprintf("%s: %v", pod.Name, err))
+			continue
+		}
+		for _, pattern := range patterns {
+			found, ok := findings[pattern.name]
+			if !ok {
+				continue
+			}
+			anomalies.WithLabelValues(pattern.name).Add(float64(found.count))
+			summaries = append(summaries, fmt.Sprintf("%s: %s %d times, %s", pod.Name, pattern.name, found.count, pattern.cause))
+
+			key := pod.Name + "/" + pattern.name
+			if last, reported := c.lastReported[key]; reported && now.Sub(last) < c.reportInterval {
+				continue
+			}
+			c.lastReported[key] = now
+			syncCtx.Recorder().Warningf("OperandLog"+pattern.name, "kube-controller-manager pod %s logged %d lines like %q within %s: %s", pod.Name, found.count, found.sample, c.interval, pattern.cause)
+		}
+	}
+	for key, last := range c.lastReported {
+		if now.Sub(last) >= c.reportInterval {
+			delete(c.lastReported, key)
+		}
+	}
+
+	condition := operatorv1.OperatorCondition{
+		Type:   conditionType,
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+	if len(summaries) > 0 {
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "AnomaliesLogged"
+		condition.Message = fmt.Sprintf("within the last %s: %s", c.interval, strings.Join(summaries, "; "))
+	}
+	if len(failures) > 0 {
+		syncCtx.Recorder().Warningf("OperandLogScanFailed", "Failed to scan the logs of kube-controller-manager: %s", strings.Join(failures, "; "))
+	}
+	return c.statusUpdater.UpdateStatus(ctx, v1helpers.UpdateConditionFn(condition))
+}
+
+// scanPod reads the logs the pod wrote since the last scan, through the kubelet of its node.
+func (c *LogAnomalyController) scanPod(ctx context.Context, podName string) (map[string]*finding, error) {
+	sinceSeconds := int64(c.interval.Seconds())
+	bytes := int64(limitBytes)
+	stream, err := c.podsGetter.Pods(operatorclient.TargetNamespace).GetLogs(podName, &corev1.PodLogOptions{
+		Container:    containerName,
+		SinceSeconds: &sinceSeconds,
+		LimitBytes:   &bytes,
+	}).Stream(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+	return scan(stream)
+}
+
+// scan returns the patterns which matched lines of the logs.
+func scan(logs io.Reader) (map[string]*finding, error) {
+	findings := map[string]*finding{}
+	scanner := bufio.NewScanner(logs)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		for _, pattern := range patterns {
+			if !pattern.regexp.MatchString(line) {
+				continue
+			}
+			found, ok := findings[pattern.name]
+			if !ok {
+				found = &finding{}
+				findings[pattern.name] = found
+			}
+			found.count++
+			found.sample = line
+			if len(found.sample) > maxSampleLength {
+				found.sample = found.sample[:maxSampleLength] + "..."
+			}
+		}
+	}
+	return findings, scanner.Err()
+}
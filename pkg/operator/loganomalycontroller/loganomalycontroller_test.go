@@ -0,0 +1,82 @@
+package loganomalycontroller
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+)
+
+func TestScan(t *testing.T) {
+	logs := strings.Join([]string{
+		`I1015 10:00:00.000000       1 controllermanager.go:300] Started "garbagecollector"`,
+		`I1015 10:00:01.000000       1 request.go:601] Waited for 1.19s due to client-side throttling, not priority and fairness, request: GET:https://api:6443/api/v1/nodes`,
+		`I1015 10:00:02.000000       1 request.go:601] Waited for 2.01s due to client-side throttling, not priority and fairness, request: GET:https://api:6443/api/v1/pods`,
+		`E1015 10:00:03.000000       1 resource_quota_controller.go:414] failed calling webhook "validate.example.com": Post "https://webhook.example.svc:443/validate": context deadline exceeded`,
+		`E1015 10:00:04.000000       1 leaderelection.go:330] error retrieving resource lock kube-system/kube-controller-manager: Get "https://api:6443/apis/coordination.k8s.io/v1/namespaces/kube-system/leases/kube-controller-manager": dial tcp: i/o timeout`,
+		`F1015 10:00:05.000000       1 controllermanager.go:320] leaderelection lost`,
+	}, "\n")
+
+	findings, err := scan(strings.NewReader(logs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]int{"APIServerThrottling": 2, "WebhookTimeout": 1, "LeaderElectionLost": 2}
+	if len(findings) != len(expected) {
+		t.Errorf("expected %v, got %v", expected, findings)
+	}
+	for name, count := range expected {
+		if found, ok := findings[name]; !ok || found.count != count {
+			t.Errorf("expected %s %d times, got %v", name, count, findings[name])
+		}
+	}
+	if sample := findings["LeaderElectionLost"].sample; !strings.HasSuffix(sample, "leaderelection lost") {
+		t.Errorf("expected the last matching line as the sample, got %q", sample)
+	}
+}
+
+func TestSync(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	if err := indexer.Add(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "kube-controller-manager-master-0", Labels: map[string]string{"app": "kube-controller-manager"}},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil)
+	c := &LogAnomalyController{
+		statusUpdater:  statusbatcher.NewDirectUpdater(operatorClient),
+		podLister:      corev1listers.NewPodLister(indexer),
+		podsGetter:     fake.NewSimpleClientset().CoreV1(),
+		interval:       5 * time.Minute,
+		now:            time.Now,
+		lastReported:   map[string]time.Time{},
+		reportInterval: time.Hour,
+	}
+	// the fake logs match no pattern
+	if err := c.sync(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))); err != nil {
+		t.Fatal(err)
+	}
+	_, status, _, _ := operatorClient.GetOperatorState()
+	condition := v1helpers.FindOperatorCondition(status.Conditions, conditionType)
+	if condition == nil {
+		t.Fatalf("missing condition %s", conditionType)
+	}
+	if condition.Status != operatorv1.ConditionFalse {
+		t.Errorf("expected no anomalies, got %s %q", condition.Status, condition.Message)
+	}
+}
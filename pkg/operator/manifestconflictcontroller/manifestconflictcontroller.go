@@ -0,0 +1,94 @@
+package manifestconflictcontroller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/labels"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/controllerpause"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operationprofile"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
+)
+
+const conditionType = "ConflictingManifest"
+
+// ManifestConflictController reports the static pod manifests the installer pods found to run a second
+// kube-controller-manager next to the one the operator installs. Such a copy runs with a config of its own, often with
+// certificates and flags the operator no longer maintains, and fights over the leases with the operand.
+type ManifestConflictController struct {
+	operatorClient  v1helpers.StaticPodOperatorClient
+	statusUpdater   statusbatcher.StatusUpdater
+	configMapLister corev1listers.ConfigMapLister
+}
+
+func NewManifestConflictController(
+	operatorClient v1helpers.StaticPodOperatorClient,
+	statusUpdater statusbatcher.StatusUpdater,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &ManifestConflictController{
+		operatorClient:  operatorClient,
+		statusUpdater:   statusUpdater,
+		configMapLister: kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().ConfigMaps().Lister(),
+	}
+
+	return factory.New().WithInformers(
+		operatorClient.Informer(),
+		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().ConfigMaps().Informer(),
+	).ResyncEvery(operationprofile.Resync(time.Minute)).WithSync(syncmetrics.Timed("ManifestConflictController", controllerpause.Pausable("ManifestConflictController", c.sync))).ToController("ManifestConflictController", eventRecorder)
+}
+
+func (c *ManifestConflictController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	_, status, _, err := c.operatorClient.GetStaticPodOperatorState()
+	if err != nil {
+		return err
+	}
+	configMaps, err := c.configMapLister.ConfigMaps(operatorclient.TargetNamespace).List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	conflictsByNode := map[string]string{}
+	for _, configMap := range configMaps {
+		if strings.HasPrefix(configMap.Name, ConfigMapPrefix) && len(configMap.Data[filesKey]) > 0 {
+			conflictsByNode[strings.TrimPrefix(configMap.Name, ConfigMapPrefix)] = configMap.Data[filesKey]
+		}
+	}
+
+	// the reports of removed nodes stay behind, only the nodes the operand runs on count
+	conflicts := []string{}
+	for _, nodeStatus := range status.NodeStatuses {
+		files, found := conflictsByNode[nodeStatus.NodeName]
+		if !found {
+			continue
+		}
+		for _, file := range strings.Split(files, "\n") {
+			conflicts = append(conflicts, fmt.Sprintf("node %s: %s", nodeStatus.NodeName, file))
+		}
+	}
+	sort.Strings(conflicts)
+
+	condition := operatorv1.OperatorCondition{
+		Type:   conditionType,
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+	if len(conflicts) > 0 {
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "ExtraStaticPodManifest"
+		condition.Message = fmt.Sprintf("static pod manifests besides the installed one run a kube-controller-manager, remove them to avoid competing controller managers: %s", strings.Join(conflicts, ", "))
+	}
+	return c.statusUpdater.UpdateStatus(ctx, v1helpers.UpdateConditionFn(condition))
+}
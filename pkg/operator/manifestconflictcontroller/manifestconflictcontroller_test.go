@@ -0,0 +1,141 @@
+package manifestconflictcontroller
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+)
+
+func TestFindConflicts(t *testing.T) {
+	kubeControllerManager := "apiVersion: v1\nkind: Pod\nmetadata:\n  name: kube-controller-manager\n  namespace: openshift-kube-controller-manager\nspec:\n  containers:\n  - name: kube-controller-manager\n"
+	files := map[string]string{
+		"kube-controller-manager-pod.yaml":     kubeControllerManager,
+		"kube-controller-manager-pod.yaml.bak": kubeControllerManager,
+		".kube-controller-manager-pod.yaml":    kubeControllerManager,
+		"recovery-pod.yaml":                    "apiVersion: v1\nkind: Pod\nmetadata:\n  name: recovery\n  labels:\n    app: kube-controller-manager\nspec:\n  containers:\n  - name: recovery\n",
+		"kube-apiserver-pod.yaml":              "apiVersion: v1\nkind: Pod\nmetadata:\n  name: kube-apiserver\nspec:\n  containers:\n  - name: kube-apiserver\n",
+		"notes.txt":                            "{{ not a pod",
+	}
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	conflicts, err := FindConflicts(dir, "kube-controller-manager-pod.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{filepath.Join(dir, "kube-controller-manager-pod.yaml.bak"), filepath.Join(dir, "recovery-pod.yaml")}
+	if !reflect.DeepEqual(conflicts, expected) {
+		t.Errorf("expected %v, got %v", expected, conflicts)
+	}
+
+	conflicts, err = FindConflicts(filepath.Join(dir, "missing"), "kube-controller-manager-pod.yaml")
+	if err != nil || len(conflicts) != 0 {
+		t.Errorf("expected no conflicts in a missing directory, got %v, %v", conflicts, err)
+	}
+}
+
+func TestReportConflicts(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	recorder := events.NewInMemoryRecorder("test")
+	if err := ReportConflicts(context.TODO(), kubeClient.CoreV1(), recorder, "master-0", []string{"/etc/kubernetes/manifests/a.yaml", "/etc/kubernetes/manifests/b.yaml"}); err != nil {
+		t.Fatal(err)
+	}
+	configMap, err := kubeClient.CoreV1().ConfigMaps(operatorclient.TargetNamespace).Get(context.TODO(), ConfigMapPrefix+"master-0", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "/etc/kubernetes/manifests/a.yaml\n/etc/kubernetes/manifests/b.yaml"; configMap.Data[filesKey] != expected {
+		t.Errorf("expected %q, got %q", expected, configMap.Data[filesKey])
+	}
+
+	if err := ReportConflicts(context.TODO(), kubeClient.CoreV1(), recorder, "master-0", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := kubeClient.CoreV1().ConfigMaps(operatorclient.TargetNamespace).Get(context.TODO(), ConfigMapPrefix+"master-0", metav1.GetOptions{}); err == nil {
+		t.Errorf("expected the report to be removed")
+	}
+}
+
+func TestSync(t *testing.T) {
+	testCases := []struct {
+		name            string
+		reports         map[string]string
+		expectedStatus  operatorv1.ConditionStatus
+		expectedMessage string
+	}{
+		{
+			name:           "no conflicts",
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+		{
+			name: "conflicts",
+			reports: map[string]string{
+				"master-1": "/etc/kubernetes/manifests/kube-controller-manager-pod.yaml.bak\n/etc/kubernetes/manifests/recovery-pod.yaml",
+				"master-0": "/etc/kubernetes/manifests/kcm.yaml",
+			},
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedMessage: "static pod manifests besides the installed one run a kube-controller-manager, remove them to avoid competing controller managers: node master-0: /etc/kubernetes/manifests/kcm.yaml, node master-1: /etc/kubernetes/manifests/kube-controller-manager-pod.yaml.bak, node master-1: /etc/kubernetes/manifests/recovery-pod.yaml",
+		},
+		{
+			name:           "removed node",
+			reports:        map[string]string{"master-3": "/etc/kubernetes/manifests/kcm.yaml"},
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			for node, files := range tc.reports {
+				if err := indexer.Add(&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: ConfigMapPrefix + node},
+					Data:       map[string]string{filesKey: files},
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			operatorClient := v1helpers.NewFakeStaticPodOperatorClient(
+				&operatorv1.StaticPodOperatorSpec{},
+				&operatorv1.StaticPodOperatorStatus{NodeStatuses: []operatorv1.NodeStatus{{NodeName: "master-0"}, {NodeName: "master-1"}}},
+				nil,
+				nil,
+			)
+			c := &ManifestConflictController{
+				operatorClient:  operatorClient,
+				statusUpdater:   statusbatcher.NewDirectUpdater(operatorClient),
+				configMapLister: corev1listers.NewConfigMapLister(indexer),
+			}
+			if err := c.sync(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))); err != nil {
+				t.Fatal(err)
+			}
+
+			_, status, _, _ := operatorClient.GetOperatorState()
+			condition := v1helpers.FindOperatorCondition(status.Conditions, conditionType)
+			if condition == nil {
+				t.Fatalf("missing condition %s", conditionType)
+			}
+			if condition.Status != tc.expectedStatus || condition.Message != tc.expectedMessage {
+				t.Errorf("expected %s %q, got %s %q", tc.expectedStatus, tc.expectedMessage, condition.Status, condition.Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package manifestconflictcontroller
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+const (
+	// ConfigMapPrefix names the configmaps of the target namespace where the installer pods report the conflicting
+	// manifests they found, one per node.
+	ConfigMapPrefix = "conflicting-manifests-"
+	filesKey        = "files"
+)
+
+// FindConflicts returns the paths of the static pod manifests in dir which run a kube-controller-manager besides the
+// one of manifestFileName, like a copy left behind by a manual recovery. The kubelet starts every manifest of the
+// directory but the hidden ones, whatever their extension, so a kube-controller-manager-pod.yaml.bak is a conflict.
+// Files which are no pod are not ours to judge and are skipped.
+func FindConflicts(dir, manifestFileName string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	conflicts := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() || entry.Name() == manifestFileName || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		raw, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		pod := &corev1.Pod{}
+		if err := yaml.Unmarshal(raw, pod); err != nil || pod.Kind != "Pod" {
+			continue
+		}
+		if isKubeControllerManager(pod) {
+			conflicts = append(conflicts, filepath.Join(dir, entry.Name()))
+		}
+	}
+	sort.Strings(conflicts)
+	return conflicts, nil
+}
+
+func isKubeControllerManager(pod *corev1.Pod) bool {
+	if pod.Labels["app"] == "kube-controller-manager" {
+		return true
+	}
+	for _, container := range pod.Spec.Containers {
+		if container.Name == "kube-controller-manager" {
+			return true
+		}
+	}
+	return false
+}
+
+// ReportConflicts records the conflicting manifests found on the node for the operator to report them, and removes
+// the record once there are none left.
+func ReportConflicts(ctx context.Context, client corev1client.ConfigMapsGetter, recorder events.Recorder, nodeName string, conflicts []string) error {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: ConfigMapPrefix + nodeName},
+	}
+	if len(conflicts) == 0 {
+		_, _, err := resourceapply.DeleteConfigMap(ctx, client, recorder, configMap)
+		return err
+	}
+	configMap.Data = map[string]string{filesKey: strings.Join(conflicts, "\n")}
+	_, _, err := resourceapply.ApplyConfigMap(ctx, client, recorder, configMap)
+	return err
+}
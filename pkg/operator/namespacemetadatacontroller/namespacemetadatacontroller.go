@@ -0,0 +1,182 @@
+package namespacemetadatacontroller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/component-base/metrics"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceread"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/bindata"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/controllerpause"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operationprofile"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatormetrics"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
+)
+
+const conditionType = "NamespaceMetadataDegraded"
+
+var repairs = metrics.NewCounterVec(
+	&metrics.CounterOpts{
+		Name:           "kcm_operator_namespace_metadata_repairs_total",
+		Help:           "Number of times the required labels and annotations of a namespace were restored.",
+		StabilityLevel: metrics.ALPHA,
+	},
+	[]string{"namespace"},
+)
+
+func init() {
+	operatormetrics.MustRegister(repairs)
+}
+
+// requiredMetadata are the labels and annotations a namespace must carry.
+type requiredMetadata struct {
+	namespace   string
+	labels      map[string]string
+	annotations map[string]string
+}
+
+// requiredNamespaceMetadata returns the metadata the operand and the operator depend on: the run-level keeping
+// the namespaces free of SCC admission, the pod security labels admitting the static pods, the workload partitioning
+// annotations, and the monitoring label. The operand namespace reuses its manifest, the operator namespace is
+// created by the cluster-version-operator.
+func requiredNamespaceMetadata() []requiredMetadata {
+	target := resourceread.ReadNamespaceV1OrDie(bindata.MustAsset("assets/kube-controller-manager/ns.yaml"))
+	return []requiredMetadata{
+		{
+			namespace:   target.Name,
+			labels:      target.Labels,
+			annotations: target.Annotations,
+		},
+		{
+			namespace: operatorclient.OperatorNamespace,
+			labels: map[string]string{
+				"openshift.io/run-level":          "0",
+				"openshift.io/cluster-monitoring": "true",
+			},
+			annotations: map[string]string{
+				"openshift.io/node-selector":    "",
+				"workload.openshift.io/allowed": "management",
+			},
+		},
+	}
+}
+
+// NamespaceMetadataController restores the required labels and annotations of the operand and operator namespaces
+// when they are changed, and reports the namespaces it fails to restore.
+type NamespaceMetadataController struct {
+	statusUpdater    statusbatcher.StatusUpdater
+	namespaceLister  corev1listers.NamespaceLister
+	namespacesGetter corev1client.NamespacesGetter
+	required         []requiredMetadata
+}
+
+func NewNamespaceMetadataController(
+	operatorClient v1helpers.OperatorClient,
+	statusUpdater statusbatcher.StatusUpdater,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	namespacesGetter corev1client.NamespacesGetter,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &NamespaceMetadataController{
+		statusUpdater:    statusUpdater,
+		namespaceLister:  kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().Namespaces().Lister(),
+		namespacesGetter: namespacesGetter,
+		required:         requiredNamespaceMetadata(),
+	}
+
+	return factory.New().WithInformers(
+		operatorClient.Informer(),
+		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().Namespaces().Informer(),
+	).ResyncEvery(operationprofile.Resync(time.Minute)).WithSync(syncmetrics.Timed("NamespaceMetadataController", controllerpause.Pausable("NamespaceMetadataController", c.sync))).ToController("NamespaceMetadataController", eventRecorder)
+}
+
+func (c *NamespaceMetadataController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	failures := []string{}
+	for _, required := range c.required {
+		namespace, err := c.namespaceLister.Get(required.namespace)
+		if apierrors.IsNotFound(err) {
+			// the namespaces are created by the static resources and the cluster-version-operator
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		drift := metadataDrift(namespace, required)
+		if len(drift) == 0 {
+			continue
+		}
+		if err := c.repair(ctx, namespace, required); err != nil {
+			failures = append(failures, fmt.Sprintf("namespace %s: %s: %v", required.namespace, strings.Join(drift, ", "), err))
+			continue
+		}
+		repairs.WithLabelValues(required.namespace).Inc()
+		syncCtx.Recorder().Warningf("NamespaceMetadataRepaired", "Restored the metadata of namespace %s: %s", required.namespace, strings.Join(drift, ", "))
+	}
+
+	condition := operatorv1.OperatorCondition{
+		Type:   conditionType,
+		Status: operatorv1.ConditionFalse,
+	}
+	if len(failures) > 0 {
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "RepairFailed"
+		condition.Message = strings.Join(failures, "\n")
+	}
+	return c.statusUpdater.UpdateStatus(ctx, v1helpers.UpdateConditionFn(condition))
+}
+
+func (c *NamespaceMetadataController) repair(ctx context.Context, namespace *corev1.Namespace, required requiredMetadata) error {
+	namespace = namespace.DeepCopy()
+	if namespace.Labels == nil {
+		namespace.Labels = map[string]string{}
+	}
+	if namespace.Annotations == nil {
+		namespace.Annotations = map[string]string{}
+	}
+	for key, value := range required.labels {
+		namespace.Labels[key] = value
+	}
+	for key, value := range required.annotations {
+		namespace.Annotations[key] = value
+	}
+	_, err := c.namespacesGetter.Namespaces().Update(ctx, namespace, metav1.UpdateOptions{})
+	return err
+}
+
+// metadataDrift describes the required labels and annotations which are missing or changed, sorted.
+func metadataDrift(namespace *corev1.Namespace, required requiredMetadata) []string {
+	ret := []string{}
+	for key, value := range required.labels {
+		if actual, ok := namespace.Labels[key]; !ok {
+			ret = append(ret, fmt.Sprintf("label %s is missing", key))
+		} else if actual != value {
+			ret = append(ret, fmt.Sprintf("label %s is %q instead of %q", key, actual, value))
+		}
+	}
+	for key, value := range required.annotations {
+		if actual, ok := namespace.Annotations[key]; !ok {
+			ret = append(ret, fmt.Sprintf("annotation %s is missing", key))
+		} else if actual != value {
+			ret = append(ret, fmt.Sprintf("annotation %s is %q instead of %q", key, actual, value))
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
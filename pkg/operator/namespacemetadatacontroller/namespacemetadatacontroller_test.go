@@ -0,0 +1,123 @@
+package namespacemetadatacontroller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+)
+
+func TestSync(t *testing.T) {
+	required := requiredNamespaceMetadata()
+	namespace := func(required requiredMetadata) *corev1.Namespace {
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: required.namespace, Labels: map[string]string{}, Annotations: map[string]string{}}}
+		for key, value := range required.labels {
+			ns.Labels[key] = value
+		}
+		for key, value := range required.annotations {
+			ns.Annotations[key] = value
+		}
+		return ns
+	}
+	drifted := namespace(required[0])
+	drifted.Labels["pod-security.kubernetes.io/enforce"] = "restricted"
+	delete(drifted.Labels, "openshift.io/run-level")
+	drifted.Labels["admin"] = "kept"
+
+	testCases := []struct {
+		name           string
+		namespaces     []*corev1.Namespace
+		updateErr      error
+		expectedStatus operatorv1.ConditionStatus
+		expectedEvents int
+	}{
+		{
+			name:           "as expected",
+			namespaces:     []*corev1.Namespace{namespace(required[0]), namespace(required[1])},
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+		{
+			name:           "drift repaired",
+			namespaces:     []*corev1.Namespace{drifted, namespace(required[1])},
+			expectedStatus: operatorv1.ConditionFalse,
+			expectedEvents: 1,
+		},
+		{
+			name:           "repair failed",
+			namespaces:     []*corev1.Namespace{drifted, namespace(required[1])},
+			updateErr:      errors.New("forbidden"),
+			expectedStatus: operatorv1.ConditionTrue,
+		},
+		{
+			name:           "missing namespace",
+			namespaces:     []*corev1.Namespace{namespace(required[0])},
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			objects := []runtime.Object{}
+			for _, ns := range tc.namespaces {
+				if err := indexer.Add(ns); err != nil {
+					t.Fatal(err)
+				}
+				objects = append(objects, ns)
+			}
+			kubeClient := fake.NewSimpleClientset(objects...)
+			if tc.updateErr != nil {
+				kubeClient.PrependReactor("update", "namespaces", func(action clienttesting.Action) (bool, runtime.Object, error) {
+					return true, nil, tc.updateErr
+				})
+			}
+			operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil)
+			recorder := events.NewInMemoryRecorder("test")
+			c := &NamespaceMetadataController{
+				statusUpdater:    statusbatcher.NewDirectUpdater(operatorClient),
+				namespaceLister:  corev1listers.NewNamespaceLister(indexer),
+				namespacesGetter: kubeClient.CoreV1(),
+				required:         required,
+			}
+			if err := c.sync(context.TODO(), factory.NewSyncContext("test", recorder)); err != nil {
+				t.Fatal(err)
+			}
+
+			_, status, _, _ := operatorClient.GetOperatorState()
+			condition := v1helpers.FindOperatorCondition(status.Conditions, conditionType)
+			if condition == nil || condition.Status != tc.expectedStatus {
+				t.Errorf("expected %s, got %v", tc.expectedStatus, condition)
+			}
+			if events := recorder.Events(); len(events) != tc.expectedEvents {
+				t.Errorf("expected %d events, got %v", tc.expectedEvents, events)
+			}
+			if tc.expectedEvents == 0 {
+				return
+			}
+			repaired, err := kubeClient.CoreV1().Namespaces().Get(context.TODO(), operatorclient.TargetNamespace, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if drift := metadataDrift(repaired, required[0]); len(drift) > 0 {
+				t.Errorf("expected the namespace to be repaired, got %v", drift)
+			}
+			if repaired.Labels["admin"] != "kept" {
+				t.Errorf("expected unrelated labels to be kept, got %v", repaired.Labels)
+			}
+		})
+	}
+}
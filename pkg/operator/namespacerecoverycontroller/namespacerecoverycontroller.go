@@ -0,0 +1,185 @@
+package namespacerecoverycontroller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceread"
+	"github.com/openshift/library-go/pkg/operator/revisioncontroller"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/bindata"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operationprofile"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
+)
+
+const (
+	conditionType = "TargetNamespaceRecoveryDegraded"
+
+	namespaceAsset = "assets/kube-controller-manager/ns.yaml"
+)
+
+// NamespaceRecoveryController recreates the target namespace once a deletion of it completed, and reports the
+// recovery until the controllers recreated the resources of the next revision in it: the static resources from
+// their manifests, the generated configmaps and secrets, and the revisioned objects from the snapshots of the
+// RevisionRecoveryController. The writes of every controller fail while the namespace terminates, the condition
+// tells why rather than leaving the errors of each of them to be puzzled over.
+type NamespaceRecoveryController struct {
+	statusUpdater    statusbatcher.StatusUpdater
+	namespaceLister  corev1listers.NamespaceLister
+	namespacesGetter corev1client.NamespacesGetter
+	configMapLister  corev1listers.ConfigMapNamespaceLister
+	secretLister     corev1listers.SecretNamespaceLister
+
+	// configMaps and secrets are the resources of the next revision, which must exist for the recovery to complete
+	configMaps []revisioncontroller.RevisionResource
+	secrets    []revisioncontroller.RevisionResource
+
+	// knownUID is the UID of the namespace last seen, a namespace with another UID was recreated behind our back
+	knownUID types.UID
+	// recovering is set once the namespace was seen terminating, missing or recreated, until its resources exist again
+	recovering bool
+}
+
+func NewNamespaceRecoveryController(
+	statusUpdater statusbatcher.StatusUpdater,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	kubeClient corev1client.CoreV1Interface,
+	configMaps, secrets []revisioncontroller.RevisionResource,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	namespaceInformer := kubeInformersForNamespaces.InformersFor("").Core().V1().Namespaces()
+	targetInformers := kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1()
+	c := &NamespaceRecoveryController{
+		statusUpdater:    statusUpdater,
+		namespaceLister:  namespaceInformer.Lister(),
+		namespacesGetter: kubeClient,
+		configMapLister:  targetInformers.ConfigMaps().Lister().ConfigMaps(operatorclient.TargetNamespace),
+		secretLister:     targetInformers.Secrets().Lister().Secrets(operatorclient.TargetNamespace),
+		configMaps:       configMaps,
+		secrets:          secrets,
+	}
+
+	return factory.New().WithInformers(
+		namespaceInformer.Informer(),
+		targetInformers.ConfigMaps().Informer(),
+		targetInformers.Secrets().Informer(),
+	).ResyncEvery(operationprofile.Resync(time.Minute)).WithSync(syncmetrics.Timed("NamespaceRecoveryController", c.sync)).ToController("NamespaceRecoveryController", eventRecorder)
+}
+
+func (c *NamespaceRecoveryController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	condition := operatorv1.OperatorCondition{
+		Type:   conditionType,
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+	namespace, err := c.namespaceLister.Get(operatorclient.TargetNamespace)
+	switch {
+	case apierrors.IsNotFound(err):
+		c.recovering = true
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "Recreating"
+		condition.Message = fmt.Sprintf("namespace %s was deleted, recreating it", operatorclient.TargetNamespace)
+		if _, _, err := resourceapply.ApplyNamespace(ctx, c.namespacesGetter, syncCtx.Recorder(), resourceread.ReadNamespaceV1OrDie(bindata.MustAsset(namespaceAsset))); err != nil {
+			condition.Reason = "RecreateFailed"
+			condition.Message = fmt.Sprintf("namespace %s was deleted, recreating it failed: %v", operatorclient.TargetNamespace, err)
+		} else {
+			syncCtx.Recorder().Warningf("TargetNamespaceRecreated", "Recreated the deleted namespace %s", operatorclient.TargetNamespace)
+		}
+
+	case err != nil:
+		return err
+
+	case namespace.DeletionTimestamp != nil:
+		c.recovering = true
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "Terminating"
+		condition.Message = fmt.Sprintf("namespace %s is being deleted since %s, it is recreated once the deletion completes",
+			operatorclient.TargetNamespace, namespace.DeletionTimestamp.UTC().Format(time.RFC3339))
+		if remaining := terminationBlockers(namespace); len(remaining) > 0 {
+			condition.Message += ": " + strings.Join(remaining, "; ")
+		}
+
+	default:
+		if len(c.knownUID) > 0 && namespace.UID != c.knownUID {
+			c.recovering = true
+		}
+		c.knownUID = namespace.UID
+		if !c.recovering {
+			break
+		}
+		missing, err := c.missingResources()
+		if err != nil {
+			return err
+		}
+		if len(missing) > 0 {
+			condition.Status = operatorv1.ConditionTrue
+			condition.Reason = "RecreatingResources"
+			condition.Message = fmt.Sprintf("namespace %s was recreated, waiting for the resources of the next revision to be recreated: %s",
+				operatorclient.TargetNamespace, strings.Join(missing, ", "))
+			break
+		}
+		c.recovering = false
+		syncCtx.Recorder().Eventf("TargetNamespaceRecovered", "The resources of the next revision were recreated in namespace %s", operatorclient.TargetNamespace)
+	}
+	return c.statusUpdater.UpdateStatus(ctx, v1helpers.UpdateConditionFn(condition))
+}
+
+// terminationBlockers returns the messages of the conditions of the namespace controller telling what the deletion
+// waits for, like remaining content or finalizers.
+func terminationBlockers(namespace *corev1.Namespace) []string {
+	ret := []string{}
+	for _, condition := range namespace.Status.Conditions {
+		if condition.Status == corev1.ConditionTrue && len(condition.Message) > 0 {
+			ret = append(ret, condition.Message)
+		}
+	}
+	return ret
+}
+
+// missingResources lists the required unrevisioned configmaps and secrets which do not exist, the next revision is
+// copied from them.
+func (c *NamespaceRecoveryController) missingResources() ([]string, error) {
+	ret := []string{}
+	for _, resource := range c.configMaps {
+		if resource.Optional {
+			continue
+		}
+		_, err := c.configMapLister.Get(resource.Name)
+		if apierrors.IsNotFound(err) {
+			ret = append(ret, "configmap/"+resource.Name)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, resource := range c.secrets {
+		if resource.Optional {
+			continue
+		}
+		_, err := c.secretLister.Get(resource.Name)
+		if apierrors.IsNotFound(err) {
+			ret = append(ret, "secret/"+resource.Name)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,105 @@
+package namespacerecoverycontroller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/revisioncontroller"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+)
+
+func TestSync(t *testing.T) {
+	newIndexer := func() cache.Indexer {
+		return cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	}
+	namespaces, configMaps, secrets := newIndexer(), newIndexer(), newIndexer()
+	kubeClient := fake.NewSimpleClientset()
+	operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil)
+	c := &NamespaceRecoveryController{
+		statusUpdater:    statusbatcher.NewDirectUpdater(operatorClient),
+		namespaceLister:  corev1listers.NewNamespaceLister(namespaces),
+		namespacesGetter: kubeClient.CoreV1(),
+		configMapLister:  corev1listers.NewConfigMapLister(configMaps).ConfigMaps(operatorclient.TargetNamespace),
+		secretLister:     corev1listers.NewSecretLister(secrets).Secrets(operatorclient.TargetNamespace),
+		configMaps:       []revisioncontroller.RevisionResource{{Name: "config"}, {Name: "cloud-config", Optional: true}},
+		secrets:          []revisioncontroller.RevisionResource{{Name: "service-account-private-key"}},
+	}
+	recorder := events.NewInMemoryRecorder("test")
+	expectCondition := func(status operatorv1.ConditionStatus, reason, message string) {
+		t.Helper()
+		if err := c.sync(context.TODO(), factory.NewSyncContext("test", recorder)); err != nil {
+			t.Fatal(err)
+		}
+		_, operatorStatus, _, err := operatorClient.GetOperatorState()
+		if err != nil {
+			t.Fatal(err)
+		}
+		condition := v1helpers.FindOperatorCondition(operatorStatus.Conditions, conditionType)
+		if condition == nil || condition.Status != status || condition.Reason != reason || !strings.Contains(condition.Message, message) {
+			t.Fatalf("expected %s %s with reason %s and message containing %q, got %v", conditionType, status, reason, message, condition)
+		}
+	}
+
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: operatorclient.TargetNamespace, UID: "first"}}
+	if err := namespaces.Add(namespace); err != nil {
+		t.Fatal(err)
+	}
+	expectCondition(operatorv1.ConditionFalse, "AsExpected", "")
+
+	terminating := namespace.DeepCopy()
+	terminating.DeletionTimestamp = &metav1.Time{}
+	terminating.Status.Conditions = []corev1.NamespaceCondition{
+		{Type: corev1.NamespaceFinalizersRemaining, Status: corev1.ConditionTrue, Message: "Some content in the namespace has finalizers remaining: example.com/cleanup in 1 resource instances"},
+		{Type: corev1.NamespaceDeletionDiscoveryFailure, Status: corev1.ConditionFalse, Message: "All resources successfully discovered"},
+	}
+	if err := namespaces.Update(terminating); err != nil {
+		t.Fatal(err)
+	}
+	expectCondition(operatorv1.ConditionTrue, "Terminating", "is being deleted since 0001-01-01T00:00:00Z, it is recreated once the deletion completes: Some content in the namespace has finalizers remaining")
+
+	if err := namespaces.Delete(terminating); err != nil {
+		t.Fatal(err)
+	}
+	expectCondition(operatorv1.ConditionTrue, "Recreating", "was deleted, recreating it")
+	recreated, err := kubeClient.CoreV1().Namespaces().Get(context.TODO(), operatorclient.TargetNamespace, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected the namespace recreated: %v", err)
+	}
+	if recreated.Labels["openshift.io/run-level"] != "0" {
+		t.Errorf("expected the namespace recreated from its manifest, got %v", recreated.Labels)
+	}
+
+	recreated.UID = "second"
+	if err := namespaces.Add(recreated); err != nil {
+		t.Fatal(err)
+	}
+	if err := configMaps.Add(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "config"}}); err != nil {
+		t.Fatal(err)
+	}
+	expectCondition(operatorv1.ConditionTrue, "RecreatingResources", "waiting for the resources of the next revision to be recreated: secret/service-account-private-key")
+
+	if err := secrets.Add(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "service-account-private-key"}}); err != nil {
+		t.Fatal(err)
+	}
+	expectCondition(operatorv1.ConditionFalse, "AsExpected", "")
+	recovered := false
+	for _, event := range recorder.Events() {
+		recovered = recovered || event.Reason == "TargetNamespaceRecovered"
+	}
+	if !recovered {
+		t.Errorf("expected the recovery reported")
+	}
+}
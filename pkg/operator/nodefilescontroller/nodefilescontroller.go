@@ -0,0 +1,153 @@
+package nodefilescontroller
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/controllerpause"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operationprofile"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
+)
+
+const (
+	conditionType = "NodeFilesProgressing"
+
+	// nodeFilesConfigMapName is the revisioned copy of openshift-config/kube-controller-manager-node-files. Every
+	// revision places its keys as files in nodeFilesDir of the nodes running it, the flags of kube-controller-manager
+	// refer to them there.
+	nodeFilesConfigMapName = "node-files"
+	nodeFilesDir           = "/etc/kubernetes/static-pod-resources/configmaps/" + nodeFilesConfigMapName
+)
+
+// NodeFilesController tracks the rollout of the node files to the nodes. The files are part of the revisions, a
+// node has the current files once it runs a revision whose copy matches them. Placing the files with the revisions
+// spares the administrators authoring MachineConfigs with the exact paths kube-controller-manager reads from, and
+// the node reboots of rolling them out.
+type NodeFilesController struct {
+	operatorClient  v1helpers.StaticPodOperatorClient
+	statusUpdater   statusbatcher.StatusUpdater
+	configMapLister corev1listers.ConfigMapNamespaceLister
+}
+
+func NewNodeFilesController(
+	operatorClient v1helpers.StaticPodOperatorClient,
+	statusUpdater statusbatcher.StatusUpdater,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	configMapInformer := kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().ConfigMaps()
+	c := &NodeFilesController{
+		operatorClient:  operatorClient,
+		statusUpdater:   statusUpdater,
+		configMapLister: configMapInformer.Lister().ConfigMaps(operatorclient.TargetNamespace),
+	}
+
+	return factory.New().WithInformers(
+		operatorClient.Informer(),
+		configMapInformer.Informer(),
+	).ResyncEvery(operationprofile.Resync(time.Minute)).WithSync(syncmetrics.Timed("NodeFilesController", controllerpause.Pausable("NodeFilesController", c.sync))).ToController("NodeFilesController", eventRecorder)
+}
+
+func (c *NodeFilesController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	_, status, _, err := c.operatorClient.GetStaticPodOperatorState()
+	if err != nil {
+		return err
+	}
+	current, err := c.configMapLister.Get(nodeFilesConfigMapName)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	condition := operatorv1.OperatorCondition{
+		Type:   conditionType,
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+	if paths := filePaths(current); len(paths) > 0 {
+		condition.Message = "the node files are " + strings.Join(paths, ", ")
+	}
+
+	pending := []string{}
+	for _, nodeStatus := range status.NodeStatuses {
+		placed, err := c.revisionFiles(nodeStatus.CurrentRevision)
+		if err != nil {
+			return err
+		}
+		if !sameFiles(current, placed) {
+			pending = append(pending, nodeStatus.NodeName)
+		}
+	}
+	if len(pending) > 0 {
+		sort.Strings(pending)
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "FilesPending"
+		condition.Message = fmt.Sprintf("%d of %d nodes run a revision without the current node files: %s",
+			len(pending), len(status.NodeStatuses), strings.Join(pending, ", "))
+		if paths := filePaths(current); len(paths) > 0 {
+			condition.Message += "; the node files are " + strings.Join(paths, ", ")
+		} else {
+			condition.Message += "; the node files were removed"
+		}
+	}
+	return c.statusUpdater.UpdateStatus(ctx, v1helpers.UpdateConditionFn(condition))
+}
+
+// revisionFiles returns the copy of the node files of the revision, nil when the revision has none.
+func (c *NodeFilesController) revisionFiles(revision int32) (*corev1.ConfigMap, error) {
+	if revision == 0 {
+		return nil, nil
+	}
+	configMap, err := c.configMapLister.Get(fmt.Sprintf("%s-%d", nodeFilesConfigMapName, revision))
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	return configMap, err
+}
+
+// sameFiles tells whether the two copies of the node files, nil when there are none, hold the same files.
+func sameFiles(a, b *corev1.ConfigMap) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return reflect.DeepEqual(fileContents(a), fileContents(b))
+}
+
+func fileContents(configMap *corev1.ConfigMap) map[string]string {
+	ret := map[string]string{}
+	for key, value := range configMap.Data {
+		ret[key] = value
+	}
+	for key, value := range configMap.BinaryData {
+		ret[key] = string(value)
+	}
+	return ret
+}
+
+// filePaths returns the paths kube-controller-manager reads the node files from.
+func filePaths(configMap *corev1.ConfigMap) []string {
+	ret := []string{}
+	if configMap == nil {
+		return ret
+	}
+	for key := range fileContents(configMap) {
+		ret = append(ret, path.Join(nodeFilesDir, key))
+	}
+	sort.Strings(ret)
+	return ret
+}
@@ -0,0 +1,93 @@
+package nodefilescontroller
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+)
+
+func TestSync(t *testing.T) {
+	nodeFiles := func(name, template string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: name},
+			Data:       map[string]string{"recycler-nfs.yaml": template},
+		}
+	}
+	tests := []struct {
+		name            string
+		configMaps      []*corev1.ConfigMap
+		expectedStatus  operatorv1.ConditionStatus
+		expectedMessage string
+	}{
+		{
+			name:           "no node files",
+			configMaps:     []*corev1.ConfigMap{},
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+		{
+			name:            "rolled out",
+			configMaps:      []*corev1.ConfigMap{nodeFiles("node-files", "v2"), nodeFiles("node-files-4", "v2"), nodeFiles("node-files-5", "v2")},
+			expectedStatus:  operatorv1.ConditionFalse,
+			expectedMessage: "the node files are /etc/kubernetes/static-pod-resources/configmaps/node-files/recycler-nfs.yaml",
+		},
+		{
+			name:            "changed",
+			configMaps:      []*corev1.ConfigMap{nodeFiles("node-files", "v2"), nodeFiles("node-files-4", "v1"), nodeFiles("node-files-5", "v2")},
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedMessage: "1 of 2 nodes run a revision without the current node files: master-0; the node files are /etc/kubernetes/static-pod-resources/configmaps/node-files/recycler-nfs.yaml",
+		},
+		{
+			name:            "removed",
+			configMaps:      []*corev1.ConfigMap{nodeFiles("node-files-5", "v2")},
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedMessage: "1 of 2 nodes run a revision without the current node files: master-1; the node files were removed",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			for _, configMap := range test.configMaps {
+				if err := indexer.Add(configMap); err != nil {
+					t.Fatal(err)
+				}
+			}
+			operatorClient := v1helpers.NewFakeStaticPodOperatorClient(
+				&operatorv1.StaticPodOperatorSpec{},
+				&operatorv1.StaticPodOperatorStatus{NodeStatuses: []operatorv1.NodeStatus{
+					{NodeName: "master-0", CurrentRevision: 4},
+					{NodeName: "master-1", CurrentRevision: 5},
+				}},
+				nil,
+				nil,
+			)
+			c := &NodeFilesController{
+				operatorClient:  operatorClient,
+				statusUpdater:   statusbatcher.NewDirectUpdater(operatorClient),
+				configMapLister: corev1listers.NewConfigMapLister(indexer).ConfigMaps(operatorclient.TargetNamespace),
+			}
+			if err := c.sync(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))); err != nil {
+				t.Fatal(err)
+			}
+			_, status, _, err := operatorClient.GetOperatorState()
+			if err != nil {
+				t.Fatal(err)
+			}
+			condition := v1helpers.FindOperatorCondition(status.Conditions, conditionType)
+			if condition == nil || condition.Status != test.expectedStatus || condition.Message != test.expectedMessage {
+				t.Errorf("expected %s %s with message %q, got %v", conditionType, test.expectedStatus, test.expectedMessage, condition)
+			}
+		})
+	}
+}
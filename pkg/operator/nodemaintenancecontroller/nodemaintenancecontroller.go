@@ -0,0 +1,139 @@
+package nodemaintenancecontroller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/component-base/metrics"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/controllerpause"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operationprofile"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatormetrics"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
+)
+
+const (
+	conditionType = "RolloutWaitingForNode"
+
+	// the machine config daemon annotates the state of its update and the config it moves the node to
+	machineConfigStateAnnotation   = "machineconfiguration.openshift.io/state"
+	currentMachineConfigAnnotation = "machineconfiguration.openshift.io/currentConfig"
+	desiredMachineConfigAnnotation = "machineconfiguration.openshift.io/desiredConfig"
+	machineConfigStateWorking      = "Working"
+
+	reasonCordoned              = "Cordoned"
+	reasonMachineConfigUpdating = "MachineConfigUpdating"
+)
+
+var waitingForNode = metrics.NewGaugeVec(
+	&metrics.GaugeOpts{
+		Name:           "kcm_operator_rollout_waiting_for_node",
+		Help:           "Set to 1 for a control plane node whose rollout waits while it is cordoned or updated by the machine config daemon.",
+		StabilityLevel: metrics.ALPHA,
+	},
+	[]string{"node", "reason"},
+)
+
+func init() {
+	operatormetrics.MustRegister(waitingForNode)
+}
+
+// NodeMaintenanceController explains rollouts which do not progress because a control plane node is cordoned or in
+// the middle of a machine config update. The revision is installed once the node is back, until then the operator
+// stays Progressing without saying why.
+type NodeMaintenanceController struct {
+	operatorClient v1helpers.StaticPodOperatorClient
+	statusUpdater  statusbatcher.StatusUpdater
+	nodeLister     corev1listers.NodeLister
+}
+
+func NewNodeMaintenanceController(
+	operatorClient v1helpers.StaticPodOperatorClient,
+	statusUpdater statusbatcher.StatusUpdater,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &NodeMaintenanceController{
+		operatorClient: operatorClient,
+		statusUpdater:  statusUpdater,
+		nodeLister:     kubeInformersForNamespaces.InformersFor("").Core().V1().Nodes().Lister(),
+	}
+
+	return factory.New().WithInformers(
+		operatorClient.Informer(),
+		kubeInformersForNamespaces.InformersFor("").Core().V1().Nodes().Informer(),
+	).ResyncEvery(operationprofile.Resync(time.Minute)).WithSync(syncmetrics.Timed("NodeMaintenanceController", controllerpause.Pausable("NodeMaintenanceController", c.sync))).ToController("NodeMaintenanceController", eventRecorder)
+}
+
+func (c *NodeMaintenanceController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	_, status, _, err := c.operatorClient.GetStaticPodOperatorState()
+	if err != nil {
+		return err
+	}
+
+	reasons := map[string]bool{}
+	messages := []string{}
+	waitingForNode.Reset()
+	for _, nodeStatus := range status.NodeStatuses {
+		if nodeStatus.CurrentRevision == status.LatestAvailableRevision {
+			continue
+		}
+		node, err := c.nodeLister.Get(nodeStatus.NodeName)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		reason, maintenance := maintenanceOf(node)
+		if len(reason) == 0 {
+			continue
+		}
+		reasons[reason] = true
+		waitingForNode.WithLabelValues(node.Name, reason).Set(1)
+		messages = append(messages, fmt.Sprintf("node %s at revision %d %s", node.Name, nodeStatus.CurrentRevision, maintenance))
+	}
+
+	condition := operatorv1.OperatorCondition{
+		Type:   conditionType,
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+	if len(messages) > 0 {
+		sortedReasons := []string{}
+		for reason := range reasons {
+			sortedReasons = append(sortedReasons, reason)
+		}
+		sort.Strings(sortedReasons)
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = strings.Join(sortedReasons, "And")
+		condition.Message = fmt.Sprintf("the rollout of revision %d waits for nodes under maintenance: %s", status.LatestAvailableRevision, strings.Join(messages, ", "))
+	}
+	return c.statusUpdater.UpdateStatus(ctx, v1helpers.UpdateConditionFn(condition))
+}
+
+// maintenanceOf returns the reason and description of the maintenance the node is under, an empty reason if it is not.
+// A node the machine config daemon updates is cordoned as well, the update is the more specific explanation.
+func maintenanceOf(node *corev1.Node) (string, string) {
+	state := node.Annotations[machineConfigStateAnnotation]
+	current, desired := node.Annotations[currentMachineConfigAnnotation], node.Annotations[desiredMachineConfigAnnotation]
+	switch {
+	case state == machineConfigStateWorking || current != desired:
+		return reasonMachineConfigUpdating, fmt.Sprintf("is updated by the machine config daemon from %s to %s", current, desired)
+	case node.Spec.Unschedulable:
+		return reasonCordoned, "is cordoned, uncordon it once its maintenance is done"
+	}
+	return "", ""
+}
@@ -0,0 +1,110 @@
+package nodemaintenancecontroller
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+)
+
+func TestSync(t *testing.T) {
+	updating := map[string]string{
+		machineConfigStateAnnotation:   machineConfigStateWorking,
+		currentMachineConfigAnnotation: "rendered-master-1",
+		desiredMachineConfigAnnotation: "rendered-master-2",
+	}
+	testCases := []struct {
+		name            string
+		nodes           []*corev1.Node
+		expectedStatus  operatorv1.ConditionStatus
+		expectedReason  string
+		expectedMessage string
+	}{
+		{
+			name: "schedulable",
+			nodes: []*corev1.Node{
+				{ObjectMeta: metav1.ObjectMeta{Name: "master-0"}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "master-1"}},
+			},
+			expectedStatus: operatorv1.ConditionFalse,
+			expectedReason: "AsExpected",
+		},
+		{
+			name: "cordoned at the latest revision",
+			nodes: []*corev1.Node{
+				{ObjectMeta: metav1.ObjectMeta{Name: "master-0"}, Spec: corev1.NodeSpec{Unschedulable: true}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "master-1"}},
+			},
+			expectedStatus: operatorv1.ConditionFalse,
+			expectedReason: "AsExpected",
+		},
+		{
+			name: "cordoned",
+			nodes: []*corev1.Node{
+				{ObjectMeta: metav1.ObjectMeta{Name: "master-0"}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "master-1"}, Spec: corev1.NodeSpec{Unschedulable: true}},
+			},
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedReason:  "Cordoned",
+			expectedMessage: "the rollout of revision 3 waits for nodes under maintenance: node master-1 at revision 2 is cordoned, uncordon it once its maintenance is done",
+		},
+		{
+			name: "machine config update",
+			nodes: []*corev1.Node{
+				{ObjectMeta: metav1.ObjectMeta{Name: "master-0"}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "master-1", Annotations: updating}, Spec: corev1.NodeSpec{Unschedulable: true}},
+			},
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedReason:  "MachineConfigUpdating",
+			expectedMessage: "the rollout of revision 3 waits for nodes under maintenance: node master-1 at revision 2 is updated by the machine config daemon from rendered-master-1 to rendered-master-2",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			for _, node := range tc.nodes {
+				if err := indexer.Add(node); err != nil {
+					t.Fatal(err)
+				}
+			}
+			operatorClient := v1helpers.NewFakeStaticPodOperatorClient(
+				&operatorv1.StaticPodOperatorSpec{},
+				&operatorv1.StaticPodOperatorStatus{
+					LatestAvailableRevision: 3,
+					NodeStatuses: []operatorv1.NodeStatus{
+						{NodeName: "master-0", CurrentRevision: 3},
+						{NodeName: "master-1", CurrentRevision: 2, TargetRevision: 3},
+					},
+				},
+				nil,
+				nil,
+			)
+			c := &NodeMaintenanceController{
+				operatorClient: operatorClient,
+				statusUpdater:  statusbatcher.NewDirectUpdater(operatorClient),
+				nodeLister:     corev1listers.NewNodeLister(indexer),
+			}
+			if err := c.sync(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))); err != nil {
+				t.Fatal(err)
+			}
+			_, status, _, _ := operatorClient.GetStaticPodOperatorState()
+			condition := v1helpers.FindOperatorCondition(status.Conditions, conditionType)
+			if condition == nil {
+				t.Fatalf("missing condition %s", conditionType)
+			}
+			if condition.Status != tc.expectedStatus || condition.Reason != tc.expectedReason || condition.Message != tc.expectedMessage {
+				t.Errorf("expected %s %s %q, got %s %s %q", tc.expectedStatus, tc.expectedReason, tc.expectedMessage, condition.Status, condition.Reason, condition.Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package operandendpoint
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+const (
+	// servingName is the name of the serving certificate of kube-controller-manager, issued for its service
+	servingName = "kube-controller-manager.openshift-kube-controller-manager.svc"
+	servingPort = "10257"
+	// maxResponseBytes bounds what is read of a response, the metrics of a large cluster take a few MB
+	maxResponseBytes = 16 * 1024 * 1024
+)
+
+// Fetcher returns the response of the serving endpoint of a kube-controller-manager pod to a GET of the path.
+type Fetcher func(ctx context.Context, pod *corev1.Pod, path string) ([]byte, error)
+
+// NewFetcher returns a Fetcher reading the endpoints of the pods with the bearer token of the operator, which
+// kube-controller-manager authorizes through kube-apiserver. The serving certificate of kube-controller-manager is
+// verified against the service CA synced to the target namespace.
+func NewFetcher(kubeConfig *rest.Config, configMapLister corev1listers.ConfigMapLister) Fetcher {
+	return func(ctx context.Context, pod *corev1.Pod, path string) ([]byte, error) {
+		serviceCA, err := configMapLister.ConfigMaps(operatorclient.TargetNamespace).Get("service-ca")
+		if err != nil {
+			return nil, err
+		}
+		transport, err := rest.TransportFor(&rest.Config{
+			BearerToken:     kubeConfig.BearerToken,
+			BearerTokenFile: kubeConfig.BearerTokenFile,
+			TLSClientConfig: rest.TLSClientConfig{
+				ServerName: servingName,
+				CAData:     []byte(serviceCA.Data["ca-bundle.crt"]),
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		client := &http.Client{Transport: transport, Timeout: 30 * time.Second}
+		url := fmt.Sprintf("https://%s%s", net.JoinHostPort(pod.Status.PodIP, servingPort), path)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("%s: %s", url, resp.Status)
+		}
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes+1))
+		if err != nil {
+			return nil, err
+		}
+		if len(body) > maxResponseBytes {
+			return nil, fmt.Errorf("%s: the response exceeds %d bytes", url, maxResponseBytes)
+		}
+		return body, nil
+	}
+}
+
+// CertificateProber returns the serving certificate a kube-controller-manager pod presents.
+type CertificateProber func(ctx context.Context, pod *corev1.Pod) (*x509.Certificate, error)
+
+// NewCertificateProber returns a CertificateProber completing a TLS handshake with the serving endpoint of the pods.
+// The certificate is not verified, it is compared to the one the pod is expected to serve rather than trusted.
+func NewCertificateProber() CertificateProber {
+	return func(ctx context.Context, pod *corev1.Pod) (*x509.Certificate, error) {
+		dialer := &tls.Dialer{Config: &tls.Config{ServerName: servingName, InsecureSkipVerify: true}}
+		ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+		conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(pod.Status.PodIP, servingPort))
+		if err != nil {
+			return nil, err
+		}
+		defer conn.Close()
+		certificates := conn.(*tls.Conn).ConnectionState().PeerCertificates
+		if len(certificates) == 0 {
+			return nil, fmt.Errorf("%s presents no serving certificate", pod.Name)
+		}
+		return certificates[0], nil
+	}
+}
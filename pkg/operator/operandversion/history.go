@@ -0,0 +1,136 @@
+package operandversion
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operationprofile"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statestore"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
+)
+
+const (
+	// HistoryConfigMapName is the state document in the operator namespace holding the operand version of past revisions.
+	HistoryConfigMapName = "kube-controller-manager-operand-versions"
+	historyKey           = "history.json"
+	// maxHistoryLength keeps the document small, older revisions are long gone from the nodes
+	maxHistoryLength = 50
+
+	podConfigMapPrefix = "kube-controller-manager-pod-"
+	// OperandVersionKey is the key of the pod configmap recording the kube-controller-manager version of a revision.
+	OperandVersionKey = "operandVersion"
+)
+
+// RevisionVersion is the kube-controller-manager version deployed by a revision.
+type RevisionVersion struct {
+	Revision       int32  `json:"revision"`
+	OperandVersion string `json:"operandVersion"`
+}
+
+// HistoryController records the operand version of every revision. The revisioned pod configmaps are pruned
+// together with their revisions, the history outlives them.
+type HistoryController struct {
+	configMapLister corev1listers.ConfigMapLister
+	stateStore      statestore.Store
+}
+
+func NewHistoryController(
+	operatorClient v1helpers.StaticPodOperatorClient,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	stateStore statestore.Store,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &HistoryController{
+		configMapLister: kubeInformersForNamespaces.ConfigMapLister(),
+		stateStore:      stateStore,
+	}
+
+	return factory.New().WithInformers(
+		operatorClient.Informer(),
+		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().ConfigMaps().Informer(),
+		stateStore.Informer(),
+	).ResyncEvery(operationprofile.Resync(time.Minute)).WithSync(syncmetrics.Timed("OperandVersionHistoryController", c.sync)).ToController("OperandVersionHistoryController", eventRecorder)
+}
+
+func (c *HistoryController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	history, err := c.history()
+	if err != nil {
+		return err
+	}
+
+	configMaps, err := c.configMapLister.ConfigMaps(operatorclient.TargetNamespace).List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	history = mergeHistory(history, revisionVersions(configMaps))
+
+	raw, err := json.Marshal(history)
+	if err != nil {
+		return err
+	}
+	return c.stateStore.Put(ctx, syncCtx.Recorder(), HistoryConfigMapName, map[string]string{historyKey: string(raw)})
+}
+
+func (c *HistoryController) history() ([]RevisionVersion, error) {
+	data, err := c.stateStore.Get(HistoryConfigMapName)
+	if err != nil || data == nil {
+		return nil, err
+	}
+	history := []RevisionVersion{}
+	if err := json.Unmarshal([]byte(data[historyKey]), &history); err != nil {
+		// start over rather than getting stuck on a hand-edited document
+		return nil, nil
+	}
+	return history, nil
+}
+
+// revisionVersions reads the operand version of the revisioned pod configmaps.
+func revisionVersions(configMaps []*corev1.ConfigMap) []RevisionVersion {
+	ret := []RevisionVersion{}
+	for _, configMap := range configMaps {
+		if !strings.HasPrefix(configMap.Name, podConfigMapPrefix) {
+			continue
+		}
+		revision, err := strconv.ParseInt(strings.TrimPrefix(configMap.Name, podConfigMapPrefix), 10, 32)
+		if err != nil {
+			continue
+		}
+		if version := configMap.Data[OperandVersionKey]; len(version) > 0 {
+			ret = append(ret, RevisionVersion{Revision: int32(revision), OperandVersion: version})
+		}
+	}
+	return ret
+}
+
+// mergeHistory adds the revisions to the history, sorted by revision and capped to the latest maxHistoryLength.
+// Recorded revisions are never rewritten.
+func mergeHistory(history, revisions []RevisionVersion) []RevisionVersion {
+	known := map[int32]bool{}
+	for _, entry := range history {
+		known[entry.Revision] = true
+	}
+	for _, entry := range revisions {
+		if !known[entry.Revision] {
+			history = append(history, entry)
+			known[entry.Revision] = true
+		}
+	}
+	sort.Slice(history, func(i, j int) bool { return history[i].Revision < history[j].Revision })
+	if len(history) > maxHistoryLength {
+		history = history[len(history)-maxHistoryLength:]
+	}
+	return history
+}
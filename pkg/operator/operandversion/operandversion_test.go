@@ -0,0 +1,77 @@
+package operandversion
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateSkew(t *testing.T) {
+	tests := []struct {
+		name             string
+		operandVersion   string
+		apiServerVersion string
+		expectedErr      bool
+	}{
+		{name: "same version", operandVersion: "1.25.2", apiServerVersion: "1.25.2"},
+		{name: "older patch", operandVersion: "1.25.0", apiServerVersion: "1.25.2"},
+		{name: "one minor behind", operandVersion: "1.24.6", apiServerVersion: "1.25.2"},
+		{name: "unknown kube-apiserver version", operandVersion: "1.25.2"},
+		{name: "newer patch", operandVersion: "1.25.3", apiServerVersion: "1.25.2"},
+		{name: "with build metadata", operandVersion: "v1.25.2+5533733", apiServerVersion: "1.25.2"},
+		{name: "newer minor", operandVersion: "1.26.0", apiServerVersion: "1.25.2", expectedErr: true},
+		{name: "two minors behind", operandVersion: "1.23.0", apiServerVersion: "1.25.2", expectedErr: true},
+		{name: "different major", operandVersion: "2.25.0", apiServerVersion: "1.25.2", expectedErr: true},
+		{name: "invalid version", operandVersion: "latest", apiServerVersion: "1.25.2", expectedErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateSkew(test.operandVersion, test.apiServerVersion)
+			if (err != nil) != test.expectedErr {
+				t.Errorf("expected error %v, got %v", test.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestMergeHistory(t *testing.T) {
+	configMaps := []*corev1.ConfigMap{
+		{ObjectMeta: metav1.ObjectMeta{Name: "kube-controller-manager-pod"}, Data: map[string]string{OperandVersionKey: "1.25.2"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "kube-controller-manager-pod-4"}, Data: map[string]string{OperandVersionKey: "1.25.2"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "kube-controller-manager-pod-3"}, Data: map[string]string{OperandVersionKey: "1.24.6"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "kube-controller-manager-pod-2"}, Data: map[string]string{}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "config-4"}, Data: map[string]string{OperandVersionKey: "1.25.2"}},
+	}
+	history := []RevisionVersion{
+		{Revision: 1, OperandVersion: "1.24.0"},
+		// recorded entries are kept even if the configmap changed since
+		{Revision: 3, OperandVersion: "1.24.5"},
+	}
+
+	got := mergeHistory(history, revisionVersions(configMaps))
+	expected := []RevisionVersion{
+		{Revision: 1, OperandVersion: "1.24.0"},
+		{Revision: 3, OperandVersion: "1.24.5"},
+		{Revision: 4, OperandVersion: "1.25.2"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestMergeHistoryIsBounded(t *testing.T) {
+	revisions := []RevisionVersion{}
+	for i := int32(1); i <= maxHistoryLength+10; i++ {
+		revisions = append(revisions, RevisionVersion{Revision: i, OperandVersion: "1.25.2"})
+	}
+
+	got := mergeHistory(nil, revisions)
+	if len(got) != maxHistoryLength {
+		t.Fatalf("expected %d entries, got %d", maxHistoryLength, len(got))
+	}
+	if got[0].Revision != 11 || got[len(got)-1].Revision != maxHistoryLength+10 {
+		t.Errorf("expected the latest revisions to be kept, got %d to %d", got[0].Revision, got[len(got)-1].Revision)
+	}
+}
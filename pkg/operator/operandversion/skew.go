@@ -0,0 +1,58 @@
+package operandversion
+
+import (
+	"fmt"
+
+	"github.com/blang/semver"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	configv1listers "github.com/openshift/client-go/config/listers/config/v1"
+)
+
+// maxMinorVersionsBehind is how many minor versions kube-controller-manager may lag behind kube-apiserver.
+const maxMinorVersionsBehind = 1
+
+// APIServerVersion returns the kube-apiserver version reported by its operator, or an empty string when it
+// is not reported yet.
+func APIServerVersion(clusterOperatorLister configv1listers.ClusterOperatorLister) (string, error) {
+	clusterOperator, err := clusterOperatorLister.Get("kube-apiserver")
+	if apierrors.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	for _, version := range clusterOperator.Status.Versions {
+		if version.Name == "kube-apiserver" {
+			return version.Version, nil
+		}
+	}
+	return "", nil
+}
+
+// ValidateSkew returns an error when kube-controller-manager at operandVersion is not supported against
+// kube-apiserver at apiServerVersion: it must not be newer, and not more than one minor version older.
+// Unknown versions are not validated.
+func ValidateSkew(operandVersion, apiServerVersion string) error {
+	if len(operandVersion) == 0 || len(apiServerVersion) == 0 {
+		return nil
+	}
+	operand, err := semver.ParseTolerant(operandVersion)
+	if err != nil {
+		return fmt.Errorf("invalid kube-controller-manager version %q: %v", operandVersion, err)
+	}
+	apiServer, err := semver.ParseTolerant(apiServerVersion)
+	if err != nil {
+		return fmt.Errorf("invalid kube-apiserver version %q: %v", apiServerVersion, err)
+	}
+
+	switch {
+	case operand.Major != apiServer.Major:
+		return fmt.Errorf("kube-controller-manager %s and kube-apiserver %s differ in major version", operandVersion, apiServerVersion)
+	case operand.Minor > apiServer.Minor:
+		return fmt.Errorf("kube-controller-manager %s must not be newer than kube-apiserver %s", operandVersion, apiServerVersion)
+	case apiServer.Minor-operand.Minor > maxMinorVersionsBehind:
+		return fmt.Errorf("kube-controller-manager %s must not be more than %d minor version behind kube-apiserver %s", operandVersion, maxMinorVersionsBehind, apiServerVersion)
+	}
+	return nil
+}
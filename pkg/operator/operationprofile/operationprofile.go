@@ -0,0 +1,148 @@
+package operationprofile
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+)
+
+// Profile tunes how much the operator writes to the kube-apiserver.
+type Profile string
+
+const (
+	// Default is the profile of standalone clusters.
+	Default Profile = "Default"
+	// Fleet cuts the writes of the operator for managed fleets, where thousands of clusters share the
+	// observability budget: status updates are batched longer, the operator's own controllers resync less often,
+	// the optional metrics and debug endpoints are off and only Warning events are recorded.
+	Fleet Profile = "Fleet"
+
+	conditionType = "OperationProfile"
+)
+
+// Settings are what a profile tunes.
+type Settings struct {
+	// StatusBatchInterval is how long the status batcher collects condition updates before writing them.
+	StatusBatchInterval time.Duration
+	// ResyncMultiplier stretches the resync interval of the operator's own controllers.
+	ResyncMultiplier int
+	// OptionalEndpoints enables the external metrics listener and the debug endpoints.
+	OptionalEndpoints bool
+	// NormalEvents records Normal events, Warning events are recorded either way.
+	NormalEvents bool
+}
+
+var profiles = map[Profile]Settings{
+	Default: {StatusBatchInterval: time.Second, ResyncMultiplier: 1, OptionalEndpoints: true, NormalEvents: true},
+	Fleet:   {StatusBatchInterval: 30 * time.Second, ResyncMultiplier: 5, OptionalEndpoints: false, NormalEvents: false},
+}
+
+// active is the profile of the process, set once on start like the global tracer provider.
+var active = Default
+
+// Parse validates the name of a profile, an empty name is the Default profile.
+func Parse(name string) (Profile, error) {
+	if len(name) == 0 {
+		return Default, nil
+	}
+	if _, ok := profiles[Profile(name)]; !ok {
+		return "", fmt.Errorf("must be %s or %s", Default, Fleet)
+	}
+	return Profile(name), nil
+}
+
+// Activate makes the profile the one of the process. It has to be called before the controllers are built.
+func Activate(profile Profile) {
+	active = profile
+}
+
+// Active returns the profile of the process.
+func Active() Profile {
+	return active
+}
+
+// Current returns the settings of the profile of the process.
+func Current() Settings {
+	return profiles[active]
+}
+
+// Resync returns the resync interval of an operator controller under the profile of the process.
+func Resync(interval time.Duration) time.Duration {
+	return interval * time.Duration(Current().ResyncMultiplier)
+}
+
+// FilterEvents returns the recorder dropping the events the profile of the process does not record. Dropped events
+// are still logged.
+func FilterEvents(recorder events.Recorder) events.Recorder {
+	if Current().NormalEvents {
+		return recorder
+	}
+	return &warningsOnly{Recorder: recorder}
+}
+
+type warningsOnly struct {
+	events.Recorder
+}
+
+func (r *warningsOnly) Event(reason, message string) {
+	klog.V(2).Infof("Event(%s): %s (not recorded with the %s operation profile)", reason, message, active)
+}
+
+func (r *warningsOnly) Eventf(reason, messageFmt string, args ...interface{}) {
+	r.Event(reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r *warningsOnly) ForComponent(componentName string) events.Recorder {
+	return &warningsOnly{Recorder: r.Recorder.ForComponent(componentName)}
+}
+
+func (r *warningsOnly) WithComponentSuffix(componentNameSuffix string) events.Recorder {
+	return &warningsOnly{Recorder: r.Recorder.WithComponentSuffix(componentNameSuffix)}
+}
+
+func (r *warningsOnly) WithContext(ctx context.Context) events.Recorder {
+	return &warningsOnly{Recorder: r.Recorder.WithContext(ctx)}
+}
+
+// NewProfileController reports the profile of the process in the OperationProfile condition, so that the reduced
+// events and endpoints of a fleet cluster are not mistaken for a fault.
+func NewProfileController(statusUpdater statusbatcher.StatusUpdater, eventRecorder events.Recorder) factory.Controller {
+	return factory.New().ResyncEvery(Resync(10*time.Minute)).WithSync(func(ctx context.Context, _ factory.SyncContext) error {
+		return statusUpdater.UpdateStatus(ctx, v1helpers.UpdateConditionFn(condition()))
+	}).ToController("OperationProfileController", eventRecorder)
+}
+
+func condition() operatorv1.OperatorCondition {
+	settings := Current()
+	condition := operatorv1.OperatorCondition{
+		Type:   conditionType,
+		Status: operatorv1.ConditionFalse,
+		Reason: string(active),
+	}
+	if active == Default {
+		return condition
+	}
+	effects := []string{
+		fmt.Sprintf("status updates are batched for %s", settings.StatusBatchInterval),
+		fmt.Sprintf("the operator controllers resync %d times less often", settings.ResyncMultiplier),
+	}
+	if !settings.OptionalEndpoints {
+		effects = append(effects, "the optional metrics and debug endpoints are off")
+	}
+	if !settings.NormalEvents {
+		effects = append(effects, "only Warning events are recorded")
+	}
+	condition.Status = operatorv1.ConditionTrue
+	condition.Message = fmt.Sprintf("the %s operation profile is active: %s", active, strings.Join(effects, ", "))
+	return condition
+}
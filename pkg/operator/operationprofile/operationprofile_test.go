@@ -0,0 +1,46 @@
+package operationprofile
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/events"
+)
+
+func TestParse(t *testing.T) {
+	for name, expected := range map[string]Profile{"": Default, "Default": Default, "Fleet": Fleet} {
+		if profile, err := Parse(name); err != nil || profile != expected {
+			t.Errorf("%q: expected %s, got %s, %v", name, expected, profile, err)
+		}
+	}
+	if _, err := Parse("fleet"); err == nil {
+		t.Errorf("expected unknown profiles to be rejected")
+	}
+}
+
+func TestFleet(t *testing.T) {
+	defer Activate(Default)
+	Activate(Fleet)
+
+	if resync := Resync(time.Minute); resync != 5*time.Minute {
+		t.Errorf("expected the resync stretched to 5m, got %s", resync)
+	}
+
+	recorder := events.NewInMemoryRecorder("test")
+	filtered := FilterEvents(recorder).ForComponent("other")
+	filtered.Eventf("ConfigMapUpdated", "updated %s", "config")
+	filtered.Warningf("OperandLogLeaderElectionLost", "lost %s", "lease")
+	recorded := recorder.Events()
+	if len(recorded) != 1 || recorded[0].Type != corev1.EventTypeWarning {
+		t.Errorf("expected only the warning recorded, got %v", recorded)
+	}
+
+	condition := condition()
+	expectedMessage := "the Fleet operation profile is active: status updates are batched for 30s, the operator controllers resync 5 times less often, the optional metrics and debug endpoints are off, only Warning events are recorded"
+	if condition.Status != operatorv1.ConditionTrue || condition.Reason != "Fleet" || condition.Message != expectedMessage {
+		t.Errorf("expected %q, got %s %s %q", expectedMessage, condition.Status, condition.Reason, condition.Message)
+	}
+}
@@ -0,0 +1,84 @@
+package operatorleasecontroller
+
+import (
+	"context"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	coordinationv1listers "k8s.io/client-go/listers/coordination/v1"
+	"k8s.io/component-base/metrics"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operationprofile"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatormetrics"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
+)
+
+// leaseName is the lease the operator replicas elect their leader with, named by library-go after the component
+const leaseName = "kube-controller-manager-operator-lock"
+
+var (
+	isLeader = metrics.NewGauge(
+		&metrics.GaugeOpts{
+			Name:           "kcm_operator_leader_election_is_leader",
+			Help:           "1 when this operator replica holds the leader lease, 0 otherwise.",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+	leaseTransitions = metrics.NewGauge(
+		&metrics.GaugeOpts{
+			Name:           "kcm_operator_leader_election_transitions",
+			Help:           "Number of times the leader lease of the operator changed hands, as recorded in the lease.",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+	leaseDurationSeconds = metrics.NewGauge(
+		&metrics.GaugeOpts{
+			Name:           "kcm_operator_leader_election_lease_duration_seconds",
+			Help:           "Duration of the leader lease of the operator, after which another replica may take over.",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+)
+
+func init() {
+	operatormetrics.MustRegister(isLeader, leaseTransitions, leaseDurationSeconds)
+}
+
+// OperatorLeaseController exports the leadership of the operator and its lease as metrics. It only runs once this
+// replica leads, the other replicas serve a 0 in kcm_operator_leader_election_is_leader.
+type OperatorLeaseController struct {
+	leaseLister coordinationv1listers.LeaseLister
+}
+
+func NewOperatorLeaseController(kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces, eventRecorder events.Recorder) factory.Controller {
+	c := &OperatorLeaseController{
+		leaseLister: kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Coordination().V1().Leases().Lister(),
+	}
+	isLeader.Set(1)
+
+	return factory.New().WithInformers(
+		kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Coordination().V1().Leases().Informer(),
+	).ResyncEvery(operationprofile.Resync(5*time.Minute)).WithSync(syncmetrics.Timed("OperatorLeaseController", c.sync)).ToController("OperatorLeaseController", eventRecorder)
+}
+
+func (c *OperatorLeaseController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	lease, err := c.leaseLister.Leases(operatorclient.OperatorNamespace).Get(leaseName)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if lease.Spec.LeaseTransitions != nil {
+		leaseTransitions.Set(float64(*lease.Spec.LeaseTransitions))
+	}
+	if lease.Spec.LeaseDurationSeconds != nil {
+		leaseDurationSeconds.Set(float64(*lease.Spec.LeaseDurationSeconds))
+	}
+	return nil
+}
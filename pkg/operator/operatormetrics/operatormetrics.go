@@ -0,0 +1,108 @@
+package operatormetrics
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+)
+
+// registry holds the metrics of the operator. It is exposed through the legacy registry, so that they are served
+// at /metrics of the operator with the labels set through SetLabels.
+var registry = metrics.NewKubeRegistry()
+
+var (
+	// MustRegister registers metrics of the operator.
+	MustRegister = registry.MustRegister
+	// CustomMustRegister registers stable collectors of the operator.
+	CustomMustRegister = registry.CustomMustRegister
+)
+
+var (
+	lock   sync.RWMutex
+	labels = func() map[string]string { return nil }
+)
+
+// SetLabels sets the labels added to every metric of the operator, they are read on every scrape. Metrics with a
+// label of the same name keep theirs.
+func SetLabels(labelsFn func() map[string]string) {
+	lock.Lock()
+	defer lock.Unlock()
+	labels = labelsFn
+}
+
+func currentLabels() map[string]string {
+	lock.RLock()
+	defer lock.RUnlock()
+	return labels()
+}
+
+func init() {
+	//nolint:staticcheck // SA1019 - the labels are only known on collection, the collector cannot be a stable one.
+	legacyregistry.RawMustRegister(&labelingCollector{gatherer: registry, labels: currentLabels})
+}
+
+// labelingCollector collects the metrics of the gatherer with the labels added. It describes no metrics, so that
+// the registry accepts labels changing between scrapes.
+type labelingCollector struct {
+	gatherer prometheus.Gatherer
+	labels   func() map[string]string
+}
+
+func (c *labelingCollector) Describe(chan<- *prometheus.Desc) {}
+
+func (c *labelingCollector) Collect(ch chan<- prometheus.Metric) {
+	families, err := c.gatherer.Gather()
+	if err != nil {
+		// the gatherer returns what it could collect
+		klog.Warningf("failed to gather the operator metrics: %v", err)
+	}
+	labels := c.labels()
+	for _, family := range families {
+		desc := prometheus.NewDesc(family.GetName(), family.GetHelp(), nil, nil)
+		for _, metric := range family.Metric {
+			ch <- &labeledMetric{desc: desc, metric: metric, labels: withLabels(metric.Label, labels)}
+		}
+	}
+}
+
+// labeledMetric is a gathered metric with the labels replaced.
+type labeledMetric struct {
+	desc   *prometheus.Desc
+	metric *dto.Metric
+	labels []*dto.LabelPair
+}
+
+func (m *labeledMetric) Desc() *prometheus.Desc {
+	return m.desc
+}
+
+func (m *labeledMetric) Write(out *dto.Metric) error {
+	out.Label = m.labels
+	out.Gauge = m.metric.Gauge
+	out.Counter = m.metric.Counter
+	out.Summary = m.metric.Summary
+	out.Untyped = m.metric.Untyped
+	out.Histogram = m.metric.Histogram
+	out.TimestampMs = m.metric.TimestampMs
+	return nil
+}
+
+func withLabels(pairs []*dto.LabelPair, labels map[string]string) []*dto.LabelPair {
+	present := map[string]bool{}
+	for _, pair := range pairs {
+		present[pair.GetName()] = true
+	}
+	for name, value := range labels {
+		if !present[name] {
+			pairs = append(pairs, &dto.LabelPair{Name: pointer.String(name), Value: pointer.String(value)})
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].GetName() < pairs[j].GetName() })
+	return pairs
+}
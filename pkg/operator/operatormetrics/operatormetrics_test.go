@@ -0,0 +1,54 @@
+package operatormetrics
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/component-base/metrics"
+)
+
+func TestLabelingCollector(t *testing.T) {
+	operatorRegistry := metrics.NewKubeRegistry()
+	repairs := metrics.NewCounterVec(&metrics.CounterOpts{Name: "kcm_operator_test_total"}, []string{"platform", "destination"})
+	operatorRegistry.MustRegister(repairs)
+	repairs.WithLabelValues("own", "csr-controller-ca").Inc()
+
+	identity := map[string]string{"infrastructure_name": "fleet-7xk2p", "platform": "GCP", "topology": "SingleReplica"}
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(&labelingCollector{gatherer: operatorRegistry, labels: func() map[string]string { return identity }})
+
+	for _, scrape := range []string{"first", "after the labels changed"} {
+		families, err := registry.Gather()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(families) != 1 || len(families[0].Metric) != 1 {
+			t.Fatalf("%s scrape: expected a single metric, got %v", scrape, families)
+		}
+		if families[0].Metric[0].GetCounter().GetValue() != 1 {
+			t.Errorf("%s scrape: expected the counter to be 1, got %v", scrape, families[0].Metric[0])
+		}
+		labels := map[string]string{}
+		names := []string{}
+		for _, pair := range families[0].Metric[0].Label {
+			labels[pair.GetName()] = pair.GetValue()
+			names = append(names, pair.GetName())
+		}
+		expected := map[string]string{
+			"destination":         "csr-controller-ca",
+			"infrastructure_name": "fleet-7xk2p",
+			// the label of the metric wins
+			"platform": "own",
+			"topology": identity["topology"],
+		}
+		if !reflect.DeepEqual(labels, expected) {
+			t.Errorf("%s scrape: expected the labels %v, got %v", scrape, expected, labels)
+		}
+		if expectedNames := []string{"destination", "infrastructure_name", "platform", "topology"}; !reflect.DeepEqual(names, expectedNames) {
+			t.Errorf("%s scrape: expected the labels sorted as %v, got %v", scrape, expectedNames, names)
+		}
+
+		identity = map[string]string{"infrastructure_name": "fleet-7xk2p", "platform": "GCP", "topology": "HighlyAvailable"}
+	}
+}
@@ -0,0 +1,182 @@
+package pendingconfigcontroller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"k8s.io/component-base/metrics"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operationprofile"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatormetrics"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/revisioncache"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/targetconfigcontroller"
+)
+
+const conditionType = "ObservedConfigRolloutPending"
+
+var pendingPaths = metrics.NewGauge(
+	&metrics.GaugeOpts{
+		Name:           "kcm_operator_observed_config_pending_paths",
+		Help:           "Number of observed config paths whose value is not yet in the latest revision of the kube-controller-manager config.",
+		StabilityLevel: metrics.ALPHA,
+	},
+)
+
+func init() {
+	operatormetrics.MustRegister(pendingPaths)
+}
+
+// PendingConfigController lists the paths of the observed config which did not make it into the config of the
+// latest revision yet, so that a change waiting for its rollout can be told apart from a change which is ignored.
+// Paths the unsupportedConfigOverrides replace are ignored, paths outside of the kube-controller-manager config
+// end up elsewhere and are skipped.
+type PendingConfigController struct {
+	operatorClient v1helpers.StaticPodOperatorClient
+	statusUpdater  statusbatcher.StatusUpdater
+	revisionCache  *revisioncache.RevisionCache
+}
+
+func NewPendingConfigController(
+	operatorClient v1helpers.StaticPodOperatorClient,
+	statusUpdater statusbatcher.StatusUpdater,
+	revisionCache *revisioncache.RevisionCache,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &PendingConfigController{
+		operatorClient: operatorClient,
+		statusUpdater:  statusUpdater,
+		revisionCache:  revisionCache,
+	}
+
+	return factory.New().WithInformers(
+		operatorClient.Informer(),
+		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().ConfigMaps().Informer(),
+	).ResyncEvery(operationprofile.Resync(time.Minute)).WithSync(syncmetrics.Timed("PendingConfigController", c.sync)).ToController("PendingConfigController", eventRecorder)
+}
+
+func (c *PendingConfigController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	spec, status, _, err := c.operatorClient.GetStaticPodOperatorState()
+	if err != nil {
+		return err
+	}
+
+	observed, err := decode(spec.ObservedConfig.Raw)
+	if err != nil {
+		return fmt.Errorf("observedConfig: %v", err)
+	}
+	requiredConfigMap, err := targetconfigcontroller.MergeKubeControllerManagerConfig(spec.ObservedConfig.Raw, spec.UnsupportedConfigOverrides.Raw)
+	if err != nil {
+		return err
+	}
+	required, err := decode([]byte(requiredConfigMap.Data["config.yaml"]))
+	if err != nil {
+		return fmt.Errorf("configmap/%s: %v", requiredConfigMap.Name, err)
+	}
+	revisioned := map[string]interface{}{}
+	if status.LatestAvailableRevision > 0 {
+		revisionConfig, err := c.revisionCache.Config(status.LatestAvailableRevision)
+		if err != nil {
+			return err
+		}
+		if revisionConfig != nil {
+			revisioned = revisionConfig.Raw
+		}
+	}
+
+	pending, ignored := pendingAndIgnoredPaths(observed, required, revisioned)
+	pendingPaths.Set(float64(len(pending)))
+
+	condition := operatorv1.OperatorCondition{
+		Type:   conditionType,
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+	messages := []string{}
+	if len(pending) > 0 {
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "NotInLatestRevision"
+		messages = append(messages, fmt.Sprintf("not in revision %d yet: %s", status.LatestAvailableRevision, strings.Join(pending, ", ")))
+	}
+	if len(ignored) > 0 {
+		messages = append(messages, fmt.Sprintf("replaced by unsupportedConfigOverrides: %s", strings.Join(ignored, ", ")))
+	}
+	condition.Message = strings.Join(messages, "\n")
+	return c.statusUpdater.UpdateStatus(ctx, v1helpers.UpdateConditionFn(condition))
+}
+
+// pendingAndIgnoredPaths compares every leaf of the observed config with the config it results in and the config of
+// the latest revision, both sorted.
+func pendingAndIgnoredPaths(observed, required, revisioned map[string]interface{}) ([]string, []string) {
+	pending := []string{}
+	ignored := []string{}
+	for _, path := range leafPaths(observed, nil) {
+		observedValue, _ := lookup(observed, path)
+		requiredValue, found := lookup(required, path)
+		if !found {
+			continue
+		}
+		if !reflect.DeepEqual(observedValue, requiredValue) {
+			ignored = append(ignored, strings.Join(path, "."))
+			continue
+		}
+		if revisionedValue, _ := lookup(revisioned, path); !reflect.DeepEqual(requiredValue, revisionedValue) {
+			pending = append(pending, strings.Join(path, "."))
+		}
+	}
+	sort.Strings(pending)
+	sort.Strings(ignored)
+	return pending, ignored
+}
+
+// leafPaths returns the paths of all values in the config which are not maps themselves.
+func leafPaths(config map[string]interface{}, prefix []string) [][]string {
+	ret := [][]string{}
+	for key, value := range config {
+		path := append(append([]string{}, prefix...), key)
+		if nested, ok := value.(map[string]interface{}); ok && len(nested) > 0 {
+			ret = append(ret, leafPaths(nested, path)...)
+			continue
+		}
+		ret = append(ret, path)
+	}
+	return ret
+}
+
+func lookup(config map[string]interface{}, path []string) (interface{}, bool) {
+	var current interface{} = config
+	for _, key := range path {
+		nested, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = nested[key]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+func decode(raw []byte) (map[string]interface{}, error) {
+	ret := map[string]interface{}{}
+	if len(raw) == 0 {
+		return ret, nil
+	}
+	if err := yaml.Unmarshal(raw, &ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
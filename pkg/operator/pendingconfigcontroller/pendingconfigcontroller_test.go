@@ -0,0 +1,100 @@
+package pendingconfigcontroller
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/revisioncache"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/targetconfigcontroller"
+)
+
+func TestSync(t *testing.T) {
+	observed := `{"extendedArguments":{"cluster-name":["cluster-1"],"cluster-cidr":["10.128.0.0/14"]},"targetconfigcontroller":{"proxy":{"HTTP_PROXY":"http://proxy"}}}`
+	revisionOf := func(observedConfig, overrides string) *corev1.ConfigMap {
+		configMap, err := targetconfigcontroller.MergeKubeControllerManagerConfig([]byte(observedConfig), []byte(overrides))
+		if err != nil {
+			t.Fatal(err)
+		}
+		configMap.Name = "config-3"
+		return configMap
+	}
+
+	testCases := []struct {
+		name            string
+		overrides       string
+		revision        *corev1.ConfigMap
+		expectedStatus  operatorv1.ConditionStatus
+		expectedMessage string
+	}{
+		{
+			name:           "rolled out",
+			revision:       revisionOf(observed, ""),
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+		{
+			name:            "pending",
+			revision:        revisionOf(`{"extendedArguments":{"cluster-name":["cluster-1"]}}`, ""),
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedMessage: "not in revision 3 yet: extendedArguments.cluster-cidr",
+		},
+		{
+			name:            "missing revision",
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedMessage: "not in revision 3 yet: extendedArguments.cluster-cidr, extendedArguments.cluster-name",
+		},
+		{
+			name:            "replaced by overrides",
+			overrides:       `{"extendedArguments":{"cluster-cidr":["10.0.0.0/8"]}}`,
+			revision:        revisionOf(observed, `{"extendedArguments":{"cluster-cidr":["10.0.0.0/8"]}}`),
+			expectedStatus:  operatorv1.ConditionFalse,
+			expectedMessage: "replaced by unsupportedConfigOverrides: extendedArguments.cluster-cidr",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			configMapInformer := kubeinformers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0).Core().V1().ConfigMaps()
+			if tc.revision != nil {
+				if err := configMapInformer.Informer().GetIndexer().Add(tc.revision); err != nil {
+					t.Fatal(err)
+				}
+			}
+			operatorClient := v1helpers.NewFakeStaticPodOperatorClient(
+				&operatorv1.StaticPodOperatorSpec{OperatorSpec: operatorv1.OperatorSpec{
+					ObservedConfig:             runtime.RawExtension{Raw: []byte(observed)},
+					UnsupportedConfigOverrides: runtime.RawExtension{Raw: []byte(tc.overrides)},
+				}},
+				&operatorv1.StaticPodOperatorStatus{LatestAvailableRevision: 3},
+				nil,
+				nil,
+			)
+			c := &PendingConfigController{
+				operatorClient: operatorClient,
+				statusUpdater:  statusbatcher.NewDirectUpdater(operatorClient),
+				revisionCache:  revisioncache.New(configMapInformer),
+			}
+			if err := c.sync(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))); err != nil {
+				t.Fatal(err)
+			}
+
+			_, status, _, _ := operatorClient.GetOperatorState()
+			condition := v1helpers.FindOperatorCondition(status.Conditions, conditionType)
+			if condition == nil {
+				t.Fatalf("missing condition %s", conditionType)
+			}
+			if condition.Status != tc.expectedStatus || condition.Message != tc.expectedMessage {
+				t.Errorf("expected %s %q, got %s %q", tc.expectedStatus, tc.expectedMessage, condition.Status, condition.Message)
+			}
+		})
+	}
+}
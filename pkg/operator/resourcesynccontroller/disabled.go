@@ -0,0 +1,127 @@
+package resourcesynccontroller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resourcesynccontroller"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operationprofile"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
+)
+
+const disabledSyncsConditionType = "ResourceSyncsDisabled"
+
+// disabledSyncs returns the destinations, as <namespace>/<name>, whose sync is disabled through the resourceSync
+// stanza of the operator's UnsupportedConfigOverrides. Environments handling the material through an external
+// PKI automation disable the syncs they manage themselves:
+//
+//	unsupportedConfigOverrides:
+//	  resourceSync:
+//	    disabled:
+//	    - openshift-kube-controller-manager/kube-controller-manager-client-cert-key
+func disabledSyncs(unsupportedConfigOverrides []byte) (sets.String, error) {
+	if len(unsupportedConfigOverrides) == 0 {
+		return sets.NewString(), nil
+	}
+	overrides := struct {
+		ResourceSync struct {
+			Disabled []string `json:"disabled"`
+		} `json:"resourceSync"`
+	}{}
+	if err := json.Unmarshal(unsupportedConfigOverrides, &overrides); err != nil {
+		return nil, fmt.Errorf("failed to load resourceSync from UnsupportedConfigOverride: %v", err)
+	}
+	return sets.NewString(overrides.ResourceSync.Disabled...), nil
+}
+
+func locationKey(location resourcesynccontroller.ResourceLocation) string {
+	return location.Namespace + "/" + location.Name
+}
+
+// syncEnabled is the precondition of the sync to the destination, unfulfilled while the sync is disabled.
+func syncEnabled(operatorClient v1helpers.OperatorClient, destination resourcesynccontroller.ResourceLocation) func() (bool, error) {
+	return func() (bool, error) {
+		spec, _, _, err := operatorClient.GetOperatorState()
+		if err != nil {
+			return false, err
+		}
+		disabled, err := disabledSyncs(spec.UnsupportedConfigOverrides.Raw)
+		if err != nil {
+			return false, err
+		}
+		return !disabled.Has(locationKey(destination)), nil
+	}
+}
+
+// DisabledSyncsController reports the disabled syncs, so that it is obvious why their destinations are not
+// updated anymore.
+type DisabledSyncsController struct {
+	operatorClient v1helpers.OperatorClient
+	statusUpdater  statusbatcher.StatusUpdater
+}
+
+func NewDisabledSyncsController(
+	operatorClient v1helpers.OperatorClient,
+	statusUpdater statusbatcher.StatusUpdater,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &DisabledSyncsController{
+		operatorClient: operatorClient,
+		statusUpdater:  statusUpdater,
+	}
+
+	return factory.New().WithInformers(
+		operatorClient.Informer(),
+	).ResyncEvery(operationprofile.Resync(time.Minute)).WithSync(syncmetrics.Timed("DisabledResourceSyncsController", c.sync)).ToController("DisabledResourceSyncsController", eventRecorder)
+}
+
+func (c *DisabledSyncsController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	spec, _, _, err := c.operatorClient.GetOperatorState()
+	if err != nil {
+		return err
+	}
+	disabled, err := disabledSyncs(spec.UnsupportedConfigOverrides.Raw)
+	if err != nil {
+		return err
+	}
+
+	known := sets.NewString()
+	for _, sync := range append(append([]ResourceSync{}, ConfigMapSyncs...), SecretSyncs...) {
+		known.Insert(locationKey(sync.Destination))
+	}
+	messages := []string{}
+	for _, destination := range disabled.List() {
+		if known.Has(destination) {
+			messages = append(messages, fmt.Sprintf("the sync to %s is disabled", destination))
+		} else {
+			messages = append(messages, fmt.Sprintf("%s is not synced by the operator, ignoring it", destination))
+		}
+	}
+	sort.Strings(messages)
+
+	condition := operatorv1.OperatorCondition{
+		Type:   disabledSyncsConditionType,
+		Status: operatorv1.ConditionFalse,
+	}
+	if disabled.Intersection(known).Len() > 0 {
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "DisabledInOverrides"
+		condition.Message = strings.Join(messages, "\n")
+	} else if len(messages) > 0 {
+		condition.Reason = "UnknownSyncsIgnored"
+		condition.Message = strings.Join(messages, "\n")
+	}
+	return c.statusUpdater.UpdateStatus(ctx, v1helpers.UpdateConditionFn(condition))
+}
@@ -0,0 +1,78 @@
+package resourcesynccontroller
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+)
+
+func TestDisabledSyncs(t *testing.T) {
+	testCases := []struct {
+		name            string
+		overrides       string
+		expectedEnabled bool
+		expectedStatus  operatorv1.ConditionStatus
+		expectedReason  string
+		expectedMessage string
+	}{
+		{
+			name:            "no overrides",
+			expectedEnabled: true,
+			expectedStatus:  operatorv1.ConditionFalse,
+		},
+		{
+			name:            "disabled",
+			overrides:       `{"resourceSync":{"disabled":["openshift-kube-controller-manager/kube-controller-manager-client-cert-key","openshift-kube-controller-manager/unknown"]}}`,
+			expectedEnabled: false,
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedReason:  "DisabledInOverrides",
+			expectedMessage: "openshift-kube-controller-manager/unknown is not synced by the operator, ignoring it\nthe sync to openshift-kube-controller-manager/kube-controller-manager-client-cert-key is disabled",
+		},
+		{
+			name:            "unknown only",
+			overrides:       `{"resourceSync":{"disabled":["openshift-kube-controller-manager/unknown"]}}`,
+			expectedEnabled: true,
+			expectedStatus:  operatorv1.ConditionFalse,
+			expectedReason:  "UnknownSyncsIgnored",
+			expectedMessage: "openshift-kube-controller-manager/unknown is not synced by the operator, ignoring it",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			operatorClient := v1helpers.NewFakeOperatorClient(
+				&operatorv1.OperatorSpec{UnsupportedConfigOverrides: runtime.RawExtension{Raw: []byte(tc.overrides)}},
+				&operatorv1.OperatorStatus{},
+				nil,
+			)
+
+			enabled, err := syncEnabled(operatorClient, clientCertKeySecretSync.Destination)()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if enabled != tc.expectedEnabled {
+				t.Errorf("expected the sync enabled: %v, got %v", tc.expectedEnabled, enabled)
+			}
+			if enabled, err := syncEnabled(operatorClient, csrControllerCASync.Destination)(); err != nil || !enabled {
+				t.Errorf("expected other syncs to stay enabled, got %v, %v", enabled, err)
+			}
+
+			c := &DisabledSyncsController{operatorClient: operatorClient, statusUpdater: statusbatcher.NewDirectUpdater(operatorClient)}
+			if err := c.sync(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))); err != nil {
+				t.Fatal(err)
+			}
+			_, status, _, _ := operatorClient.GetOperatorState()
+			condition := v1helpers.FindOperatorCondition(status.Conditions, disabledSyncsConditionType)
+			if condition == nil || condition.Status != tc.expectedStatus || condition.Reason != tc.expectedReason || condition.Message != tc.expectedMessage {
+				t.Errorf("expected %s %s %q, got %v", tc.expectedStatus, tc.expectedReason, tc.expectedMessage, condition)
+			}
+		})
+	}
+}
@@ -0,0 +1,243 @@
+package resourcesynccontroller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/component-base/metrics"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/management"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operationprofile"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatormetrics"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
+)
+
+// DriftPolicy tells what the operator does about a destination edited out-of-band.
+type DriftPolicy string
+
+const (
+	// RepairDrift copies the source over the edited destination.
+	RepairDrift DriftPolicy = "Repair"
+	// ReportDrift leaves the edited destination alone and reports it in the ResourceSyncDriftDegraded condition.
+	ReportDrift DriftPolicy = "Report"
+
+	driftConditionType = "ResourceSyncDriftDegraded"
+)
+
+// guardedConfigMapSyncs are the syncs whose destinations are checked for out-of-band edits. The kube-apiserver trusts
+// the signer of the kube-controller-manager through the csr-controller-ca destination, an edit there breaks the
+// kubelet serving certificates.
+var guardedConfigMapSyncs = []ResourceSync{
+	csrControllerCASync,
+}
+
+var driftRepairs = metrics.NewCounterVec(
+	&metrics.CounterOpts{
+		Name:           "kcm_operator_resource_sync_drift_repairs_total",
+		Help:           "Number of synced destinations the operator repaired after they were edited out-of-band.",
+		StabilityLevel: metrics.ALPHA,
+	},
+	[]string{"destination"},
+)
+
+func init() {
+	operatormetrics.MustRegister(driftRepairs)
+}
+
+// driftPolicy returns the policy of the resourceSync stanza of the operator's UnsupportedConfigOverrides, Repair by
+// default:
+//
+//	unsupportedConfigOverrides:
+//	  resourceSync:
+//	    drift: Report
+func driftPolicy(unsupportedConfigOverrides []byte) (DriftPolicy, error) {
+	if len(unsupportedConfigOverrides) == 0 {
+		return RepairDrift, nil
+	}
+	overrides := struct {
+		ResourceSync struct {
+			Drift DriftPolicy `json:"drift"`
+		} `json:"resourceSync"`
+	}{}
+	if err := json.Unmarshal(unsupportedConfigOverrides, &overrides); err != nil {
+		return "", fmt.Errorf("failed to load resourceSync from UnsupportedConfigOverride: %v", err)
+	}
+	switch overrides.ResourceSync.Drift {
+	case "":
+		return RepairDrift, nil
+	case RepairDrift, ReportDrift:
+		return overrides.ResourceSync.Drift, nil
+	default:
+		return "", fmt.Errorf("resourceSync.drift: must be %s or %s, not %q", RepairDrift, ReportDrift, overrides.ResourceSync.Drift)
+	}
+}
+
+// configMapDrifted tells whether the destination of the sync was edited since the operator mirrored it: its content
+// is neither the mirrored content nor the one of the source. The DriftController records the mirrored content in
+// the same write that syncs the destination, a destination the operator did not mark yet is never considered drifted.
+func configMapDrifted(destination, source *corev1.ConfigMap, sync ResourceSync) bool {
+	mirroredContent, marked := destination.Annotations[MirroredContentAnnotation]
+	if !marked || destination.Annotations[MirroredFromAnnotation] != locationKey(sync.Source) {
+		return false
+	}
+	destinationContent := configMapContentHash(destination)
+	return destinationContent != mirroredContent && destinationContent != configMapContentHash(source)
+}
+
+func configMapContentHash(configMap *corev1.ConfigMap) string {
+	data := map[string][]byte{}
+	for key, value := range configMap.Data {
+		data[key] = []byte(value)
+	}
+	for key, value := range configMap.BinaryData {
+		data[key] = value
+	}
+	return contentHash(data)
+}
+
+// DriftController syncs the guarded destinations in place of the ResourceSyncController, and repairs the ones edited
+// out-of-band, or reports them with the Report policy. Every write marks the destination with the content it
+// mirrored, so that a source rotated again before a mark caught up is not taken for an edit.
+type DriftController struct {
+	operatorClient   v1helpers.OperatorClient
+	statusUpdater    statusbatcher.StatusUpdater
+	configMapLister  corev1listers.ConfigMapLister
+	configMapsGetter corev1client.ConfigMapsGetter
+	syncs            []ResourceSync
+}
+
+func NewDriftController(
+	operatorClient v1helpers.OperatorClient,
+	statusUpdater statusbatcher.StatusUpdater,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	configMapsGetter corev1client.ConfigMapsGetter,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &DriftController{
+		operatorClient:   operatorClient,
+		statusUpdater:    statusUpdater,
+		configMapLister:  kubeInformersForNamespaces.ConfigMapLister(),
+		configMapsGetter: configMapsGetter,
+		syncs:            guardedConfigMapSyncs,
+	}
+
+	informers := []factory.Informer{operatorClient.Informer()}
+	for _, sync := range guardedConfigMapSyncs {
+		informers = append(informers,
+			kubeInformersForNamespaces.InformersFor(sync.Destination.Namespace).Core().V1().ConfigMaps().Informer(),
+			kubeInformersForNamespaces.InformersFor(sync.Source.Namespace).Core().V1().ConfigMaps().Informer(),
+		)
+	}
+	return factory.New().WithInformers(informers...).ResyncEvery(operationprofile.Resync(time.Minute)).WithSync(syncmetrics.Timed("ResourceSyncDriftController", c.sync)).ToController("ResourceSyncDriftController", eventRecorder)
+}
+
+func (c *DriftController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	spec, _, _, err := c.operatorClient.GetOperatorState()
+	if err != nil {
+		return err
+	}
+	if !management.IsOperatorManaged(spec.ManagementState) {
+		return nil
+	}
+	disabled, err := disabledSyncs(spec.UnsupportedConfigOverrides.Raw)
+	if err != nil {
+		return err
+	}
+	condition := operatorv1.OperatorCondition{
+		Type:   driftConditionType,
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+	policy, err := driftPolicy(spec.UnsupportedConfigOverrides.Raw)
+	if err != nil {
+		// an invalid policy must not repair what was meant to be reported
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "InvalidDriftPolicy"
+		condition.Message = err.Error()
+		return c.statusUpdater.UpdateStatus(ctx, v1helpers.UpdateConditionFn(condition))
+	}
+
+	drifts := []string{}
+	for _, sync := range c.syncs {
+		if disabled.Has(locationKey(sync.Destination)) {
+			continue
+		}
+		source, err := c.configMapLister.ConfigMaps(sync.Source.Namespace).Get(sync.Source.Name)
+		if apierrors.IsNotFound(err) {
+			// like the ResourceSyncController, a destination without source is removed
+			if err := c.configMapsGetter.ConfigMaps(sync.Destination.Namespace).Delete(ctx, sync.Destination.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+				return err
+			} else if err == nil {
+				syncCtx.Recorder().Eventf("TargetConfigDeleted", "Deleted target configmap %s because source config does not exist", locationKey(sync.Destination))
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		destination, err := c.configMapLister.ConfigMaps(sync.Destination.Namespace).Get(sync.Destination.Name)
+		if apierrors.IsNotFound(err) {
+			if err := c.mirror(ctx, syncCtx.Recorder(), sync, source); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if !configMapDrifted(destination, source, sync) {
+			if err := c.mirror(ctx, syncCtx.Recorder(), sync, source); err != nil {
+				return err
+			}
+			continue
+		}
+		if policy == ReportDrift {
+			drifts = append(drifts, fmt.Sprintf("configmap %s was edited out-of-band and differs from %s", locationKey(sync.Destination), locationKey(sync.Source)))
+			continue
+		}
+		if err := c.mirror(ctx, syncCtx.Recorder(), sync, source); err != nil {
+			return err
+		}
+		driftRepairs.WithLabelValues(locationKey(sync.Destination)).Inc()
+		syncCtx.Recorder().Warningf("ResourceSyncDriftRepaired", "Repaired configmap %s, which was edited out-of-band, from %s", locationKey(sync.Destination), locationKey(sync.Source))
+	}
+	if len(drifts) > 0 {
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "DriftReported"
+		condition.Message = strings.Join(drifts, "\n") + "\nthe sync is paused until the destination is repaired or resourceSync.drift is set to Repair"
+	}
+	return c.statusUpdater.UpdateStatus(ctx, v1helpers.UpdateConditionFn(condition))
+}
+
+// mirror copies the source to the destination of the sync and marks it with the mirrored content in the same write.
+func (c *DriftController) mirror(ctx context.Context, recorder events.Recorder, sync ResourceSync, source *corev1.ConfigMap) error {
+	required := source.DeepCopy()
+	required.ObjectMeta = metav1.ObjectMeta{
+		Namespace:   sync.Destination.Namespace,
+		Name:        sync.Destination.Name,
+		Labels:      source.Labels,
+		Annotations: map[string]string{},
+	}
+	for key, value := range source.Annotations {
+		required.Annotations[key] = value
+	}
+	required.Annotations[MirroredFromAnnotation] = locationKey(sync.Source)
+	required.Annotations[MirroredContentAnnotation] = configMapContentHash(source)
+	_, _, err := resourceapply.ApplyConfigMap(ctx, c.configMapsGetter, recorder, required)
+	return err
+}
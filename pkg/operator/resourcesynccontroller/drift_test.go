@@ -0,0 +1,158 @@
+package resourcesynccontroller
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+)
+
+func TestDrift(t *testing.T) {
+	mirrored := map[string]string{"ca-bundle.crt": "ca"}
+	hash := contentHash(map[string][]byte{"ca-bundle.crt": []byte("ca")})
+	configMap := func(location ResourceSync, destination bool, data map[string]string, mirroredContent string) *corev1.ConfigMap {
+		if destination {
+			annotations := map[string]string{MirroredFromAnnotation: locationKey(location.Source), MirroredContentAnnotation: mirroredContent}
+			return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: location.Destination.Namespace, Name: location.Destination.Name, Annotations: annotations}, Data: data}
+		}
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: location.Source.Namespace, Name: location.Source.Name}, Data: data}
+	}
+
+	tests := []struct {
+		name        string
+		overrides   string
+		source      map[string]string
+		destination map[string]string
+		// mirroredContent is the content the destination is marked with, the hash of the mirrored data by default
+		mirroredContent   string
+		expectedDrift     bool
+		expectedData      map[string]string
+		expectedCondition operatorv1.ConditionStatus
+	}{
+		{
+			name:              "in sync",
+			source:            mirrored,
+			destination:       mirrored,
+			expectedData:      mirrored,
+			expectedCondition: operatorv1.ConditionFalse,
+		},
+		{
+			name:              "source rotated",
+			source:            map[string]string{"ca-bundle.crt": "rotated"},
+			destination:       mirrored,
+			expectedData:      map[string]string{"ca-bundle.crt": "rotated"},
+			expectedCondition: operatorv1.ConditionFalse,
+		},
+		{
+			name:              "source rotated again after the last sync",
+			source:            map[string]string{"ca-bundle.crt": "rotated-again"},
+			destination:       map[string]string{"ca-bundle.crt": "rotated"},
+			mirroredContent:   contentHash(map[string][]byte{"ca-bundle.crt": []byte("rotated")}),
+			expectedData:      map[string]string{"ca-bundle.crt": "rotated-again"},
+			expectedCondition: operatorv1.ConditionFalse,
+		},
+		{
+			name:              "edited out-of-band, repaired",
+			source:            mirrored,
+			destination:       map[string]string{"ca-bundle.crt": "edited"},
+			expectedDrift:     true,
+			expectedData:      mirrored,
+			expectedCondition: operatorv1.ConditionFalse,
+		},
+		{
+			name:              "edited out-of-band, reported",
+			overrides:         `{"resourceSync":{"drift":"Report"}}`,
+			source:            mirrored,
+			destination:       map[string]string{"ca-bundle.crt": "edited"},
+			expectedDrift:     true,
+			expectedData:      map[string]string{"ca-bundle.crt": "edited"},
+			expectedCondition: operatorv1.ConditionTrue,
+		},
+		{
+			name:              "edited out-of-band with an invalid policy",
+			overrides:         `{"resourceSync":{"drift":"Ignore"}}`,
+			source:            mirrored,
+			destination:       map[string]string{"ca-bundle.crt": "edited"},
+			expectedDrift:     true,
+			expectedData:      map[string]string{"ca-bundle.crt": "edited"},
+			expectedCondition: operatorv1.ConditionTrue,
+		},
+		{
+			name:              "edited with the sync disabled",
+			overrides:         `{"resourceSync":{"disabled":["openshift-config-managed/csr-controller-ca"]}}`,
+			source:            mirrored,
+			destination:       map[string]string{"ca-bundle.crt": "edited"},
+			expectedDrift:     true,
+			expectedData:      map[string]string{"ca-bundle.crt": "edited"},
+			expectedCondition: operatorv1.ConditionFalse,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mirroredContent := tc.mirroredContent
+			if len(mirroredContent) == 0 {
+				mirroredContent = hash
+			}
+			source, destination := configMap(csrControllerCASync, false, tc.source, ""), configMap(csrControllerCASync, true, tc.destination, mirroredContent)
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			for _, object := range []*corev1.ConfigMap{source, destination} {
+				if err := indexer.Add(object); err != nil {
+					t.Fatal(err)
+				}
+			}
+			kubeClient := fake.NewSimpleClientset(source, destination)
+			operatorClient := v1helpers.NewFakeOperatorClient(
+				&operatorv1.OperatorSpec{UnsupportedConfigOverrides: runtime.RawExtension{Raw: []byte(tc.overrides)}},
+				&operatorv1.OperatorStatus{},
+				nil,
+			)
+			lister := corev1listers.NewConfigMapLister(indexer)
+
+			if drifted := configMapDrifted(destination, source, csrControllerCASync); drifted != tc.expectedDrift {
+				t.Errorf("expected drift %v, got %v", tc.expectedDrift, drifted)
+			}
+
+			c := &DriftController{
+				operatorClient:   operatorClient,
+				statusUpdater:    statusbatcher.NewDirectUpdater(operatorClient),
+				configMapLister:  lister,
+				configMapsGetter: kubeClient.CoreV1(),
+				syncs:            guardedConfigMapSyncs,
+			}
+			if err := c.sync(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))); err != nil {
+				t.Fatal(err)
+			}
+
+			actual, err := kubeClient.CoreV1().ConfigMaps(destination.Namespace).Get(context.TODO(), destination.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual.Data["ca-bundle.crt"] != tc.expectedData["ca-bundle.crt"] {
+				t.Errorf("expected destination data %v, got %v", tc.expectedData, actual.Data)
+			}
+			if actual.Data["ca-bundle.crt"] == tc.source["ca-bundle.crt"] && actual.Annotations[MirroredContentAnnotation] != configMapContentHash(source) {
+				t.Errorf("expected the synced destination marked with the source content, got %v", actual.Annotations)
+			}
+			_, status, _, err := operatorClient.GetOperatorState()
+			if err != nil {
+				t.Fatal(err)
+			}
+			condition := v1helpers.FindOperatorCondition(status.Conditions, driftConditionType)
+			if condition == nil || condition.Status != tc.expectedCondition {
+				t.Errorf("expected %s %s, got %v", driftConditionType, tc.expectedCondition, condition)
+			}
+		})
+	}
+}
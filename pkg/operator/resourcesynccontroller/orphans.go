@@ -0,0 +1,287 @@
+package resourcesynccontroller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/controllerpause"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operationprofile"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
+)
+
+const (
+	// MirroredFromAnnotation marks a destination the operator mirrored, with the <namespace>/<name> of its source.
+	MirroredFromAnnotation = "kube-controller-manager.openshift.io/mirrored-from"
+	// MirroredContentAnnotation is the hash of the data the operator mirrored last. A destination whose data changed
+	// since is managed by someone else and never deleted.
+	MirroredContentAnnotation = "kube-controller-manager.openshift.io/mirrored-content"
+)
+
+// mirrorNamespaces are the namespaces the operator ever mirrored into. They are kept when the last mapping into one
+// is removed, so that its copies are still found.
+var mirrorNamespaces = []string{
+	operatorclient.TargetNamespace,
+	operatorclient.GlobalMachineSpecifiedConfigNamespace,
+}
+
+// OrphanedMirrorsController marks the destinations of the enabled syncs as mirrored, and deletes the marked
+// destinations whose mapping was removed, so that stale copies do not linger across upgrades. The destinations of
+// disabled syncs are managed by someone else, they are kept and only lose their marks.
+type OrphanedMirrorsController struct {
+	operatorClient   v1helpers.OperatorClient
+	configMapLister  corev1listers.ConfigMapLister
+	secretLister     corev1listers.SecretLister
+	configMapsGetter corev1client.ConfigMapsGetter
+	secretsGetter    corev1client.SecretsGetter
+	configMapSyncs   []ResourceSync
+	secretSyncs      []ResourceSync
+}
+
+func NewOrphanedMirrorsController(
+	operatorClient v1helpers.OperatorClient,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	configMapsGetter corev1client.ConfigMapsGetter,
+	secretsGetter corev1client.SecretsGetter,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &OrphanedMirrorsController{
+		operatorClient:   operatorClient,
+		configMapLister:  kubeInformersForNamespaces.ConfigMapLister(),
+		secretLister:     kubeInformersForNamespaces.SecretLister(),
+		configMapsGetter: configMapsGetter,
+		secretsGetter:    secretsGetter,
+		configMapSyncs:   ConfigMapSyncs,
+		secretSyncs:      SecretSyncs,
+	}
+
+	informers := []factory.Informer{operatorClient.Informer()}
+	for _, namespace := range mirrorNamespaces {
+		informers = append(informers,
+			kubeInformersForNamespaces.InformersFor(namespace).Core().V1().ConfigMaps().Informer(),
+			kubeInformersForNamespaces.InformersFor(namespace).Core().V1().Secrets().Informer(),
+		)
+	}
+	return factory.New().WithInformers(informers...).ResyncEvery(operationprofile.Resync(time.Minute)).WithSync(syncmetrics.Timed("OrphanedMirrorsController", controllerpause.Pausable("OrphanedMirrorsController", c.sync))).ToController("OrphanedMirrorsController", eventRecorder)
+}
+
+// mirror is a destination found in the mirror namespaces, or a source, reduced to what the cleanup compares.
+type mirror struct {
+	namespace, name string
+	uid             types.UID
+	annotations     map[string]string
+	contentHash     string
+}
+
+func (c *OrphanedMirrorsController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	spec, _, _, err := c.operatorClient.GetOperatorState()
+	if err != nil {
+		return err
+	}
+	disabled, err := disabledSyncs(spec.UnsupportedConfigOverrides.Raw)
+	if err != nil {
+		return err
+	}
+
+	configMaps, err := c.listConfigMaps()
+	if err != nil {
+		return err
+	}
+	if err := c.reconcile(ctx, syncCtx.Recorder(), "ConfigMap", c.configMapSyncs, disabled.Has, configMaps, c.configMapClient()); err != nil {
+		return err
+	}
+	secrets, err := c.listSecrets()
+	if err != nil {
+		return err
+	}
+	return c.reconcile(ctx, syncCtx.Recorder(), "Secret", c.secretSyncs, disabled.Has, secrets, c.secretClient())
+}
+
+// objectClient patches and deletes the configmaps or secrets of the mirror namespaces.
+type objectClient struct {
+	patch  func(ctx context.Context, namespace, name string, patch []byte) error
+	delete func(ctx context.Context, namespace, name string, uid types.UID) error
+}
+
+func (c *OrphanedMirrorsController) configMapClient() objectClient {
+	return objectClient{
+		patch: func(ctx context.Context, namespace, name string, patch []byte) error {
+			_, err := c.configMapsGetter.ConfigMaps(namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+			return err
+		},
+		delete: func(ctx context.Context, namespace, name string, uid types.UID) error {
+			return c.configMapsGetter.ConfigMaps(namespace).Delete(ctx, name, metav1.DeleteOptions{Preconditions: &metav1.Preconditions{UID: &uid}})
+		},
+	}
+}
+
+func (c *OrphanedMirrorsController) secretClient() objectClient {
+	return objectClient{
+		patch: func(ctx context.Context, namespace, name string, patch []byte) error {
+			_, err := c.secretsGetter.Secrets(namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+			return err
+		},
+		delete: func(ctx context.Context, namespace, name string, uid types.UID) error {
+			return c.secretsGetter.Secrets(namespace).Delete(ctx, name, metav1.DeleteOptions{Preconditions: &metav1.Preconditions{UID: &uid}})
+		},
+	}
+}
+
+// reconcile marks the copies of the enabled syncs, unmarks the copies of the disabled syncs and deletes the unchanged
+// copies of the removed ones. objects maps <namespace>/<name> to the objects of the mirror namespaces and the sources.
+func (c *OrphanedMirrorsController) reconcile(ctx context.Context, recorder events.Recorder, kind string, syncs []ResourceSync, isDisabled func(string) bool, objects map[string]mirror, client objectClient) error {
+	enabled := map[string]string{}
+	disabled := map[string]bool{}
+	for _, sync := range syncs {
+		if destination := locationKey(sync.Destination); isDisabled(destination) {
+			disabled[destination] = true
+		} else {
+			enabled[destination] = locationKey(sync.Source)
+		}
+	}
+
+	keys := make([]string, 0, len(objects))
+	for key := range objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		object := objects[key]
+		if source, ok := enabled[key]; ok {
+			// only a copy of the current source content is ours, the sync may not have caught up yet
+			sourceObject, found := objects[source]
+			if !found || sourceObject.contentHash != object.contentHash {
+				continue
+			}
+			if object.annotations[MirroredFromAnnotation] == source && object.annotations[MirroredContentAnnotation] == object.contentHash {
+				continue
+			}
+			if err := patchAnnotations(ctx, client, object, map[string]interface{}{
+				MirroredFromAnnotation:    source,
+				MirroredContentAnnotation: object.contentHash,
+			}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if _, marked := object.annotations[MirroredFromAnnotation]; !marked {
+			continue
+		}
+		if disabled[key] {
+			// the sync was disabled to hand the destination to someone else, it stays in place
+			if err := patchAnnotations(ctx, client, object, map[string]interface{}{
+				MirroredFromAnnotation:    nil,
+				MirroredContentAnnotation: nil,
+			}); err != nil {
+				return err
+			}
+			recorder.Eventf("OrphanedMirrorKept", "%s %s is no longer synced because its sync is disabled, leaving it in place", kind, key)
+			continue
+		}
+		if object.annotations[MirroredContentAnnotation] != object.contentHash {
+			// changed since it was mirrored, whoever changed it manages it now
+			if err := patchAnnotations(ctx, client, object, map[string]interface{}{
+				MirroredFromAnnotation:    nil,
+				MirroredContentAnnotation: nil,
+			}); err != nil {
+				return err
+			}
+			recorder.Eventf("OrphanedMirrorKept", "%s %s is no longer synced and was modified since it was mirrored, leaving it in place", kind, key)
+			continue
+		}
+		// the precondition keeps a recreated object with the same name
+		err := client.delete(ctx, object.namespace, object.name, object.uid)
+		if apierrors.IsNotFound(err) || apierrors.IsConflict(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		recorder.Eventf("OrphanedMirrorDeleted", "Deleted %s %s, a copy of %s which is no longer synced", kind, key, object.annotations[MirroredFromAnnotation])
+	}
+	return nil
+}
+
+func patchAnnotations(ctx context.Context, client objectClient, object mirror, annotations map[string]interface{}) error {
+	patch, err := json.Marshal(map[string]interface{}{"metadata": map[string]interface{}{"annotations": annotations}})
+	if err != nil {
+		return err
+	}
+	return client.patch(ctx, object.namespace, object.name, patch)
+}
+
+// contentHash hashes the data of a configmap or secret, independent of the order of its keys.
+func contentHash(data map[string][]byte) string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	hash := sha256.New()
+	for _, key := range keys {
+		fmt.Fprintf(hash, "%s:%d:", key, len(data[key]))
+		hash.Write(data[key])
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil))
+}
+
+func (c *OrphanedMirrorsController) listConfigMaps() (map[string]mirror, error) {
+	objects := map[string]mirror{}
+	for _, namespace := range c.namespaces(c.configMapSyncs) {
+		configMaps, err := c.configMapLister.ConfigMaps(namespace).List(labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+		for _, configMap := range configMaps {
+			objects[configMap.Namespace+"/"+configMap.Name] = mirror{namespace: configMap.Namespace, name: configMap.Name, uid: configMap.UID, annotations: configMap.Annotations, contentHash: configMapContentHash(configMap)}
+		}
+	}
+	return objects, nil
+}
+
+func (c *OrphanedMirrorsController) listSecrets() (map[string]mirror, error) {
+	objects := map[string]mirror{}
+	for _, namespace := range c.namespaces(c.secretSyncs) {
+		secrets, err := c.secretLister.Secrets(namespace).List(labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+		for _, secret := range secrets {
+			objects[secret.Namespace+"/"+secret.Name] = mirror{namespace: secret.Namespace, name: secret.Name, uid: secret.UID, annotations: secret.Annotations, contentHash: contentHash(secret.Data)}
+		}
+	}
+	return objects, nil
+}
+
+// namespaces returns the mirror namespaces and the namespaces of the sources.
+func (c *OrphanedMirrorsController) namespaces(syncs []ResourceSync) []string {
+	namespaces := map[string]bool{}
+	for _, namespace := range mirrorNamespaces {
+		namespaces[namespace] = true
+	}
+	for _, sync := range syncs {
+		namespaces[sync.Source.Namespace] = true
+	}
+	sorted := make([]string, 0, len(namespaces))
+	for namespace := range namespaces {
+		sorted = append(sorted, namespace)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
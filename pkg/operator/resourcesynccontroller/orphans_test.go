@@ -0,0 +1,99 @@
+package resourcesynccontroller
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resourcesynccontroller"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+func TestOrphanedMirrors(t *testing.T) {
+	content := map[string]string{"ca-bundle.crt": "ca"}
+	hash := contentHash(map[string][]byte{"ca-bundle.crt": []byte("ca")})
+	configMap := func(namespace, name string, data map[string]string, annotations map[string]string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Annotations: annotations}, Data: data}
+	}
+	objects := []*corev1.ConfigMap{
+		configMap(operatorclient.GlobalMachineSpecifiedConfigNamespace, "source", content, nil),
+		// the destination of an enabled sync, not marked yet
+		configMap(operatorclient.TargetNamespace, "enabled", content, nil),
+		// the copy of a mapping removed from the code
+		configMap(operatorclient.TargetNamespace, "removed", content, map[string]string{MirroredFromAnnotation: "openshift-config-managed/source", MirroredContentAnnotation: hash}),
+		// the copy of a disabled mapping, since updated by someone else
+		configMap(operatorclient.TargetNamespace, "disabled", map[string]string{"ca-bundle.crt": "external"}, map[string]string{MirroredFromAnnotation: "openshift-config-managed/source", MirroredContentAnnotation: hash}),
+		// the unchanged copy of a disabled mapping
+		configMap(operatorclient.TargetNamespace, "disabled-unchanged", content, map[string]string{MirroredFromAnnotation: "openshift-config-managed/source", MirroredContentAnnotation: hash}),
+		// never mirrored
+		configMap(operatorclient.TargetNamespace, "unrelated", content, nil),
+	}
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	runtimeObjects := []runtime.Object{}
+	for _, object := range objects {
+		if err := indexer.Add(object); err != nil {
+			t.Fatal(err)
+		}
+		runtimeObjects = append(runtimeObjects, object)
+	}
+	kubeClient := fake.NewSimpleClientset(runtimeObjects...)
+	source := resourcesynccontroller.ResourceLocation{Namespace: operatorclient.GlobalMachineSpecifiedConfigNamespace, Name: "source"}
+	operatorClient := v1helpers.NewFakeOperatorClient(
+		&operatorv1.OperatorSpec{UnsupportedConfigOverrides: runtime.RawExtension{Raw: []byte(`{"resourceSync":{"disabled":["openshift-kube-controller-manager/disabled","openshift-kube-controller-manager/disabled-unchanged"]}}`)}},
+		&operatorv1.OperatorStatus{},
+		nil,
+	)
+	c := &OrphanedMirrorsController{
+		operatorClient:   operatorClient,
+		configMapLister:  corev1listers.NewConfigMapLister(indexer),
+		secretLister:     corev1listers.NewSecretLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})),
+		configMapsGetter: kubeClient.CoreV1(),
+		secretsGetter:    kubeClient.CoreV1(),
+		configMapSyncs: []ResourceSync{
+			{Destination: resourcesynccontroller.ResourceLocation{Namespace: operatorclient.TargetNamespace, Name: "enabled"}, Source: source},
+			{Destination: resourcesynccontroller.ResourceLocation{Namespace: operatorclient.TargetNamespace, Name: "disabled"}, Source: source},
+			{Destination: resourcesynccontroller.ResourceLocation{Namespace: operatorclient.TargetNamespace, Name: "disabled-unchanged"}, Source: source},
+		},
+	}
+	if err := c.sync(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))); err != nil {
+		t.Fatal(err)
+	}
+
+	get := func(name string) *corev1.ConfigMap {
+		configMap, err := kubeClient.CoreV1().ConfigMaps(operatorclient.TargetNamespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		return configMap
+	}
+	if enabled := get("enabled"); enabled.Annotations[MirroredFromAnnotation] != "openshift-config-managed/source" || enabled.Annotations[MirroredContentAnnotation] != hash {
+		t.Errorf("expected the enabled destination marked as mirrored, got %v", enabled.Annotations)
+	}
+	if removed := get("removed"); removed != nil {
+		t.Errorf("expected the copy of the removed mapping deleted")
+	}
+	if disabled := get("disabled"); disabled == nil || len(disabled.Annotations) > 0 {
+		t.Errorf("expected the modified copy of the disabled mapping kept and unmarked, got %v", disabled)
+	}
+	if disabled := get("disabled-unchanged"); disabled == nil || len(disabled.Annotations) > 0 {
+		t.Errorf("expected the unchanged copy of the disabled mapping kept and unmarked, got %v", disabled)
+	}
+	if unrelated := get("unrelated"); unrelated == nil || len(unrelated.Annotations) > 0 {
+		t.Errorf("expected unrelated configmaps left alone, got %v", unrelated)
+	}
+}
@@ -10,18 +10,60 @@ import (
 	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
 )
 
+// ResourceSync is a single mapping copying the source resource to the destination.
+type ResourceSync struct {
+	Destination resourcesynccontroller.ResourceLocation
+	Source      resourcesynccontroller.ResourceLocation
+	// Optional is set when the source only exists if the administrator creates it, its absence is not reported.
+	Optional bool
+}
+
+var csrControllerCASync = ResourceSync{
+	Destination: resourcesynccontroller.ResourceLocation{Namespace: operatorclient.GlobalMachineSpecifiedConfigNamespace, Name: "csr-controller-ca"},
+	Source:      resourcesynccontroller.ResourceLocation{Namespace: operatorclient.OperatorNamespace, Name: "csr-controller-ca"},
+}
+
+var clientCertKeySecretSync = ResourceSync{
+	Destination: resourcesynccontroller.ResourceLocation{Namespace: operatorclient.TargetNamespace, Name: "kube-controller-manager-client-cert-key"},
+	Source:      resourcesynccontroller.ResourceLocation{Namespace: operatorclient.GlobalMachineSpecifiedConfigNamespace, Name: "kube-controller-manager-client-cert-key"},
+}
+
+// ConfigMapSyncs are the configmaps synchronized by the operator.
+var ConfigMapSyncs = []ResourceSync{
+	csrControllerCASync,
+	{
+		Destination: resourcesynccontroller.ResourceLocation{Namespace: operatorclient.TargetNamespace, Name: "service-ca"},
+		Source:      resourcesynccontroller.ResourceLocation{Namespace: operatorclient.GlobalMachineSpecifiedConfigNamespace, Name: "service-ca"},
+	},
+	// kcm is re-using the generic-apiserver, so if we set the client-ca and front-proxy-ca manually, it won't try to load them
+	// dynamically from the cluster and won't crash when API isn't available
+	{
+		Destination: resourcesynccontroller.ResourceLocation{Namespace: operatorclient.TargetNamespace, Name: "client-ca"},
+		Source:      resourcesynccontroller.ResourceLocation{Namespace: operatorclient.GlobalMachineSpecifiedConfigNamespace, Name: "kube-apiserver-client-ca"},
+	},
+	{
+		Destination: resourcesynccontroller.ResourceLocation{Namespace: operatorclient.TargetNamespace, Name: "aggregator-client-ca"},
+		Source:      resourcesynccontroller.ResourceLocation{Namespace: operatorclient.GlobalMachineSpecifiedConfigNamespace, Name: "kube-apiserver-aggregator-client-ca"},
+	},
+	// the files the administrator places next to kube-controller-manager on every node, like recycler pod templates
+	{
+		Destination: resourcesynccontroller.ResourceLocation{Namespace: operatorclient.TargetNamespace, Name: "node-files"},
+		Source:      resourcesynccontroller.ResourceLocation{Namespace: operatorclient.GlobalUserSpecifiedConfigNamespace, Name: "kube-controller-manager-node-files"},
+		Optional:    true,
+	},
+}
+
+// SecretSyncs are the secrets synchronized by the operator.
+var SecretSyncs = []ResourceSync{
+	clientCertKeySecretSync,
+}
+
 func AddSyncCSRControllerCA(resourceSyncController *resourcesynccontroller.ResourceSyncController) error {
-	return resourceSyncController.SyncConfigMap(
-		resourcesynccontroller.ResourceLocation{Namespace: operatorclient.GlobalMachineSpecifiedConfigNamespace, Name: "csr-controller-ca"},
-		resourcesynccontroller.ResourceLocation{Namespace: operatorclient.OperatorNamespace, Name: "csr-controller-ca"},
-	)
+	return resourceSyncController.SyncConfigMap(csrControllerCASync.Destination, csrControllerCASync.Source)
 }
 
 func AddSyncClientCertKeySecret(resourceSyncController *resourcesynccontroller.ResourceSyncController) error {
-	return resourceSyncController.SyncSecret(
-		resourcesynccontroller.ResourceLocation{Namespace: operatorclient.TargetNamespace, Name: "kube-controller-manager-client-cert-key"},
-		resourcesynccontroller.ResourceLocation{Namespace: operatorclient.GlobalMachineSpecifiedConfigNamespace, Name: "kube-controller-manager-client-cert-key"},
-	)
+	return resourceSyncController.SyncSecret(clientCertKeySecretSync.Destination, clientCertKeySecretSync.Source)
 }
 
 func NewResourceSyncController(
@@ -38,32 +80,24 @@ func NewResourceSyncController(
 		v1helpers.CachedConfigMapGetter(configMapsGetter, kubeInformersForNamespaces),
 		eventRecorder,
 	)
-	if err := AddSyncCSRControllerCA(resourceSyncController); err != nil {
-		return nil, err
-	}
-	if err := AddSyncClientCertKeySecret(resourceSyncController); err != nil {
-		return nil, err
+	// syncs disabled in the operator spec leave their destination alone, it is managed by someone else then. The
+	// DriftController syncs the guarded destinations itself.
+	guarded := map[ResourceSync]bool{}
+	for _, sync := range guardedConfigMapSyncs {
+		guarded[sync] = true
 	}
-	if err := resourceSyncController.SyncConfigMap(
-		resourcesynccontroller.ResourceLocation{Namespace: operatorclient.TargetNamespace, Name: "service-ca"},
-		resourcesynccontroller.ResourceLocation{Namespace: operatorclient.GlobalMachineSpecifiedConfigNamespace, Name: "service-ca"},
-	); err != nil {
-		return nil, err
-	}
-
-	// kcm is re-using the generic-apiserver, so if we set the client-ca and front-proxy-ca manually, it won't try to load them
-	// dynamically from the cluster and won't crash when API isn't available
-	if err := resourceSyncController.SyncConfigMap(
-		resourcesynccontroller.ResourceLocation{Namespace: operatorclient.TargetNamespace, Name: "client-ca"},
-		resourcesynccontroller.ResourceLocation{Namespace: operatorclient.GlobalMachineSpecifiedConfigNamespace, Name: "kube-apiserver-client-ca"},
-	); err != nil {
-		return nil, err
+	for _, sync := range ConfigMapSyncs {
+		if guarded[sync] {
+			continue
+		}
+		if err := resourceSyncController.SyncConfigMapConditionally(sync.Destination, sync.Source, syncEnabled(operatorConfigClient, sync.Destination)); err != nil {
+			return nil, err
+		}
 	}
-	if err := resourceSyncController.SyncConfigMap(
-		resourcesynccontroller.ResourceLocation{Namespace: operatorclient.TargetNamespace, Name: "aggregator-client-ca"},
-		resourcesynccontroller.ResourceLocation{Namespace: operatorclient.GlobalMachineSpecifiedConfigNamespace, Name: "kube-apiserver-aggregator-client-ca"},
-	); err != nil {
-		return nil, err
+	for _, sync := range SecretSyncs {
+		if err := resourceSyncController.SyncSecretConditionally(sync.Destination, sync.Source, syncEnabled(operatorConfigClient, sync.Destination)); err != nil {
+			return nil, err
+		}
 	}
 
 	return resourceSyncController, nil
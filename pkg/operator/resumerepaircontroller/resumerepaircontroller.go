@@ -0,0 +1,179 @@
+package resumerepaircontroller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/util/cert"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operationprofile"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statestore"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
+)
+
+const (
+	conditionType = "ResumedClusterCertRepair"
+
+	// ConfigMapName is the state document where the operator records when it last ran and the last resume it detected.
+	ConfigMapName = "kube-controller-manager-operator-heartbeat"
+	lastSeenKey   = "lastSeen"
+	resumedAtKey  = "resumedAt"
+	gapKey        = "gap"
+
+	// a gap in the heartbeat longer than resumeGap means the cluster was suspended
+	resumeGap = 24 * time.Hour
+	// the heartbeat is written at most once per heartbeatInterval, far more often than resumeGap
+	heartbeatInterval = 5 * time.Minute
+)
+
+// certificate is a secret holding a certificate the operator rotates.
+type certificate struct {
+	namespace, name string
+}
+
+var certificates = []certificate{
+	{namespace: operatorclient.OperatorNamespace, name: "csr-signer-signer"},
+	{namespace: operatorclient.OperatorNamespace, name: "csr-signer"},
+	{namespace: operatorclient.TargetNamespace, name: "csr-signer"},
+	{namespace: operatorclient.TargetNamespace, name: "cluster-policy-controller-client-cert-key"},
+}
+
+// ResumeRepairController detects that the cluster was resumed after being suspended for long, by a gap in the
+// heartbeat it records, and follows the repair of the certificates the operator rotates. The rotation controllers
+// replace expired certificates on their first sync, this controller does not trigger any rotation: it only reports
+// which certificates were past their refresh threshold at the resume and which of them were replaced since.
+type ResumeRepairController struct {
+	statusUpdater statusbatcher.StatusUpdater
+	secretLister  corev1listers.SecretLister
+	stateStore    statestore.Store
+	now           func() time.Time
+}
+
+func NewResumeRepairController(
+	operatorClient v1helpers.OperatorClient,
+	statusUpdater statusbatcher.StatusUpdater,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	stateStore statestore.Store,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &ResumeRepairController{
+		statusUpdater: statusUpdater,
+		secretLister:  kubeInformersForNamespaces.SecretLister(),
+		stateStore:    stateStore,
+		now:           time.Now,
+	}
+
+	return factory.New().WithInformers(
+		operatorClient.Informer(),
+		stateStore.Informer(),
+		kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().Secrets().Informer(),
+		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().Secrets().Informer(),
+	).ResyncEvery(operationprofile.Resync(heartbeatInterval)).WithSync(syncmetrics.Timed("ResumeRepairController", c.sync)).ToController("ResumeRepairController", eventRecorder)
+}
+
+func (c *ResumeRepairController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	now := c.now()
+	heartbeat := map[string]string{}
+	existing, err := c.stateStore.Get(ConfigMapName)
+	if err != nil {
+		return err
+	}
+	for key, value := range existing {
+		heartbeat[key] = value
+	}
+
+	// a missing or unreadable heartbeat starts over, a fresh install is no resume
+	if lastSeen, err := time.Parse(time.RFC3339, heartbeat[lastSeenKey]); err == nil && now.Sub(lastSeen) > resumeGap {
+		gap := now.Sub(lastSeen).Round(time.Minute)
+		heartbeat[resumedAtKey] = now.Format(time.RFC3339)
+		heartbeat[gapKey] = gap.String()
+		syncCtx.Recorder().Warningf("ClusterResumed", "The operator did not run for %s, checking the certificates it rotates", gap)
+	}
+	// the controller syncs on every status and secret change, the heartbeat is only written once it got older than
+	// heartbeatInterval to keep the writes to etcd down
+	if lastSeen, err := time.Parse(time.RFC3339, heartbeat[lastSeenKey]); err != nil || now.Sub(lastSeen) >= heartbeatInterval {
+		heartbeat[lastSeenKey] = now.Format(time.RFC3339)
+		if err := c.stateStore.Put(ctx, syncCtx.Recorder(), ConfigMapName, heartbeat); err != nil {
+			return err
+		}
+	}
+
+	condition := operatorv1.OperatorCondition{
+		Type:   conditionType,
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+	resumedAt, err := time.Parse(time.RFC3339, heartbeat[resumedAtKey])
+	if err != nil {
+		// never resumed
+		return c.statusUpdater.UpdateStatus(ctx, v1helpers.UpdateConditionFn(condition))
+	}
+
+	pending, replaced, err := c.certificateRepairs(resumedAt, now)
+	if err != nil {
+		return err
+	}
+	summary := fmt.Sprintf("cluster resumed at %s after %s", heartbeat[resumedAtKey], heartbeat[gapKey])
+	switch {
+	case len(pending) > 0:
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "RotationPending"
+		condition.Message = fmt.Sprintf("%s, awaiting rotation by the cert rotation controllers: %s", summary, strings.Join(pending, ", "))
+	case len(replaced) > 0:
+		condition.Reason = "Repaired"
+		condition.Message = fmt.Sprintf("%s, rotated: %s", summary, strings.Join(replaced, ", "))
+	default:
+		condition.Reason = "NoRepairNeeded"
+		condition.Message = summary
+	}
+	return c.statusUpdater.UpdateStatus(ctx, v1helpers.UpdateConditionFn(condition))
+}
+
+// certificateRepairs returns the certificates past their refresh threshold, 80% of their validity as the rotation
+// controllers use, and the ones issued since the resume, both sorted.
+func (c *ResumeRepairController) certificateRepairs(resumedAt, now time.Time) ([]string, []string, error) {
+	pending := []string{}
+	replaced := []string{}
+	for _, certificate := range certificates {
+		secret, err := c.secretLister.Secrets(certificate.namespace).Get(certificate.name)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		certs, err := cert.ParseCertsPEM(secret.Data["tls.crt"])
+		if err != nil || len(certs) == 0 {
+			// a broken certificate is reported and replaced by its rotation controller
+			continue
+		}
+		name := certificate.namespace + "/" + certificate.name
+		notBefore, notAfter := certs[0].NotBefore, certs[0].NotAfter
+		if refreshAt := notAfter.Add(-notAfter.Sub(notBefore) / 5); now.After(refreshAt) {
+			state := "past refresh"
+			if now.After(notAfter) {
+				state = "expired"
+			}
+			pending = append(pending, fmt.Sprintf("%s (%s)", name, state))
+			continue
+		}
+		if notBefore.After(resumedAt.Add(-time.Minute)) {
+			replaced = append(replaced, name)
+		}
+	}
+	sort.Strings(pending)
+	sort.Strings(replaced)
+	return pending, replaced, nil
+}
@@ -0,0 +1,139 @@
+package resumerepaircontroller
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/crypto"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statestore"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+)
+
+func TestSync(t *testing.T) {
+	signer, err := crypto.MakeSelfSignedCAConfigForDuration("kube-csr-signer", 30*24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM, keyPEM, err := signer.GetPEMBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	issued := signer.Certs[0].NotBefore
+
+	testCases := []struct {
+		name     string
+		lastSeen string
+		now      time.Time
+		// heartbeatKept is set when the heartbeat is too recent to be written again
+		heartbeatKept   bool
+		expectedStatus  operatorv1.ConditionStatus
+		expectedReason  string
+		expectedMessage string
+	}{
+		{
+			name:           "first start",
+			now:            issued.Add(time.Hour),
+			expectedStatus: operatorv1.ConditionFalse,
+			expectedReason: "AsExpected",
+		},
+		{
+			name:           "regular restart",
+			lastSeen:       issued.Format(time.RFC3339),
+			now:            issued.Add(time.Hour),
+			expectedStatus: operatorv1.ConditionFalse,
+			expectedReason: "AsExpected",
+		},
+		{
+			name:           "recent heartbeat",
+			lastSeen:       issued.Format(time.RFC3339),
+			now:            issued.Add(time.Minute),
+			heartbeatKept:  true,
+			expectedStatus: operatorv1.ConditionFalse,
+			expectedReason: "AsExpected",
+		},
+		{
+			name:            "resumed with an expired certificate",
+			lastSeen:        issued.Format(time.RFC3339),
+			now:             issued.Add(60 * 24 * time.Hour),
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedReason:  "RotationPending",
+			expectedMessage: "awaiting rotation by the cert rotation controllers: openshift-kube-controller-manager/csr-signer (expired)",
+		},
+		{
+			name:            "resumed and rotated",
+			lastSeen:        issued.Add(-60 * 24 * time.Hour).Format(time.RFC3339),
+			now:             issued.Add(time.Second),
+			expectedStatus:  operatorv1.ConditionFalse,
+			expectedReason:  "Repaired",
+			expectedMessage: "rotated: openshift-kube-controller-manager/csr-signer",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			configMapIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			if len(tc.lastSeen) > 0 {
+				if err := configMapIndexer.Add(&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: ConfigMapName},
+					Data:       map[string]string{lastSeenKey: tc.lastSeen},
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			secretIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			if err := secretIndexer.Add(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "csr-signer"},
+				Data:       map[string][]byte{"tls.crt": certPEM, "tls.key": keyPEM},
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil)
+			kubeClient := fake.NewSimpleClientset()
+			c := &ResumeRepairController{
+				statusUpdater: statusbatcher.NewDirectUpdater(operatorClient),
+				secretLister:  corev1listers.NewSecretLister(secretIndexer),
+				stateStore:    statestore.NewConfigMapStoreFromLister(corev1listers.NewConfigMapLister(configMapIndexer), kubeClient.CoreV1()),
+				now:           func() time.Time { return tc.now },
+			}
+			if err := c.sync(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))); err != nil {
+				t.Fatal(err)
+			}
+
+			if tc.heartbeatKept {
+				if actions := kubeClient.Actions(); len(actions) > 0 {
+					t.Errorf("expected the recent heartbeat to be kept, got %v", actions)
+				}
+			} else {
+				heartbeat, err := kubeClient.CoreV1().ConfigMaps(operatorclient.OperatorNamespace).Get(context.TODO(), ConfigMapName, metav1.GetOptions{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if heartbeat.Data[lastSeenKey] != tc.now.Format(time.RFC3339) {
+					t.Errorf("expected the heartbeat to be %s, got %s", tc.now.Format(time.RFC3339), heartbeat.Data[lastSeenKey])
+				}
+			}
+			_, status, _, _ := operatorClient.GetOperatorState()
+			condition := v1helpers.FindOperatorCondition(status.Conditions, conditionType)
+			if condition == nil {
+				t.Fatalf("missing condition %s", conditionType)
+			}
+			if condition.Status != tc.expectedStatus || condition.Reason != tc.expectedReason || !strings.Contains(condition.Message, tc.expectedMessage) {
+				t.Errorf("expected %s %s %q, got %s %s %q", tc.expectedStatus, tc.expectedReason, tc.expectedMessage, condition.Status, condition.Reason, condition.Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package revisioncache
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ghodss/yaml"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	corev1informers "k8s.io/client-go/informers/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	kubecontrolplanev1 "github.com/openshift/api/kubecontrolplane/v1"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/targetconfigcontroller"
+)
+
+const (
+	// configConfigMapName is the revisioned configmap holding the config.yaml of kube-controller-manager
+	configConfigMapName = "config"
+	// flagsConfigMapName is the revisioned configmap the target config controller publishes the flags of the
+	// kube-controller-manager container in, one per line
+	flagsConfigMapName = "kube-controller-manager-flags"
+)
+
+// Config is the parsed config.yaml of a revision. It is shared by all readers of the cache and must not be modified.
+type Config struct {
+	// Typed is the config decoded into its API type.
+	Typed *kubecontrolplanev1.KubeControllerManagerConfig
+	// Raw is the config decoded into maps, for the comparisons of arbitrary paths.
+	Raw map[string]interface{}
+	// Checksum is the checksum of the config.yaml, as exposed to the kube-controller-manager container by the
+	// configIdentity override.
+	Checksum string
+}
+
+// RevisionCache parses the revisioned configmaps of kube-controller-manager once per change instead of on every sync
+// of every controller reading them. An entry is valid as long as the resourceVersion of its configmap in the informer
+// does not change, the entries of deleted configmaps, the pruned revisions, are dropped.
+type RevisionCache struct {
+	configMapLister corev1listers.ConfigMapNamespaceLister
+
+	lock    sync.Mutex
+	entries map[string]*entry
+}
+
+type entry struct {
+	resourceVersion string
+	value           interface{}
+	err             error
+}
+
+// New returns a cache reading the configmaps of the operand namespace from the informer.
+func New(configMapInformer corev1informers.ConfigMapInformer) *RevisionCache {
+	c := newRevisionCache(configMapInformer.Lister())
+	configMapInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		DeleteFunc: c.evict,
+	})
+	return c
+}
+
+func newRevisionCache(configMapLister corev1listers.ConfigMapLister) *RevisionCache {
+	return &RevisionCache{
+		configMapLister: configMapLister.ConfigMaps(operatorclient.TargetNamespace),
+		entries:         map[string]*entry{},
+	}
+}
+
+// Config returns the kube-controller-manager config of the revision, nil when the revision has no config configmap.
+func (c *RevisionCache) Config(revision int32) (*Config, error) {
+	value, err := c.get(configConfigMapName, revision, parseConfig)
+	if value == nil || err != nil {
+		return nil, err
+	}
+	return value.(*Config), nil
+}
+
+// Flags returns the command line flags of the kube-controller-manager container of the revision, nil when the
+// revision has no flags configmap, as the revisions created before the flags were published.
+func (c *RevisionCache) Flags(revision int32) ([]string, error) {
+	value, err := c.get(flagsConfigMapName, revision, parseFlags)
+	if value == nil || err != nil {
+		return nil, err
+	}
+	return value.([]string), nil
+}
+
+func (c *RevisionCache) get(configMapName string, revision int32, parse func(*corev1.ConfigMap) (interface{}, error)) (interface{}, error) {
+	name := fmt.Sprintf("%s-%d", configMapName, revision)
+	configMap, err := c.configMapLister.Get(name)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if cached, ok := c.entries[name]; ok && cached.resourceVersion == configMap.ResourceVersion {
+		return cached.value, cached.err
+	}
+	value, err := parse(configMap)
+	if err != nil {
+		// a broken configmap stays broken until it changes
+		err = fmt.Errorf("configmap/%s: %v", name, err)
+	}
+	c.entries[name] = &entry{resourceVersion: configMap.ResourceVersion, value: value, err: err}
+	return value, err
+}
+
+func (c *RevisionCache) evict(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		return
+	}
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil || namespace != operatorclient.TargetNamespace {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.entries, name)
+}
+
+func parseConfig(configMap *corev1.ConfigMap) (interface{}, error) {
+	raw := []byte(configMap.Data["config.yaml"])
+	config := &Config{
+		Typed:    &kubecontrolplanev1.KubeControllerManagerConfig{},
+		Raw:      map[string]interface{}{},
+		Checksum: targetconfigcontroller.ConfigChecksum(configMap.Data["config.yaml"]),
+	}
+	if len(raw) == 0 {
+		return config, nil
+	}
+	if err := yaml.Unmarshal(raw, config.Typed); err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(raw, &config.Raw); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func parseFlags(configMap *corev1.ConfigMap) (interface{}, error) {
+	flags := []string{}
+	for _, line := range strings.Split(configMap.Data["flags"], "\n") {
+		if line = strings.TrimSpace(line); len(line) > 0 {
+			flags = append(flags, line)
+		}
+	}
+	return flags, nil
+}
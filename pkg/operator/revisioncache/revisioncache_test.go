@@ -0,0 +1,70 @@
+package revisioncache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	kubecontrolplanev1 "github.com/openshift/api/kubecontrolplane/v1"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+func TestConfig(t *testing.T) {
+	configMap := func(resourceVersion, config string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "config-3", ResourceVersion: resourceVersion},
+			Data:       map[string]string{"config.yaml": config},
+		}
+	}
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	c := newRevisionCache(corev1listers.NewConfigMapLister(indexer))
+
+	config, err := c.Config(3)
+	require.NoError(t, err)
+	assert.Nil(t, config, "a missing revision has no config")
+
+	require.NoError(t, indexer.Add(configMap("1", `{"extendedArguments":{"cluster-name":["cluster-1"]}}`)))
+	config, err = c.Config(3)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]kubecontrolplanev1.Arguments{"cluster-name": {"cluster-1"}}, config.Typed.ExtendedArguments)
+	assert.Equal(t, map[string]interface{}{"extendedArguments": map[string]interface{}{"cluster-name": []interface{}{"cluster-1"}}}, config.Raw)
+
+	cached, err := c.Config(3)
+	require.NoError(t, err)
+	assert.Same(t, config, cached, "an unchanged configmap is parsed once")
+
+	require.NoError(t, indexer.Update(configMap("2", `{"extendedArguments": [`)))
+	_, err = c.Config(3)
+	assert.Error(t, err, "a changed configmap is parsed again")
+
+	require.NoError(t, indexer.Update(configMap("3", `{"extendedArguments":{"cluster-name":["cluster-2"]}}`)))
+	config, err = c.Config(3)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cluster-2"}, []string(config.Typed.ExtendedArguments["cluster-name"]))
+
+	c.evict(cache.DeletedFinalStateUnknown{Key: operatorclient.TargetNamespace + "/config-3"})
+	assert.Empty(t, c.entries, "the entries of deleted configmaps are dropped")
+}
+
+func TestFlags(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	require.NoError(t, indexer.Add(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "kube-controller-manager-flags-4"},
+		Data:       map[string]string{"flags": "--controllers=*\n--controllers=-ttl\n\n-v=2"},
+	}))
+	c := newRevisionCache(corev1listers.NewConfigMapLister(indexer))
+
+	flags, err := c.Flags(4)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"--controllers=*", "--controllers=-ttl", "-v=2"}, flags)
+
+	flags, err = c.Flags(3)
+	require.NoError(t, err)
+	assert.Nil(t, flags, "revisions created before the flags were published have none")
+}
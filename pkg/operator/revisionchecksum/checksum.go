@@ -0,0 +1,116 @@
+package revisionchecksum
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+const (
+	// ChecksumKey is the key of the revision-status configmaps holding the checksum of the payload of their revision.
+	ChecksumKey = "payloadChecksum"
+
+	// ConfigMapPrefix names the configmaps of the target namespace where the installer pods report the files of a
+	// revision which do not match its payload, one per node.
+	ConfigMapPrefix = "checksum-mismatch-"
+	revisionKey     = "revision"
+	filesKey        = "files"
+)
+
+// Payload is the content of the revisioned configmaps and secrets of a revision, by the path the installer writes it
+// to relative to the resource directory of the revision.
+type Payload map[string][]byte
+
+// AddConfigMap adds the data of the revisioned configmap of the resource name, without the revision suffix.
+func (p Payload) AddConfigMap(name string, data map[string]string) {
+	for key, value := range data {
+		p[filepath.Join("configmaps", name, key)] = []byte(value)
+	}
+}
+
+// AddSecret adds the data of the revisioned secret of the resource name, without the revision suffix.
+func (p Payload) AddSecret(name string, data map[string][]byte) {
+	for key, value := range data {
+		p[filepath.Join("secrets", name, key)] = value
+	}
+}
+
+// Checksum is the sha256 of the paths and contents of the payload, independent of their order.
+func (p Payload) Checksum() string {
+	paths := make([]string, 0, len(p))
+	for path := range p {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	hash := sha256.New()
+	for _, path := range paths {
+		content := sha256.Sum256(p[path])
+		fmt.Fprintf(hash, "%s\x00%s\n", path, hex.EncodeToString(content[:]))
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// Substitute returns the payload with the placeholders the installer replaces on the node replaced.
+func (p Payload) Substitute(revision, nodeName string) Payload {
+	ret := Payload{}
+	for path, content := range p {
+		substituted := strings.ReplaceAll(string(content), "REVISION", revision)
+		substituted = strings.ReplaceAll(substituted, "NODE_NAME", nodeName)
+		substituted = strings.ReplaceAll(substituted, "NODE_ENVVAR_NAME", strings.ReplaceAll(strings.ReplaceAll(nodeName, "-", "_"), ".", "_"))
+		ret[path] = []byte(substituted)
+	}
+	return ret
+}
+
+// VerifyFiles returns the paths of the payload whose files in resourceDir are missing or differ, sorted.
+func (p Payload) VerifyFiles(resourceDir string) ([]string, error) {
+	mismatches := []string{}
+	for path, content := range p {
+		actual, err := os.ReadFile(filepath.Join(resourceDir, path))
+		if os.IsNotExist(err) {
+			mismatches = append(mismatches, filepath.Join(resourceDir, path))
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(actual, content) {
+			mismatches = append(mismatches, filepath.Join(resourceDir, path))
+		}
+	}
+	sort.Strings(mismatches)
+	return mismatches, nil
+}
+
+// ReportMismatches records the files of the revision which do not match its payload on the node for the operator to
+// report them, and removes the record once they match.
+func ReportMismatches(ctx context.Context, client corev1client.ConfigMapsGetter, recorder events.Recorder, nodeName, revision string, mismatches []string) error {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: ConfigMapPrefix + nodeName},
+	}
+	if len(mismatches) == 0 {
+		_, _, err := resourceapply.DeleteConfigMap(ctx, client, recorder, configMap)
+		return err
+	}
+	configMap.Data = map[string]string{
+		revisionKey: revision,
+		filesKey:    strings.Join(mismatches, "\n"),
+	}
+	_, _, err := resourceapply.ApplyConfigMap(ctx, client, recorder, configMap)
+	return err
+}
@@ -0,0 +1,187 @@
+package revisionchecksum
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/revisioncontroller"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/controllerpause"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operationprofile"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
+)
+
+const (
+	conditionType = "ChecksumMismatch"
+
+	revisionStatusPrefix = "revision-status-"
+)
+
+// RevisionChecksumController stores the checksum of the payload of every revision in its revision-status configmap,
+// for the installer pods to verify the files they write against it, and reports the mismatches the installer pods
+// found. A mismatch fails the installer pod, so the node is not marked at the revision and the installation is
+// retried, which replaces a partial write.
+type RevisionChecksumController struct {
+	operatorClient   v1helpers.StaticPodOperatorClient
+	statusUpdater    statusbatcher.StatusUpdater
+	configMapLister  corev1listers.ConfigMapNamespaceLister
+	secretLister     corev1listers.SecretNamespaceLister
+	configMapsGetter corev1client.ConfigMapsGetter
+
+	configMaps []revisioncontroller.RevisionResource
+	secrets    []revisioncontroller.RevisionResource
+}
+
+func NewRevisionChecksumController(
+	operatorClient v1helpers.StaticPodOperatorClient,
+	statusUpdater statusbatcher.StatusUpdater,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	configMapsGetter corev1client.ConfigMapsGetter,
+	configMaps, secrets []revisioncontroller.RevisionResource,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	informers := kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1()
+	c := &RevisionChecksumController{
+		operatorClient:   operatorClient,
+		statusUpdater:    statusUpdater,
+		configMapLister:  informers.ConfigMaps().Lister().ConfigMaps(operatorclient.TargetNamespace),
+		secretLister:     informers.Secrets().Lister().Secrets(operatorclient.TargetNamespace),
+		configMapsGetter: configMapsGetter,
+		configMaps:       configMaps,
+		secrets:          secrets,
+	}
+
+	return factory.New().WithInformers(
+		operatorClient.Informer(),
+		informers.ConfigMaps().Informer(),
+		informers.Secrets().Informer(),
+	).ResyncEvery(operationprofile.Resync(time.Minute)).WithSync(syncmetrics.Timed("RevisionChecksumController", controllerpause.Pausable("RevisionChecksumController", c.sync))).ToController("RevisionChecksumController", eventRecorder)
+}
+
+func (c *RevisionChecksumController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	_, status, _, err := c.operatorClient.GetStaticPodOperatorState()
+	if err != nil {
+		return err
+	}
+
+	// the revision controller copies the payload before it bumps the latest available revision, later revisions
+	// may still be incomplete
+	for revision := int32(1); revision <= status.LatestAvailableRevision; revision++ {
+		if err := c.storeChecksum(ctx, syncCtx.Recorder(), revision); err != nil {
+			return err
+		}
+	}
+
+	configMaps, err := c.configMapLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	reports := map[string]string{}
+	for _, configMap := range configMaps {
+		if strings.HasPrefix(configMap.Name, ConfigMapPrefix) && len(configMap.Data[filesKey]) > 0 {
+			reports[strings.TrimPrefix(configMap.Name, ConfigMapPrefix)] = fmt.Sprintf("node %s revision %s: %s",
+				strings.TrimPrefix(configMap.Name, ConfigMapPrefix), configMap.Data[revisionKey], strings.ReplaceAll(configMap.Data[filesKey], "\n", ", "))
+		}
+	}
+	// the reports of removed nodes stay behind, only the nodes the operand runs on count
+	mismatches := []string{}
+	for _, nodeStatus := range status.NodeStatuses {
+		if report, found := reports[nodeStatus.NodeName]; found {
+			mismatches = append(mismatches, report)
+		}
+	}
+	sort.Strings(mismatches)
+
+	condition := operatorv1.OperatorCondition{
+		Type:   conditionType,
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+	if len(mismatches) > 0 {
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "RevisionFilesMismatch"
+		condition.Message = fmt.Sprintf("files written by the installer do not match the revision payload, the installation is retried: %s", strings.Join(mismatches, "; "))
+	}
+	return c.statusUpdater.UpdateStatus(ctx, v1helpers.UpdateConditionFn(condition))
+}
+
+// storeChecksum adds the checksum of the payload of the revision to its revision-status configmap once. Revisions
+// which were pruned are skipped.
+func (c *RevisionChecksumController) storeChecksum(ctx context.Context, recorder events.Recorder, revision int32) error {
+	revisionStatus, err := c.configMapLister.Get(fmt.Sprintf("%s%d", revisionStatusPrefix, revision))
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if _, found := revisionStatus.Data[ChecksumKey]; found {
+		return nil
+	}
+
+	payload, err := c.payload(revision)
+	if err != nil {
+		return err
+	}
+	if payload == nil {
+		// a required resource is missing, the revision recovery controller recreates it
+		return nil
+	}
+	updated := revisionStatus.DeepCopy()
+	if updated.Data == nil {
+		updated.Data = map[string]string{}
+	}
+	updated.Data[ChecksumKey] = payload.Checksum()
+	if _, err := c.configMapsGetter.ConfigMaps(operatorclient.TargetNamespace).Update(ctx, updated, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+	recorder.Eventf("RevisionChecksumStored", "Stored the payload checksum %s of revision %d", updated.Data[ChecksumKey], revision)
+	return nil
+}
+
+// payload returns the payload of the revision, nil when a required resource is missing.
+func (c *RevisionChecksumController) payload(revision int32) (Payload, error) {
+	payload := Payload{}
+	for _, resource := range c.configMaps {
+		configMap, err := c.configMapLister.Get(fmt.Sprintf("%s-%d", resource.Name, revision))
+		if apierrors.IsNotFound(err) && resource.Optional {
+			continue
+		}
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		payload.AddConfigMap(resource.Name, configMap.Data)
+	}
+	for _, resource := range c.secrets {
+		secret, err := c.secretLister.Get(fmt.Sprintf("%s-%d", resource.Name, revision))
+		if apierrors.IsNotFound(err) && resource.Optional {
+			continue
+		}
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		payload.AddSecret(resource.Name, secret.Data)
+	}
+	return payload, nil
+}
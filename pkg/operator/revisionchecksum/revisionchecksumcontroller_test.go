@@ -0,0 +1,173 @@
+package revisionchecksum
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/revisioncontroller"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+)
+
+func TestPayload(t *testing.T) {
+	payload := Payload{}
+	payload.AddConfigMap("config", map[string]string{"config.yaml": "node: NODE_NAME\nrevision: REVISION\n"})
+	payload.AddSecret("service-account-private-key", map[string][]byte{"service-account.key": []byte("key")})
+
+	reordered := Payload{}
+	reordered.AddSecret("service-account-private-key", map[string][]byte{"service-account.key": []byte("key")})
+	reordered.AddConfigMap("config", map[string]string{"config.yaml": "node: NODE_NAME\nrevision: REVISION\n"})
+	if payload.Checksum() != reordered.Checksum() {
+		t.Errorf("expected the checksum to be independent of the order")
+	}
+	changed := Payload{}
+	changed.AddConfigMap("config", map[string]string{"config.yaml": "node: NODE_NAME\nrevision: REVISION\n"})
+	changed.AddSecret("service-account-private-key", map[string][]byte{"service-account.key": []byte("other")})
+	if payload.Checksum() == changed.Checksum() {
+		t.Errorf("expected the checksum to change with the content")
+	}
+
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "configmaps", "config"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "configmaps", "config", "config.yaml"), []byte("node: master-0\nrevision: 3\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "secrets", "service-account-private-key"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// a partial write
+	if err := os.WriteFile(filepath.Join(dir, "secrets", "service-account-private-key", "service-account.key"), []byte("k"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	mismatches, err := payload.Substitute("3", "master-0").VerifyFiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{filepath.Join(dir, "secrets", "service-account-private-key", "service-account.key")}; !reflect.DeepEqual(mismatches, expected) {
+		t.Errorf("expected %v, got %v", expected, mismatches)
+	}
+
+	mismatches, err = payload.Substitute("3", "master-1").VerifyFiles(filepath.Join(dir, "missing"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mismatches) != 2 {
+		t.Errorf("expected the missing files to mismatch, got %v", mismatches)
+	}
+}
+
+func TestSync(t *testing.T) {
+	configMaps := []revisioncontroller.RevisionResource{{Name: "config"}, {Name: "cloud-config", Optional: true}}
+	secrets := []revisioncontroller.RevisionResource{{Name: "service-account-private-key"}}
+	expectedPayload := Payload{}
+	expectedPayload.AddConfigMap("config", map[string]string{"config.yaml": "revision 2"})
+	expectedPayload.AddSecret("service-account-private-key", map[string][]byte{"service-account.key": []byte("key")})
+
+	revisionStatus := func(revision string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: revisionStatusPrefix + revision},
+			Data:       map[string]string{"revision": revision},
+		}
+	}
+	objects := []interface{}{
+		revisionStatus("2"),
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "config-2"},
+			Data:       map[string]string{"config.yaml": "revision 2"},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "service-account-private-key-2"},
+			Data:       map[string][]byte{"service-account.key": []byte("key")},
+		},
+		// revision 3 is still being copied
+		revisionStatus("3"),
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: ConfigMapPrefix + "master-1"},
+			Data:       map[string]string{revisionKey: "2", filesKey: "/etc/kubernetes/static-pod-resources/kube-controller-manager-pod-2/configmaps/config/config.yaml"},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: ConfigMapPrefix + "removed"},
+			Data:       map[string]string{revisionKey: "1", filesKey: "/etc/kubernetes/manifests/kube-controller-manager-pod.yaml"},
+		},
+	}
+	configMapIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	secretIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	kubeClient := fake.NewSimpleClientset()
+	for _, obj := range objects {
+		indexer := configMapIndexer
+		if secret, ok := obj.(*corev1.Secret); ok {
+			indexer = secretIndexer
+			if _, err := kubeClient.CoreV1().Secrets(secret.Namespace).Create(context.TODO(), secret, metav1.CreateOptions{}); err != nil {
+				t.Fatal(err)
+			}
+		} else if _, err := kubeClient.CoreV1().ConfigMaps(operatorclient.TargetNamespace).Create(context.TODO(), obj.(*corev1.ConfigMap), metav1.CreateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		if err := indexer.Add(obj); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	operatorClient := v1helpers.NewFakeStaticPodOperatorClient(
+		&operatorv1.StaticPodOperatorSpec{},
+		&operatorv1.StaticPodOperatorStatus{
+			LatestAvailableRevision: 2,
+			NodeStatuses:            []operatorv1.NodeStatus{{NodeName: "master-0", CurrentRevision: 2}, {NodeName: "master-1", CurrentRevision: 1, TargetRevision: 2}},
+		},
+		nil,
+		nil,
+	)
+	c := &RevisionChecksumController{
+		operatorClient:   operatorClient,
+		statusUpdater:    statusbatcher.NewDirectUpdater(operatorClient),
+		configMapLister:  corev1listers.NewConfigMapLister(configMapIndexer).ConfigMaps(operatorclient.TargetNamespace),
+		secretLister:     corev1listers.NewSecretLister(secretIndexer).Secrets(operatorclient.TargetNamespace),
+		configMapsGetter: kubeClient.CoreV1(),
+		configMaps:       configMaps,
+		secrets:          secrets,
+	}
+	if err := c.sync(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))); err != nil {
+		t.Fatal(err)
+	}
+
+	stored, err := kubeClient.CoreV1().ConfigMaps(operatorclient.TargetNamespace).Get(context.TODO(), revisionStatusPrefix+"2", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Data[ChecksumKey] != expectedPayload.Checksum() {
+		t.Errorf("expected the checksum %s of revision 2, got %q", expectedPayload.Checksum(), stored.Data[ChecksumKey])
+	}
+	incomplete, err := kubeClient.CoreV1().ConfigMaps(operatorclient.TargetNamespace).Get(context.TODO(), revisionStatusPrefix+"3", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checksum, found := incomplete.Data[ChecksumKey]; found {
+		t.Errorf("expected no checksum of the incomplete revision 3, got %s", checksum)
+	}
+
+	_, status, _, _ := operatorClient.GetOperatorState()
+	condition := v1helpers.FindOperatorCondition(status.Conditions, conditionType)
+	if condition == nil {
+		t.Fatalf("missing condition %s", conditionType)
+	}
+	expectedMessage := "files written by the installer do not match the revision payload, the installation is retried: node master-1 revision 2: /etc/kubernetes/static-pod-resources/kube-controller-manager-pod-2/configmaps/config/config.yaml"
+	if condition.Status != operatorv1.ConditionTrue || condition.Message != expectedMessage {
+		t.Errorf("expected %s %q, got %s %q", operatorv1.ConditionTrue, expectedMessage, condition.Status, condition.Message)
+	}
+}
@@ -0,0 +1,286 @@
+package revisionrecoverycontroller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/revisioncontroller"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operationprofile"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/teardown"
+)
+
+const (
+	conditionType = "RevisionRecoveryDegraded"
+
+	revisionStatusPrefix = "revision-status-"
+)
+
+// RevisionRecoveryController recreates the revisioned configmaps and secrets of revisions the nodes still refer
+// to when they are deleted by hand. It keeps the last state of every revisioned object it saw as a snapshot to
+// recreate it from. Objects without a snapshot cannot be recovered: for the latest revision the revision
+// controller then rolls out a new revision, older revisions are left behind by the nodes once they converge.
+type RevisionRecoveryController struct {
+	operatorClient   v1helpers.StaticPodOperatorClient
+	statusUpdater    statusbatcher.StatusUpdater
+	configMapLister  corev1listers.ConfigMapNamespaceLister
+	secretLister     corev1listers.SecretNamespaceLister
+	configMapsGetter corev1client.ConfigMapsGetter
+	secretsGetter    corev1client.SecretsGetter
+
+	configMaps []revisioncontroller.RevisionResource
+	secrets    []revisioncontroller.RevisionResource
+
+	lock               sync.Mutex
+	configMapSnapshots map[string]*corev1.ConfigMap
+	secretSnapshots    map[string]*corev1.Secret
+	// reportedLost keeps the lost objects reported already, to warn about each once
+	reportedLost sets.String
+}
+
+func NewRevisionRecoveryController(
+	operatorClient v1helpers.StaticPodOperatorClient,
+	statusUpdater statusbatcher.StatusUpdater,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	kubeClient corev1client.CoreV1Interface,
+	configMaps, secrets []revisioncontroller.RevisionResource,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	informers := kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1()
+	c := &RevisionRecoveryController{
+		operatorClient:     operatorClient,
+		statusUpdater:      statusUpdater,
+		configMapLister:    informers.ConfigMaps().Lister().ConfigMaps(operatorclient.TargetNamespace),
+		secretLister:       informers.Secrets().Lister().Secrets(operatorclient.TargetNamespace),
+		configMapsGetter:   kubeClient,
+		secretsGetter:      kubeClient,
+		configMapSnapshots: map[string]*corev1.ConfigMap{},
+		secretSnapshots:    map[string]*corev1.Secret{},
+		configMaps:         configMaps,
+		secrets:            secrets,
+		reportedLost:       sets.NewString(),
+	}
+
+	// deletions are not handled, the snapshot outlives the object
+	informers.ConfigMaps().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.snapshotConfigMap,
+		UpdateFunc: func(_, obj interface{}) { c.snapshotConfigMap(obj) },
+	})
+	informers.Secrets().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.snapshotSecret,
+		UpdateFunc: func(_, obj interface{}) { c.snapshotSecret(obj) },
+	})
+
+	return factory.New().WithInformers(
+		operatorClient.Informer(),
+		informers.ConfigMaps().Informer(),
+		informers.Secrets().Informer(),
+	).ResyncEvery(operationprofile.Resync(time.Minute)).WithSync(syncmetrics.Timed("RevisionRecoveryController", teardown.Quiesceable("RevisionRecoveryController", c.sync))).ToController("RevisionRecoveryController", eventRecorder)
+}
+
+func (c *RevisionRecoveryController) snapshotConfigMap(obj interface{}) {
+	configMap, ok := obj.(*corev1.ConfigMap)
+	if !ok || configMap.Namespace != operatorclient.TargetNamespace {
+		return
+	}
+	if _, ok := revisionOf(configMap.Name, c.configMaps); !ok {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.configMapSnapshots[configMap.Name] = configMap.DeepCopy()
+}
+
+func (c *RevisionRecoveryController) snapshotSecret(obj interface{}) {
+	secret, ok := obj.(*corev1.Secret)
+	if !ok || secret.Namespace != operatorclient.TargetNamespace {
+		return
+	}
+	if _, ok := revisionOf(secret.Name, c.secrets); !ok {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.secretSnapshots[secret.Name] = secret.DeepCopy()
+}
+
+func (c *RevisionRecoveryController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	_, status, _, err := c.operatorClient.GetStaticPodOperatorState()
+	if err != nil {
+		return err
+	}
+	referenced := referencedRevisions(status)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.pruneSnapshots(referenced)
+
+	failures := []string{}
+	for _, revision := range sortedRevisions(referenced) {
+		for _, resource := range c.configMaps {
+			name := fmt.Sprintf("%s-%d", resource.Name, revision)
+			if _, err := c.configMapLister.Get(name); !apierrors.IsNotFound(err) {
+				continue
+			}
+			snapshot, ok := c.configMapSnapshots[name]
+			if !ok && resource.Optional {
+				// optional resources are missing from revisions created while they did not exist
+				continue
+			}
+			if !ok {
+				c.reportLost(syncCtx.Recorder(), "configmap", name, revision, status.LatestAvailableRevision)
+				continue
+			}
+			recreated := snapshot.DeepCopy()
+			c.resetMeta(&recreated.ObjectMeta, revision)
+			if _, err := c.configMapsGetter.ConfigMaps(operatorclient.TargetNamespace).Create(ctx, recreated, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+				failures = append(failures, fmt.Sprintf("configmap/%s: %v", name, err))
+				continue
+			}
+			syncCtx.Recorder().Warningf("RevisionedResourceRecreated", "Recreated the deleted configmap/%s of revision %d from its snapshot", name, revision)
+		}
+		for _, resource := range c.secrets {
+			name := fmt.Sprintf("%s-%d", resource.Name, revision)
+			if _, err := c.secretLister.Get(name); !apierrors.IsNotFound(err) {
+				continue
+			}
+			snapshot, ok := c.secretSnapshots[name]
+			if !ok && resource.Optional {
+				// optional resources are missing from revisions created while they did not exist
+				continue
+			}
+			if !ok {
+				c.reportLost(syncCtx.Recorder(), "secret", name, revision, status.LatestAvailableRevision)
+				continue
+			}
+			recreated := snapshot.DeepCopy()
+			c.resetMeta(&recreated.ObjectMeta, revision)
+			if _, err := c.secretsGetter.Secrets(operatorclient.TargetNamespace).Create(ctx, recreated, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+				failures = append(failures, fmt.Sprintf("secret/%s: %v", name, err))
+				continue
+			}
+			syncCtx.Recorder().Warningf("RevisionedResourceRecreated", "Recreated the deleted secret/%s of revision %d from its snapshot", name, revision)
+		}
+	}
+
+	condition := operatorv1.OperatorCondition{
+		Type:   conditionType,
+		Status: operatorv1.ConditionFalse,
+	}
+	if len(failures) > 0 {
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "RecreateFailed"
+		condition.Message = strings.Join(failures, "\n")
+	}
+	return c.statusUpdater.UpdateStatus(ctx, v1helpers.UpdateConditionFn(condition))
+}
+
+// reportLost warns once about an object which was deleted before it was ever snapshotted.
+func (c *RevisionRecoveryController) reportLost(recorder events.Recorder, kind, name string, revision, latestRevision int32) {
+	key := kind + "/" + name
+	if c.reportedLost.Has(key) {
+		return
+	}
+	c.reportedLost.Insert(key)
+	if revision == latestRevision {
+		recorder.Warningf("RevisionedResourceLost", "The %s of the latest revision %d is missing and cannot be recreated, a new revision will be rolled out", key, revision)
+		return
+	}
+	recorder.Warningf("RevisionedResourceLost", "The %s of revision %d is missing and cannot be recreated, the nodes still on it need to move to revision %d", key, revision, latestRevision)
+}
+
+// resetMeta prepares the snapshot metadata for a create. The owner references to the revision status are kept
+// only while it exists, the garbage collector would delete the recreated object right away otherwise.
+func (c *RevisionRecoveryController) resetMeta(meta *metav1.ObjectMeta, revision int32) {
+	meta.ResourceVersion = ""
+	meta.UID = ""
+	meta.CreationTimestamp = metav1.Time{}
+	meta.ManagedFields = nil
+	meta.Generation = 0
+
+	revisionStatus, err := c.configMapLister.Get(fmt.Sprintf("%s%d", revisionStatusPrefix, revision))
+	ownerReferences := []metav1.OwnerReference{}
+	for _, ownerReference := range meta.OwnerReferences {
+		if err == nil && ownerReference.UID == revisionStatus.UID {
+			ownerReferences = append(ownerReferences, ownerReference)
+		}
+	}
+	meta.OwnerReferences = ownerReferences
+}
+
+// pruneSnapshots drops the snapshots of the revisions older than every referenced one, they are pruned by design.
+// The resources of a new revision are created before LatestAvailableRevision points to it, their snapshots are kept.
+func (c *RevisionRecoveryController) pruneSnapshots(referenced sets.Int32) {
+	if referenced.Len() == 0 {
+		return
+	}
+	oldest := sortedRevisions(referenced)[0]
+	for name := range c.configMapSnapshots {
+		if revision, _ := revisionOf(name, c.configMaps); revision < oldest {
+			delete(c.configMapSnapshots, name)
+		}
+	}
+	for name := range c.secretSnapshots {
+		if revision, _ := revisionOf(name, c.secrets); revision < oldest {
+			delete(c.secretSnapshots, name)
+		}
+	}
+}
+
+// referencedRevisions are the revisions the nodes run or are moving to, and the latest one.
+func referencedRevisions(status *operatorv1.StaticPodOperatorStatus) sets.Int32 {
+	ret := sets.NewInt32()
+	if status.LatestAvailableRevision > 0 {
+		ret.Insert(status.LatestAvailableRevision)
+	}
+	for _, nodeStatus := range status.NodeStatuses {
+		if nodeStatus.CurrentRevision > 0 {
+			ret.Insert(nodeStatus.CurrentRevision)
+		}
+		if nodeStatus.TargetRevision > 0 {
+			ret.Insert(nodeStatus.TargetRevision)
+		}
+	}
+	return ret
+}
+
+func sortedRevisions(revisions sets.Int32) []int32 {
+	ret := revisions.UnsortedList()
+	sort.Slice(ret, func(i, j int) bool { return ret[i] < ret[j] })
+	return ret
+}
+
+// revisionOf returns the revision of a revisioned object named <resource>-<revision>.
+func revisionOf(name string, resources []revisioncontroller.RevisionResource) (int32, bool) {
+	for _, resource := range resources {
+		if !strings.HasPrefix(name, resource.Name+"-") {
+			continue
+		}
+		revision, err := strconv.ParseInt(strings.TrimPrefix(name, resource.Name+"-"), 10, 32)
+		if err != nil || revision <= 0 {
+			continue
+		}
+		return int32(revision), true
+	}
+	return 0, false
+}
@@ -0,0 +1,117 @@
+package revisionrecoverycontroller
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/revisioncontroller"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+)
+
+func TestSync(t *testing.T) {
+	revisionStatus := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "revision-status-3", UID: "status-3"}}
+	owned := func(name string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{
+			Namespace:       operatorclient.TargetNamespace,
+			Name:            name,
+			ResourceVersion: "42",
+			UID:             "deleted",
+			OwnerReferences: []metav1.OwnerReference{{APIVersion: "v1", Kind: "ConfigMap", Name: revisionStatus.Name, UID: revisionStatus.UID}},
+		}
+	}
+	config3 := &corev1.ConfigMap{ObjectMeta: owned("config-3"), Data: map[string]string{"config.yaml": "{}"}}
+	token3 := &corev1.Secret{ObjectMeta: owned("localhost-recovery-client-token-3"), Data: map[string][]byte{"token": []byte("secret")}}
+	config2 := &corev1.ConfigMap{ObjectMeta: owned("config-2")}
+
+	configMapIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	if err := configMapIndexer.Add(revisionStatus); err != nil {
+		t.Fatal(err)
+	}
+	secretIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	kubeClient := fake.NewSimpleClientset()
+	operatorClient := v1helpers.NewFakeStaticPodOperatorClient(
+		&operatorv1.StaticPodOperatorSpec{},
+		&operatorv1.StaticPodOperatorStatus{
+			LatestAvailableRevision: 3,
+			NodeStatuses: []operatorv1.NodeStatus{
+				{NodeName: "master-0", CurrentRevision: 2, TargetRevision: 3},
+				{NodeName: "master-1", CurrentRevision: 3},
+			},
+		},
+		nil,
+		nil,
+	)
+	c := &RevisionRecoveryController{
+		operatorClient:     operatorClient,
+		statusUpdater:      statusbatcher.NewDirectUpdater(operatorClient),
+		configMapLister:    corev1listers.NewConfigMapLister(configMapIndexer).ConfigMaps(operatorclient.TargetNamespace),
+		secretLister:       corev1listers.NewSecretLister(secretIndexer).Secrets(operatorclient.TargetNamespace),
+		configMapsGetter:   kubeClient.CoreV1(),
+		secretsGetter:      kubeClient.CoreV1(),
+		configMaps:         []revisioncontroller.RevisionResource{{Name: "config"}, {Name: "cloud-config", Optional: true}},
+		secrets:            []revisioncontroller.RevisionResource{{Name: "localhost-recovery-client-token"}},
+		configMapSnapshots: map[string]*corev1.ConfigMap{},
+		secretSnapshots:    map[string]*corev1.Secret{},
+		reportedLost:       sets.NewString(),
+	}
+	// config-2 and the secret of revision 2 were deleted before they were seen, revision 1 is no longer referenced,
+	// revision 4 is being created
+	c.snapshotConfigMap(config3)
+	c.snapshotConfigMap(&corev1.ConfigMap{ObjectMeta: owned("config-1")})
+	c.snapshotConfigMap(&corev1.ConfigMap{ObjectMeta: owned("config-4")})
+	c.snapshotSecret(token3)
+	if err := configMapIndexer.Add(config2); err != nil {
+		t.Fatal(err)
+	}
+
+	recorder := events.NewInMemoryRecorder("test")
+	for i := 0; i < 2; i++ {
+		if err := c.sync(context.TODO(), factory.NewSyncContext("test", recorder)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	recreated, err := kubeClient.CoreV1().ConfigMaps(operatorclient.TargetNamespace).Get(context.TODO(), "config-3", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recreated.Data["config.yaml"] != "{}" || len(recreated.UID) > 0 || len(recreated.OwnerReferences) != 1 {
+		t.Errorf("expected config-3 to be recreated from its snapshot with its owner, got %#v", recreated)
+	}
+	if _, err := kubeClient.CoreV1().Secrets(operatorclient.TargetNamespace).Get(context.TODO(), "localhost-recovery-client-token-3", metav1.GetOptions{}); err != nil {
+		t.Errorf("expected the secret of revision 3 to be recreated: %v", err)
+	}
+	if _, ok := c.configMapSnapshots["config-1"]; ok {
+		t.Errorf("expected the snapshot of the unreferenced revision 1 to be pruned")
+	}
+	if _, ok := c.configMapSnapshots["config-4"]; !ok {
+		t.Errorf("expected the snapshot of the new revision 4 to be kept")
+	}
+
+	lost := 0
+	for _, event := range recorder.Events() {
+		if event.Reason == "RevisionedResourceLost" {
+			lost++
+		}
+	}
+	if lost != 1 {
+		t.Errorf("expected the lost secret of revision 2 to be reported once, got %d reports in %v", lost, recorder.Events())
+	}
+	_, status, _, _ := operatorClient.GetStaticPodOperatorState()
+	if condition := v1helpers.FindOperatorCondition(status.Conditions, conditionType); condition == nil || condition.Status != operatorv1.ConditionFalse {
+		t.Errorf("expected %s to be False, got %v", conditionType, condition)
+	}
+}
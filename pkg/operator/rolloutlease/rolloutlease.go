@@ -0,0 +1,325 @@
+package rolloutlease
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	coordinationv1client "k8s.io/client-go/kubernetes/typed/coordination/v1"
+	coordinationv1listers "k8s.io/client-go/listers/coordination/v1"
+	"k8s.io/component-base/metrics"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatormetrics"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
+)
+
+const (
+	// LeaseName is the lease the control plane operators hold while they roll out their static pods. It lives in
+	// openshift-config-managed, the namespace shared by every operator.
+	LeaseName = "control-plane-rollout"
+	// HolderIdentity identifies the kube-controller-manager operator as the holder of the lease.
+	HolderIdentity = operatorclient.OperatorNamespace
+
+	conditionType = "RolloutQueued"
+
+	// leaseDuration is how long the lease stays held without a renewal, an operator which dies during its rollout
+	// blocks the others no longer than that
+	leaseDuration = 60 * time.Second
+	renewInterval = 15 * time.Second
+)
+
+var (
+	queuedGauge = metrics.NewGauge(
+		&metrics.GaugeOpts{
+			Name:           "kcm_operator_rollout_queued",
+			Help:           "Set to 1 while the rollout of kube-controller-manager waits for another control plane operator to finish its rollout.",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+	waitSeconds = metrics.NewHistogram(
+		&metrics.HistogramOpts{
+			Name:           "kcm_operator_rollout_lease_wait_seconds",
+			Help:           "Time the rollout of kube-controller-manager waited for another control plane operator to release the control-plane-rollout lease.",
+			Buckets:        []float64{15, 30, 60, 120, 300, 600, 1200, 2400},
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+)
+
+func init() {
+	operatormetrics.MustRegister(queuedGauge, waitSeconds)
+}
+
+var (
+	lock   sync.RWMutex
+	queued bool
+	// held is set once the operator acquired or renewed the lease, the installation of a pending revision waits for it
+	held bool
+)
+
+// Queued tells whether the rollout of kube-controller-manager waits for another control plane operator.
+func Queued() bool {
+	lock.RLock()
+	defer lock.RUnlock()
+	return queued
+}
+
+// setQueued returns whether the value changed.
+func setQueued(value bool) bool {
+	lock.Lock()
+	defer lock.Unlock()
+	if queued == value {
+		return false
+	}
+	queued = value
+	return true
+}
+
+func setHeld(value bool) {
+	lock.Lock()
+	defer lock.Unlock()
+	held = value
+}
+
+// gated tells whether the static pod controllers wait for the lease: a node does not run the latest revision yet and
+// the operator did not acquire the lease for its rollout.
+func gated(status *operatorv1.StaticPodOperatorStatus) bool {
+	_, rollingOut := rolloutInProgress(status)
+	lock.RLock()
+	defer lock.RUnlock()
+	return rollingOut && !held
+}
+
+// NewQueueingClient returns the operator client for the static pod controllers of library-go. It reports the
+// operator Unmanaged while a revision waits to be rolled out and the operator does not hold the lease, which holds
+// back new revisions and the installation of the next node until the lease is acquired. The decision is taken on the
+// status it returns, so that no node is installed between a new revision and the acquisition of the lease.
+func NewQueueingClient(operatorClient v1helpers.StaticPodOperatorClient) v1helpers.StaticPodOperatorClient {
+	return &queueingClient{StaticPodOperatorClient: operatorClient}
+}
+
+type queueingClient struct {
+	v1helpers.StaticPodOperatorClient
+}
+
+func (c *queueingClient) GetOperatorState() (*operatorv1.OperatorSpec, *operatorv1.OperatorStatus, string, error) {
+	spec, status, resourceVersion, err := c.StaticPodOperatorClient.GetOperatorState()
+	if err != nil {
+		return spec, status, resourceVersion, err
+	}
+	// the operator status carries no node statuses, the static pod status decides
+	_, staticPodStatus, _, err := c.StaticPodOperatorClient.GetStaticPodOperatorState()
+	if err != nil || !gated(staticPodStatus) {
+		return spec, status, resourceVersion, err
+	}
+	// the spec may come from the lister, it must not be modified
+	spec = spec.DeepCopy()
+	spec.ManagementState = operatorv1.Unmanaged
+	return spec, status, resourceVersion, nil
+}
+
+func (c *queueingClient) GetStaticPodOperatorState() (*operatorv1.StaticPodOperatorSpec, *operatorv1.StaticPodOperatorStatus, string, error) {
+	spec, status, resourceVersion, err := c.StaticPodOperatorClient.GetStaticPodOperatorState()
+	if err != nil || !gated(status) {
+		return spec, status, resourceVersion, err
+	}
+	spec = spec.DeepCopy()
+	spec.ManagementState = operatorv1.Unmanaged
+	return spec, status, resourceVersion, nil
+}
+
+func (c *queueingClient) GetStaticPodOperatorStateWithQuorum(ctx context.Context) (*operatorv1.StaticPodOperatorSpec, *operatorv1.StaticPodOperatorStatus, string, error) {
+	spec, status, resourceVersion, err := c.StaticPodOperatorClient.GetStaticPodOperatorStateWithQuorum(ctx)
+	if err != nil || !gated(status) {
+		return spec, status, resourceVersion, err
+	}
+	spec = spec.DeepCopy()
+	spec.ManagementState = operatorv1.Unmanaged
+	return spec, status, resourceVersion, nil
+}
+
+type rolloutLeaseController struct {
+	operatorClient v1helpers.StaticPodOperatorClient
+	statusUpdater  statusbatcher.StatusUpdater
+	leaseLister    coordinationv1listers.LeaseNamespaceLister
+	leasesGetter   coordinationv1client.LeasesGetter
+
+	// queuedSince is when the rollout started to wait, zero while it does not
+	queuedSince time.Time
+	now         func() time.Time
+}
+
+// NewRolloutLeaseController serializes the rollouts of the control plane operators through the control-plane-rollout
+// lease, so that no two control plane components restart at the same time. The operator acquires the lease before the
+// first node installs a new revision of kube-controller-manager, holds it during the rollout and releases it once
+// all nodes run the latest revision. Until the lease is acquired the static pod controllers see the operator
+// Unmanaged, while another operator holds it the RolloutQueued condition tells whom the rollout waits for. A lease
+// left unrenewed by its holder is taken over once it expired.
+func NewRolloutLeaseController(
+	operatorClient v1helpers.StaticPodOperatorClient,
+	statusUpdater statusbatcher.StatusUpdater,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	leasesGetter coordinationv1client.LeasesGetter,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	leaseInformer := kubeInformersForNamespaces.InformersFor(operatorclient.GlobalMachineSpecifiedConfigNamespace).Coordination().V1().Leases()
+	c := &rolloutLeaseController{
+		operatorClient: operatorClient,
+		statusUpdater:  statusUpdater,
+		leaseLister:    leaseInformer.Lister().Leases(operatorclient.GlobalMachineSpecifiedConfigNamespace),
+		leasesGetter:   leasesGetter,
+		now:            time.Now,
+	}
+	// the lease is renewed on every resync, the interval is not stretched by the operation profile
+	return factory.New().WithInformers(
+		operatorClient.Informer(),
+		leaseInformer.Informer(),
+	).ResyncEvery(renewInterval).WithSync(syncmetrics.Timed("RolloutLeaseController", c.sync)).ToController("RolloutLeaseController", eventRecorder)
+}
+
+func (c *rolloutLeaseController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	_, status, _, err := c.operatorClient.GetStaticPodOperatorState()
+	if err != nil {
+		return err
+	}
+	lease, err := c.leaseLister.Get(LeaseName)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	now := c.now()
+
+	condition := operatorv1.OperatorCondition{
+		Type:   conditionType,
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+	revision, rollingOut := rolloutInProgress(status)
+	holder := activeHolder(lease, now)
+	if holder != HolderIdentity {
+		setHeld(false)
+	}
+	if rollingOut && len(holder) > 0 && holder != HolderIdentity {
+		if setQueued(true) {
+			c.queuedSince = now
+			queuedGauge.Set(1)
+			syncCtx.Recorder().Eventf("RolloutQueued", "The rollout of kube-controller-manager waits for %s to release the %s lease", holder, LeaseName)
+		}
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "LeaseHeldByOtherOperator"
+		condition.Message = fmt.Sprintf("%s holds the %s/%s lease, the rollout of kube-controller-manager is queued since %s",
+			holder, operatorclient.GlobalMachineSpecifiedConfigNamespace, LeaseName, c.queuedSince.UTC().Format(time.RFC3339))
+		return c.statusUpdater.UpdateStatus(ctx, v1helpers.UpdateConditionFn(condition))
+	}
+	if setQueued(false) {
+		queuedGauge.Set(0)
+		waitSeconds.Observe(now.Sub(c.queuedSince).Seconds())
+		syncCtx.Recorder().Eventf("RolloutDequeued", "The rollout of kube-controller-manager resumes after waiting %s for the %s lease", now.Sub(c.queuedSince).Round(time.Second), LeaseName)
+		c.queuedSince = time.Time{}
+	}
+
+	if rollingOut {
+		acquired, err := c.acquire(ctx, lease, now)
+		if err != nil {
+			return err
+		}
+		if !acquired {
+			// another operator was first, the lease informer brings its lease and the next sync queues the rollout
+			return nil
+		}
+		setHeld(true)
+		condition.Message = fmt.Sprintf("holding the %s/%s lease while revision %d rolls out", operatorclient.GlobalMachineSpecifiedConfigNamespace, LeaseName, revision)
+	} else if holder == HolderIdentity {
+		setHeld(false)
+		if err := c.release(ctx, lease); err != nil {
+			return err
+		}
+	}
+	return c.statusUpdater.UpdateStatus(ctx, v1helpers.UpdateConditionFn(condition))
+}
+
+// rolloutInProgress tells whether a node does not run the latest revision yet.
+func rolloutInProgress(status *operatorv1.StaticPodOperatorStatus) (int32, bool) {
+	for _, nodeStatus := range status.NodeStatuses {
+		if nodeStatus.CurrentRevision != status.LatestAvailableRevision {
+			return status.LatestAvailableRevision, true
+		}
+	}
+	return 0, false
+}
+
+// activeHolder returns the holder of the lease, nothing when the lease is missing, released or expired. A lease
+// without a renew time or a duration never expires otherwise, it is treated as expired.
+func activeHolder(lease *coordinationv1.Lease, now time.Time) string {
+	if lease == nil || lease.Spec.HolderIdentity == nil || len(*lease.Spec.HolderIdentity) == 0 {
+		return ""
+	}
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return ""
+	}
+	if now.After(lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second)) {
+		return ""
+	}
+	return *lease.Spec.HolderIdentity
+}
+
+// acquire takes the free lease or renews the lease the operator holds, it returns whether the operator holds the
+// lease. A conflict means another operator took it first.
+func (c *rolloutLeaseController) acquire(ctx context.Context, lease *coordinationv1.Lease, now time.Time) (bool, error) {
+	renewTime := metav1.NewMicroTime(now)
+	if lease == nil {
+		_, err := c.leasesGetter.Leases(operatorclient.GlobalMachineSpecifiedConfigNamespace).Create(ctx, &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.GlobalMachineSpecifiedConfigNamespace, Name: LeaseName},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       pointer.String(HolderIdentity),
+				LeaseDurationSeconds: pointer.Int32(int32(leaseDuration.Seconds())),
+				AcquireTime:          &renewTime,
+				RenewTime:            &renewTime,
+			},
+		}, metav1.CreateOptions{})
+		if apierrors.IsAlreadyExists(err) {
+			return false, nil
+		}
+		return err == nil, err
+	}
+
+	lease = lease.DeepCopy()
+	if activeHolder(lease, now) != HolderIdentity {
+		lease.Spec.HolderIdentity = pointer.String(HolderIdentity)
+		lease.Spec.AcquireTime = &renewTime
+		lease.Spec.LeaseTransitions = pointer.Int32(pointer.Int32Deref(lease.Spec.LeaseTransitions, 0) + 1)
+		klog.Infof("Acquiring the %s lease for the rollout of kube-controller-manager", LeaseName)
+	}
+	lease.Spec.LeaseDurationSeconds = pointer.Int32(int32(leaseDuration.Seconds()))
+	lease.Spec.RenewTime = &renewTime
+	_, err := c.leasesGetter.Leases(operatorclient.GlobalMachineSpecifiedConfigNamespace).Update(ctx, lease, metav1.UpdateOptions{})
+	if apierrors.IsConflict(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// release hands the lease over to the next operator once the rollout is done.
+func (c *rolloutLeaseController) release(ctx context.Context, lease *coordinationv1.Lease) error {
+	lease = lease.DeepCopy()
+	lease.Spec.HolderIdentity = nil
+	lease.Spec.AcquireTime = nil
+	lease.Spec.RenewTime = nil
+	_, err := c.leasesGetter.Leases(operatorclient.GlobalMachineSpecifiedConfigNamespace).Update(ctx, lease, metav1.UpdateOptions{})
+	if apierrors.IsConflict(err) {
+		return nil
+	}
+	return err
+}
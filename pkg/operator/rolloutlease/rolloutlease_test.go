@@ -0,0 +1,248 @@
+package rolloutlease
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	coordinationv1listers "k8s.io/client-go/listers/coordination/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/pointer"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+)
+
+func TestSync(t *testing.T) {
+	defer setQueued(false)
+	defer setHeld(false)
+
+	now := time.Date(2026, 10, 15, 10, 0, 0, 0, time.UTC)
+	newLease := func(holder string, renewed time.Time) *coordinationv1.Lease {
+		renewTime := metav1.NewMicroTime(renewed)
+		return &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.GlobalMachineSpecifiedConfigNamespace, Name: LeaseName},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       pointer.String(holder),
+				LeaseDurationSeconds: pointer.Int32(60),
+				RenewTime:            &renewTime,
+			},
+		}
+	}
+	rollingOut := []operatorv1.NodeStatus{{NodeName: "master-0", CurrentRevision: 4}, {NodeName: "master-1", CurrentRevision: 3}}
+	rolledOut := []operatorv1.NodeStatus{{NodeName: "master-0", CurrentRevision: 4}, {NodeName: "master-1", CurrentRevision: 4}}
+
+	testCases := []struct {
+		name            string
+		lease           *coordinationv1.Lease
+		nodeStatuses    []operatorv1.NodeStatus
+		expectedHolder  string
+		expectedHeld    bool
+		expectedQueued  bool
+		expectedStatus  operatorv1.ConditionStatus
+		expectedReason  string
+		expectedMessage string
+	}{
+		{
+			name:           "no rollout, no lease",
+			nodeStatuses:   rolledOut,
+			expectedStatus: operatorv1.ConditionFalse,
+			expectedReason: "AsExpected",
+		},
+		{
+			name:            "rollout acquires the missing lease",
+			nodeStatuses:    rollingOut,
+			expectedHolder:  HolderIdentity,
+			expectedHeld:    true,
+			expectedStatus:  operatorv1.ConditionFalse,
+			expectedReason:  "AsExpected",
+			expectedMessage: "holding the openshift-config-managed/control-plane-rollout lease while revision 4 rolls out",
+		},
+		{
+			name:            "rollout acquires the released lease",
+			lease:           newLease("", now.Add(-time.Minute)),
+			nodeStatuses:    rollingOut,
+			expectedHolder:  HolderIdentity,
+			expectedHeld:    true,
+			expectedStatus:  operatorv1.ConditionFalse,
+			expectedReason:  "AsExpected",
+			expectedMessage: "holding the openshift-config-managed/control-plane-rollout lease while revision 4 rolls out",
+		},
+		{
+			name:            "rollout queued behind another operator",
+			lease:           newLease("openshift-kube-apiserver-operator", now.Add(-10*time.Second)),
+			nodeStatuses:    rollingOut,
+			expectedHolder:  "openshift-kube-apiserver-operator",
+			expectedQueued:  true,
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedReason:  "LeaseHeldByOtherOperator",
+			expectedMessage: "openshift-kube-apiserver-operator holds the openshift-config-managed/control-plane-rollout lease, the rollout of kube-controller-manager is queued since 2026-10-15T10:00:00Z",
+		},
+		{
+			name:            "expired lease of another operator is taken over",
+			lease:           newLease("openshift-kube-apiserver-operator", now.Add(-2*time.Minute)),
+			nodeStatuses:    rollingOut,
+			expectedHolder:  HolderIdentity,
+			expectedHeld:    true,
+			expectedStatus:  operatorv1.ConditionFalse,
+			expectedReason:  "AsExpected",
+			expectedMessage: "holding the openshift-config-managed/control-plane-rollout lease while revision 4 rolls out",
+		},
+		{
+			name: "lease of another operator without a renew time is taken over",
+			lease: &coordinationv1.Lease{
+				ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.GlobalMachineSpecifiedConfigNamespace, Name: LeaseName},
+				Spec:       coordinationv1.LeaseSpec{HolderIdentity: pointer.String("openshift-kube-apiserver-operator")},
+			},
+			nodeStatuses:    rollingOut,
+			expectedHolder:  HolderIdentity,
+			expectedHeld:    true,
+			expectedStatus:  operatorv1.ConditionFalse,
+			expectedReason:  "AsExpected",
+			expectedMessage: "holding the openshift-config-managed/control-plane-rollout lease while revision 4 rolls out",
+		},
+		{
+			name:           "no rollout, lease of another operator",
+			lease:          newLease("openshift-kube-apiserver-operator", now.Add(-10*time.Second)),
+			nodeStatuses:   rolledOut,
+			expectedHolder: "openshift-kube-apiserver-operator",
+			expectedStatus: operatorv1.ConditionFalse,
+			expectedReason: "AsExpected",
+		},
+		{
+			name:           "lease released after the rollout",
+			lease:          newLease(HolderIdentity, now.Add(-10*time.Second)),
+			nodeStatuses:   rolledOut,
+			expectedStatus: operatorv1.ConditionFalse,
+			expectedReason: "AsExpected",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer setQueued(false)
+			defer setHeld(false)
+
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			kubeClient := fake.NewSimpleClientset()
+			if tc.lease != nil {
+				if err := indexer.Add(tc.lease); err != nil {
+					t.Fatal(err)
+				}
+				kubeClient = fake.NewSimpleClientset(tc.lease)
+			}
+			operatorClient := v1helpers.NewFakeStaticPodOperatorClient(
+				&operatorv1.StaticPodOperatorSpec{OperatorSpec: operatorv1.OperatorSpec{ManagementState: operatorv1.Managed}},
+				&operatorv1.StaticPodOperatorStatus{LatestAvailableRevision: 4, NodeStatuses: tc.nodeStatuses},
+				nil, nil,
+			)
+			c := &rolloutLeaseController{
+				operatorClient: operatorClient,
+				statusUpdater:  statusbatcher.NewDirectUpdater(operatorClient),
+				leaseLister:    coordinationv1listers.NewLeaseLister(indexer).Leases(operatorclient.GlobalMachineSpecifiedConfigNamespace),
+				leasesGetter:   kubeClient.CoordinationV1(),
+				now:            func() time.Time { return now },
+			}
+			recorder := events.NewInMemoryRecorder("test")
+			if err := c.sync(context.TODO(), factory.NewSyncContext("test", recorder)); err != nil {
+				t.Fatal(err)
+			}
+
+			if Queued() != tc.expectedQueued {
+				t.Errorf("expected queued %v, got %v", tc.expectedQueued, Queued())
+			}
+			if held != tc.expectedHeld {
+				t.Errorf("expected held %v, got %v", tc.expectedHeld, held)
+			}
+			if tc.expectedQueued && len(recorder.Events()) != 1 {
+				t.Errorf("expected a RolloutQueued event, got %v", recorder.Events())
+			}
+
+			lease, err := kubeClient.CoordinationV1().Leases(operatorclient.GlobalMachineSpecifiedConfigNamespace).Get(context.TODO(), LeaseName, metav1.GetOptions{})
+			switch {
+			case err != nil && (tc.lease != nil || len(tc.expectedHolder) > 0):
+				t.Fatal(err)
+			case err == nil && pointer.StringDeref(lease.Spec.HolderIdentity, "") != tc.expectedHolder:
+				t.Errorf("expected holder %q, got %q", tc.expectedHolder, pointer.StringDeref(lease.Spec.HolderIdentity, ""))
+			}
+
+			_, status, _, err := operatorClient.GetOperatorState()
+			if err != nil {
+				t.Fatal(err)
+			}
+			condition := v1helpers.FindOperatorCondition(status.Conditions, conditionType)
+			if condition == nil {
+				t.Fatalf("missing %s condition", conditionType)
+			}
+			if condition.Status != tc.expectedStatus || condition.Reason != tc.expectedReason || condition.Message != tc.expectedMessage {
+				t.Errorf("unexpected condition %#v", condition)
+			}
+		})
+	}
+}
+
+func TestQueueingClient(t *testing.T) {
+	defer setHeld(false)
+
+	operatorClient := v1helpers.NewFakeStaticPodOperatorClient(
+		&operatorv1.StaticPodOperatorSpec{OperatorSpec: operatorv1.OperatorSpec{ManagementState: operatorv1.Managed}},
+		&operatorv1.StaticPodOperatorStatus{
+			LatestAvailableRevision: 4,
+			NodeStatuses:            []operatorv1.NodeStatus{{NodeName: "master-0", CurrentRevision: 4}},
+		},
+		nil, nil,
+	)
+	client := NewQueueingClient(operatorClient)
+	expect := func(expected operatorv1.ManagementState) {
+		t.Helper()
+		spec, _, _, err := client.GetStaticPodOperatorState()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if spec.ManagementState != expected {
+			t.Errorf("expected %s, got %s", expected, spec.ManagementState)
+		}
+		operatorSpec, _, _, err := client.GetOperatorState()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if operatorSpec.ManagementState != expected {
+			t.Errorf("expected %s, got %s", expected, operatorSpec.ManagementState)
+		}
+	}
+
+	// no revision waits to be rolled out
+	expect(operatorv1.Managed)
+
+	// the installer waits for the lease before the first node restarts
+	_, status, resourceVersion, err := operatorClient.GetStaticPodOperatorState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	status = status.DeepCopy()
+	status.LatestAvailableRevision = 5
+	if _, err := operatorClient.UpdateStaticPodOperatorStatus(context.TODO(), resourceVersion, status); err != nil {
+		t.Fatal(err)
+	}
+	expect(operatorv1.Unmanaged)
+
+	setHeld(true)
+	expect(operatorv1.Managed)
+
+	setHeld(false)
+	expect(operatorv1.Unmanaged)
+	originalSpec, _, _, err := operatorClient.GetStaticPodOperatorState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if originalSpec.ManagementState != operatorv1.Managed {
+		t.Errorf("the wrapped spec must not be modified, got %s", originalSpec.ManagementState)
+	}
+}
@@ -0,0 +1,194 @@
+package satokenkeycontroller
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/util/keyutil"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operationprofile"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
+)
+
+const (
+	conditionType = "SATokenPublicKeyDegraded"
+
+	privateKeySecretName = "service-account-private-key"
+	privateKeyKey        = "service-account.key"
+	// publicKeysConfigMapName holds the public keys the kube-apiserver validates service account tokens with. The
+	// SATokenSignerController publishes it in openshift-config-managed, the kube-apiserver-operator copies it to the
+	// kube-apiserver namespace and into its revisions.
+	publicKeysConfigMapName = "sa-token-signing-certs"
+	kubeAPIServerNamespace  = "openshift-kube-apiserver"
+)
+
+// SATokenPublicKeyController verifies that the kube-apiserver trusts the keys the token controller of
+// kube-controller-manager signs service account tokens with: the key of every revision the nodes run and the key
+// of the next revision. A key missing from the public keys of the kube-apiserver fails the tokens of every new
+// pod, the condition reports it before the revision with that key is rolled out.
+type SATokenPublicKeyController struct {
+	operatorClient   v1helpers.StaticPodOperatorClient
+	statusUpdater    statusbatcher.StatusUpdater
+	secretLister     corev1listers.SecretNamespaceLister
+	configMapLister  corev1listers.ConfigMapNamespaceLister
+	configMapsGetter corev1client.ConfigMapsGetter
+}
+
+func NewSATokenPublicKeyController(
+	operatorClient v1helpers.StaticPodOperatorClient,
+	statusUpdater statusbatcher.StatusUpdater,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	kubeClient corev1client.CoreV1Interface,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &SATokenPublicKeyController{
+		operatorClient:  operatorClient,
+		statusUpdater:   statusUpdater,
+		secretLister:    kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().Secrets().Lister().Secrets(operatorclient.TargetNamespace),
+		configMapLister: kubeInformersForNamespaces.InformersFor(operatorclient.GlobalMachineSpecifiedConfigNamespace).Core().V1().ConfigMaps().Lister().ConfigMaps(operatorclient.GlobalMachineSpecifiedConfigNamespace),
+		// the kube-apiserver namespace is not watched, its copy is read on resync
+		configMapsGetter: kubeClient,
+	}
+
+	return factory.New().WithInformers(
+		operatorClient.Informer(),
+		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().Secrets().Informer(),
+		kubeInformersForNamespaces.InformersFor(operatorclient.GlobalMachineSpecifiedConfigNamespace).Core().V1().ConfigMaps().Informer(),
+	).ResyncEvery(operationprofile.Resync(time.Minute)).WithSync(syncmetrics.Timed("SATokenPublicKeyController", c.sync)).ToController("SATokenPublicKeyController", eventRecorder)
+}
+
+func (c *SATokenPublicKeyController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	condition := operatorv1.OperatorCondition{
+		Type:   conditionType,
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+	signingKeys, err := c.signingKeys()
+	if err != nil {
+		return err
+	}
+
+	published, err := c.configMapLister.Get(publicKeysConfigMapName)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	distributed, err := c.configMapsGetter.ConfigMaps(kubeAPIServerNamespace).Get(ctx, publicKeysConfigMapName, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	messages := []string{}
+	for _, trusted := range []struct {
+		configMap *corev1.ConfigMap
+		namespace string
+	}{
+		{configMap: published, namespace: operatorclient.GlobalMachineSpecifiedConfigNamespace},
+		{configMap: distributed, namespace: kubeAPIServerNamespace},
+	} {
+		trustedKeys := sets.NewString()
+		if trusted.configMap != nil {
+			trustedKeys = publicKeys(trusted.configMap)
+		}
+		missing := []string{}
+		for _, signingKey := range signingKeys {
+			if !trustedKeys.Has(signingKey.publicKey) {
+				missing = append(missing, signingKey.secretName)
+			}
+		}
+		if len(missing) > 0 {
+			messages = append(messages, fmt.Sprintf("configmap %s/%s lacks the public key of secret %s", trusted.namespace, publicKeysConfigMapName, strings.Join(missing, ", ")))
+		}
+	}
+	if len(messages) > 0 {
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "PublicKeyNotTrusted"
+		condition.Message = "the kube-apiserver rejects the service account tokens signed by kube-controller-manager with keys it does not trust, the pods started with them cannot reach the API: " + strings.Join(messages, "; ")
+	}
+	return c.statusUpdater.UpdateStatus(ctx, v1helpers.UpdateConditionFn(condition))
+}
+
+type signingKey struct {
+	secretName string
+	// publicKey is the DER encoding of the public key
+	publicKey string
+}
+
+// signingKeys returns the public keys of the private keys of the revisions the nodes run and of the next revision.
+// The secrets which do not exist yet or carry no key are skipped, there is no key to trust yet.
+func (c *SATokenPublicKeyController) signingKeys() ([]signingKey, error) {
+	_, status, _, err := c.operatorClient.GetStaticPodOperatorState()
+	if err != nil {
+		return nil, err
+	}
+	secretNames := sets.NewString(privateKeySecretName)
+	for _, nodeStatus := range status.NodeStatuses {
+		if nodeStatus.CurrentRevision > 0 {
+			secretNames.Insert(fmt.Sprintf("%s-%d", privateKeySecretName, nodeStatus.CurrentRevision))
+		}
+	}
+
+	ret := []signingKey{}
+	for _, secretName := range secretNames.List() {
+		secret, err := c.secretLister.Get(secretName)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(secret.Data[privateKeyKey]) == 0 {
+			continue
+		}
+		privateKey, err := keyutil.ParsePrivateKeyPEM(secret.Data[privateKeyKey])
+		if err != nil {
+			return nil, fmt.Errorf("secret %s/%s: %v", secret.Namespace, secret.Name, err)
+		}
+		signer, ok := privateKey.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("secret %s/%s: unsupported private key %T", secret.Namespace, secret.Name, privateKey)
+		}
+		publicKey, err := x509.MarshalPKIXPublicKey(signer.Public())
+		if err != nil {
+			return nil, fmt.Errorf("secret %s/%s: %v", secret.Namespace, secret.Name, err)
+		}
+		ret = append(ret, signingKey{secretName: secretName, publicKey: string(publicKey)})
+	}
+	return ret, nil
+}
+
+// publicKeys returns the DER encoding of the public keys in the configmap. The keys which do not parse are skipped,
+// the kube-apiserver does not trust them either.
+func publicKeys(configMap *corev1.ConfigMap) sets.String {
+	ret := sets.NewString()
+	for _, value := range configMap.Data {
+		keys, err := keyutil.ParsePublicKeysPEM([]byte(value))
+		if err != nil {
+			continue
+		}
+		for _, key := range keys {
+			der, err := x509.MarshalPKIXPublicKey(key)
+			if err != nil {
+				continue
+			}
+			ret.Insert(string(der))
+		}
+	}
+	return ret
+}
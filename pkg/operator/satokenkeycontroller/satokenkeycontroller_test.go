@@ -0,0 +1,107 @@
+package satokenkeycontroller
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+)
+
+func newKeyPair(t *testing.T) (privateKeyPEM, publicKeyPEM string) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKey, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKey}))
+}
+
+func TestSync(t *testing.T) {
+	currentKey, currentPublicKey := newKeyPair(t)
+	nextKey, nextPublicKey := newKeyPair(t)
+
+	secrets := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for name, key := range map[string]string{privateKeySecretName + "-4": currentKey, privateKeySecretName: nextKey} {
+		if err := secrets.Add(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: name},
+			Data:       map[string][]byte{privateKeyKey: []byte(key)},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	configMaps := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	if err := configMaps.Add(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.GlobalMachineSpecifiedConfigNamespace, Name: publicKeysConfigMapName},
+		Data:       map[string]string{"service-account-001.pub": currentPublicKey, "service-account-002.pub": nextPublicKey},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	// the kube-apiserver-operator did not copy the next key yet
+	distributed := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: kubeAPIServerNamespace, Name: publicKeysConfigMapName},
+		Data:       map[string]string{"service-account-001.pub": currentPublicKey},
+	}
+	kubeClient := fake.NewSimpleClientset(distributed)
+
+	operatorClient := v1helpers.NewFakeStaticPodOperatorClient(
+		&operatorv1.StaticPodOperatorSpec{},
+		&operatorv1.StaticPodOperatorStatus{NodeStatuses: []operatorv1.NodeStatus{{NodeName: "master-0", CurrentRevision: 4}}},
+		nil,
+		nil,
+	)
+	c := &SATokenPublicKeyController{
+		operatorClient:   operatorClient,
+		statusUpdater:    statusbatcher.NewDirectUpdater(operatorClient),
+		secretLister:     corev1listers.NewSecretLister(secrets).Secrets(operatorclient.TargetNamespace),
+		configMapLister:  corev1listers.NewConfigMapLister(configMaps).ConfigMaps(operatorclient.GlobalMachineSpecifiedConfigNamespace),
+		configMapsGetter: kubeClient.CoreV1(),
+	}
+	condition := func() *operatorv1.OperatorCondition {
+		if err := c.sync(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))); err != nil {
+			t.Fatal(err)
+		}
+		_, status, _, err := operatorClient.GetOperatorState()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v1helpers.FindOperatorCondition(status.Conditions, conditionType)
+	}
+
+	actual := condition()
+	if actual == nil || actual.Status != operatorv1.ConditionTrue {
+		t.Fatalf("expected %s True, got %v", conditionType, actual)
+	}
+	expected := "configmap openshift-kube-apiserver/sa-token-signing-certs lacks the public key of secret service-account-private-key"
+	if !strings.HasSuffix(actual.Message, expected) {
+		t.Errorf("expected the message to end with %q, got %q", expected, actual.Message)
+	}
+
+	distributed.Data["service-account-002.pub"] = nextPublicKey
+	if _, err := kubeClient.CoreV1().ConfigMaps(kubeAPIServerNamespace).Update(context.TODO(), distributed, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if actual := condition(); actual.Status != operatorv1.ConditionFalse {
+		t.Errorf("expected %s False once the key is distributed, got %v", conditionType, actual)
+	}
+}
@@ -0,0 +1,133 @@
+package scopedinformers
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+// NamespaceScope narrows what the informers of a single namespace list and watch.
+type NamespaceScope struct {
+	Namespace string
+	// TweakListOptions is applied to every list and watch issued by the informers of the namespace.
+	TweakListOptions func(*metav1.ListOptions)
+}
+
+var _ v1helpers.KubeInformersForNamespaces = &kubeInformersForNamespaces{}
+
+// NewKubeInformersForNamespaces wraps v1helpers.NewKubeInformersForNamespaces, the informers of the scoped namespaces
+// only cache the objects matching their list options. The other namespaces are served by library-go unchanged.
+func NewKubeInformersForNamespaces(kubeClient kubernetes.Interface, scopes []NamespaceScope, namespaces ...string) v1helpers.KubeInformersForNamespaces {
+	ret := &kubeInformersForNamespaces{scoped: map[string]informers.SharedInformerFactory{}}
+	for _, scope := range scopes {
+		ret.scoped[scope.Namespace] = informers.NewSharedInformerFactoryWithOptions(kubeClient, 10*time.Minute,
+			informers.WithNamespace(scope.Namespace),
+			informers.WithTweakListOptions(scope.TweakListOptions),
+		)
+	}
+	unscoped := []string{}
+	for _, namespace := range namespaces {
+		if _, ok := ret.scoped[namespace]; !ok {
+			unscoped = append(unscoped, namespace)
+		}
+	}
+	ret.KubeInformersForNamespaces = v1helpers.NewKubeInformersForNamespaces(kubeClient, unscoped...)
+
+	return ret
+}
+
+// ExcludeLabels returns list options that drop every object carrying one of the given labels.
+func ExcludeLabels(labelKeys ...string) func(*metav1.ListOptions) {
+	selector := labels.NewSelector()
+	for _, key := range labelKeys {
+		requirement, err := labels.NewRequirement(key, "!", nil)
+		if err != nil {
+			panic(err)
+		}
+		selector = selector.Add(*requirement)
+	}
+	return func(options *metav1.ListOptions) {
+		options.LabelSelector = selector.String()
+	}
+}
+
+// kubeInformersForNamespaces delegates to the library-go informers, except for the namespaces of the scoped factories.
+// Cross namespace lists go to the library-go informers of "", which is never scoped.
+type kubeInformersForNamespaces struct {
+	v1helpers.KubeInformersForNamespaces
+
+	scoped map[string]informers.SharedInformerFactory
+}
+
+func (i *kubeInformersForNamespaces) Start(stopCh <-chan struct{}) {
+	i.KubeInformersForNamespaces.Start(stopCh)
+	for _, informer := range i.scoped {
+		informer.Start(stopCh)
+	}
+}
+
+func (i *kubeInformersForNamespaces) Namespaces() sets.String {
+	return i.KubeInformersForNamespaces.Namespaces().Union(sets.StringKeySet(i.scoped))
+}
+
+func (i *kubeInformersForNamespaces) InformersFor(namespace string) informers.SharedInformerFactory {
+	if informer, ok := i.scoped[namespace]; ok {
+		return informer
+	}
+	return i.KubeInformersForNamespaces.InformersFor(namespace)
+}
+
+func (i *kubeInformersForNamespaces) ConfigMapLister() corev1listers.ConfigMapLister {
+	return configMapLister{ConfigMapLister: i.KubeInformersForNamespaces.ConfigMapLister(), scoped: i.scoped}
+}
+
+func (i *kubeInformersForNamespaces) SecretLister() corev1listers.SecretLister {
+	return secretLister{SecretLister: i.KubeInformersForNamespaces.SecretLister(), scoped: i.scoped}
+}
+
+func (i *kubeInformersForNamespaces) PodLister() corev1listers.PodLister {
+	return podLister{PodLister: i.KubeInformersForNamespaces.PodLister(), scoped: i.scoped}
+}
+
+type configMapLister struct {
+	corev1listers.ConfigMapLister
+	scoped map[string]informers.SharedInformerFactory
+}
+
+func (l configMapLister) ConfigMaps(namespace string) corev1listers.ConfigMapNamespaceLister {
+	if informer, ok := l.scoped[namespace]; ok {
+		return informer.Core().V1().ConfigMaps().Lister().ConfigMaps(namespace)
+	}
+	return l.ConfigMapLister.ConfigMaps(namespace)
+}
+
+type secretLister struct {
+	corev1listers.SecretLister
+	scoped map[string]informers.SharedInformerFactory
+}
+
+func (l secretLister) Secrets(namespace string) corev1listers.SecretNamespaceLister {
+	if informer, ok := l.scoped[namespace]; ok {
+		return informer.Core().V1().Secrets().Lister().Secrets(namespace)
+	}
+	return l.SecretLister.Secrets(namespace)
+}
+
+type podLister struct {
+	corev1listers.PodLister
+	scoped map[string]informers.SharedInformerFactory
+}
+
+func (l podLister) Pods(namespace string) corev1listers.PodNamespaceLister {
+	if informer, ok := l.scoped[namespace]; ok {
+		return informer.Core().V1().Pods().Lister().Pods(namespace)
+	}
+	return l.PodLister.Pods(namespace)
+}
@@ -0,0 +1,75 @@
+package scopedinformers
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestExcludeLabels(t *testing.T) {
+	options := &metav1.ListOptions{}
+	ExcludeLabels("console.openshift.io/dashboard", "console.openshift.io/odc-dashboard")(options)
+
+	selector, err := labels.Parse(options.LabelSelector)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		labels   labels.Set
+		expected bool
+	}{
+		{labels: nil, expected: true},
+		{labels: labels.Set{"app": "kube-controller-manager"}, expected: true},
+		{labels: labels.Set{"console.openshift.io/dashboard": "true"}, expected: false},
+		{labels: labels.Set{"console.openshift.io/odc-dashboard": "true"}, expected: false},
+	} {
+		if actual := selector.Matches(tc.labels); actual != tc.expected {
+			t.Errorf("expected %v for %v with %q, got %v", tc.expected, tc.labels, options.LabelSelector, actual)
+		}
+	}
+}
+
+func TestNewKubeInformersForNamespaces(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset(
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "scoped", Name: "kept"}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "scoped", Name: "dropped", Labels: map[string]string{"drop": "true"}}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "plain", Name: "labelled", Labels: map[string]string{"drop": "true"}}},
+	)
+	kubeInformersForNamespaces := NewKubeInformersForNamespaces(kubeClient,
+		[]NamespaceScope{{Namespace: "scoped", TweakListOptions: ExcludeLabels("drop")}},
+		"scoped", "plain",
+	)
+	if expected := sets.NewString("scoped", "plain"); !kubeInformersForNamespaces.Namespaces().Equal(expected) {
+		t.Errorf("expected namespaces %v, got %v", expected.List(), kubeInformersForNamespaces.Namespaces().List())
+	}
+	// register the informers before starting them
+	for _, namespace := range []string{"scoped", "plain"} {
+		kubeInformersForNamespaces.InformersFor(namespace).Core().V1().ConfigMaps().Informer()
+	}
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	kubeInformersForNamespaces.Start(stopCh)
+	for _, namespace := range []string{"scoped", "plain"} {
+		kubeInformersForNamespaces.InformersFor(namespace).WaitForCacheSync(stopCh)
+	}
+
+	for namespace, expected := range map[string][]string{"scoped": {"kept"}, "plain": {"labelled"}} {
+		configMaps, err := kubeInformersForNamespaces.ConfigMapLister().ConfigMaps(namespace).List(labels.Everything())
+		if err != nil {
+			t.Fatal(err)
+		}
+		names := []string{}
+		for _, configMap := range configMaps {
+			names = append(names, configMap.Name)
+		}
+		if !reflect.DeepEqual(names, expected) {
+			t.Errorf("expected %v in %q, got %v", expected, namespace, names)
+		}
+	}
+}
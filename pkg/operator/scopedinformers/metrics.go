@@ -0,0 +1,70 @@
+package scopedinformers
+
+import (
+	"k8s.io/apimachinery/pkg/labels"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/component-base/metrics"
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatormetrics"
+)
+
+var cachedObjectsDesc = metrics.NewDesc(
+	"kcm_operator_informer_cached_objects",
+	"Number of configmaps and secrets held in the operator informer caches, by namespace.",
+	[]string{"namespace", "resource"},
+	nil,
+	metrics.ALPHA,
+	"",
+)
+
+// RegisterCacheMetrics exposes the size of the configmap and secret caches of the given namespaces. The caches of
+// scoped namespaces are counted after their list options applied, the objects filtered out are not counted.
+// It must be called before the informers are started, so that listing does not register new informers.
+func RegisterCacheMetrics(kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces, namespaces ...string) {
+	collector := &cacheCollector{}
+	for _, namespace := range namespaces {
+		informers := kubeInformersForNamespaces.InformersFor(namespace)
+		collector.namespaces = append(collector.namespaces, cachedNamespace{
+			namespace:       namespace,
+			configMapLister: informers.Core().V1().ConfigMaps().Lister(),
+			secretLister:    informers.Core().V1().Secrets().Lister(),
+		})
+	}
+	operatormetrics.CustomMustRegister(collector)
+}
+
+type cachedNamespace struct {
+	namespace       string
+	configMapLister corev1listers.ConfigMapLister
+	secretLister    corev1listers.SecretLister
+}
+
+type cacheCollector struct {
+	metrics.BaseStableCollector
+
+	namespaces []cachedNamespace
+}
+
+func (c *cacheCollector) DescribeWithStability(ch chan<- *metrics.Desc) {
+	ch <- cachedObjectsDesc
+}
+
+func (c *cacheCollector) CollectWithStability(ch chan<- metrics.Metric) {
+	for _, ns := range c.namespaces {
+		configMaps, err := ns.configMapLister.ConfigMaps(ns.namespace).List(labels.Everything())
+		if err != nil {
+			klog.Warningf("failed to list cached configmaps in %q: %v", ns.namespace, err)
+		} else {
+			ch <- metrics.NewLazyConstMetric(cachedObjectsDesc, metrics.GaugeValue, float64(len(configMaps)), ns.namespace, "configmaps")
+		}
+		secrets, err := ns.secretLister.Secrets(ns.namespace).List(labels.Everything())
+		if err != nil {
+			klog.Warningf("failed to list cached secrets in %q: %v", ns.namespace, err)
+		} else {
+			ch <- metrics.NewLazyConstMetric(cachedObjectsDesc, metrics.GaugeValue, float64(len(secrets)), ns.namespace, "secrets")
+		}
+	}
+}
@@ -2,7 +2,9 @@ package operator
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"strconv"
 	"time"
 
 	configv1 "github.com/openshift/api/config/v1"
@@ -10,13 +12,69 @@ import (
 	configv1client "github.com/openshift/client-go/config/clientset/versioned"
 	configinformers "github.com/openshift/client-go/config/informers/externalversions"
 	"github.com/openshift/cluster-kube-controller-manager-operator/bindata"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/argumentmatrixcontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/argumentrequestcontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/backpressure"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/certreloadcontroller"
 	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/certrotationcontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/clusteridentity"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/conditionaggregation"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/conditionjanitor"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configbaseline"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation/breakglass"
 	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation/configobservercontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation/network"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation/observerhealth"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation/ownership"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/contentionprofilecontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/contenttypecontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/controllergraph"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/controllerpause"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/controlplanecapacitycontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/decisionlog"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/degradedinfo"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/degradedseverity"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/dependencycontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/errorbudgetcontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/eventcategory"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/externalmetrics"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/faultinjection"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/guardhealthcontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/healthsnapshot"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/hostpathpreflightcontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/leadertakeovercontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/loganomalycontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/manifestconflictcontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/namespacemetadatacontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/namespacerecoverycontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/nodefilescontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/nodemaintenancecontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operandversion"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operationprofile"
 	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorleasecontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/pendingconfigcontroller"
 	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/resourcesynccontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/resumerepaircontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/revisioncache"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/revisionchecksum"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/revisionrecoverycontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/rolloutlease"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/satokenkeycontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/scopedinformers"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statestore"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statuspage"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
 	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/targetconfigcontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/teardown"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/upgradeablecontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/webhookinterference"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/zonetopologycontroller"
 	"github.com/openshift/library-go/pkg/controller/controllercmd"
+	"github.com/openshift/library-go/pkg/controller/factory"
 	"github.com/openshift/library-go/pkg/operator/certrotation"
+	"github.com/openshift/library-go/pkg/operator/events"
 	"github.com/openshift/library-go/pkg/operator/genericoperatorclient"
 	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
 	"github.com/openshift/library-go/pkg/operator/staleconditions"
@@ -30,22 +88,79 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
 )
 
 func RunOperator(ctx context.Context, cc *controllercmd.ControllerContext) error {
+	// OPERATION_PROFILE=Fleet cuts the writes of the operator for managed fleets
+	profile, err := operationprofile.Parse(os.Getenv("OPERATION_PROFILE"))
+	if err != nil {
+		return fmt.Errorf("invalid OPERATION_PROFILE %q: %v", os.Getenv("OPERATION_PROFILE"), err)
+	}
+	operationprofile.Activate(profile)
+	cc.EventRecorder = operationprofile.FilterEvents(cc.EventRecorder)
+
+	// syncs are traced to the OTLP collector at TRACING_ENDPOINT, TRACING_SAMPLING_RATE_PER_MILLION of them by default all
+	if endpoint := os.Getenv("TRACING_ENDPOINT"); len(endpoint) > 0 {
+		samplingRatePerMillion := 1000000
+		if rate := os.Getenv("TRACING_SAMPLING_RATE_PER_MILLION"); len(rate) > 0 {
+			samplingRatePerMillion, err = strconv.Atoi(rate)
+			if err != nil {
+				return fmt.Errorf("invalid TRACING_SAMPLING_RATE_PER_MILLION %q: %v", rate, err)
+			}
+		}
+		syncmetrics.EnableTracing(ctx, endpoint, samplingRatePerMillion, cc.KubeConfig, cc.ProtoKubeConfig)
+	}
 	// This kube client use protobuf, do not use it for CR
-	kubeClient, err := kubernetes.NewForConfig(cc.ProtoKubeConfig)
+	var kubeClient kubernetes.Interface
+	kubeClient, err = kubernetes.NewForConfig(cc.ProtoKubeConfig)
+	if err != nil {
+		return err
+	}
+	operatorClient, dynamicInformers, err := genericoperatorclient.NewStaticPodOperatorClient(cc.KubeConfig, operatorv1.GroupVersion.WithResource("kubecontrollermanagers"))
 	if err != nil {
 		return err
 	}
+	// the writes to the target namespace are compared with what persists, to spot the mutating webhooks changing them
+	webhookInterferenceDetector := webhookinterference.NewDetector()
+	kubeClient = webhookInterferenceDetector.WrapKubeClient(kubeClient)
+	// builds with the faultinjection tag break the operator on request of the operator resource, for e2e suites
+	kubeClient = faultinjection.WrapKubeClient(kubeClient, operatorClient)
+	// the events of the rollouts, certificate rotations, config observations and degradations are rate limited per
+	// category and annotated with it, the events.Recorder of the controller command cannot set annotations
+	controllerRef, err := events.GetControllerReferenceForCurrentPod(ctx, kubeClient, operatorclient.OperatorNamespace, nil)
+	if err != nil {
+		klog.Warningf("unable to get owner reference (falling back to namespace): %v", err)
+	}
+	categorizedEvents := eventcategory.NewRecorder(kubeClient.CoreV1().Events(operatorclient.OperatorNamespace), controllerRef, cc.EventRecorder.ComponentName(), eventcategory.DefaultLimits)
+	rolloutRecorder := operationprofile.FilterEvents(categorizedEvents.For(eventcategory.Rollout))
+	certRotationRecorder := operationprofile.FilterEvents(categorizedEvents.For(eventcategory.CertRotation))
+	observationRecorder := operationprofile.FilterEvents(categorizedEvents.For(eventcategory.Observation))
+	degradationRecorder := operationprofile.FilterEvents(categorizedEvents.For(eventcategory.Degradation))
 	configClient, err := configv1client.NewForConfig(cc.KubeConfig)
 	if err != nil {
 		return err
 	}
 
 	configInformers := configinformers.NewSharedInformerFactory(configClient, 10*time.Minute)
-	kubeInformersForNamespaces := v1helpers.NewKubeInformersForNamespaces(kubeClient,
+	// the metrics and events carry the identity of the cluster, read upfront so that the first ones have it too
+	if err := clusteridentity.Load(ctx, configClient.ConfigV1()); err != nil {
+		klog.Warningf("unable to read the cluster identity, the metrics and events lack it until the Infrastructure config is synced: %v", err)
+	}
+	clusterIdentityController := clusteridentity.NewController(configInformers, cc.EventRecorder)
+	kubeInformersForNamespaces := scopedinformers.NewKubeInformersForNamespaces(kubeClient,
+		[]scopedinformers.NamespaceScope{
+			// openshift-config-managed is shared by every operator, the console dashboards there are large and never read by
+			// us. They are the only objects filtered: the revision, installer and prune controllers of library-go list the
+			// target and operator namespaces in full, so those informers stay unscoped.
+			{
+				Namespace:        operatorclient.GlobalMachineSpecifiedConfigNamespace,
+				TweakListOptions: scopedinformers.ExcludeLabels("console.openshift.io/dashboard", "console.openshift.io/odc-dashboard"),
+			},
+		},
 		"",
 		operatorclient.GlobalUserSpecifiedConfigNamespace,
 		operatorclient.GlobalMachineSpecifiedConfigNamespace,
@@ -54,11 +169,21 @@ func RunOperator(ctx context.Context, cc *controllercmd.ControllerContext) error
 		"kube-system",
 	)
 
-	operatorClient, dynamicInformers, err := genericoperatorclient.NewStaticPodOperatorClient(cc.KubeConfig, operatorv1.GroupVersion.WithResource("kubecontrollermanagers"))
-	if err != nil {
-		return err
-	}
 	operatorLister := dynamicInformers.ForResource(operatorv1.GroupVersion.WithResource("kubecontrollermanagers")).Lister()
+	// CONDITION_DWELL_TIME holds back flips of the Degraded conditions of our own controllers until they persisted that long
+	conditionDwellTime := time.Duration(0)
+	if dwell := os.Getenv("CONDITION_DWELL_TIME"); len(dwell) > 0 {
+		conditionDwellTime, err = time.ParseDuration(dwell)
+		if err != nil {
+			return fmt.Errorf("invalid CONDITION_DWELL_TIME %q: %v", dwell, err)
+		}
+	}
+	// the conditions of our own controllers are written in batches to avoid racing each other on the operator resource
+	statusBatcher := statusbatcher.NewStatusBatcher(operatorClient, operationprofile.Current().StatusBatchInterval).WithDampening(conditionDwellTime).WithEventRecorder(degradationRecorder)
+	// conditions which cannot be written for a while are kept in STATUS_SPOOL_DIR until the kube-apiserver is back
+	if spoolDir := os.Getenv("STATUS_SPOOL_DIR"); len(spoolDir) > 0 {
+		statusBatcher.WithSpool(statusbatcher.NewSpool(spoolDir), 2*time.Minute)
+	}
 
 	resourceSyncController, err := resourcesynccontroller.NewResourceSyncController(
 		operatorClient,
@@ -70,13 +195,121 @@ func RunOperator(ctx context.Context, cc *controllercmd.ControllerContext) error
 	if err != nil {
 		return err
 	}
+	disabledSyncsController := resourcesynccontroller.NewDisabledSyncsController(operatorClient, statusBatcher, cc.EventRecorder)
+	orphanedMirrorsController := resourcesynccontroller.NewOrphanedMirrorsController(operatorClient, kubeInformersForNamespaces, kubeClient.CoreV1(), kubeClient.CoreV1(), cc.EventRecorder)
+	resourceSyncDriftController := resourcesynccontroller.NewDriftController(operatorClient, statusBatcher, kubeInformersForNamespaces, kubeClient.CoreV1(), cc.EventRecorder)
+	dependencyController := dependencycontroller.NewDependencyController(
+		operatorClient,
+		statusBatcher,
+		kubeInformersForNamespaces,
+		resourcesynccontroller.ConfigMapSyncs,
+		resourcesynccontroller.SecretSyncs,
+		cc.EventRecorder,
+	)
+	// the decision log is opt-in, it keeps the last DECISION_LOG_SIZE decisions in memory
+	decisionLogSize := 0
+	if size := os.Getenv("DECISION_LOG_SIZE"); len(size) > 0 {
+		decisionLogSize, err = strconv.Atoi(size)
+		if err != nil {
+			return fmt.Errorf("invalid DECISION_LOG_SIZE %q: %v", size, err)
+		}
+	}
+	decisionLog := decisionlog.New(decisionLogSize)
+	if decisionLog != nil && cc.Server != nil && operationprofile.Current().OptionalEndpoints {
+		cc.Server.Handler.NonGoRestfulMux.Handle("/debug/decisions", decisionLog)
+	}
+	// the server authenticates and authorizes the requests to the debug endpoints
+	if cc.Server != nil && operationprofile.Current().OptionalEndpoints {
+		cc.Server.Handler.NonGoRestfulMux.Handle("/debug/controllers", syncmetrics.ControllersHandler())
+	}
+
+	// scrapers outside of the cluster network authenticate with client certificates on a separate listener
+	externalMetrics, err := externalmetrics.OptionsFromEnv()
+	if err != nil {
+		return err
+	}
+	if externalMetrics != nil && operationprofile.Current().OptionalEndpoints {
+		go func() {
+			if err := externalmetrics.Serve(ctx, externalMetrics); err != nil {
+				klog.Errorf("external metrics listener failed: %v", err)
+			}
+		}()
+	}
+	// STATUS_PAGE_LISTEN_ADDRESS serves the status page on a loopback address for debugging without oc, the pod is not
+	// on the host network and the page is read from the node within the network namespace of the pod
+	statusPageAddress, err := statuspage.ListenAddressFromEnv()
+	if err != nil {
+		return err
+	}
+	if len(statusPageAddress) > 0 && operationprofile.Current().OptionalEndpoints {
+		go func() {
+			if err := statuspage.Serve(ctx, statusPageAddress, statuspage.NewHandler(operatorClient, decisionLog)); err != nil {
+				klog.Errorf("status page listener failed: %v", err)
+			}
+		}()
+	}
+
+	// config observation runs on its own loop, CONFIG_OBSERVATION_RESYNC_INTERVAL shortens it without
+	// affecting the resync of the status controllers
+	configObservationResync := time.Minute
+	if interval := os.Getenv("CONFIG_OBSERVATION_RESYNC_INTERVAL"); len(interval) > 0 {
+		configObservationResync, err = time.ParseDuration(interval)
+		if err != nil {
+			return fmt.Errorf("invalid CONFIG_OBSERVATION_RESYNC_INTERVAL %q: %v", interval, err)
+		}
+		if configObservationResync < time.Second {
+			return fmt.Errorf("invalid CONFIG_OBSERVATION_RESYNC_INTERVAL %q: must be at least 1s", interval)
+		}
+	}
+
+	observerTracker := observerhealth.NewTracker()
+	// the break-glass annotation freezes the observed config during incidents
+	configFreezer := breakglass.NewFreezer(operatorClient)
+	// the ownership of the observed config paths also tells the sources of the kube-controller-manager arguments
+	configOwnership := ownership.New()
+	// other operators request kube-controller-manager flags with KubeControllerManagerArgumentRequests in their
+	// namespaces
+	dynamicClient, err := dynamic.NewForConfig(cc.KubeConfig)
+	if err != nil {
+		return err
+	}
+	argumentRequestInformers := dynamicinformer.NewDynamicSharedInformerFactory(dynamicClient, 12*time.Hour)
+	argumentRequestInformer := argumentRequestInformers.ForResource(argumentrequestcontroller.ArgumentRequestGVR)
 	configObserver := configobservercontroller.NewConfigObserver(
 		operatorClient,
 		configInformers,
 		kubeInformersForNamespaces,
+		argumentRequestInformer,
 		resourceSyncController,
-		cc.EventRecorder,
+		observerTracker,
+		configFreezer,
+		configOwnership,
+		decisionLog,
+		configObservationResync,
+		observationRecorder,
 	)
+	staleObservationController := observerhealth.NewStaleObservationController(observerTracker, 10*time.Minute, statusBatcher, observationRecorder)
+	breakGlassController := breakglass.NewBreakGlassController(configFreezer, statusBatcher, observationRecorder)
+	// single misbehaving controllers are paused through an annotation, without setting the whole operator Unmanaged
+	pauseController := controllerpause.NewPauseController(operatorClient, statusBatcher, cc.EventRecorder)
+	// no new revisions and no certificate rotation while the cluster is torn down
+	teardownController := teardown.NewTeardownController(statusBatcher, kubeInformersForNamespaces, cc.EventRecorder)
+	// the control plane operators serialize their rollouts through a shared lease, CONTROL_PLANE_ROLLOUT_LEASE enables it
+	rolloutOperatorClient := teardown.NewQuiescingClient(operatorClient)
+	var rolloutLeaseController factory.Controller
+	if enabled := os.Getenv("CONTROL_PLANE_ROLLOUT_LEASE"); len(enabled) > 0 {
+		useLease, err := strconv.ParseBool(enabled)
+		if err != nil {
+			return fmt.Errorf("invalid CONTROL_PLANE_ROLLOUT_LEASE %q: %v", enabled, err)
+		}
+		if useLease {
+			rolloutOperatorClient = rolloutlease.NewQueueingClient(rolloutOperatorClient)
+			rolloutLeaseController = rolloutlease.NewRolloutLeaseController(operatorClient, statusBatcher, kubeInformersForNamespaces, kubeClient.CoordinationV1(), rolloutRecorder)
+		}
+	}
+	argumentRequestController := argumentrequestcontroller.NewArgumentRequestController(operatorClient, argumentRequestInformer, dynamicClient, statusBatcher, observationRecorder)
+	nodeCIDRTopologyController := network.NewNodeCIDRTopologyController(operatorClient, statusBatcher, configInformers, observationRecorder)
+	networkConfigMismatchController := network.NewNetworkConfigMismatchController(operatorClient, statusBatcher, configInformers, observationRecorder)
 
 	staticResourceController := staticresourcecontroller.NewStaticResourceController(
 		"KubeControllerManagerStaticResources",
@@ -93,6 +326,9 @@ func RunOperator(ctx context.Context, cc *controllercmd.ControllerContext) error
 			"assets/kube-controller-manager/namespace-security-allocation-controller-clusterrolebinding.yaml",
 			"assets/kube-controller-manager/namespace-openshift-infra.yaml",
 			"assets/kube-controller-manager/svc.yaml",
+			"assets/kube-controller-manager/cluster-policy-controller-svc.yaml",
+			"assets/kube-controller-manager/cluster-policy-controller-clusterrole.yaml",
+			"assets/kube-controller-manager/cluster-policy-controller-clusterrolebinding.yaml",
 			"assets/kube-controller-manager/sa.yaml",
 			"assets/kube-controller-manager/recycler-sa.yaml",
 			"assets/kube-controller-manager/localhost-recovery-client-crb.yaml",
@@ -115,9 +351,15 @@ func RunOperator(ctx context.Context, cc *controllercmd.ControllerContext) error
 		os.Getenv("TOOLS_IMAGE"),
 		kubeInformersForNamespaces,
 		operatorClient,
+		statusBatcher,
 		operatorLister,
 		kubeClient,
 		configInformers.Config().V1().Infrastructures(),
+		configInformers.Config().V1().ClusterOperators(),
+		configInformers.Config().V1().Images(),
+		status.VersionForOperandFromEnv(),
+		configOwnership,
+		decisionLog,
 		cc.EventRecorder,
 	)
 
@@ -132,8 +374,8 @@ func RunOperator(ctx context.Context, cc *controllercmd.ControllerContext) error
 	}
 	versionRecorder.SetVersion("raw-internal", status.VersionForOperatorFromEnv())
 
-	staticPodControllers, err := staticpod.NewBuilder(operatorClient, kubeClient, kubeInformersForNamespaces).
-		WithEvents(cc.EventRecorder).
+	staticPodControllers, err := staticpod.NewBuilder(rolloutOperatorClient, kubeClient, kubeInformersForNamespaces).
+		WithEvents(rolloutRecorder).
 		WithInstaller([]string{"cluster-kube-controller-manager-operator", "installer"}).
 		WithPruning([]string{"cluster-kube-controller-manager-operator", "prune"}, "kube-controller-manager-pod").
 		WithRevisionedResources(operatorclient.TargetNamespace, "kube-controller-manager", deploymentConfigMaps, deploymentSecrets).
@@ -155,6 +397,88 @@ func RunOperator(ctx context.Context, cc *controllercmd.ControllerContext) error
 		return err
 	}
 
+	// the controllers keep their state in configmaps, STATE_STORAGE=CustomResource moves it to OperatorState resources
+	stateStore := statestore.NewConfigMapStore(kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().ConfigMaps(), kubeClient.CoreV1())
+	var stateInformers dynamicinformer.DynamicSharedInformerFactory
+	switch storage := os.Getenv("STATE_STORAGE"); storage {
+	case "", statestore.ConfigMap:
+	case statestore.CustomResource:
+		stateStore, stateInformers, err = statestore.NewCustomResourceStore(cc.KubeConfig)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("invalid STATE_STORAGE %q: must be %s or %s", storage, statestore.ConfigMap, statestore.CustomResource)
+	}
+
+	// the controllers verifying revisions share the parsed revisioned configmaps
+	revisionCache := revisioncache.New(kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().ConfigMaps())
+
+	operandVersionHistoryController := operandversion.NewHistoryController(operatorClient, kubeInformersForNamespaces, stateStore, cc.EventRecorder)
+
+	argumentMatrixController := argumentmatrixcontroller.NewArgumentMatrixController(operatorClient, revisionCache, kubeInformersForNamespaces, kubeClient.CoreV1(), cc.EventRecorder)
+
+	configBaselineController := configbaseline.NewBaselineController(status.VersionForOperatorFromEnv(), kubeInformersForNamespaces, kubeClient.CoreV1(), cc.EventRecorder)
+
+	degradedInfoController := degradedinfo.NewDegradedInfoController(operatorClient, stateStore, kubeClient.CoreV1(), degradationRecorder)
+
+	namespaceMetadataController := namespacemetadatacontroller.NewNamespaceMetadataController(operatorClient, statusBatcher, kubeInformersForNamespaces, kubeClient.CoreV1(), cc.EventRecorder)
+
+	leaderTakeoverController := leadertakeovercontroller.NewLeaderTakeoverController(kubeInformersForNamespaces, cc.EventRecorder)
+	operatorLeaseController := operatorleasecontroller.NewOperatorLeaseController(kubeInformersForNamespaces, cc.EventRecorder)
+
+	// scanning the operand logs is opt-in, OPERAND_LOG_SCAN_INTERVAL enables it
+	var logAnomalyController factory.Controller
+	if interval := os.Getenv("OPERAND_LOG_SCAN_INTERVAL"); len(interval) > 0 {
+		scanInterval, err := time.ParseDuration(interval)
+		if err != nil {
+			return fmt.Errorf("invalid OPERAND_LOG_SCAN_INTERVAL %q: %v", interval, err)
+		}
+		if scanInterval < time.Minute {
+			return fmt.Errorf("invalid OPERAND_LOG_SCAN_INTERVAL %q: must be at least 1m", interval)
+		}
+		logAnomalyController = loganomalycontroller.NewLogAnomalyController(statusBatcher, kubeInformersForNamespaces, kubeClient.CoreV1(), scanInterval, cc.EventRecorder)
+	}
+
+	contentionProfileController := contentionprofilecontroller.NewContentionProfileController(operatorClient, statusBatcher, kubeInformersForNamespaces, kubeClient.CoreV1(), cc.KubeConfig, 5*time.Minute, cc.EventRecorder)
+
+	errorBudgetController := errorbudgetcontroller.NewErrorBudgetController(statusBatcher, kubeInformersForNamespaces, kubeClient, cc.KubeConfig, degradationRecorder)
+
+	revisionRecoveryController := revisionrecoverycontroller.NewRevisionRecoveryController(operatorClient, statusBatcher, kubeInformersForNamespaces, kubeClient.CoreV1(), deploymentConfigMaps, deploymentSecrets, rolloutRecorder)
+	namespaceRecoveryController := namespacerecoverycontroller.NewNamespaceRecoveryController(statusBatcher, kubeInformersForNamespaces, kubeClient.CoreV1(), deploymentConfigMaps, deploymentSecrets, rolloutRecorder)
+
+	certReloadController := certreloadcontroller.NewCertReloadController(operatorClient, kubeInformersForNamespaces, rolloutRecorder)
+
+	revisionChecksumController := revisionchecksum.NewRevisionChecksumController(operatorClient, statusBatcher, kubeInformersForNamespaces, kubeClient.CoreV1(), deploymentConfigMaps, deploymentSecrets, rolloutRecorder)
+
+	controlPlaneCapacityController := controlplanecapacitycontroller.NewControlPlaneCapacityController(operatorClient, statusBatcher, kubeInformersForNamespaces, kubeClient.CoreV1(), cc.EventRecorder)
+
+	pendingConfigController := pendingconfigcontroller.NewPendingConfigController(operatorClient, statusBatcher, revisionCache, kubeInformersForNamespaces, cc.EventRecorder)
+	contentTypeController := contenttypecontroller.NewContentTypeController(operatorClient, statusBatcher, revisionCache, kubeInformersForNamespaces, cc.EventRecorder)
+
+	resumeRepairController := resumerepaircontroller.NewResumeRepairController(operatorClient, statusBatcher, kubeInformersForNamespaces, stateStore, cc.EventRecorder)
+
+	healthSnapshotController := healthsnapshot.NewHealthSnapshotController(operatorClient, kubeInformersForNamespaces, stateStore, cc.EventRecorder)
+
+	operationProfileController := operationprofile.NewProfileController(statusBatcher, cc.EventRecorder)
+	// the optional controllers defer their syncs while the operator is close to the limits of its pod
+	backpressureController := backpressure.NewController(statusBatcher, cc.EventRecorder)
+
+	nodeMaintenanceController := nodemaintenancecontroller.NewNodeMaintenanceController(operatorClient, statusBatcher, kubeInformersForNamespaces, rolloutRecorder)
+	zoneTopologyController := zonetopologycontroller.NewZoneTopologyController(statusBatcher, kubeInformersForNamespaces, cc.EventRecorder)
+
+	manifestConflictController := manifestconflictcontroller.NewManifestConflictController(operatorClient, statusBatcher, kubeInformersForNamespaces, cc.EventRecorder)
+
+	webhookInterferenceController := webhookinterference.NewWebhookInterferenceController(webhookInterferenceDetector, statusBatcher, kubeInformersForNamespaces, cc.EventRecorder)
+
+	hostPathPreflightController := hostpathpreflightcontroller.NewHostPathPreflightController(operatorClient, statusBatcher, kubeInformersForNamespaces, rolloutRecorder)
+
+	upgradeableController := upgradeablecontroller.NewUpgradeableController(operatorClient, statusBatcher, kubeInformersForNamespaces, cc.EventRecorder)
+
+	guardHealthController := guardhealthcontroller.NewGuardHealthController(operatorClient, statusBatcher, kubeInformersForNamespaces, degradationRecorder)
+
+	// orders the causes of the Degraded and Progressing conditions of the ClusterOperator by priority and caps them
+	conditionAggregator := conditionaggregation.NewAggregator()
 	clusterOperatorStatus := status.NewClusterOperatorStatusController(
 		"kube-controller-manager",
 		[]configv1.ObjectReference{
@@ -169,12 +493,14 @@ func RunOperator(ctx context.Context, cc *controllercmd.ControllerContext) error
 			// TODO move to a more appropriate operator. One that creates and manages these.
 			{Resource: "nodes"},
 		},
-		configClient.ConfigV1(),
-		configInformers.Config().V1().ClusterOperators(),
-		operatorClient,
+		conditionAggregator.ClusterOperatorsGetter(configClient.ConfigV1()),
+		conditionAggregator.ClusterOperatorInformer(configInformers.Config().V1().ClusterOperators()),
+		// the Degraded conditions of Warning severity do not escalate into the Degraded condition of the ClusterOperator
+		degradedseverity.NewCriticalOnlyClient(operatorClient),
 		versionRecorder,
-		cc.EventRecorder,
+		degradationRecorder,
 	)
+	degradedWarningsController := degradedseverity.NewWarningsController(operatorClient, statusBatcher, degradationRecorder)
 
 	certRotationScale, err := certrotation.GetCertRotationScale(ctx, kubeClient, operatorclient.GlobalUserSpecifiedConfigNamespace)
 	if err != nil {
@@ -186,7 +512,7 @@ func RunOperator(ctx context.Context, cc *controllercmd.ControllerContext) error
 		v1helpers.CachedConfigMapGetter(kubeClient.CoreV1(), kubeInformersForNamespaces),
 		operatorClient,
 		kubeInformersForNamespaces,
-		cc.EventRecorder,
+		certRotationRecorder,
 		// this is weird, but when we turn down rotation in CI, we go fast enough that kubelets and kas are racing to observe the new signer before the signer is used.
 		// we need to establish some kind of delay or back pressure to prevent the rollout.  This ensures we don't trigger kas restart
 		// during e2e tests for now.
@@ -195,7 +521,16 @@ func RunOperator(ctx context.Context, cc *controllercmd.ControllerContext) error
 	if err != nil {
 		return err
 	}
-	saTokenController := certrotationcontroller.NewSATokenSignerController(operatorClient, kubeInformersForNamespaces, kubeClient, cc.EventRecorder)
+	saTokenController := certrotationcontroller.NewSATokenSignerController(operatorClient, statusBatcher, kubeInformersForNamespaces, kubeClient, certRotationRecorder)
+	nodeFilesController := nodefilescontroller.NewNodeFilesController(operatorClient, statusBatcher, kubeInformersForNamespaces, rolloutRecorder)
+	saTokenPublicKeyController := satokenkeycontroller.NewSATokenPublicKeyController(operatorClient, statusBatcher, kubeInformersForNamespaces, kubeClient.CoreV1(), certRotationRecorder)
+
+	// the conditions left behind by controllers removed in an upgrade are pruned
+	graph, err := controllergraph.Load()
+	if err != nil {
+		return err
+	}
+	conditionJanitor := conditionjanitor.NewConditionJanitor(operatorClient, statusBatcher, graph, cc.EventRecorder)
 
 	staleConditions := staleconditions.NewRemoveStaleConditionsController(
 		[]string{
@@ -207,19 +542,79 @@ func RunOperator(ctx context.Context, cc *controllercmd.ControllerContext) error
 		cc.EventRecorder,
 	)
 
+	scopedinformers.RegisterCacheMetrics(kubeInformersForNamespaces,
+		operatorclient.GlobalUserSpecifiedConfigNamespace,
+		operatorclient.GlobalMachineSpecifiedConfigNamespace,
+		operatorclient.OperatorNamespace,
+		operatorclient.TargetNamespace,
+	)
+
 	configInformers.Start(ctx.Done())
 	kubeInformersForNamespaces.Start(ctx.Done())
 	dynamicInformers.Start(ctx.Done())
+	argumentRequestInformers.Start(ctx.Done())
+	if stateInformers != nil {
+		stateInformers.Start(ctx.Done())
+	}
 
 	go staticPodControllers.Start(ctx)
+	go guardHealthController.Run(ctx, 1)
+	go operandVersionHistoryController.Run(ctx, 1)
+	go configBaselineController.Run(ctx, 1)
+	go argumentMatrixController.Run(ctx, 1)
+	go degradedInfoController.Run(ctx, 1)
+	go upgradeableController.Run(ctx, 1)
+	go manifestConflictController.Run(ctx, 1)
+	go webhookInterferenceController.Run(ctx, 1)
+	go hostPathPreflightController.Run(ctx, 1)
+	go argumentRequestController.Run(ctx, 1)
+	go nodeMaintenanceController.Run(ctx, 1)
+	go zoneTopologyController.Run(ctx, 1)
+	go operationProfileController.Run(ctx, 1)
+	go backpressureController.Run(ctx, 1)
+	go revisionChecksumController.Run(ctx, 1)
+	go healthSnapshotController.Run(ctx, 1)
+	go namespaceMetadataController.Run(ctx, 1)
+	go leaderTakeoverController.Run(ctx, 1)
+	go operatorLeaseController.Run(ctx, 1)
+	if logAnomalyController != nil {
+		go logAnomalyController.Run(ctx, 1)
+	}
+	go contentionProfileController.Run(ctx, 1)
+	go errorBudgetController.Run(ctx, 1)
+	go revisionRecoveryController.Run(ctx, 1)
+	go namespaceRecoveryController.Run(ctx, 1)
+	go controlPlaneCapacityController.Run(ctx, 1)
+	go pendingConfigController.Run(ctx, 1)
+	go contentTypeController.Run(ctx, 1)
+	go resumeRepairController.Run(ctx, 1)
 	go staticResourceController.Run(ctx, 1)
 	go targetConfigController.Run(ctx, 1)
 	go configObserver.Run(ctx, 1)
+	go staleObservationController.Run(ctx, 1)
+	go breakGlassController.Run(ctx, 1)
+	go pauseController.Run(ctx, 1)
+	go teardownController.Run(ctx, 1)
+	if rolloutLeaseController != nil {
+		go rolloutLeaseController.Run(ctx, 1)
+	}
+	go nodeCIDRTopologyController.Run(ctx, 1)
+	go networkConfigMismatchController.Run(ctx, 1)
 	go clusterOperatorStatus.Run(ctx, 1)
+	go clusterIdentityController.Run(ctx, 1)
+	go degradedWarningsController.Run(ctx, 1)
 	go resourceSyncController.Run(ctx, 1)
+	go disabledSyncsController.Run(ctx, 1)
+	go orphanedMirrorsController.Run(ctx, 1)
+	go certReloadController.Run(ctx, 1)
+	go resourceSyncDriftController.Run(ctx, 1)
+	go dependencyController.Run(ctx, 1)
 	go certRotationController.Run(ctx, 1)
 	go saTokenController.Run(ctx, 1)
+	go saTokenPublicKeyController.Run(ctx, 1)
+	go nodeFilesController.Run(ctx, 1)
 	go staleConditions.Run(ctx, 1)
+	go conditionJanitor.Run(ctx, 1)
 
 	<-ctx.Done()
 	return nil
@@ -233,24 +628,34 @@ var deploymentConfigMaps = []revision.RevisionResource{
 	{Name: "config"},
 	{Name: "cluster-policy-controller-config"},
 	{Name: "controller-manager-kubeconfig"},
+	{Name: "cluster-policy-controller-kubeconfig"},
 	{Name: "cloud-config", Optional: true},
 	{Name: "kube-controller-cert-syncer-kubeconfig"},
 	{Name: "serviceaccount-ca"},
 	{Name: "service-ca"},
 	{Name: "recycler-config"},
+	{Name: "kube-controller-manager-flags"},
+	// the CAs of the image registries, present only in clusters which trust registries with custom CAs
+	{Name: "image-registry-ca-bundle", Optional: true},
+	// the files of openshift-config/kube-controller-manager-node-files, present only when the administrator created it
+	{Name: "node-files", Optional: true},
 }
 
 // deploymentSecrets is a list of secrets that are directly copied for the current values.  A different actor/controller modifies these.
 var deploymentSecrets = []revision.RevisionResource{
 	{Name: "service-account-private-key"},
 
-	// this cert is created by the service-ca controller, which doesn't come up until after we are available. this piece of config must be optional.
-	{Name: "serving-cert", Optional: true},
+	// issued by the service-ca controller, which doesn't come up until after we are available. this piece of config must be optional.
+	// cluster-policy-controller does not reload it, its rotations need a new revision.
+	{Name: "cluster-policy-controller-serving-cert", Optional: true},
 
 	// this needs to be revisioned as certsyncer's kubeconfig isn't wired to be live reloaded, nor will be autorecovery
 	{Name: "localhost-recovery-client-token"},
 }
 
+// CertConfigMaps and CertSecrets are synced in place by the cert syncer of the kube-controller-manager pods, which
+// reload them from disk. Their rotations restart no pod, unlike changes of the revisioned deploymentConfigMaps and
+// deploymentSecrets.
 var CertConfigMaps = []installer.UnrevisionedResource{
 	{Name: "aggregator-client-ca"},
 	{Name: "client-ca"},
@@ -260,6 +665,12 @@ var CertConfigMaps = []installer.UnrevisionedResource{
 }
 
 var CertSecrets = []installer.UnrevisionedResource{
+	// this cert is created by the service-ca controller, which doesn't come up until after we are available. this piece of config must be optional.
+	// the CertReloadController verifies its rotations are reloaded.
+	{Name: "serving-cert", Optional: true},
 	{Name: "kube-controller-manager-client-cert-key"},
+	// issued by the cert rotation after an upgrade, cluster-policy-controller uses the certificate of
+	// kube-controller-manager until then
+	{Name: "cluster-policy-controller-client-cert-key", Optional: true},
 	{Name: "csr-signer"},
 }
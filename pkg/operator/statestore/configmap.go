@@ -0,0 +1,66 @@
+package statestore
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1informers "k8s.io/client-go/informers/core/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+type configMapStore struct {
+	lister           corev1listers.ConfigMapLister
+	informer         factory.Informer
+	configMapsGetter corev1client.ConfigMapsGetter
+}
+
+// NewConfigMapStore keeps the documents in configmaps of the operator namespace, the informer must watch that
+// namespace.
+func NewConfigMapStore(informer corev1informers.ConfigMapInformer, configMapsGetter corev1client.ConfigMapsGetter) Store {
+	return &configMapStore{
+		lister:           informer.Lister(),
+		informer:         informer.Informer(),
+		configMapsGetter: configMapsGetter,
+	}
+}
+
+// NewConfigMapStoreFromLister keeps the documents in configmaps of the operator namespace, for callers which wait for
+// the lister to sync themselves.
+func NewConfigMapStoreFromLister(lister corev1listers.ConfigMapLister, configMapsGetter corev1client.ConfigMapsGetter) Store {
+	return &configMapStore{
+		lister:           lister,
+		configMapsGetter: configMapsGetter,
+	}
+}
+
+func (s *configMapStore) Get(name string) (map[string]string, error) {
+	configMap, err := s.lister.ConfigMaps(operatorclient.OperatorNamespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return configMap.Data, nil
+}
+
+func (s *configMapStore) Put(ctx context.Context, recorder events.Recorder, name string, data map[string]string) error {
+	_, _, err := resourceapply.ApplyConfigMap(ctx, s.configMapsGetter, recorder, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: name},
+		Data:       data,
+	})
+	return err
+}
+
+func (s *configMapStore) Informer() factory.Informer {
+	return s.informer
+}
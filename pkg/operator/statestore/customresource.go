@@ -0,0 +1,107 @@
+package statestore
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+// OperatorStateGVR is the resource the CustomResource store keeps the documents in, defined by the operatorstate CRD
+// of the manifests.
+var OperatorStateGVR = schema.GroupVersionResource{Group: "kubecontrollermanager.operator.openshift.io", Version: "v1alpha1", Resource: "operatorstates"}
+
+type customResourceStore struct {
+	informer cache.SharedIndexInformer
+	lister   cache.GenericNamespaceLister
+	client   dynamic.ResourceInterface
+}
+
+// NewCustomResourceStore keeps the documents in OperatorState resources of the operator namespace. The returned
+// informers must be started.
+func NewCustomResourceStore(config *rest.Config) (Store, dynamicinformer.DynamicSharedInformerFactory, error) {
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	informers := dynamicinformer.NewFilteredDynamicSharedInformerFactory(dynamicClient, 12*time.Hour, operatorclient.OperatorNamespace, nil)
+	return newCustomResourceStore(dynamicClient, informers), informers, nil
+}
+
+func newCustomResourceStore(dynamicClient dynamic.Interface, informers dynamicinformer.DynamicSharedInformerFactory) Store {
+	informer := informers.ForResource(OperatorStateGVR)
+	return &customResourceStore{
+		informer: informer.Informer(),
+		lister:   informer.Lister().ByNamespace(operatorclient.OperatorNamespace),
+		client:   dynamicClient.Resource(OperatorStateGVR).Namespace(operatorclient.OperatorNamespace),
+	}
+}
+
+func (s *customResourceStore) Get(name string) (map[string]string, error) {
+	obj, err := s.lister.Get(name)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	data, _, err := unstructured.NestedStringMap(obj.(*unstructured.Unstructured).UnstructuredContent(), "data")
+	if err != nil {
+		return nil, fmt.Errorf("operatorstate/%s: %v", name, err)
+	}
+	return data, nil
+}
+
+func (s *customResourceStore) Put(ctx context.Context, recorder events.Recorder, name string, data map[string]string) error {
+	existing, err := s.lister.Get(name)
+	if apierrors.IsNotFound(err) {
+		required := &unstructured.Unstructured{}
+		required.SetAPIVersion(OperatorStateGVR.GroupVersion().String())
+		required.SetKind("OperatorState")
+		required.SetNamespace(operatorclient.OperatorNamespace)
+		required.SetName(name)
+		if err := unstructured.SetNestedStringMap(required.Object, data, "data"); err != nil {
+			return err
+		}
+		if _, err := s.client.Create(ctx, required, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+		recorder.Eventf("OperatorStateCreated", "Created operatorstate/%s -n %s because it was missing", name, operatorclient.OperatorNamespace)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	updated := existing.(*unstructured.Unstructured).DeepCopy()
+	current, _, _ := unstructured.NestedStringMap(updated.Object, "data")
+	if reflect.DeepEqual(current, data) || (len(current) == 0 && len(data) == 0) {
+		return nil
+	}
+	if err := unstructured.SetNestedStringMap(updated.Object, data, "data"); err != nil {
+		return err
+	}
+	if _, err := s.client.Update(ctx, updated, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+	recorder.Eventf("OperatorStateUpdated", "Updated operatorstate/%s -n %s because it changed", name, operatorclient.OperatorNamespace)
+	return nil
+}
+
+func (s *customResourceStore) Informer() factory.Informer {
+	return s.informer
+}
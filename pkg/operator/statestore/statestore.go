@@ -0,0 +1,29 @@
+package statestore
+
+import (
+	"context"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+)
+
+// Store persists the state of the operator controllers across restarts of the operator, like the operand version
+// history or the degraded info. Every controller keeps a document of serialized values under a name of its own in the
+// operator namespace.
+type Store interface {
+	// Get returns the document stored under the name, nil when there is none.
+	Get(name string) (map[string]string, error)
+	// Put replaces the document stored under the name.
+	Put(ctx context.Context, recorder events.Recorder, name string, data map[string]string) error
+	// Informer notifies about changes of the stored documents. The controllers must wait for it to sync before they
+	// read, or they start over and overwrite their state.
+	Informer() factory.Informer
+}
+
+const (
+	// ConfigMap keeps every document in a configmap of its own, the default.
+	ConfigMap = "ConfigMap"
+	// CustomResource keeps every document in an OperatorState resource of its own, so that large documents do not
+	// end up in configmaps and access to them can be granted separately.
+	CustomResource = "CustomResource"
+)
@@ -0,0 +1,98 @@
+package statestore
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+func TestConfigMapStore(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	if err := indexer.Add(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: "existing"},
+		Data:       map[string]string{"key": "value"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	kubeClient := fake.NewSimpleClientset()
+	store := NewConfigMapStoreFromLister(corev1listers.NewConfigMapLister(indexer), kubeClient.CoreV1())
+
+	data, err := store.Get("existing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(data, map[string]string{"key": "value"}) {
+		t.Errorf("unexpected data of the existing document: %v", data)
+	}
+	data, err = store.Get("missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data != nil {
+		t.Errorf("expected no data of the missing document, got %v", data)
+	}
+
+	if err := store.Put(context.TODO(), events.NewInMemoryRecorder("test"), "missing", map[string]string{"key": "new"}); err != nil {
+		t.Fatal(err)
+	}
+	configMap, err := kubeClient.CoreV1().ConfigMaps(operatorclient.OperatorNamespace).Get(context.TODO(), "missing", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(configMap.Data, map[string]string{"key": "new"}) {
+		t.Errorf("unexpected data of the stored document: %v", configMap.Data)
+	}
+}
+
+func TestCustomResourceStore(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	existing := &unstructured.Unstructured{}
+	existing.SetAPIVersion(OperatorStateGVR.GroupVersion().String())
+	existing.SetKind("OperatorState")
+	existing.SetNamespace(operatorclient.OperatorNamespace)
+	existing.SetName("existing")
+	if err := unstructured.SetNestedStringMap(existing.Object, map[string]string{"key": "value"}, "data"); err != nil {
+		t.Fatal(err)
+	}
+	if err := indexer.Add(existing); err != nil {
+		t.Fatal(err)
+	}
+	// without a client, writes other than the unchanged document fail
+	store := &customResourceStore{
+		lister: cache.NewGenericLister(indexer, OperatorStateGVR.GroupResource()).ByNamespace(operatorclient.OperatorNamespace),
+	}
+
+	data, err := store.Get("existing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(data, map[string]string{"key": "value"}) {
+		t.Errorf("unexpected data of the existing document: %v", data)
+	}
+	data, err = store.Get("missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data != nil {
+		t.Errorf("expected no data of the missing document, got %v", data)
+	}
+
+	recorder := events.NewInMemoryRecorder("test")
+	if err := store.Put(context.TODO(), recorder, "existing", map[string]string{"key": "value"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(recorder.Events()) != 0 {
+		t.Errorf("expected no update of the unchanged document, got %v", recorder.Events())
+	}
+}
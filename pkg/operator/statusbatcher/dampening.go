@@ -0,0 +1,99 @@
+package statusbatcher
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"k8s.io/component-base/metrics"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatormetrics"
+)
+
+var suppressedFlaps = metrics.NewCounterVec(&metrics.CounterOpts{
+	Name: "kcm_operator_condition_flaps_suppressed_total",
+	Help: "Number of condition flips which reverted before the dwell time passed and were never written.",
+}, []string{"condition"})
+
+func init() {
+	operatormetrics.MustRegister(suppressedFlaps)
+}
+
+type pendingFlip struct {
+	status operatorv1.ConditionStatus
+	since  time.Time
+}
+
+// dampener keeps the previous status of the Degraded conditions until the new status was reported for the whole dwell
+// time. A blip of the API server otherwise flips Degraded back and forth within a single resync and pages somebody.
+type dampener struct {
+	dwell time.Duration
+	now   func() time.Time
+
+	lock    sync.Mutex
+	pending map[string]pendingFlip
+}
+
+func newDampener(dwell time.Duration) *dampener {
+	return &dampener{
+		dwell:   dwell,
+		now:     time.Now,
+		pending: map[string]pendingFlip{},
+	}
+}
+
+func dampened(conditionType string) bool {
+	return strings.HasSuffix(conditionType, "Degraded")
+}
+
+// wrap surrounds the update functions with a snapshot of the conditions before the update and a final step reverting
+// the flips which did not persist long enough yet. The update functions are opaque, comparing the conditions before
+// and after them is the only way to tell what they flip.
+func (d *dampener) wrap(updateFuncs []v1helpers.UpdateStatusFunc) []v1helpers.UpdateStatusFunc {
+	var before []operatorv1.OperatorCondition
+	snapshot := func(status *operatorv1.OperatorStatus) error {
+		before = make([]operatorv1.OperatorCondition, len(status.Conditions))
+		copy(before, status.Conditions)
+		return nil
+	}
+	dampen := func(status *operatorv1.OperatorStatus) error {
+		d.lock.Lock()
+		defer d.lock.Unlock()
+
+		now := d.now()
+		for i, condition := range status.Conditions {
+			if !dampened(condition.Type) {
+				continue
+			}
+			previous := v1helpers.FindOperatorCondition(before, condition.Type)
+			if previous == nil {
+				// a new condition has nothing to flap against
+				continue
+			}
+			flip, pending := d.pending[condition.Type]
+			if previous.Status == condition.Status {
+				// the flip either landed or the state went back before the dwell time passed
+				if pending && flip.status != previous.Status {
+					suppressedFlaps.WithLabelValues(condition.Type).Inc()
+				}
+				delete(d.pending, condition.Type)
+				continue
+			}
+			if !pending || flip.status != condition.Status {
+				flip = pendingFlip{status: condition.Status, since: now}
+				d.pending[condition.Type] = flip
+			}
+			if now.Sub(flip.since) < d.dwell {
+				status.Conditions[i] = *previous
+			}
+		}
+		return nil
+	}
+
+	wrapped := []v1helpers.UpdateStatusFunc{snapshot}
+	wrapped = append(wrapped, updateFuncs...)
+	return append(wrapped, dampen)
+}
@@ -0,0 +1,66 @@
+package statusbatcher
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+func TestDampening(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{
+		Conditions: []operatorv1.OperatorCondition{
+			{Type: "ADegraded", Status: operatorv1.ConditionFalse},
+			{Type: "BAvailable", Status: operatorv1.ConditionTrue},
+		},
+	}, nil)
+	d := newDampener(time.Minute)
+	d.now = func() time.Time { return now }
+
+	set := func(conditionType string, status operatorv1.ConditionStatus) {
+		t.Helper()
+		condition := operatorv1.OperatorCondition{Type: conditionType, Status: status}
+		updateFuncs := d.wrap([]v1helpers.UpdateStatusFunc{v1helpers.UpdateConditionFn(condition)})
+		if _, _, err := v1helpers.UpdateStatus(context.TODO(), operatorClient, updateFuncs...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expect := func(conditionType string, status operatorv1.ConditionStatus) {
+		t.Helper()
+		_, operatorStatus, _, _ := operatorClient.GetOperatorState()
+		condition := v1helpers.FindOperatorCondition(operatorStatus.Conditions, conditionType)
+		if condition == nil || condition.Status != status {
+			t.Errorf("expected %s to be %s, got %v", conditionType, status, condition)
+		}
+	}
+
+	// conditions other than Degraded flip right away
+	set("BAvailable", operatorv1.ConditionFalse)
+	expect("BAvailable", operatorv1.ConditionFalse)
+
+	// a blip shorter than the dwell time is never written
+	set("ADegraded", operatorv1.ConditionTrue)
+	expect("ADegraded", operatorv1.ConditionFalse)
+	now = now.Add(30 * time.Second)
+	set("ADegraded", operatorv1.ConditionFalse)
+	expect("ADegraded", operatorv1.ConditionFalse)
+	if _, pending := d.pending["ADegraded"]; pending {
+		t.Errorf("expected the reverted flip to be forgotten")
+	}
+
+	// a state persisting for the dwell time is written
+	set("ADegraded", operatorv1.ConditionTrue)
+	now = now.Add(59 * time.Second)
+	set("ADegraded", operatorv1.ConditionTrue)
+	expect("ADegraded", operatorv1.ConditionFalse)
+	now = now.Add(time.Second)
+	set("ADegraded", operatorv1.ConditionTrue)
+	expect("ADegraded", operatorv1.ConditionTrue)
+
+	// new conditions are written right away
+	set("CDegraded", operatorv1.ConditionTrue)
+	expect("CDegraded", operatorv1.ConditionTrue)
+}
@@ -0,0 +1,155 @@
+package statusbatcher
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+const spoolFileName = "status.json"
+
+// Spool keeps the conditions which could not be written while the kube-apiserver rejected status updates in a file
+// on a volume of the operator pod, and writes them once status updates are accepted again. The controllers only
+// report their current state on their next sync, the transitions in between would otherwise be lost.
+type Spool struct {
+	path string
+	lock sync.Mutex
+}
+
+type spooledStatus struct {
+	Conditions []operatorv1.OperatorCondition `json:"conditions"`
+}
+
+func NewSpool(dir string) *Spool {
+	return &Spool{path: filepath.Join(dir, spoolFileName)}
+}
+
+// add records the conditions changed by the update functions on top of the status last seen by the operator.
+func (s *Spool) add(operatorClient v1helpers.OperatorClient, updateFuncs []v1helpers.UpdateStatusFunc) error {
+	_, current, _, err := operatorClient.GetOperatorState()
+	if err != nil {
+		return err
+	}
+	intended := current.DeepCopy()
+	for _, update := range updateFuncs {
+		if err := update(intended); err != nil {
+			return err
+		}
+	}
+	changed := []operatorv1.OperatorCondition{}
+	for _, condition := range intended.Conditions {
+		if previous := v1helpers.FindOperatorCondition(current.Conditions, condition.Type); previous != nil && equality.Semantic.DeepEqual(*previous, condition) {
+			continue
+		}
+		changed = append(changed, condition)
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	spooled := s.read()
+	for _, condition := range changed {
+		v1helpers.SetOperatorCondition(&spooled.Conditions, condition)
+		// the transition happened now, not when the spool is written
+		v1helpers.FindOperatorCondition(spooled.Conditions, condition.Type).LastTransitionTime = condition.LastTransitionTime
+	}
+	return s.write(spooled)
+}
+
+// replay writes the spooled conditions which are newer than the conditions of the status and differ from them, and
+// empties the spool. The conditions set by the write which just succeeded are the current state reported by their
+// controllers, their spooled transitions are stale and dropped.
+func (s *Spool) replay(ctx context.Context, operatorClient v1helpers.OperatorClient, written sets.String) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	spooled := s.read()
+	if len(spooled.Conditions) == 0 {
+		return nil
+	}
+
+	replayed := 0
+	_, _, err := v1helpers.UpdateStatus(ctx, operatorClient, func(status *operatorv1.OperatorStatus) error {
+		replayed = 0
+		for _, condition := range spooled.Conditions {
+			if written.Has(condition.Type) {
+				continue
+			}
+			if existing := v1helpers.FindOperatorCondition(status.Conditions, condition.Type); existing != nil &&
+				(!condition.LastTransitionTime.After(existing.LastTransitionTime.Time) || sameState(*existing, condition)) {
+				continue
+			}
+			v1helpers.SetOperatorCondition(&status.Conditions, condition)
+			v1helpers.FindOperatorCondition(status.Conditions, condition.Type).LastTransitionTime = condition.LastTransitionTime
+			replayed++
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	klog.Infof("Wrote %d of %d conditions spooled while status updates failed", replayed, len(spooled.Conditions))
+	if err := os.Remove(s.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// sameState tells whether two conditions report the same state, regardless of when they transitioned.
+func sameState(a, b operatorv1.OperatorCondition) bool {
+	return a.Status == b.Status && a.Reason == b.Reason && a.Message == b.Message
+}
+
+// conditionTypes returns the types of the conditions set by the update functions.
+func conditionTypes(updateFuncs []v1helpers.UpdateStatusFunc) sets.String {
+	status := &operatorv1.OperatorStatus{}
+	for _, update := range updateFuncs {
+		// a failing update function failed the write as well
+		_ = update(status)
+	}
+	ret := sets.NewString()
+	for _, condition := range status.Conditions {
+		ret.Insert(condition.Type)
+	}
+	return ret
+}
+
+// read returns the spooled conditions, a spool which cannot be read starts over.
+func (s *Spool) read() spooledStatus {
+	ret := spooledStatus{}
+	content, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return ret
+	}
+	if err == nil {
+		err = json.Unmarshal(content, &ret)
+	}
+	if err != nil {
+		klog.Warningf("Discarding the spooled status in %s: %v", s.path, err)
+		return spooledStatus{}
+	}
+	return ret
+}
+
+func (s *Spool) write(spooled spooledStatus) error {
+	content, err := json.Marshal(spooled)
+	if err != nil {
+		return err
+	}
+	// a crash while writing must not leave a truncated spool behind
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, content, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
@@ -0,0 +1,102 @@
+package statusbatcher
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+func TestSpool(t *testing.T) {
+	dir := t.TempDir()
+	failing := true
+	operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{
+		Conditions: []operatorv1.OperatorCondition{{Type: "ADegraded", Status: operatorv1.ConditionFalse}},
+	}, func(string, *operatorv1.OperatorStatus) error {
+		if failing {
+			return errors.New("etcdserver: request timed out")
+		}
+		return nil
+	})
+	batcher := NewStatusBatcher(operatorClient, 10*time.Millisecond).WithSpool(NewSpool(dir), 0)
+	spoolFile := filepath.Join(dir, spoolFileName)
+
+	degraded := operatorv1.OperatorCondition{Type: "ADegraded", Status: operatorv1.ConditionTrue, Reason: "Blip"}
+	if err := batcher.UpdateStatus(context.TODO(), v1helpers.UpdateConditionFn(degraded)); err == nil {
+		t.Fatal("expected the status update to fail")
+	}
+	spooled := NewSpool(dir).read()
+	if len(spooled.Conditions) != 1 || spooled.Conditions[0].Reason != "Blip" {
+		t.Fatalf("expected ADegraded to be spooled, got %v", spooled.Conditions)
+	}
+	transitionTime := spooled.Conditions[0].LastTransitionTime
+
+	failing = false
+	other := operatorv1.OperatorCondition{Type: "BDegraded", Status: operatorv1.ConditionFalse}
+	if err := batcher.UpdateStatus(context.TODO(), v1helpers.UpdateConditionFn(other)); err != nil {
+		t.Fatal(err)
+	}
+	_, status, _, _ := operatorClient.GetOperatorState()
+	condition := v1helpers.FindOperatorCondition(status.Conditions, "ADegraded")
+	if condition == nil || condition.Status != operatorv1.ConditionTrue || !condition.LastTransitionTime.Equal(&transitionTime) {
+		t.Errorf("expected the spooled transition to be written, got %v", condition)
+	}
+	if !v1helpers.IsOperatorConditionFalse(status.Conditions, "BDegraded") {
+		t.Errorf("expected BDegraded to be written, got %v", status.Conditions)
+	}
+	if _, err := os.Stat(spoolFile); !os.IsNotExist(err) {
+		t.Errorf("expected the spool to be emptied, got %v", err)
+	}
+}
+
+func TestSpoolRecovered(t *testing.T) {
+	dir := t.TempDir()
+	failing := true
+	operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{
+		Conditions: []operatorv1.OperatorCondition{
+			{Type: "ADegraded", Status: operatorv1.ConditionFalse},
+			{Type: "BDegraded", Status: operatorv1.ConditionFalse},
+		},
+	}, func(string, *operatorv1.OperatorStatus) error {
+		if failing {
+			return errors.New("etcdserver: request timed out")
+		}
+		return nil
+	})
+	batcher := NewStatusBatcher(operatorClient, 10*time.Millisecond).WithSpool(NewSpool(dir), 0)
+
+	if err := batcher.UpdateStatus(context.TODO(),
+		v1helpers.UpdateConditionFn(operatorv1.OperatorCondition{Type: "ADegraded", Status: operatorv1.ConditionTrue, Reason: "Blip"}),
+		v1helpers.UpdateConditionFn(operatorv1.OperatorCondition{Type: "BDegraded", Status: operatorv1.ConditionTrue, Reason: "Blip"}),
+	); err == nil {
+		t.Fatal("expected the status update to fail")
+	}
+
+	// the controller of ADegraded recovered and reports it, the one of BDegraded did not sync since
+	failing = false
+	if err := batcher.UpdateStatus(context.TODO(), v1helpers.UpdateConditionFn(operatorv1.OperatorCondition{Type: "ADegraded", Status: operatorv1.ConditionFalse})); err != nil {
+		t.Fatal(err)
+	}
+	_, status, _, _ := operatorClient.GetOperatorState()
+	if !v1helpers.IsOperatorConditionFalse(status.Conditions, "ADegraded") {
+		t.Errorf("expected the recovered ADegraded to stay False, got %v", status.Conditions)
+	}
+	if condition := v1helpers.FindOperatorCondition(status.Conditions, "BDegraded"); condition == nil || condition.Status != operatorv1.ConditionTrue {
+		t.Errorf("expected the spooled BDegraded to be written, got %v", condition)
+	}
+}
+
+func TestSpoolUnreadable(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, spoolFileName), []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if spooled := NewSpool(dir).read(); len(spooled.Conditions) != 0 {
+		t.Errorf("expected an unreadable spool to start over, got %v", spooled.Conditions)
+	}
+}
@@ -0,0 +1,161 @@
+package statusbatcher
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+// StatusUpdater applies status updates to the operator resource.
+type StatusUpdater interface {
+	UpdateStatus(ctx context.Context, updateFuncs ...v1helpers.UpdateStatusFunc) error
+}
+
+type directUpdater struct {
+	operatorClient v1helpers.OperatorClient
+}
+
+// NewDirectUpdater returns a StatusUpdater which writes every update right away.
+func NewDirectUpdater(operatorClient v1helpers.OperatorClient) StatusUpdater {
+	return &directUpdater{operatorClient: operatorClient}
+}
+
+func (u *directUpdater) UpdateStatus(ctx context.Context, updateFuncs ...v1helpers.UpdateStatusFunc) error {
+	_, _, err := v1helpers.UpdateStatus(ctx, u.operatorClient, MonotonicTransitions(updateFuncs, nil)...)
+	return err
+}
+
+type pendingUpdate struct {
+	updateFuncs []v1helpers.UpdateStatusFunc
+	done        chan error
+}
+
+// StatusBatcher collects the status updates of several controllers arriving within a short interval and writes them
+// with a single status update. Right after the operator starts every controller syncs at once and racing on the
+// resourceVersion of the operator resource otherwise causes a burst of conflicts and retries.
+type StatusBatcher struct {
+	operatorClient v1helpers.OperatorClient
+	interval       time.Duration
+	dampener       *dampener
+	spool          *Spool
+	spoolAfter     time.Duration
+	recorder       events.Recorder
+
+	lock           sync.Mutex
+	pending        []pendingUpdate
+	flushScheduled bool
+	// failingSince is when the status updates started to fail, zero while they succeed
+	failingSince time.Time
+}
+
+var _ StatusUpdater = &StatusBatcher{}
+
+func NewStatusBatcher(operatorClient v1helpers.OperatorClient, interval time.Duration) *StatusBatcher {
+	return &StatusBatcher{
+		operatorClient: operatorClient,
+		interval:       interval,
+	}
+}
+
+// WithDampening holds back flips of the Degraded conditions until the new status was reported for at least dwell.
+// A zero dwell disables the dampening.
+func (b *StatusBatcher) WithDampening(dwell time.Duration) *StatusBatcher {
+	if dwell > 0 {
+		b.dampener = newDampener(dwell)
+	}
+	return b
+}
+
+// WithSpool keeps the updates in the spool once status updates failed for longer than after, and writes them once
+// status updates succeed again.
+func (b *StatusBatcher) WithSpool(spool *Spool, after time.Duration) *StatusBatcher {
+	b.spool = spool
+	b.spoolAfter = after
+	return b
+}
+
+// WithEventRecorder reports the condition transitions stamped by a clock which went backwards with events.
+func (b *StatusBatcher) WithEventRecorder(recorder events.Recorder) *StatusBatcher {
+	b.recorder = recorder
+	return b
+}
+
+// UpdateStatus queues the update functions and blocks until the batch they end up in is written.
+func (b *StatusBatcher) UpdateStatus(ctx context.Context, updateFuncs ...v1helpers.UpdateStatusFunc) error {
+	done := make(chan error, 1)
+
+	b.lock.Lock()
+	b.pending = append(b.pending, pendingUpdate{updateFuncs: updateFuncs, done: done})
+	if !b.flushScheduled {
+		b.flushScheduled = true
+		time.AfterFunc(b.interval, b.flush)
+	}
+	b.lock.Unlock()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (b *StatusBatcher) flush() {
+	b.lock.Lock()
+	batch := b.pending
+	b.pending = nil
+	b.flushScheduled = false
+	b.lock.Unlock()
+
+	updateFuncs := []v1helpers.UpdateStatusFunc{}
+	for _, update := range batch {
+		updateFuncs = append(updateFuncs, update.updateFuncs...)
+	}
+	// the wrappers below keep state, the conditions are read from the update functions of the controllers only
+	written := conditionTypes(updateFuncs)
+	if b.dampener != nil {
+		updateFuncs = b.dampener.wrap(updateFuncs)
+	}
+	updateFuncs = MonotonicTransitions(updateFuncs, b.recorder)
+
+	// the callers may have given up waiting, the write must not depend on any of their contexts
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	_, _, err := v1helpers.UpdateStatus(ctx, b.operatorClient, updateFuncs...)
+	if b.spool != nil {
+		b.spoolOrReplay(ctx, updateFuncs, written, err)
+	}
+	for _, update := range batch {
+		update.done <- err
+	}
+}
+
+func (b *StatusBatcher) spoolOrReplay(ctx context.Context, updateFuncs []v1helpers.UpdateStatusFunc, written sets.String, updateErr error) {
+	b.lock.Lock()
+	if updateErr == nil {
+		b.failingSince = time.Time{}
+	} else if b.failingSince.IsZero() {
+		b.failingSince = time.Now()
+	}
+	failingSince := b.failingSince
+	b.lock.Unlock()
+
+	if updateErr == nil {
+		if err := b.spool.replay(ctx, b.operatorClient, written); err != nil {
+			klog.Warningf("Failed to write the spooled status: %v", err)
+		}
+		return
+	}
+	if time.Since(failingSince) < b.spoolAfter {
+		return
+	}
+	if err := b.spool.add(b.operatorClient, updateFuncs); err != nil {
+		klog.Warningf("Failed to spool the status: %v", err)
+	}
+}
@@ -0,0 +1,40 @@
+package statusbatcher
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+func TestStatusBatcher(t *testing.T) {
+	operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil)
+	batcher := NewStatusBatcher(operatorClient, 100*time.Millisecond)
+
+	conditionTypes := []string{"ADegraded", "BDegraded", "CDegraded"}
+	wg := sync.WaitGroup{}
+	for _, conditionType := range conditionTypes {
+		wg.Add(1)
+		go func(conditionType string) {
+			defer wg.Done()
+			condition := operatorv1.OperatorCondition{Type: conditionType, Status: operatorv1.ConditionFalse}
+			if err := batcher.UpdateStatus(context.TODO(), v1helpers.UpdateConditionFn(condition)); err != nil {
+				t.Error(err)
+			}
+		}(conditionType)
+	}
+	wg.Wait()
+
+	_, status, resourceVersion, _ := operatorClient.GetOperatorState()
+	for _, conditionType := range conditionTypes {
+		if !v1helpers.IsOperatorConditionFalse(status.Conditions, conditionType) {
+			t.Errorf("missing condition %s in %v", conditionType, status.Conditions)
+		}
+	}
+	if resourceVersion != "1" {
+		t.Errorf("expected a single status update, got resourceVersion %s", resourceVersion)
+	}
+}
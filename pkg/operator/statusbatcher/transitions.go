@@ -0,0 +1,57 @@
+package statusbatcher
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/component-base/metrics"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatormetrics"
+)
+
+var skewedTransitions = metrics.NewCounterVec(&metrics.CounterOpts{
+	Name: "kcm_operator_condition_transition_clock_skew_total",
+	Help: "Number of condition transitions stamped before the previous transition of the condition, by a clock which went backwards.",
+}, []string{"condition"})
+
+func init() {
+	operatormetrics.MustRegister(skewedTransitions)
+}
+
+// MonotonicTransitions surrounds the update functions with a snapshot of the conditions before the update and a final
+// step which keeps the LastTransitionTime of every condition from moving backwards. The transitions are stamped with
+// the wall clock of whichever node the operator runs on, a node with a skewed clock otherwise orders the history of a
+// condition wrongly. A transition stamped too early is moved a second past the previous one and reported with a
+// ConditionClockSkew event when a recorder is given.
+func MonotonicTransitions(updateFuncs []v1helpers.UpdateStatusFunc, recorder events.Recorder) []v1helpers.UpdateStatusFunc {
+	var before []operatorv1.OperatorCondition
+	snapshot := func(status *operatorv1.OperatorStatus) error {
+		before = make([]operatorv1.OperatorCondition, len(status.Conditions))
+		copy(before, status.Conditions)
+		return nil
+	}
+	sequence := func(status *operatorv1.OperatorStatus) error {
+		for i, condition := range status.Conditions {
+			previous := v1helpers.FindOperatorCondition(before, condition.Type)
+			if previous == nil || !condition.LastTransitionTime.Before(&previous.LastTransitionTime) {
+				continue
+			}
+			sequenced := metav1.NewTime(previous.LastTransitionTime.Add(time.Second))
+			skewedTransitions.WithLabelValues(condition.Type).Inc()
+			if recorder != nil {
+				recorder.Warningf("ConditionClockSkew", "The transition of %s to %s was stamped %s, before its previous transition at %s, recording it at %s",
+					condition.Type, condition.Status, condition.LastTransitionTime.UTC().Format(time.RFC3339), previous.LastTransitionTime.UTC().Format(time.RFC3339), sequenced.UTC().Format(time.RFC3339))
+			}
+			status.Conditions[i].LastTransitionTime = sequenced
+		}
+		return nil
+	}
+
+	wrapped := []v1helpers.UpdateStatusFunc{snapshot}
+	wrapped = append(wrapped, updateFuncs...)
+	return append(wrapped, sequence)
+}
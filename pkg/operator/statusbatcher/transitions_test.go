@@ -0,0 +1,47 @@
+package statusbatcher
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+func TestMonotonicTransitions(t *testing.T) {
+	// the previous transitions were stamped by a node whose clock runs an hour ahead
+	ahead := metav1.NewTime(time.Now().Add(time.Hour))
+	operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{
+		Conditions: []operatorv1.OperatorCondition{
+			{Type: "ADegraded", Status: operatorv1.ConditionFalse, LastTransitionTime: ahead},
+			{Type: "BDegraded", Status: operatorv1.ConditionFalse, LastTransitionTime: ahead},
+		},
+	}, nil)
+	recorder := events.NewInMemoryRecorder("test")
+
+	if _, _, err := v1helpers.UpdateStatus(context.TODO(), operatorClient, MonotonicTransitions([]v1helpers.UpdateStatusFunc{
+		v1helpers.UpdateConditionFn(operatorv1.OperatorCondition{Type: "ADegraded", Status: operatorv1.ConditionTrue}),
+		v1helpers.UpdateConditionFn(operatorv1.OperatorCondition{Type: "BDegraded", Status: operatorv1.ConditionFalse, Reason: "AsExpected"}),
+		v1helpers.UpdateConditionFn(operatorv1.OperatorCondition{Type: "CDegraded", Status: operatorv1.ConditionFalse}),
+	}, recorder)...); err != nil {
+		t.Fatal(err)
+	}
+
+	_, status, _, _ := operatorClient.GetOperatorState()
+	if a := v1helpers.FindOperatorCondition(status.Conditions, "ADegraded"); !a.LastTransitionTime.After(ahead.Time) {
+		t.Errorf("expected the transition of ADegraded to be sequenced after %s, got %s", ahead, a.LastTransitionTime)
+	}
+	if b := v1helpers.FindOperatorCondition(status.Conditions, "BDegraded"); !b.LastTransitionTime.Equal(&ahead) {
+		t.Errorf("expected BDegraded to keep its transition, got %s", b.LastTransitionTime)
+	}
+	if c := v1helpers.FindOperatorCondition(status.Conditions, "CDegraded"); c.LastTransitionTime.After(time.Now()) {
+		t.Errorf("expected a new condition to be stamped now, got %s", c.LastTransitionTime)
+	}
+	if skews := len(recorder.Events()); skews != 1 {
+		t.Errorf("expected a single skew event, got %d", skews)
+	}
+}
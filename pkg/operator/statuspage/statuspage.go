@@ -0,0 +1,229 @@
+package statuspage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/backpressure"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/decisionlog"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operationprofile"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/rolloutlease"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/teardown"
+)
+
+const (
+	listenAddressEnv = "STATUS_PAGE_LISTEN_ADDRESS"
+
+	// maxDecisions caps the decisions on the page, the newest are shown
+	maxDecisions = 50
+)
+
+// ListenAddressFromEnv reads the listen address of the status page from STATUS_PAGE_LISTEN_ADDRESS. It returns
+// nothing when the variable is not set. The page is not authenticated, only loopback addresses are accepted.
+func ListenAddressFromEnv() (string, error) {
+	address := os.Getenv(listenAddressEnv)
+	if len(address) == 0 {
+		return "", nil
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s %q: %v", listenAddressEnv, address, err)
+	}
+	if host != "localhost" {
+		if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+			return "", fmt.Errorf("invalid %s %q: the status page is served on loopback addresses only", listenAddressEnv, address)
+		}
+	}
+	return address, nil
+}
+
+// Serve exposes the status page on the listen address until the context is done.
+func Serve(ctx context.Context, address string, handler http.Handler) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+
+	server := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: 30 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	klog.Infof("Serving the status page on %s", listener.Addr())
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Handler renders the state of the operator as a plain HTML page: the revisions of the nodes, the operator
+// conditions, the modes holding back the rollout and the recent decisions. It reads the informer of the operator
+// resource only, so that the page works for engineers on a control plane node when kube-apiserver is not reachable
+// through oc any more. The operator pod is not on the host network, the page is read from the node by entering the
+// network namespace of the pod, as the README describes.
+type Handler struct {
+	operatorClient v1helpers.StaticPodOperatorClient
+	decisionLog    *decisionlog.Log
+	now            func() time.Time
+}
+
+func NewHandler(operatorClient v1helpers.StaticPodOperatorClient, decisionLog *decisionlog.Log) *Handler {
+	return &Handler{
+		operatorClient: operatorClient,
+		decisionLog:    decisionLog,
+		now:            time.Now,
+	}
+}
+
+type page struct {
+	Time             time.Time
+	ManagementState  operatorv1.ManagementState
+	OperationProfile operationprofile.Profile
+	Backpressure     bool
+	Quiescing        bool
+	RolloutQueued    bool
+
+	LatestAvailableRevision int32
+	Nodes                   []operatorv1.NodeStatus
+	Conditions              []operatorv1.OperatorCondition
+	Decisions               []decisionlog.Decision
+	DecisionLogEnabled      bool
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path != "/" {
+		http.NotFound(w, req)
+		return
+	}
+	spec, status, _, err := h.operatorClient.GetStaticPodOperatorState()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	conditions := append([]operatorv1.OperatorCondition{}, status.Conditions...)
+	sort.Slice(conditions, func(i, j int) bool { return conditions[i].Type < conditions[j].Type })
+	// the newest decisions first
+	decisions := h.decisionLog.Decisions()
+	if len(decisions) > maxDecisions {
+		decisions = decisions[len(decisions)-maxDecisions:]
+	}
+	for i, j := 0, len(decisions)-1; i < j; i, j = i+1, j-1 {
+		decisions[i], decisions[j] = decisions[j], decisions[i]
+	}
+
+	data := page{
+		Time:                    h.now(),
+		ManagementState:         spec.ManagementState,
+		OperationProfile:        operationprofile.Active(),
+		Backpressure:            backpressure.Active(),
+		Quiescing:               teardown.Quiescing(),
+		RolloutQueued:           rolloutlease.Queued(),
+		LatestAvailableRevision: status.LatestAvailableRevision,
+		Nodes:                   status.NodeStatuses,
+		Conditions:              conditions,
+		Decisions:               decisions,
+		DecisionLogEnabled:      h.decisionLog != nil,
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := pageTemplate.Execute(w, data); err != nil {
+		klog.Warningf("failed to write the status page: %v", err)
+	}
+}
+
+// abnormal tells whether the condition reports a problem, by the suffix convention of the operator conditions.
+func abnormal(condition operatorv1.OperatorCondition) bool {
+	switch {
+	case strings.HasSuffix(condition.Type, "Degraded"):
+		return condition.Status == operatorv1.ConditionTrue
+	case strings.HasSuffix(condition.Type, "Available"), strings.HasSuffix(condition.Type, "Upgradeable"):
+		return condition.Status == operatorv1.ConditionFalse
+	}
+	return false
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+var pageTemplate = template.Must(template.New("status").Funcs(template.FuncMap{
+	"abnormal":   abnormal,
+	"formatTime": formatTime,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>kube-controller-manager-operator</title>
+<style>
+body { font-family: monospace; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #999; padding: 2px 6px; text-align: left; vertical-align: top; }
+tr.abnormal { background: #fdd; }
+</style>
+</head>
+<body>
+<h1>kube-controller-manager-operator</h1>
+<p>as of {{ formatTime .Time }}</p>
+
+<h2>Operator</h2>
+<table>
+<tr><th>management state</th><td>{{ .ManagementState }}</td></tr>
+<tr><th>operation profile</th><td>{{ .OperationProfile }}</td></tr>
+<tr><th>backpressure</th><td>{{ .Backpressure }}</td></tr>
+<tr><th>quiesced for teardown</th><td>{{ .Quiescing }}</td></tr>
+<tr><th>rollout queued</th><td>{{ .RolloutQueued }}</td></tr>
+</table>
+
+<h2>Revisions</h2>
+<p>latest available revision {{ .LatestAvailableRevision }}</p>
+<table>
+<tr><th>node</th><th>current</th><th>target</th><th>last failed</th><th>last failed time</th></tr>
+{{- range .Nodes }}
+<tr{{ if and .TargetRevision (ne .CurrentRevision .TargetRevision) }} class="abnormal"{{ end }}><td>{{ .NodeName }}</td><td>{{ .CurrentRevision }}</td><td>{{ .TargetRevision }}</td><td>{{ .LastFailedRevision }}</td><td>{{ if .LastFailedTime }}{{ formatTime .LastFailedTime.Time }}{{ else }}-{{ end }}</td></tr>
+{{- end }}
+</table>
+
+<h2>Conditions</h2>
+<table>
+<tr><th>type</th><th>status</th><th>reason</th><th>last transition</th><th>message</th></tr>
+{{- range .Conditions }}
+<tr{{ if abnormal . }} class="abnormal"{{ end }}><td>{{ .Type }}</td><td>{{ .Status }}</td><td>{{ .Reason }}</td><td>{{ formatTime .LastTransitionTime.Time }}</td><td>{{ .Message }}</td></tr>
+{{- end }}
+</table>
+
+<h2>Recent decisions</h2>
+{{- if not .DecisionLogEnabled }}
+<p>the decision log is disabled, DECISION_LOG_SIZE enables it</p>
+{{- else }}
+<table>
+<tr><th>time</th><th>controller</th><th>action</th><th>reasons</th></tr>
+{{- range .Decisions }}
+<tr><td>{{ formatTime .Time }}</td><td>{{ .Controller }}</td><td>{{ .Action }}</td><td>{{ range $i, $reason := .Reasons }}{{ if $i }}<br>{{ end }}{{ $reason }}{{ end }}</td></tr>
+{{- end }}
+</table>
+{{- end }}
+</body>
+</html>
+`))
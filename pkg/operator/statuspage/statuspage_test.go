@@ -0,0 +1,112 @@
+package statuspage
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/decisionlog"
+)
+
+func TestListenAddressFromEnv(t *testing.T) {
+	defer os.Unsetenv(listenAddressEnv)
+
+	testCases := []struct {
+		address     string
+		expectedErr bool
+	}{
+		{address: ""},
+		{address: "127.0.0.1:10270"},
+		{address: "[::1]:10270"},
+		{address: "localhost:10270"},
+		{address: "0.0.0.0:10270", expectedErr: true},
+		{address: ":10270", expectedErr: true},
+		{address: "10.0.0.1:10270", expectedErr: true},
+		{address: "127.0.0.1", expectedErr: true},
+	}
+	for _, tc := range testCases {
+		os.Setenv(listenAddressEnv, tc.address)
+		address, err := ListenAddressFromEnv()
+		if tc.expectedErr {
+			if err == nil {
+				t.Errorf("%q: expected an error", tc.address)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %v", tc.address, err)
+		}
+		if address != tc.address {
+			t.Errorf("expected %q, got %q", tc.address, address)
+		}
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	transition := metav1.NewTime(time.Date(2026, 10, 15, 10, 0, 0, 0, time.UTC))
+	operatorClient := v1helpers.NewFakeStaticPodOperatorClient(
+		&operatorv1.StaticPodOperatorSpec{OperatorSpec: operatorv1.OperatorSpec{ManagementState: operatorv1.Managed}},
+		&operatorv1.StaticPodOperatorStatus{
+			OperatorStatus: operatorv1.OperatorStatus{Conditions: []operatorv1.OperatorCondition{
+				{Type: "StaticPodsAvailable", Status: operatorv1.ConditionTrue, LastTransitionTime: transition},
+				{Type: "NodeInstallerDegraded", Status: operatorv1.ConditionTrue, Reason: "InstallerPodFailed", Message: "installer <7> failed", LastTransitionTime: transition},
+			}},
+			LatestAvailableRevision: 7,
+			NodeStatuses: []operatorv1.NodeStatus{
+				{NodeName: "master-0", CurrentRevision: 7},
+				{NodeName: "master-1", CurrentRevision: 6, TargetRevision: 7, LastFailedRevision: 7, LastFailedTime: &transition},
+			},
+		},
+		nil,
+		nil,
+	)
+	decisionLog := decisionlog.New(10)
+	decisionLog.Record("TargetConfigController", "update configmap/config", "extendedArguments.v changed")
+
+	handler := NewHandler(operatorClient, decisionLog)
+	handler.now = func() time.Time { return transition.Add(time.Minute) }
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", recorder.Code)
+	}
+	body := recorder.Body.String()
+	for _, expected := range []string{
+		"<p>as of 2026-10-15T10:01:00Z</p>",
+		"<tr><th>management state</th><td>Managed</td></tr>",
+		"<p>latest available revision 7</p>",
+		`<tr class="abnormal"><td>master-1</td><td>6</td><td>7</td><td>7</td><td>2026-10-15T10:00:00Z</td></tr>`,
+		// the conditions are sorted by type, the messages escaped
+		`<tr class="abnormal"><td>NodeInstallerDegraded</td><td>True</td><td>InstallerPodFailed</td><td>2026-10-15T10:00:00Z</td><td>installer &lt;7&gt; failed</td></tr>`,
+		"<tr><td>StaticPodsAvailable</td><td>True</td>",
+		"<td>TargetConfigController</td><td>update configmap/config</td><td>extendedArguments.v changed</td>",
+	} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("expected the page to contain %q, got:\n%s", expected, body)
+		}
+	}
+	if strings.Index(body, "NodeInstallerDegraded") > strings.Index(body, "StaticPodsAvailable") {
+		t.Errorf("expected the conditions sorted by type")
+	}
+
+	recorder = httptest.NewRecorder()
+	NewHandler(operatorClient, nil).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	if !strings.Contains(recorder.Body.String(), "the decision log is disabled") {
+		t.Errorf("expected the page to mention the disabled decision log")
+	}
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/other", nil))
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", recorder.Code)
+	}
+}
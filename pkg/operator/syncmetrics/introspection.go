@@ -0,0 +1,155 @@
+package syncmetrics
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	// the workqueues of the controllers report their depth through the metrics provider it registers
+	_ "k8s.io/component-base/metrics/prometheus/workqueue"
+	"k8s.io/klog/v2"
+)
+
+// ControllerStatus is what the operator knows about a controller: the state of its queue, reported by the workqueue
+// metrics of every controller, and the state of its syncs, recorded for the syncs wrapped by Timed.
+type ControllerStatus struct {
+	Name string `json:"name"`
+	// QueueLength is the number of keys waiting for a sync.
+	QueueLength *float64 `json:"queueLength,omitempty"`
+	// LongestRunningSyncSeconds is how long the oldest running sync has been running.
+	LongestRunningSyncSeconds *float64 `json:"longestRunningSyncSeconds,omitempty"`
+	// Processing are the keys being synced, with the start of their sync.
+	Processing map[string]time.Time `json:"processing,omitempty"`
+	// LastError is the error of the latest failed sync.
+	LastError     string     `json:"lastError,omitempty"`
+	LastErrorTime *time.Time `json:"lastErrorTime,omitempty"`
+	// LastSuccessfulSyncTime is the end of the latest sync which succeeded.
+	LastSuccessfulSyncTime *time.Time `json:"lastSuccessfulSyncTime,omitempty"`
+}
+
+// syncs holds the state of the syncs wrapped by Timed, by controller name.
+var syncs = struct {
+	sync.Mutex
+	controllers map[string]*ControllerStatus
+}{controllers: map[string]*ControllerStatus{}}
+
+func startSync(controller, key string, now time.Time) {
+	syncs.Lock()
+	defer syncs.Unlock()
+	status := controllerStatus(controller)
+	if status.Processing == nil {
+		status.Processing = map[string]time.Time{}
+	}
+	status.Processing[key] = now
+}
+
+// endSync records the result of the sync of the key. A requeue is neither a success nor a failure.
+func endSync(controller, key string, err error, requeued bool, now time.Time) {
+	syncs.Lock()
+	defer syncs.Unlock()
+	status := controllerStatus(controller)
+	delete(status.Processing, key)
+	switch {
+	case requeued:
+	case err != nil:
+		status.LastError = err.Error()
+		status.LastErrorTime = &now
+	default:
+		status.LastSuccessfulSyncTime = &now
+	}
+}
+
+func controllerStatus(controller string) *ControllerStatus {
+	status, ok := syncs.controllers[controller]
+	if !ok {
+		status = &ControllerStatus{Name: controller}
+		syncs.controllers[controller] = status
+	}
+	return status
+}
+
+// Controllers returns the status of every controller, sorted by name. The controllers the gatherer reports no
+// workqueue of are the ones not started yet.
+func Controllers(gatherer metrics.Gatherer) ([]ControllerStatus, error) {
+	families, err := gatherer.Gather()
+	if err != nil {
+		return nil, err
+	}
+
+	syncs.Lock()
+	statuses := map[string]*ControllerStatus{}
+	for name, status := range syncs.controllers {
+		copied := *status
+		copied.Processing = map[string]time.Time{}
+		for key, since := range status.Processing {
+			copied.Processing[key] = since
+		}
+		statuses[name] = &copied
+	}
+	syncs.Unlock()
+
+	for _, family := range families {
+		var set func(status *ControllerStatus, value float64)
+		switch family.GetName() {
+		case "workqueue_depth":
+			set = func(status *ControllerStatus, value float64) { status.QueueLength = &value }
+		case "workqueue_longest_running_processor_seconds":
+			set = func(status *ControllerStatus, value float64) { status.LongestRunningSyncSeconds = &value }
+		default:
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			name := queueName(metric)
+			if len(name) == 0 {
+				continue
+			}
+			status, ok := statuses[name]
+			if !ok {
+				status = &ControllerStatus{Name: name}
+				statuses[name] = status
+			}
+			set(status, metric.GetGauge().GetValue())
+		}
+	}
+
+	ret := make([]ControllerStatus, 0, len(statuses))
+	for _, status := range statuses {
+		ret = append(ret, *status)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Name < ret[j].Name })
+	return ret, nil
+}
+
+func queueName(metric *dto.Metric) string {
+	for _, label := range metric.GetLabel() {
+		if label.GetName() == "name" {
+			return label.GetValue()
+		}
+	}
+	return ""
+}
+
+// ControllersHandler serves the status of every controller as JSON, to find a wedged controller at once.
+func ControllersHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		controllers, err := Controllers(legacyregistry.DefaultGatherer)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		data, err := json.MarshalIndent(controllers, "", "  ")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(data); err != nil {
+			klog.Warningf("failed to write the controller statuses: %v", err)
+		}
+	})
+}
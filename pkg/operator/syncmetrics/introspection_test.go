@@ -0,0 +1,60 @@
+package syncmetrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"k8s.io/component-base/metrics"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+)
+
+func TestControllers(t *testing.T) {
+	registry := metrics.NewKubeRegistry()
+	depth := metrics.NewGaugeVec(&metrics.GaugeOpts{Subsystem: "workqueue", Name: "depth"}, []string{"name"})
+	registry.MustRegister(depth)
+	depth.WithLabelValues("IntrospectedController").Set(3)
+	depth.WithLabelValues("UnwrappedController").Set(1)
+
+	syncErr := errors.New("sync failed")
+	var during []ControllerStatus
+	sync := Timed("IntrospectedController", func(ctx context.Context, syncCtx factory.SyncContext) error {
+		var err error
+		during, err = Controllers(registry)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return syncErr
+	})
+	_ = sync(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("test")))
+
+	find := func(statuses []ControllerStatus, name string) *ControllerStatus {
+		for i := range statuses {
+			if statuses[i].Name == name {
+				return &statuses[i]
+			}
+		}
+		t.Fatalf("expected the status of %s in %v", name, statuses)
+		return nil
+	}
+	if processing := find(during, "IntrospectedController").Processing; len(processing) != 1 {
+		t.Errorf("expected the key being synced, got %v", processing)
+	}
+
+	after, err := Controllers(registry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	introspected := find(after, "IntrospectedController")
+	if len(introspected.Processing) != 0 || introspected.LastError != syncErr.Error() || introspected.LastErrorTime == nil || introspected.LastSuccessfulSyncTime != nil {
+		t.Errorf("expected the failed sync recorded, got %+v", introspected)
+	}
+	if introspected.QueueLength == nil || *introspected.QueueLength != 3 {
+		t.Errorf("expected the queue length 3, got %v", introspected.QueueLength)
+	}
+	if unwrapped := find(after, "UnwrappedController"); unwrapped.QueueLength == nil || *unwrapped.QueueLength != 1 {
+		t.Errorf("expected the queue length of controllers with unwrapped syncs, got %+v", unwrapped)
+	}
+}
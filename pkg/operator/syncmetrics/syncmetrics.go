@@ -0,0 +1,48 @@
+package syncmetrics
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/component-base/metrics"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatormetrics"
+)
+
+var syncDuration = metrics.NewHistogramVec(
+	&metrics.HistogramOpts{
+		Name:           "kcm_operator_sync_duration_seconds",
+		Help:           "Duration of the sync loops of the operator controllers.",
+		Buckets:        metrics.ExponentialBuckets(0.005, 2, 14),
+		StabilityLevel: metrics.ALPHA,
+	},
+	[]string{"controller"},
+)
+
+func init() {
+	operatormetrics.MustRegister(syncDuration)
+}
+
+// Timed wraps the sync function to record its duration and its result under the given controller name, and to trace
+// it when tracing is enabled.
+func Timed(controller string, sync factory.SyncFunc) factory.SyncFunc {
+	return func(ctx context.Context, syncCtx factory.SyncContext) (err error) {
+		start := time.Now()
+		key := syncCtx.QueueKey()
+		startSync(controller, key, start)
+		ctx, span := StartSpan(ctx, controller+".sync")
+		defer func() {
+			syncDuration.WithLabelValues(controller).Observe(time.Since(start).Seconds())
+			endSync(controller, key, err, err == factory.SyntheticRequeueError, time.Now())
+			if err == factory.SyntheticRequeueError {
+				// a requeue is no failure of the sync
+				EndSpan(span, nil)
+				return
+			}
+			EndSpan(span, err)
+		}()
+		return sync(ctx, syncCtx)
+	}
+}
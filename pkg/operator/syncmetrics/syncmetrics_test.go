@@ -0,0 +1,68 @@
+package syncmetrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+)
+
+func TestTimed(t *testing.T) {
+	syncErr := errors.New("sync failed")
+	calls := 0
+	sync := Timed("test", func(ctx context.Context, syncCtx factory.SyncContext) error {
+		calls++
+		return syncErr
+	})
+
+	err := sync(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("test")))
+	if err != syncErr {
+		t.Errorf("expected the sync error to be returned, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected the sync to be called once, got %d", calls)
+	}
+}
+
+type endedSpans struct {
+	spans []sdktrace.ReadOnlySpan
+}
+
+func (e *endedSpans) OnStart(context.Context, sdktrace.ReadWriteSpan) {}
+func (e *endedSpans) OnEnd(s sdktrace.ReadOnlySpan)                   { e.spans = append(e.spans, s) }
+func (e *endedSpans) Shutdown(context.Context) error                  { return nil }
+func (e *endedSpans) ForceFlush(context.Context) error                { return nil }
+
+func TestTimedTraces(t *testing.T) {
+	ended := &endedSpans{}
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(ended)))
+	defer otel.SetTracerProvider(trace.NewNoopTracerProvider())
+
+	for _, syncErr := range []error{errors.New("sync failed"), factory.SyntheticRequeueError, nil} {
+		_ = Timed("test", func(ctx context.Context, syncCtx factory.SyncContext) error {
+			_, span := StartSpan(ctx, "step")
+			EndSpan(span, nil)
+			return syncErr
+		})(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("test")))
+	}
+
+	if len(ended.spans) != 6 {
+		t.Fatalf("expected 6 spans, got %d", len(ended.spans))
+	}
+	for i, expected := range []codes.Code{codes.Error, codes.Unset, codes.Unset} {
+		step, sync := ended.spans[2*i], ended.spans[2*i+1]
+		if sync.Name() != "test.sync" || step.Parent().SpanID() != sync.SpanContext().SpanID() {
+			t.Errorf("expected step to be a child of test.sync, got %s under %s", step.Name(), sync.Name())
+		}
+		if sync.StatusCode() != expected {
+			t.Errorf("expected status %v, got %v", expected, sync.StatusCode())
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package syncmetrics
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpgrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/semconv"
+	"go.opentelemetry.io/otel/trace"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/component-base/traces"
+)
+
+const tracerName = "cluster-kube-controller-manager-operator"
+
+// EnableTracing exports the spans of the syncs to the OTLP collector at the endpoint and makes the clients built from
+// the configs propagate them, so that the apiserver calls of a slow sync show up in its trace. Without it the spans
+// go to the no-op provider.
+func EnableTracing(ctx context.Context, endpoint string, samplingRatePerMillion int, configs ...*rest.Config) {
+	provider := traces.NewProvider(ctx,
+		sdktrace.TraceIDRatioBased(float64(samplingRatePerMillion)/float64(1000000)),
+		[]resource.Option{resource.WithAttributes(semconv.ServiceNameKey.String(tracerName))},
+		otlpgrpc.WithEndpoint(endpoint),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(traces.Propagators())
+	for _, config := range configs {
+		config.Wrap(traces.WrapperFor(&provider))
+	}
+}
+
+// StartSpan starts a span for a step of a sync, it is a child of the span of the sync in the context.
+func StartSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name)
+}
+
+// EndSpan records the error of the step, if any, and ends its span.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,70 @@
+package targetconfigcontroller
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// ConfigChecksumEnv is the environment variable of the kube-controller-manager container holding the checksum of
+	// its config.yaml.
+	ConfigChecksumEnv = "KCM_CONFIG_CHECKSUM"
+	// ConfigRevisionEnv is the environment variable of the kube-controller-manager container holding the revision of
+	// the static pod, from its revision label.
+	ConfigRevisionEnv = "KCM_CONFIG_REVISION"
+)
+
+// configIdentityConfig exposes the identity of the config to the kube-controller-manager container, so that its logs
+// and core dumps can be correlated with the revision and the config they were produced with. It is read from the
+// configIdentity stanza of the operator's UnsupportedConfigOverrides:
+//
+//	unsupportedConfigOverrides:
+//	  configIdentity:
+//	    enabled: true
+type configIdentityConfig struct {
+	Enabled bool `json:"enabled"`
+}
+
+// getConfigIdentityConfig reads the config identity override, it is disabled when the stanza is missing.
+func getConfigIdentityConfig(unsupportedConfigOverrides []byte) (configIdentityConfig, error) {
+	if len(unsupportedConfigOverrides) == 0 {
+		return configIdentityConfig{}, nil
+	}
+	overrides := struct {
+		ConfigIdentity configIdentityConfig `json:"configIdentity"`
+	}{}
+	if err := json.Unmarshal(unsupportedConfigOverrides, &overrides); err != nil {
+		return configIdentityConfig{}, fmt.Errorf("failed to load configIdentity from UnsupportedConfigOverride: %v", err)
+	}
+	return overrides.ConfigIdentity, nil
+}
+
+// applyTo sets the checksum of the config and the revision of the pod in the environment of the kube-controller-manager
+// container. The revision is only known once the installer substituted it in the revision label, the downward API
+// reads it from there.
+func (c configIdentityConfig) applyTo(pod *corev1.Pod, config string) {
+	if !c.Enabled {
+		return
+	}
+	for i := range pod.Spec.Containers {
+		if pod.Spec.Containers[i].Name != "kube-controller-manager" {
+			continue
+		}
+		pod.Spec.Containers[i].Env = append(pod.Spec.Containers[i].Env,
+			corev1.EnvVar{Name: ConfigChecksumEnv, Value: ConfigChecksum(config)},
+			corev1.EnvVar{Name: ConfigRevisionEnv, ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.labels['revision']"},
+			}},
+		)
+	}
+}
+
+// ConfigChecksum returns the sha256 checksum of the config.yaml of kube-controller-manager.
+func ConfigChecksum(config string) string {
+	checksum := sha256.Sum256([]byte(config))
+	return hex.EncodeToString(checksum[:])
+}
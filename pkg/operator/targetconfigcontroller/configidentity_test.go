@@ -0,0 +1,64 @@
+package targetconfigcontroller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestGetConfigIdentityConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		overrides   string
+		expected    configIdentityConfig
+		expectedErr bool
+	}{
+		{
+			name: "no overrides",
+		},
+		{
+			name:      "other overrides",
+			overrides: `{"extendedArguments":{"v":["4"]}}`,
+		},
+		{
+			name:      "enabled",
+			overrides: `{"configIdentity":{"enabled":true}}`,
+			expected:  configIdentityConfig{Enabled: true},
+		},
+		{
+			name:        "invalid",
+			overrides:   `{"configIdentity":{"enabled":"yes"}}`,
+			expectedErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config, err := getConfigIdentityConfig([]byte(test.overrides))
+			if test.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, config)
+		})
+	}
+}
+
+func TestConfigIdentityConfigApplyTo(t *testing.T) {
+	newPod := func() *corev1.Pod {
+		return &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "kube-controller-manager"}, {Name: "cluster-policy-controller"}}}}
+	}
+
+	pod := newPod()
+	configIdentityConfig{}.applyTo(pod, "apiVersion: kubecontrolplane.config.openshift.io/v1")
+	assert.Equal(t, newPod(), pod, "disabled")
+
+	configIdentityConfig{Enabled: true}.applyTo(pod, "apiVersion: kubecontrolplane.config.openshift.io/v1")
+	assert.Equal(t, []corev1.EnvVar{
+		{Name: ConfigChecksumEnv, Value: "343fe4edfdee0545009592ee6e5f6f52a9bb6ebc43f4b469b75ad2327c4409a2"},
+		{Name: ConfigRevisionEnv, ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.labels['revision']"}}},
+	}, pod.Spec.Containers[0].Env)
+	assert.Empty(t, pod.Spec.Containers[1].Env, "only kube-controller-manager reads the config")
+}
@@ -0,0 +1,168 @@
+package targetconfigcontroller
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	kubecontrolplanev1 "github.com/openshift/api/kubecontrolplane/v1"
+)
+
+// OperatorOverrides are keys of the unsupported config overrides read by the operator itself instead of being
+// merged into the kube-controller-manager config.
+var OperatorOverrides = sets.NewString(
+	"shutdown",
+	"configidentity",
+	"zoneeviction",
+	"podeviction",
+	"contentionprofiling",
+	"leadertakeover",
+	"resourcesync",
+	"degradedseverity",
+	"enabledeprecatedandremovedservicecakeyuntilnextrelease_thismakesclusterimpossibletoupgrade",
+)
+
+// validateConfigLayers checks the observed config and the unsupported config overrides before they are merged, so
+// that the error names the layer and the argument at fault rather than failing the merge with a bare decoding error.
+// The observed config carries the stanzas of the config observers besides the schema, only its extendedArguments
+// are checked.
+func validateConfigLayers(observedConfig, unsupportedConfigOverrides []byte) error {
+	if err := validateExtendedArguments("observedConfig", observedConfig); err != nil {
+		return err
+	}
+	return ValidateConfigOverrides(unsupportedConfigOverrides)
+}
+
+// ValidateConfigOverrides checks the unsupported config overrides, without the keys read by the operator, against
+// the KubeControllerManagerConfig schema. Unknown fields are rejected, the merge would prune them silently.
+func ValidateConfigOverrides(unsupportedConfigOverrides []byte) error {
+	overrides, err := configLayer(unsupportedConfigOverrides)
+	if err != nil {
+		return fmt.Errorf("unsupportedConfigOverrides are not an object: %v", err)
+	}
+	if len(overrides) == 0 {
+		return nil
+	}
+	if err := validateExtendedArguments("unsupportedConfigOverrides", unsupportedConfigOverrides); err != nil {
+		return err
+	}
+	for key := range overrides {
+		if OperatorOverrides.Has(strings.ToLower(key)) {
+			delete(overrides, key)
+		}
+	}
+	if err := strictDecode(overrides); err != nil {
+		return fmt.Errorf("unsupportedConfigOverrides do not match the KubeControllerManagerConfig schema: %v", err)
+	}
+	return nil
+}
+
+// validateRenderedConfig checks the rendered config.yaml against the KubeControllerManagerConfig schema before it is
+// published, a revision with a config kube-controller-manager cannot load would crashloop on every node it reaches.
+func validateRenderedConfig(configYAML []byte) error {
+	config, err := configLayer(configYAML)
+	if err != nil {
+		return fmt.Errorf("the rendered config.yaml is not an object: %v", err)
+	}
+	if err := validateExtendedArguments("config.yaml", configYAML); err != nil {
+		return err
+	}
+	if err := strictDecode(config); err != nil {
+		return fmt.Errorf("the rendered config.yaml does not match the KubeControllerManagerConfig schema: %v", err)
+	}
+	return nil
+}
+
+// validateExtendedArguments checks that the extendedArguments of the config layer map every flag to a list of
+// strings, the errors name the path of the offending value within the layer.
+func validateExtendedArguments(layerName string, layer []byte) error {
+	config, err := configLayer(layer)
+	if err != nil {
+		return fmt.Errorf("%s is not an object: %v", layerName, err)
+	}
+	value, ok := config["extendedArguments"]
+	if !ok || value == nil {
+		return nil
+	}
+	arguments, ok := value.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("%s.extendedArguments must map flag names to lists of strings, got %s", layerName, jsonType(value))
+	}
+	names := make([]string, 0, len(arguments))
+	for name := range arguments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		// a null removes the flag set by a lower layer
+		if arguments[name] == nil {
+			continue
+		}
+		values, ok := arguments[name].([]interface{})
+		if !ok {
+			return fmt.Errorf("%s.extendedArguments.%s must be a list of strings, got %s", layerName, name, jsonType(arguments[name]))
+		}
+		for i, value := range values {
+			if _, ok := value.(string); !ok {
+				return fmt.Errorf("%s.extendedArguments.%s[%d] must be a string, got %s %s", layerName, name, i, jsonType(value), jsonValue(value))
+			}
+		}
+	}
+	return nil
+}
+
+// configLayer decodes a config layer in JSON or YAML, an empty layer is returned as nil.
+func configLayer(layer []byte) (map[string]interface{}, error) {
+	if len(bytes.TrimSpace(layer)) == 0 {
+		return nil, nil
+	}
+	raw, err := yaml.YAMLToJSON(layer)
+	if err != nil {
+		return nil, err
+	}
+	config := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func strictDecode(config map[string]interface{}) error {
+	raw, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(&kubecontrolplanev1.KubeControllerManagerConfig{})
+}
+
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "list"
+	default:
+		return "object"
+	}
+}
+
+func jsonValue(value interface{}) string {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(raw)
+}
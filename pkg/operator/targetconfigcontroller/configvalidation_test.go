@@ -0,0 +1,63 @@
+package targetconfigcontroller
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMergeKubeControllerManagerConfigValidation(t *testing.T) {
+	tests := []struct {
+		name           string
+		observedConfig string
+		overrides      string
+		expectedErr    string
+	}{
+		{
+			name:           "valid layers",
+			observedConfig: `{"extendedArguments":{"cluster-name":["foo"]},"targetconfigcontroller":{"proxy":{"HTTPS_PROXY":"https://proxy"}}}`,
+			overrides:      `{"extendedArguments":{"node-monitor-grace-period":["50s"],"cluster-name":null},"shutdown":{"preStopSleepSeconds":10}}`,
+		},
+		{
+			name:        "unknown field in the overrides",
+			overrides:   `{"extendedArgument":{"node-monitor-grace-period":["50s"]}}`,
+			expectedErr: `unsupportedConfigOverrides do not match the KubeControllerManagerConfig schema: json: unknown field "extendedArgument"`,
+		},
+		{
+			name:        "argument not a list",
+			overrides:   `{"extendedArguments":{"node-monitor-grace-period":"50s"}}`,
+			expectedErr: "unsupportedConfigOverrides.extendedArguments.node-monitor-grace-period must be a list of strings, got string",
+		},
+		{
+			name:        "argument value not a string",
+			overrides:   `{"extendedArguments":{"concurrent-deployment-syncs":[5]}}`,
+			expectedErr: "unsupportedConfigOverrides.extendedArguments.concurrent-deployment-syncs[0] must be a string, got number 5",
+		},
+		{
+			name:        "extendedArguments not a map",
+			overrides:   `{"extendedArguments":["--node-monitor-grace-period=50s"]}`,
+			expectedErr: "unsupportedConfigOverrides.extendedArguments must map flag names to lists of strings, got list",
+		},
+		{
+			name:           "observed argument value not a string",
+			observedConfig: `{"extendedArguments":{"allocate-node-cidrs":[true]}}`,
+			expectedErr:    "observedConfig.extendedArguments.allocate-node-cidrs[0] must be a string, got boolean true",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			configMap, err := MergeKubeControllerManagerConfig([]byte(test.observedConfig), []byte(test.overrides))
+			if len(test.expectedErr) == 0 {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !strings.Contains(configMap.Data["config.yaml"], `"node-monitor-grace-period":["50s"]`) {
+					t.Errorf("expected the overrides rendered, got %s", configMap.Data["config.yaml"])
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), test.expectedErr) {
+				t.Fatalf("expected error %q, got %v", test.expectedErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package targetconfigcontroller
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MaxContentionProfilingWindow bounds how long kube-controller-manager runs with contention profiling, the block
+// profile records every blocking event and slows the process down.
+const MaxContentionProfilingWindow = 2 * time.Hour
+
+// contentionProfilingConfig turns on the contention profiling of kube-controller-manager for a limited window.
+// It is read from the contentionProfiling stanza of the operator's UnsupportedConfigOverrides:
+//
+//	unsupportedConfigOverrides:
+//	  contentionProfiling:
+//	    until: "2026-10-15T12:00:00Z"
+//
+// A revision with --contention-profiling is rolled out until then, and one without it once the window closed.
+type contentionProfilingConfig struct {
+	Until *metav1.Time `json:"until,omitempty"`
+}
+
+// ContentionProfilingUntil validates the contentionProfiling stanza of the overrides and returns the end of its
+// window, which may have passed already. It returns nil when the stanza is missing.
+func ContentionProfilingUntil(unsupportedConfigOverrides []byte, now time.Time) (*time.Time, error) {
+	if len(unsupportedConfigOverrides) == 0 {
+		return nil, nil
+	}
+	overrides := struct {
+		ContentionProfiling *contentionProfilingConfig `json:"contentionProfiling"`
+	}{}
+	if err := json.Unmarshal(unsupportedConfigOverrides, &overrides); err != nil {
+		return nil, fmt.Errorf("failed to load contentionProfiling from UnsupportedConfigOverride: %v", err)
+	}
+	config := overrides.ContentionProfiling
+	if config == nil {
+		return nil, nil
+	}
+	if config.Until == nil {
+		return nil, fmt.Errorf("contentionProfiling.until is required")
+	}
+	until := config.Until.Time
+	if until.After(now.Add(MaxContentionProfilingWindow)) {
+		return nil, fmt.Errorf("contentionProfiling.until must be at most %s ahead, got %s", MaxContentionProfilingWindow, until.UTC().Format(time.RFC3339))
+	}
+	return &until, nil
+}
+
+// contentionProfilingArguments returns the config layer turning on contention profiling while the window of the
+// contentionProfiling stanza is open, nil otherwise.
+func contentionProfilingArguments(unsupportedConfigOverrides []byte, now time.Time) ([]byte, error) {
+	until, err := ContentionProfilingUntil(unsupportedConfigOverrides, now)
+	if err != nil || until == nil || !now.Before(*until) {
+		return nil, err
+	}
+	return json.Marshal(map[string]interface{}{"extendedArguments": map[string][]string{
+		"profiling":            {"true"},
+		"contention-profiling": {"true"},
+	}})
+}
@@ -0,0 +1,60 @@
+package targetconfigcontroller
+
+import (
+	"testing"
+	"time"
+)
+
+func TestContentionProfilingArguments(t *testing.T) {
+	now := time.Date(2026, 10, 15, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name          string
+		overrides     string
+		expected      string
+		expectedError string
+	}{
+		{
+			name: "no overrides",
+		},
+		{
+			name:      "no stanza",
+			overrides: `{"extendedArguments":{"v":["4"]}}`,
+		},
+		{
+			name:      "window open",
+			overrides: `{"contentionProfiling":{"until":"2026-10-15T11:00:00Z"}}`,
+			expected:  `{"extendedArguments":{"contention-profiling":["true"],"profiling":["true"]}}`,
+		},
+		{
+			name:      "window closed",
+			overrides: `{"contentionProfiling":{"until":"2026-10-15T09:00:00Z"}}`,
+		},
+		{
+			name:          "window too long",
+			overrides:     `{"contentionProfiling":{"until":"2026-10-15T13:00:00Z"}}`,
+			expectedError: "contentionProfiling.until must be at most 2h0m0s ahead, got 2026-10-15T13:00:00Z",
+		},
+		{
+			name:          "no end",
+			overrides:     `{"contentionProfiling":{}}`,
+			expectedError: "contentionProfiling.until is required",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			config, err := contentionProfilingArguments([]byte(tc.overrides), now)
+			if len(tc.expectedError) > 0 {
+				if err == nil || err.Error() != tc.expectedError {
+					t.Fatalf("expected error %q, got %v", tc.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(config) != tc.expected {
+				t.Errorf("expected %s, got %s", tc.expected, config)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package targetconfigcontroller
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/util/cert"
+
+	configv1listers "github.com/openshift/client-go/config/listers/config/v1"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+const (
+	// ImageRegistryCABundleName is the revisioned configmap holding the CAs of the image registries, which the
+	// kube-controller-manager pod adds to its system trust bundle.
+	ImageRegistryCABundleName = "image-registry-ca-bundle"
+	// imageRegistryCAName is the configmap the image registry operator publishes the serving CA of the internal
+	// registry in, by registry host
+	imageRegistryCAName = "image-registry-ca"
+)
+
+// manageImageRegistryCABundle combines the CAs of the image registries into the image-registry-ca-bundle configmap:
+// the additionalTrustedCA of the cluster image config, by registry host in openshift-config, and the serving CA of the
+// internal registry. The controllers of kube-controller-manager reaching registries or image webhooks fail TLS in
+// custom CA environments otherwise. The configmap is removed when there are no such CAs.
+func manageImageRegistryCABundle(ctx context.Context, imageLister configv1listers.ImageLister, configMapLister corev1listers.ConfigMapLister, client corev1client.ConfigMapsGetter, recorder events.Recorder) (*corev1.ConfigMap, bool, error) {
+	sources := []*corev1.ConfigMap{}
+	image, err := imageLister.Get("cluster")
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return nil, false, err
+	case len(image.Spec.AdditionalTrustedCA.Name) > 0:
+		additionalTrustedCA, err := configMapLister.ConfigMaps(operatorclient.GlobalUserSpecifiedConfigNamespace).Get(image.Spec.AdditionalTrustedCA.Name)
+		if err != nil {
+			return nil, false, fmt.Errorf("additionalTrustedCA of image.config.openshift.io/cluster: %v", err)
+		}
+		sources = append(sources, additionalTrustedCA)
+	}
+	registryCA, err := configMapLister.ConfigMaps(operatorclient.GlobalMachineSpecifiedConfigNamespace).Get(imageRegistryCAName)
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return nil, false, err
+	default:
+		sources = append(sources, registryCA)
+	}
+
+	caBundle, err := combineImageRegistryCAs(sources)
+	if err != nil {
+		return nil, false, err
+	}
+	required := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: ImageRegistryCABundleName},
+	}
+	if len(caBundle) == 0 {
+		return resourceapply.DeleteConfigMap(ctx, client, recorder, required)
+	}
+	required.Data = map[string]string{"ca-bundle.crt": string(caBundle)}
+	return resourceapply.ApplyConfigMap(ctx, client, recorder, required)
+}
+
+// combineImageRegistryCAs returns the certificates of every key of the configmaps, which are named after the registry
+// hosts, without duplicates and in the order of the configmaps and their keys.
+func combineImageRegistryCAs(sources []*corev1.ConfigMap) ([]byte, error) {
+	combined := []*x509.Certificate{}
+	for _, source := range sources {
+		keys := make([]string, 0, len(source.Data))
+		for key := range source.Data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			certificates, err := cert.ParseCertsPEM([]byte(source.Data[key]))
+			if err != nil {
+				return nil, fmt.Errorf("configmap/%s key %q in %s: %v", source.Name, key, source.Namespace, err)
+			}
+		next:
+			for _, certificate := range certificates {
+				for _, existing := range combined {
+					if bytes.Equal(existing.Raw, certificate.Raw) {
+						continue next
+					}
+				}
+				combined = append(combined, certificate)
+			}
+		}
+	}
+	if len(combined) == 0 {
+		return nil, nil
+	}
+	return cert.EncodeCertificates(combined...)
+}
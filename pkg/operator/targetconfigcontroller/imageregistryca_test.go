@@ -0,0 +1,114 @@
+package targetconfigcontroller
+
+import (
+	"context"
+	"crypto/x509"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/cert"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configv1listers "github.com/openshift/client-go/config/listers/config/v1"
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+func TestManageImageRegistryCABundle(t *testing.T) {
+	customCA := string(makeCerts(t, time.Now(), time.Hour)["tls.crt"])
+	registryCA := string(makeCerts(t, time.Now(), time.Hour)["tls.crt"])
+
+	imageIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	configMapIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	kubeClient := fake.NewSimpleClientset()
+	manage := func() (*corev1.ConfigMap, bool, error) {
+		return manageImageRegistryCABundle(context.TODO(), configv1listers.NewImageLister(imageIndexer), corev1listers.NewConfigMapLister(configMapIndexer), kubeClient.CoreV1(), events.NewInMemoryRecorder("test"))
+	}
+
+	// without registry CAs there is no bundle
+	if _, modified, err := manage(); err != nil || modified {
+		t.Fatalf("expected no bundle, got %v %v", modified, err)
+	}
+
+	if err := imageIndexer.Add(&configv1.Image{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Spec:       configv1.ImageSpec{AdditionalTrustedCA: configv1.ConfigMapNameReference{Name: "registry-cas"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := manage(); err == nil {
+		t.Errorf("expected a missing additionalTrustedCA configmap to fail")
+	}
+
+	for _, configMap := range []*corev1.ConfigMap{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.GlobalUserSpecifiedConfigNamespace, Name: "registry-cas"},
+			// the same CA may be trusted for several registries
+			Data: map[string]string{"registry.example.com": customCA, "registry.example.com..5000": customCA},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.GlobalMachineSpecifiedConfigNamespace, Name: "image-registry-ca"},
+			Data:       map[string]string{"image-registry.openshift-image-registry.svc..5000": registryCA},
+		},
+	} {
+		if err := configMapIndexer.Add(configMap); err != nil {
+			t.Fatal(err)
+		}
+	}
+	configMap, modified, err := manage()
+	if err != nil || !modified {
+		t.Fatalf("expected the bundle created, got %v %v", modified, err)
+	}
+	certificates, err := cert.ParseCertsPEM([]byte(configMap.Data["ca-bundle.crt"]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := append(mustParseCerts(t, customCA), mustParseCerts(t, registryCA)...)
+	if len(certificates) != len(expected) {
+		t.Fatalf("expected %d certificates, got %d", len(expected), len(certificates))
+	}
+	for i := range expected {
+		if !certificates[i].Equal(expected[i]) {
+			t.Errorf("unexpected certificate %d: %s", i, certificates[i].Subject)
+		}
+	}
+	if _, modified, err := manage(); err != nil || modified {
+		t.Errorf("expected the bundle unchanged, got %v %v", modified, err)
+	}
+
+	// an invalid CA keeps the previous bundle
+	if err := configMapIndexer.Update(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.GlobalUserSpecifiedConfigNamespace, Name: "registry-cas"},
+		Data:       map[string]string{"registry.example.com": "not a certificate"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := manage(); err == nil {
+		t.Errorf("expected an invalid CA to fail")
+	}
+
+	// the bundle is removed with the last CA
+	if err := imageIndexer.Delete(&configv1.Image{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := configMapIndexer.Delete(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.GlobalMachineSpecifiedConfigNamespace, Name: "image-registry-ca"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, modified, err := manage(); err != nil || !modified {
+		t.Errorf("expected the bundle removed, got %v %v", modified, err)
+	}
+}
+
+func mustParseCerts(t *testing.T, pem string) []*x509.Certificate {
+	certificates, err := cert.ParseCertsPEM([]byte(pem))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return certificates
+}
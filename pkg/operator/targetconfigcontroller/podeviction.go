@@ -0,0 +1,107 @@
+package targetconfigcontroller
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+)
+
+const (
+	// defaultNodeMonitorGracePeriod is the kube-controller-manager default for how long a node may stop reporting
+	// before it is tainted unreachable.
+	defaultNodeMonitorGracePeriod = 40 * time.Second
+	// defaultTolerationSeconds is how long pods without tolerations of their own tolerate the unreachable and
+	// not-ready taints, added by the DefaultTolerationSeconds admission plugin of kube-apiserver.
+	defaultTolerationSeconds = 300 * time.Second
+	maxPodEvictionTimeout    = time.Hour
+)
+
+// podEvictionConfig sets the pod-eviction-timeout flag for workloads which depend on it, validated. It is read from
+// the podEviction stanza of the operator's UnsupportedConfigOverrides:
+//
+//	unsupportedConfigOverrides:
+//	  podEviction:
+//	    timeout: 10m
+//
+// The flag is deprecated but still honoured by kube-controller-manager 1.23 while the taint manager is disabled. The
+// taint manager evicts pods once their toleration of the unreachable taint runs out, the node monitor grace period is
+// not stretched to match the timeout: that would delay the NotReady status of the nodes for every consumer.
+type podEvictionConfig struct {
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+type podEvictionOverrides struct {
+	PodEviction       *podEvictionConfig  `json:"podEviction"`
+	ExtendedArguments map[string][]string `json:"extendedArguments"`
+}
+
+// getPodEvictionConfig validates the podEviction stanza of the overrides. It returns nil when the stanza is missing.
+func getPodEvictionConfig(unsupportedConfigOverrides []byte) (*podEvictionConfig, podEvictionOverrides, error) {
+	overrides := podEvictionOverrides{}
+	if len(unsupportedConfigOverrides) == 0 {
+		return nil, overrides, nil
+	}
+	if err := json.Unmarshal(unsupportedConfigOverrides, &overrides); err != nil {
+		return nil, overrides, fmt.Errorf("failed to load podEviction from UnsupportedConfigOverride: %v", err)
+	}
+	config := overrides.PodEviction
+	if config == nil || config.Timeout == nil {
+		return nil, overrides, nil
+	}
+	if _, found := overrides.ExtendedArguments["pod-eviction-timeout"]; found {
+		return nil, overrides, fmt.Errorf("podEviction.timeout and extendedArguments.pod-eviction-timeout cannot be set together")
+	}
+	if timeout := config.Timeout.Duration; timeout <= 0 || timeout > maxPodEvictionTimeout {
+		return nil, overrides, fmt.Errorf("podEviction.timeout must be positive and at most %s, got %s", maxPodEvictionTimeout, timeout)
+	}
+	return config, overrides, nil
+}
+
+// podEvictionArguments returns the config layer with the pod-eviction-timeout of the podEviction stanza of the
+// overrides, nil when the stanza is missing.
+func podEvictionArguments(unsupportedConfigOverrides []byte) ([]byte, error) {
+	config, _, err := getPodEvictionConfig(unsupportedConfigOverrides)
+	if err != nil || config == nil {
+		return nil, err
+	}
+	return json.Marshal(map[string]interface{}{"extendedArguments": map[string][]string{
+		"pod-eviction-timeout": {config.Timeout.Duration.String()},
+	}})
+}
+
+// podEvictionCondition summarizes when pods without tolerations of their own are evicted from a node which stopped
+// reporting.
+func podEvictionCondition(unsupportedConfigOverrides []byte) operatorv1.OperatorCondition {
+	condition := operatorv1.OperatorCondition{
+		Type:   "PodEvictionTimeoutCompatibility",
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+	config, overrides, err := getPodEvictionConfig(unsupportedConfigOverrides)
+	if err != nil {
+		condition.Reason = "InvalidPodEviction"
+		condition.Message = err.Error()
+		return condition
+	}
+	gracePeriod := defaultNodeMonitorGracePeriod
+	if values := overrides.ExtendedArguments["node-monitor-grace-period"]; len(values) == 1 {
+		if overridden, err := time.ParseDuration(values[0]); err == nil {
+			gracePeriod = overridden
+		}
+	}
+	tainted := fmt.Sprintf("pods are evicted %s after their node stops reporting: the node monitor grace period of %s and the default toleration of %s",
+		gracePeriod+defaultTolerationSeconds, gracePeriod, defaultTolerationSeconds)
+	if config == nil {
+		condition.Message = tainted
+		return condition
+	}
+	condition.Status = operatorv1.ConditionTrue
+	condition.Reason = "LegacyPodEvictionTimeout"
+	condition.Message = fmt.Sprintf("pod-eviction-timeout is set to %s, it applies only while the taint manager is disabled. With the taint manager %s",
+		config.Timeout.Duration, tainted)
+	return condition
+}
@@ -0,0 +1,74 @@
+package targetconfigcontroller
+
+import (
+	"strings"
+	"testing"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+)
+
+func TestPodEvictionArguments(t *testing.T) {
+	tests := []struct {
+		name           string
+		overrides      string
+		expected       string
+		expectedErr    bool
+		expectedStatus operatorv1.ConditionStatus
+		expectedMsg    string
+	}{
+		{
+			name:           "no overrides",
+			expectedStatus: operatorv1.ConditionFalse,
+			expectedMsg:    "pods are evicted 5m40s after their node stops reporting",
+		},
+		{
+			name:           "overridden grace period",
+			overrides:      `{"extendedArguments":{"node-monitor-grace-period":["50s"]}}`,
+			expectedStatus: operatorv1.ConditionFalse,
+			expectedMsg:    "pods are evicted 5m50s after their node stops reporting",
+		},
+		{
+			name:           "legacy timeout",
+			overrides:      `{"podEviction":{"timeout":"10m"}}`,
+			expected:       `{"extendedArguments":{"pod-eviction-timeout":["10m0s"]}}`,
+			expectedStatus: operatorv1.ConditionTrue,
+			expectedMsg:    "pod-eviction-timeout is set to 10m0s, it applies only while the taint manager is disabled. With the taint manager pods are evicted 5m40s after their node stops reporting",
+		},
+		{
+			name:           "legacy timeout with an overridden grace period",
+			overrides:      `{"podEviction":{"timeout":"1m"},"extendedArguments":{"node-monitor-grace-period":["50s"]}}`,
+			expected:       `{"extendedArguments":{"pod-eviction-timeout":["1m0s"]}}`,
+			expectedStatus: operatorv1.ConditionTrue,
+			expectedMsg:    "With the taint manager pods are evicted 5m50s after their node stops reporting",
+		},
+		{
+			name:           "timeout too long",
+			overrides:      `{"podEviction":{"timeout":"2h"}}`,
+			expectedErr:    true,
+			expectedStatus: operatorv1.ConditionFalse,
+			expectedMsg:    "podEviction.timeout must be positive and at most 1h0m0s, got 2h0m0s",
+		},
+		{
+			name:           "flag set as well",
+			overrides:      `{"podEviction":{"timeout":"10m"},"extendedArguments":{"pod-eviction-timeout":["5m"]}}`,
+			expectedErr:    true,
+			expectedStatus: operatorv1.ConditionFalse,
+			expectedMsg:    "cannot be set together",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := podEvictionArguments([]byte(test.overrides))
+			if (err != nil) != test.expectedErr {
+				t.Fatalf("expected error %v, got %v", test.expectedErr, err)
+			}
+			if string(got) != test.expected {
+				t.Errorf("expected %s, got %s", test.expected, got)
+			}
+			condition := podEvictionCondition([]byte(test.overrides))
+			if condition.Status != test.expectedStatus || !strings.Contains(condition.Message, test.expectedMsg) {
+				t.Errorf("expected %s %q, got %s %q", test.expectedStatus, test.expectedMsg, condition.Status, condition.Message)
+			}
+		})
+	}
+}
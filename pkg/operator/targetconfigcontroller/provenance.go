@@ -0,0 +1,140 @@
+package targetconfigcontroller
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/ghodss/yaml"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/bindata"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation/ownership"
+)
+
+// ProvenanceKey is the key of the config configmap telling where every extended argument of config.yaml comes from.
+const ProvenanceKey = "provenance.json"
+
+const (
+	// SourceDefault is the source of the arguments of the default config.
+	SourceDefault = "default"
+	// SourceObserved is the source of observed arguments whose observer is not known.
+	SourceObserved = "observer"
+	// SourceOverride is the source of the arguments set by the unsupported config overrides.
+	SourceOverride = "override"
+)
+
+// Provenance tells where the extended arguments of the kube-controller-manager config come from.
+type Provenance struct {
+	ExtendedArguments map[string]ArgumentProvenance `json:"extendedArguments"`
+}
+
+// ArgumentProvenance is the source of an extended argument: default, override, or observer/<name> for the
+// observer which set it, and the time its value or source last changed.
+type ArgumentProvenance struct {
+	Source      string      `json:"source"`
+	LastChanged metav1.Time `json:"lastChanged"`
+}
+
+// configProvenance returns the provenance document of the merged config. Arguments whose value and source are
+// unchanged from the existing config keep their last change time.
+func configProvenance(observedConfig, unsupportedConfigOverrides []byte, configOwnership *ownership.Ownership, required, existing *corev1.ConfigMap, now time.Time) (string, error) {
+	sources, err := extendedArgumentSources(observedConfig, unsupportedConfigOverrides, configOwnership, now)
+	if err != nil {
+		return "", err
+	}
+	arguments, err := extendedArguments([]byte(required.Data["config.yaml"]))
+	if err != nil {
+		return "", err
+	}
+
+	existingArguments := map[string][]string{}
+	existingProvenance := Provenance{}
+	if existing != nil {
+		// a broken or missing existing document only resets the change times
+		existingArguments, _ = extendedArguments([]byte(existing.Data["config.yaml"]))
+		_ = json.Unmarshal([]byte(existing.Data[ProvenanceKey]), &existingProvenance)
+	}
+
+	provenance := Provenance{ExtendedArguments: map[string]ArgumentProvenance{}}
+	for argument, value := range arguments {
+		current := ArgumentProvenance{Source: sources[argument], LastChanged: metav1.NewTime(now.UTC())}
+		if previous, ok := existingProvenance.ExtendedArguments[argument]; ok && previous.Source == current.Source && reflect.DeepEqual(existingArguments[argument], value) {
+			current.LastChanged = previous.LastChanged
+		}
+		provenance.ExtendedArguments[argument] = current
+	}
+	raw, err := json.Marshal(provenance)
+	if err != nil {
+		return "", err
+	}
+	return string(raw), nil
+}
+
+// extendedArgumentSources returns the source of every extended argument, in the order of MergeKubeControllerManagerConfig,
+// the last layer setting an argument wins.
+func extendedArgumentSources(observedConfig, unsupportedConfigOverrides []byte, configOwnership *ownership.Ownership, now time.Time) (map[string]string, error) {
+	zoneEvictionConfig, err := zoneEvictionArguments(unsupportedConfigOverrides)
+	if err != nil {
+		return nil, err
+	}
+	podEvictionConfig, err := podEvictionArguments(unsupportedConfigOverrides)
+	if err != nil {
+		return nil, err
+	}
+	contentionProfilingConfig, err := contentionProfilingArguments(unsupportedConfigOverrides, now)
+	if err != nil {
+		return nil, err
+	}
+	observedConfig, _, err = migrateRenamedArguments(observedConfig)
+	if err != nil {
+		return nil, err
+	}
+	unsupportedConfigOverrides, _, err = migrateRenamedArguments(unsupportedConfigOverrides)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := map[string]string{}
+	layers := []struct {
+		config []byte
+		source func(argument string) string
+	}{
+		{config: bindata.MustAsset("assets/config/defaultconfig.yaml"), source: func(string) string { return SourceDefault }},
+		{config: observedConfig, source: func(argument string) string {
+			if owner := configOwnership.Owner([]string{"extendedArguments", argument}); len(owner) > 0 {
+				return SourceObserved + "/" + owner
+			}
+			return SourceObserved
+		}},
+		{config: zoneEvictionConfig, source: func(string) string { return SourceOverride }},
+		{config: podEvictionConfig, source: func(string) string { return SourceOverride }},
+		{config: contentionProfilingConfig, source: func(string) string { return SourceOverride }},
+		{config: unsupportedConfigOverrides, source: func(string) string { return SourceOverride }},
+	}
+	for _, layer := range layers {
+		arguments, err := extendedArguments(layer.config)
+		if err != nil {
+			return nil, err
+		}
+		for argument := range arguments {
+			sources[argument] = layer.source(argument)
+		}
+	}
+	return sources, nil
+}
+
+func extendedArguments(config []byte) (map[string][]string, error) {
+	if len(config) == 0 {
+		return nil, nil
+	}
+	parsed := struct {
+		ExtendedArguments map[string][]string `json:"extendedArguments"`
+	}{}
+	if err := yaml.Unmarshal(config, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to read extendedArguments: %v", err)
+	}
+	return parsed.ExtendedArguments, nil
+}
@@ -0,0 +1,86 @@
+package targetconfigcontroller
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/library-go/pkg/operator/configobserver"
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation/ownership"
+)
+
+func TestConfigProvenance(t *testing.T) {
+	configOwnership := ownership.New()
+	noop := func(configobserver.Listers, events.Recorder, map[string]interface{}) (map[string]interface{}, []error) {
+		return nil, nil
+	}
+	configOwnership.Own("clustercidrs", noop, []string{"extendedArguments", "cluster-cidr"})
+
+	observedConfig := []byte(`{"extendedArguments":{"cluster-cidr":["10.128.0.0/14"],"cluster-name":["test"]}}`)
+	overrides := []byte(`{"extendedArguments":{"kube-api-qps":["200"]},"zoneEviction":{"nodeEvictionRate":0.2}}`)
+	earlier := time.Date(2022, 3, 1, 3, 0, 0, 0, time.UTC)
+	now := earlier.Add(time.Hour)
+
+	required, err := MergeKubeControllerManagerConfig(observedConfig, overrides)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := configProvenance(observedConfig, overrides, configOwnership, required, nil, earlier)
+	if err != nil {
+		t.Fatal(err)
+	}
+	provenance := Provenance{}
+	if err := json.Unmarshal([]byte(first), &provenance); err != nil {
+		t.Fatal(err)
+	}
+	for argument, expected := range map[string]string{
+		"cluster-cidr":       "observer/clustercidrs",
+		"cluster-name":       "observer",
+		"kube-api-qps":       "override",
+		"node-eviction-rate": "override",
+		"kube-api-burst":     "default",
+	} {
+		if got := provenance.ExtendedArguments[argument].Source; got != expected {
+			t.Errorf("%s: expected source %q, got %q", argument, expected, got)
+		}
+	}
+
+	// a changed override moves the change time of its argument only
+	existing := required.DeepCopy()
+	existing.Data[ProvenanceKey] = first
+	overrides = []byte(`{"extendedArguments":{"kube-api-qps":["250"]},"zoneEviction":{"nodeEvictionRate":0.2}}`)
+	required, err = MergeKubeControllerManagerConfig(observedConfig, overrides)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := configProvenance(observedConfig, overrides, configOwnership, required, existing, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	provenance = Provenance{}
+	if err := json.Unmarshal([]byte(second), &provenance); err != nil {
+		t.Fatal(err)
+	}
+	if got := provenance.ExtendedArguments["kube-api-qps"].LastChanged; !got.Time.Equal(now) {
+		t.Errorf("expected kube-api-qps to have changed at %v, got %v", now, got)
+	}
+	if got := provenance.ExtendedArguments["cluster-cidr"].LastChanged; !got.Time.Equal(earlier) {
+		t.Errorf("expected cluster-cidr to keep its change time %v, got %v", earlier, got)
+	}
+}
+
+func TestConfigProvenanceWithBrokenExisting(t *testing.T) {
+	required, err := MergeKubeControllerManagerConfig(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	existing := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config"}, Data: map[string]string{ProvenanceKey: "{"}}
+	if _, err := configProvenance(nil, nil, nil, required, existing, time.Now()); err != nil {
+		t.Errorf("expected a broken provenance to be replaced, got %v", err)
+	}
+}
@@ -0,0 +1,106 @@
+package targetconfigcontroller
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ghodss/yaml"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+)
+
+// renamedArgument is a kube-controller-manager flag upstream renamed. Config layers still using the old name are
+// migrated to the new one, so that an upgrade to the release which drops the old name does not crashloop.
+type renamedArgument struct {
+	old, new string
+	// removedIn is the kube minor version whose kube-controller-manager no longer accepts the old name. The shim is
+	// kept for one release after it, TestRenamedArgumentsExpire fails once it is due to be removed.
+	removedIn string
+}
+
+var renamedArguments = []renamedArgument{
+	{old: "experimental-cluster-signing-duration", new: "cluster-signing-duration", removedIn: "1.25"},
+}
+
+// migrateRenamedArguments returns the config layer with the renamed extendedArguments under their new name, and the
+// migrations it applied. A layer setting both names keeps the new one. Layers without renamed arguments are returned
+// unchanged.
+func migrateRenamedArguments(config []byte) ([]byte, []string, error) {
+	arguments, err := extendedArguments(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	found := false
+	for _, renamed := range renamedArguments {
+		if _, ok := arguments[renamed.old]; ok {
+			found = true
+		}
+	}
+	if !found {
+		return config, nil, nil
+	}
+
+	raw, err := yaml.YAMLToJSON(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	layer := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &layer); err != nil {
+		return nil, nil, err
+	}
+	extended := layer["extendedArguments"].(map[string]interface{})
+	applied := []string{}
+	for _, renamed := range renamedArguments {
+		value, ok := extended[renamed.old]
+		if !ok {
+			continue
+		}
+		delete(extended, renamed.old)
+		if _, ok := extended[renamed.new]; ok {
+			applied = append(applied, fmt.Sprintf("%s dropped in favor of %s", renamed.old, renamed.new))
+			continue
+		}
+		extended[renamed.new] = value
+		applied = append(applied, fmt.Sprintf("%s renamed to %s", renamed.old, renamed.new))
+	}
+	migrated, err := json.Marshal(layer)
+	if err != nil {
+		return nil, nil, err
+	}
+	return migrated, applied, nil
+}
+
+// renamedArgumentsCondition reports the renamed extendedArguments migrated at render time, for admins to update
+// their config before the shims are removed.
+func renamedArgumentsCondition(observedConfig, unsupportedConfigOverrides []byte) operatorv1.OperatorCondition {
+	condition := operatorv1.OperatorCondition{
+		Type:   "RenamedArgumentsMigrated",
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+	migrations := []string{}
+	for _, layer := range []struct {
+		name   string
+		config []byte
+	}{
+		{name: "observedConfig", config: observedConfig},
+		{name: "unsupportedConfigOverrides", config: unsupportedConfigOverrides},
+	} {
+		_, applied, err := migrateRenamedArguments(layer.config)
+		if err != nil {
+			condition.Reason = "InvalidConfig"
+			condition.Message = fmt.Sprintf("%s: %v", layer.name, err)
+			return condition
+		}
+		for _, migration := range applied {
+			migrations = append(migrations, fmt.Sprintf("%s in %s", migration, layer.name))
+		}
+	}
+	if len(migrations) > 0 {
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "LegacyArgumentNames"
+		condition.Message = fmt.Sprintf("extendedArguments use names kube-controller-manager no longer accepts, update them before the migration is removed: %s", strings.Join(migrations, ", "))
+	}
+	return condition
+}
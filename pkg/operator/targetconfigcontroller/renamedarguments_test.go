@@ -0,0 +1,111 @@
+package targetconfigcontroller
+
+import (
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/upgradeablecontroller"
+)
+
+func TestMigrateRenamedArguments(t *testing.T) {
+	tests := []struct {
+		name            string
+		config          string
+		expected        string
+		expectedApplied []string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:     "no renamed arguments",
+			config:   `{"extendedArguments":{"cluster-signing-duration":["720h"]}}`,
+			expected: `{"extendedArguments":{"cluster-signing-duration":["720h"]}}`,
+		},
+		{
+			name:            "renamed",
+			config:          `{"extendedArguments":{"experimental-cluster-signing-duration":["720h"]},"zoneEviction":{"nodeEvictionRate":0.2}}`,
+			expected:        `{"extendedArguments":{"cluster-signing-duration":["720h"]},"zoneEviction":{"nodeEvictionRate":0.2}}`,
+			expectedApplied: []string{"experimental-cluster-signing-duration renamed to cluster-signing-duration"},
+		},
+		{
+			name:            "both names",
+			config:          `{"extendedArguments":{"experimental-cluster-signing-duration":["720h"],"cluster-signing-duration":["24h"]}}`,
+			expected:        `{"extendedArguments":{"cluster-signing-duration":["24h"]}}`,
+			expectedApplied: []string{"experimental-cluster-signing-duration dropped in favor of cluster-signing-duration"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			migrated, applied, err := migrateRenamedArguments([]byte(tc.config))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(migrated) != tc.expected {
+				t.Errorf("expected %s, got %s", tc.expected, migrated)
+			}
+			if !reflect.DeepEqual(applied, tc.expectedApplied) {
+				t.Errorf("expected %v, got %v", tc.expectedApplied, applied)
+			}
+		})
+	}
+}
+
+func TestRenamedArgumentsRendered(t *testing.T) {
+	configMap, err := MergeKubeControllerManagerConfig(nil, []byte(`{"extendedArguments":{"experimental-cluster-signing-duration":["48h"]}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := configMap.Data["config.yaml"]
+	if !strings.Contains(config, `"cluster-signing-duration":["48h"]`) || strings.Contains(config, "experimental-cluster-signing-duration") {
+		t.Errorf("expected the override under the new name, got %s", config)
+	}
+
+	condition := renamedArgumentsCondition(nil, []byte(`{"extendedArguments":{"experimental-cluster-signing-duration":["48h"]}}`))
+	expectedMessage := "extendedArguments use names kube-controller-manager no longer accepts, update them before the migration is removed: experimental-cluster-signing-duration renamed to cluster-signing-duration in unsupportedConfigOverrides"
+	if condition.Status != operatorv1.ConditionTrue || condition.Message != expectedMessage {
+		t.Errorf("expected %s %q, got %s %q", operatorv1.ConditionTrue, expectedMessage, condition.Status, condition.Message)
+	}
+}
+
+// TestRenamedArgumentsExpire fails once a shim outlived the release after the one which dropped the old name.
+func TestRenamedArgumentsExpire(t *testing.T) {
+	goMod, err := os.ReadFile("../../../go.mod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	match := regexp.MustCompile(`k8s.io/client-go v0\.(\d+)\.`).FindSubmatch(goMod)
+	if match == nil {
+		t.Fatal("k8s.io/client-go not found in go.mod")
+	}
+	kubeMinor, _ := strconv.Atoi(string(match[1]))
+	for _, renamed := range renamedArguments {
+		removedIn, err := strconv.Atoi(strings.TrimPrefix(renamed.removedIn, "1."))
+		if err != nil {
+			t.Fatalf("%s: invalid removedIn %q", renamed.old, renamed.removedIn)
+		}
+		if kubeMinor > removedIn+1 {
+			t.Errorf("the migration of %s to %s is due to be removed, kube-controller-manager dropped the old name in %s", renamed.old, renamed.new, renamed.removedIn)
+		}
+	}
+}
+
+// TestRenamedArgumentsNotRemoved fails when an old name is also listed as a removed flag, the migration keeps the
+// cluster upgradeable.
+func TestRenamedArgumentsNotRemoved(t *testing.T) {
+	for _, renamed := range renamedArguments {
+		removed, err := upgradeablecontroller.RemovedFlagsInOverrides([]byte(`{"extendedArguments":{"` + renamed.old + `":["x"]}}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(removed) > 0 {
+			t.Errorf("%s is migrated to %s, it must not block upgrades as a removed flag", renamed.old, renamed.new)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package targetconfigcontroller
+
+import (
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	minTerminationGracePeriodSeconds = 30
+	maxTerminationGracePeriodSeconds = 300
+	// the process needs some time to release the leader lease after the pre-stop hook returned
+	minShutdownSecondsAfterPreStop = 15
+)
+
+// shutdownConfig tunes how the kube-controller-manager static pod is stopped when a new revision is rolled out.
+// It is read from the shutdown stanza of the operator's UnsupportedConfigOverrides:
+//
+//	unsupportedConfigOverrides:
+//	  shutdown:
+//	    terminationGracePeriodSeconds: 135
+//	    preStopSleepSeconds: 30
+type shutdownConfig struct {
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
+	PreStopSleepSeconds           *int64 `json:"preStopSleepSeconds,omitempty"`
+}
+
+// getShutdownConfig reads and bounds the shutdown overrides. Nothing is overridden when the stanza is missing.
+func getShutdownConfig(unsupportedConfigOverrides []byte) (shutdownConfig, error) {
+	if len(unsupportedConfigOverrides) == 0 {
+		return shutdownConfig{}, nil
+	}
+	overrides := struct {
+		Shutdown shutdownConfig `json:"shutdown"`
+	}{}
+	if err := json.Unmarshal(unsupportedConfigOverrides, &overrides); err != nil {
+		return shutdownConfig{}, fmt.Errorf("failed to load shutdown from UnsupportedConfigOverride: %v", err)
+	}
+	config := overrides.Shutdown
+
+	gracePeriod := int64(minTerminationGracePeriodSeconds)
+	if config.TerminationGracePeriodSeconds != nil {
+		gracePeriod = *config.TerminationGracePeriodSeconds
+		if gracePeriod < minTerminationGracePeriodSeconds || gracePeriod > maxTerminationGracePeriodSeconds {
+			return shutdownConfig{}, fmt.Errorf("shutdown.terminationGracePeriodSeconds must be between %d and %d, got %d", minTerminationGracePeriodSeconds, maxTerminationGracePeriodSeconds, gracePeriod)
+		}
+	}
+	if config.PreStopSleepSeconds != nil {
+		if sleep := *config.PreStopSleepSeconds; sleep < 0 || sleep > gracePeriod-minShutdownSecondsAfterPreStop {
+			return shutdownConfig{}, fmt.Errorf("shutdown.preStopSleepSeconds must be between 0 and %d for a termination grace period of %d seconds, got %d", gracePeriod-minShutdownSecondsAfterPreStop, gracePeriod, sleep)
+		}
+	}
+	return config, nil
+}
+
+// applyTo sets the termination grace period of the pod and the pre-stop sleep of the kube-controller-manager container.
+func (s shutdownConfig) applyTo(pod *corev1.Pod) {
+	if s.TerminationGracePeriodSeconds != nil {
+		gracePeriod := *s.TerminationGracePeriodSeconds
+		pod.Spec.TerminationGracePeriodSeconds = &gracePeriod
+	}
+	if s.PreStopSleepSeconds == nil || *s.PreStopSleepSeconds == 0 {
+		return
+	}
+	for i := range pod.Spec.Containers {
+		if pod.Spec.Containers[i].Name != "kube-controller-manager" {
+			continue
+		}
+		pod.Spec.Containers[i].Lifecycle = &corev1.Lifecycle{
+			PreStop: &corev1.LifecycleHandler{
+				Exec: &corev1.ExecAction{Command: []string{"sleep", fmt.Sprintf("%d", *s.PreStopSleepSeconds)}},
+			},
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package targetconfigcontroller
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestGetShutdownConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		overrides   string
+		expected    shutdownConfig
+		expectedErr bool
+	}{
+		{
+			name: "no overrides",
+		},
+		{
+			name:      "other overrides",
+			overrides: `{"extendedArguments":{"v":["4"]}}`,
+		},
+		{
+			name:      "grace period and pre-stop sleep",
+			overrides: `{"shutdown":{"terminationGracePeriodSeconds":135,"preStopSleepSeconds":30}}`,
+			expected:  shutdownConfig{TerminationGracePeriodSeconds: pointer.Int64(135), PreStopSleepSeconds: pointer.Int64(30)},
+		},
+		{
+			name:      "pre-stop sleep within the default grace period",
+			overrides: `{"shutdown":{"preStopSleepSeconds":15}}`,
+			expected:  shutdownConfig{PreStopSleepSeconds: pointer.Int64(15)},
+		},
+		{
+			name:        "grace period too short",
+			overrides:   `{"shutdown":{"terminationGracePeriodSeconds":5}}`,
+			expectedErr: true,
+		},
+		{
+			name:        "grace period too long",
+			overrides:   `{"shutdown":{"terminationGracePeriodSeconds":3600}}`,
+			expectedErr: true,
+		},
+		{
+			name:        "pre-stop sleep consuming the grace period",
+			overrides:   `{"shutdown":{"terminationGracePeriodSeconds":60,"preStopSleepSeconds":50}}`,
+			expectedErr: true,
+		},
+		{
+			name:        "negative pre-stop sleep",
+			overrides:   `{"shutdown":{"preStopSleepSeconds":-1}}`,
+			expectedErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config, err := getShutdownConfig([]byte(test.overrides))
+			if (err != nil) != test.expectedErr {
+				t.Fatalf("expected error %v, got %v", test.expectedErr, err)
+			}
+			if got, expected := pointer.Int64Deref(config.TerminationGracePeriodSeconds, -1), pointer.Int64Deref(test.expected.TerminationGracePeriodSeconds, -1); got != expected {
+				t.Errorf("expected terminationGracePeriodSeconds %d, got %d", expected, got)
+			}
+			if got, expected := pointer.Int64Deref(config.PreStopSleepSeconds, -1), pointer.Int64Deref(test.expected.PreStopSleepSeconds, -1); got != expected {
+				t.Errorf("expected preStopSleepSeconds %d, got %d", expected, got)
+			}
+		})
+	}
+}
+
+func TestShutdownConfigApplyTo(t *testing.T) {
+	pod := &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "kube-controller-manager"}, {Name: "cluster-policy-controller"}}}}
+	shutdownConfig{TerminationGracePeriodSeconds: pointer.Int64(135), PreStopSleepSeconds: pointer.Int64(30)}.applyTo(pod)
+
+	if pod.Spec.TerminationGracePeriodSeconds == nil || *pod.Spec.TerminationGracePeriodSeconds != 135 {
+		t.Errorf("expected terminationGracePeriodSeconds 135, got %v", pod.Spec.TerminationGracePeriodSeconds)
+	}
+	if lifecycle := pod.Spec.Containers[0].Lifecycle; lifecycle == nil || lifecycle.PreStop == nil || lifecycle.PreStop.Exec == nil || lifecycle.PreStop.Exec.Command[1] != "30" {
+		t.Errorf("expected a pre-stop sleep of 30 seconds, got %#v", lifecycle)
+	}
+	if pod.Spec.Containers[1].Lifecycle != nil {
+		t.Errorf("expected no pre-stop hook on the cluster-policy-controller, got %#v", pod.Spec.Containers[1].Lifecycle)
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"k8s.io/client-go/util/cert"
 	"k8s.io/klog/v2"
 
+	configv1 "github.com/openshift/api/config/v1"
 	kubecontrolplanev1 "github.com/openshift/api/kubecontrolplane/v1"
 	openshiftcontrolplanev1 "github.com/openshift/api/openshiftcontrolplane/v1"
 	operatorv1 "github.com/openshift/api/operator/v1"
@@ -42,7 +43,14 @@ import (
 	"github.com/openshift/library-go/pkg/operator/v1helpers"
 
 	"github.com/openshift/cluster-kube-controller-manager-operator/bindata"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation/ownership"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/decisionlog"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operandversion"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operationprofile"
 	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/teardown"
 	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/version"
 )
 
@@ -57,21 +65,37 @@ type TargetConfigController struct {
 	toolsImagePullSpec              string
 
 	operatorClient v1helpers.StaticPodOperatorClient
+	statusUpdater  statusbatcher.StatusUpdater
 	operatorLister cache.GenericLister
 
-	kubeClient          kubernetes.Interface
-	configMapLister     corev1listers.ConfigMapLister
-	secretLister        corev1listers.SecretLister
-	infrastuctureLister configv1listers.InfrastructureLister
+	kubeClient            kubernetes.Interface
+	configMapLister       corev1listers.ConfigMapLister
+	secretLister          corev1listers.SecretLister
+	infrastuctureLister   configv1listers.InfrastructureLister
+	clusterOperatorLister configv1listers.ClusterOperatorLister
+	imageLister           configv1listers.ImageLister
+
+	// operandVersion is the kube-controller-manager version this operator rolls out
+	operandVersion string
+	// configOwnership tells which observer set an argument, for the provenance of the config
+	configOwnership *ownership.Ownership
+
+	decisionLog *decisionlog.Log
 }
 
 func NewTargetConfigController(
 	targetImagePullSpec, operatorImagePullSpec, clusterPolicyControllerPullSpec, toolsImagePullSpec string,
 	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
 	operatorClient v1helpers.StaticPodOperatorClient,
+	statusUpdater statusbatcher.StatusUpdater,
 	operatorLister cache.GenericLister,
 	kubeClient kubernetes.Interface,
 	infrastuctureInformer configv1informers.InfrastructureInformer,
+	clusterOperatorInformer configv1informers.ClusterOperatorInformer,
+	imageInformer configv1informers.ImageInformer,
+	operandVersion string,
+	configOwnership *ownership.Ownership,
+	decisionLog *decisionlog.Log,
 	eventRecorder events.Recorder,
 ) factory.Controller {
 	c := &TargetConfigController{
@@ -80,12 +104,18 @@ func NewTargetConfigController(
 		clusterPolicyControllerPullSpec: clusterPolicyControllerPullSpec,
 		toolsImagePullSpec:              toolsImagePullSpec,
 
-		configMapLister:     kubeInformersForNamespaces.ConfigMapLister(),
-		secretLister:        kubeInformersForNamespaces.SecretLister(),
-		infrastuctureLister: infrastuctureInformer.Lister(),
-		operatorClient:      operatorClient,
-		operatorLister:      operatorLister,
-		kubeClient:          kubeClient,
+		configMapLister:       kubeInformersForNamespaces.ConfigMapLister(),
+		secretLister:          kubeInformersForNamespaces.SecretLister(),
+		infrastuctureLister:   infrastuctureInformer.Lister(),
+		clusterOperatorLister: clusterOperatorInformer.Lister(),
+		imageLister:           imageInformer.Lister(),
+		operandVersion:        operandVersion,
+		configOwnership:       configOwnership,
+		operatorClient:        operatorClient,
+		statusUpdater:         statusUpdater,
+		operatorLister:        operatorLister,
+		kubeClient:            kubeClient,
+		decisionLog:           decisionLog,
 	}
 
 	return factory.New().WithInformers(
@@ -95,6 +125,12 @@ func NewTargetConfigController(
 		// We use infrastuctureInformer for observing load balancer URL
 		infrastuctureInformer.Informer(),
 
+		// the kube-apiserver version bounds the kube-controller-manager version we may roll out
+		clusterOperatorInformer.Informer(),
+
+		// the image config names the additional CAs of the image registries
+		imageInformer.Informer(),
+
 		// these are for watching our outputs in case someone changes them
 		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().ConfigMaps().Informer(),
 		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().Secrets().Informer(),
@@ -112,7 +148,7 @@ func NewTargetConfigController(
 	).WithNamespaceInformer(
 		// we only watch our output namespace
 		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().Namespaces().Informer(), operatorclient.TargetNamespace,
-	).ResyncEvery(time.Minute).WithSync(c.sync).ToController("TargetConfigController", eventRecorder)
+	).ResyncEvery(operationprofile.Resync(time.Minute)).WithSync(syncmetrics.Timed("TargetConfigController", teardown.Quiesceable("TargetConfigController", c.sync))).ToController("TargetConfigController", eventRecorder)
 }
 
 func (c TargetConfigController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
@@ -122,12 +158,14 @@ func (c TargetConfigController) sync(ctx context.Context, syncCtx factory.SyncCo
 	}
 
 	if !management.IsOperatorManaged(operatorSpec.ManagementState) {
+		c.decisionLog.RecordOutcome("TargetConfigController", "Skip", fmt.Sprintf("managementState is %s", operatorSpec.ManagementState))
 		return nil
 	}
 
 	// block until config is observed and specific paths are present
 	if err := isRequiredConfigPresent(operatorSpec.ObservedConfig.Raw); err != nil {
 		syncCtx.Recorder().Warning("ConfigMissing", err.Error())
+		c.decisionLog.RecordOutcome("TargetConfigController", "Block", err.Error())
 		return err
 	}
 
@@ -198,19 +236,7 @@ func isRequiredConfigPresent(config []byte) error {
 func createTargetConfigController(ctx context.Context, syncCtx factory.SyncContext, c TargetConfigController, operatorSpec *operatorv1.StaticPodOperatorSpec, useSecureServiceCA bool) (bool, error) {
 	errors := []error{}
 
-	_, _, err := manageKubeControllerManagerConfig(ctx, c.kubeClient.CoreV1(), syncCtx.Recorder(), operatorSpec)
-	if err != nil {
-		errors = append(errors, fmt.Errorf("%q: %v", "configmap", err))
-	}
-	_, _, err = manageClusterPolicyControllerConfig(ctx, c.kubeClient.CoreV1(), syncCtx.Recorder(), operatorSpec)
-	if err != nil {
-		errors = append(errors, fmt.Errorf("%q: %v", "configmap/cluster-policy-controller-config", err))
-	}
-	_, _, err = manageRecycler(ctx, c.kubeClient.CoreV1(), syncCtx.Recorder(), c.toolsImagePullSpec)
-	if err != nil {
-		errors = append(errors, fmt.Errorf("%q: %v", "configmap/recycler-config", err))
-	}
-	_, _, err = ManageCSRIntermediateCABundle(ctx, c.secretLister, c.kubeClient.CoreV1(), syncCtx.Recorder())
+	_, _, err := ManageCSRIntermediateCABundle(ctx, c.secretLister, c.kubeClient.CoreV1(), syncCtx.Recorder())
 	if err != nil {
 		errors = append(errors, fmt.Errorf("%q: %v", "configmap/csr-intermediate-ca", err))
 	}
@@ -225,18 +251,10 @@ func createTargetConfigController(ctx context.Context, syncCtx factory.SyncConte
 	if requeueDelay > 0 {
 		syncCtx.Queue().AddAfter(syncCtx.QueueKey(), requeueDelay)
 	}
-	_, _, err = manageServiceAccountCABundle(ctx, c.configMapLister, c.kubeClient.CoreV1(), syncCtx.Recorder())
-	if err != nil {
-		errors = append(errors, fmt.Errorf("%q: %v", "configmap/serviceaccount-ca", err))
-	}
 	err = ensureLocalhostRecoverySAToken(ctx, c.kubeClient.CoreV1(), syncCtx.Recorder())
 	if err != nil {
 		errors = append(errors, fmt.Errorf("%q: %v", "serviceaccount/localhost-recovery-client", err))
 	}
-	_, _, err = manageControllerManagerKubeconfig(ctx, c.kubeClient.CoreV1(), c.infrastuctureLister, syncCtx.Recorder())
-	if err != nil {
-		errors = append(errors, fmt.Errorf("%q: %v", "configmap/controller-manager-kubeconfig", err))
-	}
 
 	// Allow the addition of the service ca to token secrets to be enabled by setting an
 	// UnsupportedConfigOverride field named
@@ -256,9 +274,30 @@ func createTargetConfigController(ctx context.Context, syncCtx factory.SyncConte
 		}
 	}
 
-	_, _, err = managePod(ctx, c.kubeClient.CoreV1(), c.kubeClient.CoreV1(), syncCtx.Recorder(), operatorSpec, c.targetImagePullSpec, c.operatorImagePullSpec, c.clusterPolicyControllerPullSpec, addServingServiceCAToTokenSecrets, useSecureServiceCA)
+	// restarting kube-controller-manager while etcd quorum is at risk amplifies the outage, so the
+	// revisioned resources are left alone until etcd recovers
+	etcdUnhealthy, err := c.etcdUnhealthy()
 	if err != nil {
-		errors = append(errors, fmt.Errorf("%q: %v", "configmap/kube-controller-manager-pod", err))
+		errors = append(errors, fmt.Errorf("%q: %v", "clusteroperator/etcd", err))
+	}
+	rolloutDeferredCondition := operatorv1.OperatorCondition{
+		Type:   "RevisionRolloutDeferred",
+		Status: operatorv1.ConditionFalse,
+	}
+	if len(etcdUnhealthy) > 0 {
+		c.decisionLog.RecordOutcome("TargetConfigController", "Block", etcdUnhealthy)
+		rolloutDeferredCondition.Status = operatorv1.ConditionTrue
+		rolloutDeferredCondition.Reason = "RolloutDeferredEtcdUnhealthy"
+		rolloutDeferredCondition.Message = fmt.Sprintf("revisioned configuration is not updated while etcd is unhealthy: %s", etcdUnhealthy)
+	} else if err == nil {
+		errors = append(errors, c.manageRevisionedResources(ctx, syncCtx, operatorSpec, addServingServiceCAToTokenSecrets, useSecureServiceCA)...)
+	}
+	if err := c.statusUpdater.UpdateStatus(ctx,
+		v1helpers.UpdateConditionFn(rolloutDeferredCondition),
+		v1helpers.UpdateConditionFn(podEvictionCondition(operatorSpec.UnsupportedConfigOverrides.Raw)),
+		v1helpers.UpdateConditionFn(renamedArgumentsCondition(operatorSpec.ObservedConfig.Raw, operatorSpec.UnsupportedConfigOverrides.Raw)),
+	); err != nil {
+		return true, err
 	}
 
 	err = ensureKubeControllerManagerTrustedCA(ctx, c.kubeClient.CoreV1(), syncCtx.Recorder())
@@ -290,6 +329,7 @@ func createTargetConfigController(ctx context.Context, syncCtx factory.SyncConte
 	}
 
 	if len(errors) > 0 {
+		c.decisionLog.RecordOutcome("TargetConfigController", "Degrade", v1helpers.NewMultiLineAggregate(errors).Error())
 		condition := operatorv1.OperatorCondition{
 			Type:    "TargetConfigControllerDegraded",
 			Status:  operatorv1.ConditionTrue,
@@ -313,10 +353,133 @@ func createTargetConfigController(ctx context.Context, syncCtx factory.SyncConte
 	if _, _, err := v1helpers.UpdateStaticPodStatus(ctx, c.operatorClient, v1helpers.UpdateStaticPodConditionFn(condition)); err != nil {
 		return true, err
 	}
+	if len(etcdUnhealthy) == 0 {
+		c.decisionLog.Proceed("TargetConfigController")
+	}
 
 	return false, nil
 }
 
+// manageRevisionedResources updates the resources copied into every revision, any change to them rolls out a new one.
+func (c TargetConfigController) manageRevisionedResources(ctx context.Context, syncCtx factory.SyncContext, operatorSpec *operatorv1.StaticPodOperatorSpec, addServingServiceCAToTokenSecrets, useSecureServiceCA bool) []error {
+	errors := []error{}
+	ctx, span := syncmetrics.StartSpan(ctx, "TargetConfigController.manageRevisionedResources")
+	defer func() {
+		syncmetrics.EndSpan(span, v1helpers.NewMultiLineAggregate(errors))
+	}()
+	recordRevisionedChange := func(resource string, modified bool) {
+		if modified {
+			c.decisionLog.Record("TargetConfigController", "Update", fmt.Sprintf("%s changed, a new revision will be rolled out", resource))
+		}
+	}
+
+	_, modified, err := manageKubeControllerManagerConfig(ctx, c.kubeClient.CoreV1(), syncCtx.Recorder(), operatorSpec, c.configOwnership)
+	if err != nil {
+		errors = append(errors, fmt.Errorf("%q: %v", "configmap", err))
+	}
+	recordRevisionedChange("configmap/config", modified)
+	_, modified, err = manageClusterPolicyControllerConfig(ctx, c.kubeClient.CoreV1(), syncCtx.Recorder(), operatorSpec)
+	if err != nil {
+		errors = append(errors, fmt.Errorf("%q: %v", "configmap/cluster-policy-controller-config", err))
+	}
+	recordRevisionedChange("configmap/cluster-policy-controller-config", modified)
+	_, modified, err = manageRecycler(ctx, c.kubeClient.CoreV1(), syncCtx.Recorder(), c.toolsImagePullSpec)
+	if err != nil {
+		errors = append(errors, fmt.Errorf("%q: %v", "configmap/recycler-config", err))
+	}
+	recordRevisionedChange("configmap/recycler-config", modified)
+	_, modified, err = manageServiceAccountCABundle(ctx, c.configMapLister, c.kubeClient.CoreV1(), syncCtx.Recorder())
+	if err != nil {
+		errors = append(errors, fmt.Errorf("%q: %v", "configmap/serviceaccount-ca", err))
+	}
+	recordRevisionedChange("configmap/serviceaccount-ca", modified)
+	_, modified, err = manageImageRegistryCABundle(ctx, c.imageLister, c.configMapLister, c.kubeClient.CoreV1(), syncCtx.Recorder())
+	if err != nil {
+		errors = append(errors, fmt.Errorf("%q: %v", "configmap/"+ImageRegistryCABundleName, err))
+	}
+	recordRevisionedChange("configmap/"+ImageRegistryCABundleName, modified)
+	_, modified, err = manageControllerManagerKubeconfig(ctx, c.kubeClient.CoreV1(), c.infrastuctureLister, syncCtx.Recorder(), "assets/kube-controller-manager/kubeconfig-cm.yaml", "kube-controller-manager-client-cert-key")
+	if err != nil {
+		errors = append(errors, fmt.Errorf("%q: %v", "configmap/controller-manager-kubeconfig", err))
+	}
+	recordRevisionedChange("configmap/controller-manager-kubeconfig", modified)
+	// cluster-policy-controller keeps authenticating as kube-controller-manager until its own client certificate was
+	// issued, so that the installation of a revision does not wait for the cert rotation after an upgrade
+	clusterPolicyControllerClientCertKey := "cluster-policy-controller-client-cert-key"
+	if _, err := c.secretLister.Secrets(operatorclient.TargetNamespace).Get(clusterPolicyControllerClientCertKey); apierrors.IsNotFound(err) {
+		clusterPolicyControllerClientCertKey = "kube-controller-manager-client-cert-key"
+	} else if err != nil {
+		errors = append(errors, fmt.Errorf("%q: %v", "secret/"+clusterPolicyControllerClientCertKey, err))
+	}
+	_, modified, err = manageControllerManagerKubeconfig(ctx, c.kubeClient.CoreV1(), c.infrastuctureLister, syncCtx.Recorder(), "assets/kube-controller-manager/cluster-policy-controller-kubeconfig-cm.yaml", clusterPolicyControllerClientCertKey)
+	if err != nil {
+		errors = append(errors, fmt.Errorf("%q: %v", "configmap/cluster-policy-controller-kubeconfig", err))
+	}
+	recordRevisionedChange("configmap/cluster-policy-controller-kubeconfig", modified)
+
+	// invalid shutdown or config identity overrides keep the pod as it is rather than rolling out the defaults
+	shutdown, err := getShutdownConfig(operatorSpec.UnsupportedConfigOverrides.Raw)
+	var configIdentity configIdentityConfig
+	if err == nil {
+		configIdentity, err = getConfigIdentityConfig(operatorSpec.UnsupportedConfigOverrides.Raw)
+	}
+	if err != nil {
+		errors = append(errors, err)
+	} else if err := c.validateOperandVersionSkew(); err != nil {
+		c.decisionLog.RecordOutcome("TargetConfigController", "Block", err.Error())
+		errors = append(errors, fmt.Errorf("%q: not rolling out kube-controller-manager %s: %v", "configmap/kube-controller-manager-pod", c.operandVersion, err))
+	} else {
+		podCtx, podSpan := syncmetrics.StartSpan(ctx, "TargetConfigController.managePod")
+		_, modified, err = managePod(podCtx, c.kubeClient.CoreV1(), c.kubeClient.CoreV1(), syncCtx.Recorder(), operatorSpec, c.targetImagePullSpec, c.operatorImagePullSpec, c.clusterPolicyControllerPullSpec, c.operandVersion, addServingServiceCAToTokenSecrets, useSecureServiceCA, shutdown, configIdentity)
+		syncmetrics.EndSpan(podSpan, err)
+		if err != nil {
+			errors = append(errors, fmt.Errorf("%q: %v", "configmap/kube-controller-manager-pod", err))
+		}
+		recordRevisionedChange("configmap/kube-controller-manager-pod", modified)
+	}
+	return errors
+}
+
+// etcdUnhealthy describes why etcd is not healthy enough to restart kube-controller-manager, it is empty when
+// etcd is healthy or its operator does not report a status yet.
+func (c TargetConfigController) etcdUnhealthy() (string, error) {
+	clusterOperator, err := c.clusterOperatorLister.Get("etcd")
+	if apierrors.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	for _, condition := range clusterOperator.Status.Conditions {
+		switch {
+		case condition.Type == configv1.OperatorAvailable && condition.Status == configv1.ConditionFalse:
+			return fmt.Sprintf("etcd is not available: %s", condition.Message), nil
+		// other reasons of degradation, like failing certificate rotation, do not put the quorum at risk
+		case condition.Type == configv1.OperatorDegraded && condition.Status == configv1.ConditionTrue && strings.Contains(condition.Reason, "EtcdMembers"):
+			return fmt.Sprintf("etcd members are degraded: %s", condition.Message), nil
+		}
+	}
+	return "", nil
+}
+
+// validateOperandVersionSkew checks the kube-controller-manager version against the kube-apiserver version
+// before it is rolled out. Only a change of the version is blocked, the configuration of the deployed version
+// can always be updated.
+func (c TargetConfigController) validateOperandVersionSkew() error {
+	current, err := c.configMapLister.ConfigMaps(operatorclient.TargetNamespace).Get("kube-controller-manager-pod")
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	if current != nil && current.Data[operandversion.OperandVersionKey] == c.operandVersion {
+		return nil
+	}
+	apiServerVersion, err := operandversion.APIServerVersion(c.clusterOperatorLister)
+	if err != nil {
+		return err
+	}
+	return operandversion.ValidateSkew(c.operandVersion, apiServerVersion)
+}
+
 // setCloudControllerOwnerCondition sets the condition to False if either external cloud
 // provider has been successfully applied for all static pods or it's not set at all. Otherwise
 // it sets the condition to True.
@@ -379,39 +542,93 @@ func isConfiguredToOwnCloudController(cm *corev1.ConfigMap) (bool, error) {
 	return len(cloudProvider) != 1 || (cloudProvider[0] != "external" && cloudProvider[0] != ""), nil
 }
 
-func manageKubeControllerManagerConfig(ctx context.Context, client corev1client.ConfigMapsGetter, recorder events.Recorder, operatorSpec *operatorv1.StaticPodOperatorSpec) (*corev1.ConfigMap, bool, error) {
+func manageKubeControllerManagerConfig(ctx context.Context, client corev1client.ConfigMapsGetter, recorder events.Recorder, operatorSpec *operatorv1.StaticPodOperatorSpec, configOwnership *ownership.Ownership) (*corev1.ConfigMap, bool, error) {
+	requiredConfigMap, err := MergeKubeControllerManagerConfig(operatorSpec.ObservedConfig.Raw, operatorSpec.UnsupportedConfigOverrides.Raw)
+	if err != nil {
+		return nil, false, err
+	}
+	existingConfigMap, err := client.ConfigMaps(requiredConfigMap.Namespace).Get(ctx, requiredConfigMap.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		existingConfigMap = nil
+	} else if err != nil {
+		return nil, false, err
+	}
+	provenance, err := configProvenance(operatorSpec.ObservedConfig.Raw, operatorSpec.UnsupportedConfigOverrides.Raw, configOwnership, requiredConfigMap, existingConfigMap, time.Now())
+	if err != nil {
+		return nil, false, err
+	}
+	requiredConfigMap.Data[ProvenanceKey] = provenance
+	return resourceapply.ApplyConfigMap(ctx, client, recorder, requiredConfigMap)
+}
+
+// MergeKubeControllerManagerConfig returns the config configmap of kube-controller-manager, merged from the default
+// config, the observed config, the zone eviction, pod eviction and contention profiling settings and the unsupported
+// config overrides. Layers and rendered configs not matching the KubeControllerManagerConfig schema are rejected, the
+// config is published into the next revision as is.
+func MergeKubeControllerManagerConfig(observedConfig, unsupportedConfigOverrides []byte) (*corev1.ConfigMap, error) {
+	if err := validateConfigLayers(observedConfig, unsupportedConfigOverrides); err != nil {
+		return nil, err
+	}
 	configMap := resourceread.ReadConfigMapV1OrDie(bindata.MustAsset("assets/kube-controller-manager/cm.yaml"))
 	defaultConfig := bindata.MustAsset("assets/config/defaultconfig.yaml")
+	zoneEvictionConfig, err := zoneEvictionArguments(unsupportedConfigOverrides)
+	if err != nil {
+		return nil, err
+	}
+	podEvictionConfig, err := podEvictionArguments(unsupportedConfigOverrides)
+	if err != nil {
+		return nil, err
+	}
+	contentionProfilingConfig, err := contentionProfilingArguments(unsupportedConfigOverrides, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	// the renamed arguments are migrated per layer, so that the layers keep their precedence
+	observedConfig, _, err = migrateRenamedArguments(observedConfig)
+	if err != nil {
+		return nil, err
+	}
+	unsupportedConfigOverrides, _, err = migrateRenamedArguments(unsupportedConfigOverrides)
+	if err != nil {
+		return nil, err
+	}
 	requiredConfigMap, _, err := resourcemerge.MergePrunedConfigMap(
 		&kubecontrolplanev1.KubeControllerManagerConfig{},
 		configMap,
 		"config.yaml",
 		nil,
 		defaultConfig,
-		operatorSpec.ObservedConfig.Raw,
-		operatorSpec.UnsupportedConfigOverrides.Raw)
+		observedConfig,
+		zoneEvictionConfig,
+		podEvictionConfig,
+		contentionProfilingConfig,
+		unsupportedConfigOverrides)
 	if err != nil {
-		return nil, false, err
+		return nil, err
 	}
-	return resourceapply.ApplyConfigMap(ctx, client, recorder, requiredConfigMap)
+	if err := validateRenderedConfig([]byte(requiredConfigMap.Data["config.yaml"])); err != nil {
+		return nil, err
+	}
+	return requiredConfigMap, nil
 }
 
 func manageClusterPolicyControllerConfig(ctx context.Context, client corev1client.CoreV1Interface, recorder events.Recorder, operatorSpec *operatorv1.StaticPodOperatorSpec) (*corev1.ConfigMap, bool, error) {
 	configMap := resourceread.ReadConfigMapV1OrDie(bindata.MustAsset("assets/kube-controller-manager/cluster-policy-controller-cm.yaml"))
 	defaultConfig := bindata.MustAsset("assets/config/default-cluster-policy-controller-config.yaml")
-	kcmService := resourceread.ReadServiceV1OrDie(bindata.MustAsset("assets/kube-controller-manager/svc.yaml"))
+	// cluster-policy-controller serves with a certificate of its own, issued for its own service
+	cpcService := resourceread.ReadServiceV1OrDie(bindata.MustAsset("assets/kube-controller-manager/cluster-policy-controller-svc.yaml"))
 	configYamls := [][]byte{
 		defaultConfig,
 		operatorSpec.ObservedConfig.Raw,
 	}
 
 	servingCertName := ""
-	if kcmService.Annotations != nil {
-		servingCertName = kcmService.Annotations[ServingCertSecretAnnotation]
+	if cpcService.Annotations != nil {
+		servingCertName = cpcService.Annotations[ServingCertSecretAnnotation]
 	}
 
 	if len(servingCertName) == 0 {
-		return nil, false, fmt.Errorf("missing %s annotation in %s/%s service", kcmService.Namespace, kcmService.Name, ServingCertSecretAnnotation)
+		return nil, false, fmt.Errorf("missing %s annotation in %s/%s service", cpcService.Namespace, cpcService.Name, ServingCertSecretAnnotation)
 	}
 
 	_, err := client.Secrets(operatorclient.TargetNamespace).Get(ctx, servingCertName, metav1.GetOptions{})
@@ -492,8 +709,11 @@ func ensureLocalhostRecoverySAToken(ctx context.Context, client corev1client.Cor
 	return err
 }
 
-func manageControllerManagerKubeconfig(ctx context.Context, client corev1client.CoreV1Interface, infrastructureLister configv1listers.InfrastructureLister, recorder events.Recorder) (*corev1.ConfigMap, bool, error) {
-	cmString := string(bindata.MustAsset("assets/kube-controller-manager/kubeconfig-cm.yaml"))
+// manageControllerManagerKubeconfig applies the kubeconfig of the given asset pointing to the internal load balancer,
+// kube-controller-manager and cluster-policy-controller each authenticate with their own client certificate. The
+// asset may name the secret of the client certificate as $CLIENT_CERT_KEY_SECRET.
+func manageControllerManagerKubeconfig(ctx context.Context, client corev1client.CoreV1Interface, infrastructureLister configv1listers.InfrastructureLister, recorder events.Recorder, asset, clientCertKeySecret string) (*corev1.ConfigMap, bool, error) {
+	cmString := string(bindata.MustAsset(asset))
 
 	infrastructure, err := infrastructureLister.Get("cluster")
 	if err != nil {
@@ -505,7 +725,8 @@ func manageControllerManagerKubeconfig(ctx context.Context, client corev1client.
 	}
 
 	for pattern, value := range map[string]string{
-		"$LB_INT_URL": apiServerInternalURL,
+		"$LB_INT_URL":             apiServerInternalURL,
+		"$CLIENT_CERT_KEY_SECRET": clientCertKeySecret,
 	} {
 		cmString = strings.ReplaceAll(cmString, pattern, value)
 	}
@@ -527,8 +748,9 @@ func manageRecycler(ctx context.Context, configMapsGetter corev1client.ConfigMap
 	return resourceapply.ApplyConfigMap(ctx, configMapsGetter, recorder, requiredCM)
 }
 
-func managePod(ctx context.Context, configMapsGetter corev1client.ConfigMapsGetter, secretsGetter corev1client.SecretsGetter, recorder events.Recorder, operatorSpec *operatorv1.StaticPodOperatorSpec, imagePullSpec, operatorImagePullSpec, clusterPolicyControllerPullSpec string, addServingServiceCAToTokenSecrets, useSecureServiceCA bool) (*corev1.ConfigMap, bool, error) {
+func managePod(ctx context.Context, configMapsGetter corev1client.ConfigMapsGetter, secretsGetter corev1client.SecretsGetter, recorder events.Recorder, operatorSpec *operatorv1.StaticPodOperatorSpec, imagePullSpec, operatorImagePullSpec, clusterPolicyControllerPullSpec, operandVersion string, addServingServiceCAToTokenSecrets, useSecureServiceCA bool, shutdown shutdownConfig, configIdentity configIdentityConfig) (*corev1.ConfigMap, bool, error) {
 	required := resourceread.ReadPodV1OrDie(bindata.MustAsset("assets/kube-controller-manager/pod.yaml"))
+	shutdown.applyTo(required)
 	// TODO: If the image pull spec is not specified, the "${IMAGE}" will be used as value and the pod will fail to start.
 	images := map[string]string{
 		"${IMAGE}":                           imagePullSpec,
@@ -596,8 +818,8 @@ func managePod(ctx context.Context, configMapsGetter corev1client.ConfigMapsGett
 	if _, err := secretsGetter.Secrets(required.Namespace).Get(ctx, "serving-cert", metav1.GetOptions{}); err != nil && !apierrors.IsNotFound(err) {
 		return nil, false, err
 	} else if err == nil {
-		kcmContainerArgsWithLoglevel[0] += " --tls-cert-file=/etc/kubernetes/static-pod-resources/secrets/serving-cert/tls.crt"
-		kcmContainerArgsWithLoglevel[0] += " --tls-private-key-file=/etc/kubernetes/static-pod-resources/secrets/serving-cert/tls.key"
+		kcmContainerArgsWithLoglevel[0] += " --tls-cert-file=/etc/kubernetes/static-pod-certs/secrets/serving-cert/tls.crt"
+		kcmContainerArgsWithLoglevel[0] += " --tls-private-key-file=/etc/kubernetes/static-pod-certs/secrets/serving-cert/tls.key"
 	}
 
 	kubeControllerManagerConfigMap, err := configMapsGetter.ConfigMaps(required.Namespace).Get(ctx, "config", metav1.GetOptions{})
@@ -612,6 +834,7 @@ func managePod(ctx context.Context, configMapsGetter corev1client.ConfigMapsGett
 		if extendedArguments := GetKubeControllerManagerArgs(kubeControllerManagerConfig); len(extendedArguments) > 0 {
 			kcmContainerArgsWithLoglevel[0] += " " + strings.Join(extendedArguments, " ")
 		}
+		configIdentity.applyTo(required, kubeControllerManagerConfigMap.Data["config.yaml"])
 	}
 
 	var observedConfig map[string]interface{}
@@ -675,13 +898,122 @@ func managePod(ctx context.Context, configMapsGetter corev1client.ConfigMapsGett
 		}
 	}
 
+	// the flags configmap is revisioned next to the pod, so it has to be in place before the pod changes
+	if _, _, err := manageEffectiveFlags(ctx, configMapsGetter, recorder, required); err != nil {
+		return nil, false, fmt.Errorf("configmap/kube-controller-manager-flags: %v", err)
+	}
+
 	configMap := resourceread.ReadConfigMapV1OrDie(bindata.MustAsset("assets/kube-controller-manager/pod-cm.yaml"))
 	configMap.Data["pod.yaml"] = resourceread.WritePodV1OrDie(required)
 	configMap.Data["forceRedeploymentReason"] = operatorSpec.ForceRedeploymentReason
 	configMap.Data["version"] = version.Get().String()
+	configMap.Data[operandversion.OperandVersionKey] = operandVersion
 	return resourceapply.ApplyConfigMap(ctx, configMapsGetter, recorder, configMap)
 }
 
+// manageEffectiveFlags publishes the final command line flags of the kube-controller-manager container, one per line.
+// The configmap is revisioned, so kube-controller-manager-flags-<revision> tells what a node at that revision runs with.
+func manageEffectiveFlags(ctx context.Context, client corev1client.ConfigMapsGetter, recorder events.Recorder, pod *corev1.Pod) (*corev1.ConfigMap, bool, error) {
+	var kcmContainer *corev1.Container
+	for i := range pod.Spec.Containers {
+		if pod.Spec.Containers[i].Name == "kube-controller-manager" {
+			kcmContainer = &pod.Spec.Containers[i]
+			break
+		}
+	}
+	if kcmContainer == nil || len(kcmContainer.Args) == 0 {
+		return nil, false, fmt.Errorf("kube-controller-manager container args not found")
+	}
+	flags, err := GetKubeControllerManagerEffectiveFlags(kcmContainer.Args[0])
+	if err != nil {
+		return nil, false, err
+	}
+
+	configMap := resourceread.ReadConfigMapV1OrDie(bindata.MustAsset("assets/kube-controller-manager/flags-cm.yaml"))
+	configMap.Data["flags"] = strings.Join(flags, "\n")
+	return resourceapply.ApplyConfigMap(ctx, client, recorder, configMap)
+}
+
+// GetKubeControllerManagerEffectiveFlags extracts the flags passed to kube-controller-manager from the container script.
+// The words are split the way the shell does, quoted values with spaces stay a single flag.
+func GetKubeControllerManagerEffectiveFlags(script string) ([]string, error) {
+	const execLine = "exec hyperkube kube-controller-manager"
+	idx := strings.Index(script, execLine)
+	if idx < 0 {
+		return nil, fmt.Errorf("%s not found in %q", execLine, script)
+	}
+	return splitShellWords(script[idx+len(execLine):])
+}
+
+// splitShellWords splits the words of a single shell command, up to the first unquoted newline or command separator.
+// Quotes and backslash escapes are removed, line continuations are joined. Expansions are not performed.
+func splitShellWords(command string) ([]string, error) {
+	words := []string{}
+	word := &strings.Builder{}
+	inWord := false
+	endWord := func() {
+		if inWord {
+			words = append(words, word.String())
+			word.Reset()
+			inWord = false
+		}
+	}
+
+	for i := 0; i < len(command); i++ {
+		c := command[i]
+		switch c {
+		case ' ', '\t':
+			endWord()
+		case '\n', ';', '&', '|':
+			endWord()
+			return words, nil
+		case '\\':
+			if i+1 == len(command) {
+				return nil, fmt.Errorf("trailing backslash")
+			}
+			i++
+			// a line continuation joins the lines
+			if command[i] != '\n' {
+				word.WriteByte(command[i])
+				inWord = true
+			}
+		case '\'':
+			end := strings.IndexByte(command[i+1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated single quote")
+			}
+			word.WriteString(command[i+1 : i+1+end])
+			inWord = true
+			i += end + 1
+		case '"':
+			inWord = true
+			closed := false
+			for i++; i < len(command); i++ {
+				if command[i] == '"' {
+					closed = true
+					break
+				}
+				// inside double quotes a backslash escapes only the characters special there
+				if command[i] == '\\' && i+1 < len(command) && strings.IndexByte("$`\"\\\n", command[i+1]) >= 0 {
+					i++
+					if command[i] == '\n' {
+						continue
+					}
+				}
+				word.WriteByte(command[i])
+			}
+			if !closed {
+				return nil, fmt.Errorf("unterminated double quote")
+			}
+		default:
+			word.WriteByte(c)
+			inWord = true
+		}
+	}
+	endWord()
+	return words, nil
+}
+
 func GetKubeControllerManagerArgs(config map[string]interface{}) []string {
 	extendedArguments, ok := config["extendedArguments"]
 	if !ok || extendedArguments == nil {
@@ -14,6 +14,8 @@ import (
 	"testing"
 	"time"
 
+	configv1 "github.com/openshift/api/config/v1"
+	configv1listers "github.com/openshift/client-go/config/listers/config/v1"
 	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
 	"github.com/openshift/library-go/pkg/crypto"
 	"github.com/openshift/library-go/pkg/operator/events"
@@ -431,6 +433,62 @@ func TestReadKubeControllerManagerArgs(t *testing.T) {
 	}
 }
 
+func TestGetKubeControllerManagerEffectiveFlags(t *testing.T) {
+	testCases := []struct {
+		name          string
+		script        string
+		expected      []string
+		expectedError bool
+	}{
+		{
+			name: "multiline",
+			script: `timeout 3m /bin/bash -exuo pipefail -c 'while [ -n "$(ss -Htanop \( sport = 10257 \))" ]; do sleep 1; done'
+
+exec hyperkube kube-controller-manager --openshift-config=/etc/kubernetes/config.yaml \
+  --kubeconfig=/etc/kubernetes/kubeconfig --allocate-node-cidrs=false -v=2`,
+			expected: []string{"--openshift-config=/etc/kubernetes/config.yaml", "--kubeconfig=/etc/kubernetes/kubeconfig", "--allocate-node-cidrs=false", "-v=2"},
+		},
+		{
+			name: "quoted values",
+			script: `exec hyperkube kube-controller-manager --cluster-name='my cluster' \
+  --feature-gates="A=true,B=false" --description="say \"hi\"" --path=a\ b`,
+			expected: []string{"--cluster-name=my cluster", "--feature-gates=A=true,B=false", `--description=say "hi"`, "--path=a b"},
+		},
+		{
+			name: "next command",
+			script: `exec hyperkube kube-controller-manager --v=2
+echo done`,
+			expected: []string{"--v=2"},
+		},
+		{
+			name:     "no flags",
+			script:   "exec hyperkube kube-controller-manager",
+			expected: []string{},
+		},
+		{
+			name:          "unterminated quote",
+			script:        "exec hyperkube kube-controller-manager --cluster-name='my cluster",
+			expectedError: true,
+		},
+		{
+			name:          "missing exec",
+			script:        "exec hyperkube kube-apiserver --foo=bar",
+			expectedError: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			flags, err := GetKubeControllerManagerEffectiveFlags(tc.script)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, flags)
+		})
+	}
+}
+
 func TestConfiguredToOwnCloudController(t *testing.T) {
 	testCases := []struct {
 		name           string
@@ -481,3 +539,55 @@ func TestConfiguredToOwnCloudController(t *testing.T) {
 		})
 	}
 }
+
+func TestEtcdUnhealthy(t *testing.T) {
+	tests := []struct {
+		name       string
+		conditions []configv1.ClusterOperatorStatusCondition
+		expected   string
+	}{
+		{
+			name: "healthy",
+			conditions: []configv1.ClusterOperatorStatusCondition{
+				{Type: configv1.OperatorAvailable, Status: configv1.ConditionTrue},
+				{Type: configv1.OperatorDegraded, Status: configv1.ConditionFalse},
+			},
+		},
+		{
+			name: "unavailable",
+			conditions: []configv1.ClusterOperatorStatusCondition{
+				{Type: configv1.OperatorAvailable, Status: configv1.ConditionFalse, Message: "no quorum"},
+			},
+			expected: "etcd is not available: no quorum",
+		},
+		{
+			name: "members degraded",
+			conditions: []configv1.ClusterOperatorStatusCondition{
+				{Type: configv1.OperatorAvailable, Status: configv1.ConditionTrue},
+				{Type: configv1.OperatorDegraded, Status: configv1.ConditionTrue, Reason: "EtcdMembers_UnhealthyMembers", Message: "2 of 3 members are available"},
+			},
+			expected: "etcd members are degraded: 2 of 3 members are available",
+		},
+		{
+			name: "degraded for another reason",
+			conditions: []configv1.ClusterOperatorStatusCondition{
+				{Type: configv1.OperatorAvailable, Status: configv1.ConditionTrue},
+				{Type: configv1.OperatorDegraded, Status: configv1.ConditionTrue, Reason: "EtcdCertSigner_Error"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			require.NoError(t, indexer.Add(&configv1.ClusterOperator{
+				ObjectMeta: metav1.ObjectMeta{Name: "etcd"},
+				Status:     configv1.ClusterOperatorStatus{Conditions: test.conditions},
+			}))
+			c := TargetConfigController{clusterOperatorLister: configv1listers.NewClusterOperatorLister(indexer)}
+
+			got, err := c.etcdUnhealthy()
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, got)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package targetconfigcontroller
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// zoneEvictionConfig tunes how the node lifecycle controller evicts pods when nodes or whole zones fail.
+// It is read from the zoneEviction stanza of the operator's UnsupportedConfigOverrides:
+//
+//	unsupportedConfigOverrides:
+//	  zoneEviction:
+//	    unhealthyZoneThreshold: 0.7
+//	    nodeEvictionRate: 0.1
+//	    secondaryNodeEvictionRate: 0.001
+//	    largeClusterSizeThreshold: 20
+type zoneEvictionConfig struct {
+	UnhealthyZoneThreshold    *float64 `json:"unhealthyZoneThreshold,omitempty"`
+	NodeEvictionRate          *float64 `json:"nodeEvictionRate,omitempty"`
+	SecondaryNodeEvictionRate *float64 `json:"secondaryNodeEvictionRate,omitempty"`
+	LargeClusterSizeThreshold *int32   `json:"largeClusterSizeThreshold,omitempty"`
+}
+
+// zoneEvictionArguments validates the zoneEviction stanza of the overrides and returns the config layer with the
+// matching kube-controller-manager flags. It returns nil when the stanza is missing.
+func zoneEvictionArguments(unsupportedConfigOverrides []byte) ([]byte, error) {
+	if len(unsupportedConfigOverrides) == 0 {
+		return nil, nil
+	}
+	overrides := struct {
+		ZoneEviction *zoneEvictionConfig `json:"zoneEviction"`
+	}{}
+	if err := json.Unmarshal(unsupportedConfigOverrides, &overrides); err != nil {
+		return nil, fmt.Errorf("failed to load zoneEviction from UnsupportedConfigOverride: %v", err)
+	}
+	config := overrides.ZoneEviction
+	if config == nil {
+		return nil, nil
+	}
+
+	args := map[string][]string{}
+	if threshold := config.UnhealthyZoneThreshold; threshold != nil {
+		if *threshold <= 0 || *threshold > 1 {
+			return nil, fmt.Errorf("zoneEviction.unhealthyZoneThreshold must be greater than 0 and at most 1, got %v", *threshold)
+		}
+		args["unhealthy-zone-threshold"] = []string{formatFloat(*threshold)}
+	}
+	nodeEvictionRate := 0.1
+	if rate := config.NodeEvictionRate; rate != nil {
+		if *rate <= 0 {
+			return nil, fmt.Errorf("zoneEviction.nodeEvictionRate must be greater than 0, got %v", *rate)
+		}
+		nodeEvictionRate = *rate
+		args["node-eviction-rate"] = []string{formatFloat(*rate)}
+	}
+	if rate := config.SecondaryNodeEvictionRate; rate != nil {
+		// a secondary rate above the primary one would evict faster from unhealthy zones than from healthy ones
+		if *rate < 0 || *rate > nodeEvictionRate {
+			return nil, fmt.Errorf("zoneEviction.secondaryNodeEvictionRate must be between 0 and the node eviction rate %v, got %v", nodeEvictionRate, *rate)
+		}
+		args["secondary-node-eviction-rate"] = []string{formatFloat(*rate)}
+	}
+	if threshold := config.LargeClusterSizeThreshold; threshold != nil {
+		if *threshold < 0 {
+			return nil, fmt.Errorf("zoneEviction.largeClusterSizeThreshold must not be negative, got %d", *threshold)
+		}
+		args["large-cluster-size-threshold"] = []string{strconv.Itoa(int(*threshold))}
+	}
+	if len(args) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(map[string]interface{}{"extendedArguments": args})
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
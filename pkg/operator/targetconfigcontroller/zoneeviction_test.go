@@ -0,0 +1,81 @@
+package targetconfigcontroller
+
+import (
+	"testing"
+
+	"github.com/ghodss/yaml"
+)
+
+func TestZoneEvictionArguments(t *testing.T) {
+	tests := []struct {
+		name        string
+		overrides   string
+		expected    string
+		expectedErr bool
+	}{
+		{
+			name: "no overrides",
+		},
+		{
+			name:      "no zone eviction",
+			overrides: `{"extendedArguments":{"v":["4"]}}`,
+		},
+		{
+			name:      "all settings",
+			overrides: `{"zoneEviction":{"unhealthyZoneThreshold":0.7,"nodeEvictionRate":0.2,"secondaryNodeEvictionRate":0.001,"largeClusterSizeThreshold":20}}`,
+			expected:  `{"extendedArguments":{"large-cluster-size-threshold":["20"],"node-eviction-rate":["0.2"],"secondary-node-eviction-rate":["0.001"],"unhealthy-zone-threshold":["0.7"]}}`,
+		},
+		{
+			name:        "threshold out of range",
+			overrides:   `{"zoneEviction":{"unhealthyZoneThreshold":1.5}}`,
+			expectedErr: true,
+		},
+		{
+			name:        "secondary rate above the default node eviction rate",
+			overrides:   `{"zoneEviction":{"secondaryNodeEvictionRate":0.5}}`,
+			expectedErr: true,
+		},
+		{
+			name:        "negative large cluster threshold",
+			overrides:   `{"zoneEviction":{"largeClusterSizeThreshold":-1}}`,
+			expectedErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := zoneEvictionArguments([]byte(test.overrides))
+			if (err != nil) != test.expectedErr {
+				t.Fatalf("expected error %v, got %v", test.expectedErr, err)
+			}
+			if string(got) != test.expected {
+				t.Errorf("expected %s, got %s", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestMergeKubeControllerManagerConfigWithZoneEviction(t *testing.T) {
+	configMap, err := MergeKubeControllerManagerConfig(
+		[]byte(`{"extendedArguments":{"cluster-name":["foo"]}}`),
+		[]byte(`{"zoneEviction":{"unhealthyZoneThreshold":0.7},"extendedArguments":{"node-eviction-rate":["0.3"]}}`),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var config map[string]interface{}
+	if err := yaml.Unmarshal([]byte(configMap.Data["config.yaml"]), &config); err != nil {
+		t.Fatal(err)
+	}
+	if _, found := config["zoneEviction"]; found {
+		t.Errorf("expected zoneEviction to be pruned from the config")
+	}
+	args := map[string]bool{}
+	for _, arg := range GetKubeControllerManagerArgs(config) {
+		args[arg] = true
+	}
+	for _, expected := range []string{"--unhealthy-zone-threshold=0.7", "--node-eviction-rate=0.3", "--cluster-name=foo"} {
+		if !args[expected] {
+			t.Errorf("expected %s in %v", expected, args)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package teardown
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/component-base/metrics"
+	"k8s.io/klog/v2"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operationprofile"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatormetrics"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
+)
+
+// ConfigMapName is the configmap in the operator namespace which announces the teardown of the cluster. Managed
+// services create it before they deprovision a cluster, its content does not matter.
+const ConfigMapName = "cluster-teardown"
+
+const conditionType = "ClusterTeardownQuiesced"
+
+var skippedSyncs = metrics.NewCounterVec(
+	&metrics.CounterOpts{
+		Name:           "kcm_operator_teardown_skipped_syncs_total",
+		Help:           "Syncs of operator controllers skipped while the cluster is torn down.",
+		StabilityLevel: metrics.ALPHA,
+	},
+	[]string{"controller"},
+)
+
+func init() {
+	operatormetrics.MustRegister(skippedSyncs)
+}
+
+var (
+	lock      sync.RWMutex
+	quiescing bool
+	// changed is closed and replaced whenever quiescing changes
+	changed = make(chan struct{})
+)
+
+// Quiescing tells whether the cluster is torn down and the operator stops creating revisions and rotating
+// certificates.
+func Quiescing() bool {
+	lock.RLock()
+	defer lock.RUnlock()
+	return quiescing
+}
+
+// Changed returns a channel which is closed when the operator enters or leaves the quiesce mode.
+func Changed() <-chan struct{} {
+	lock.RLock()
+	defer lock.RUnlock()
+	return changed
+}
+
+// setQuiescing returns whether the value changed.
+func setQuiescing(value bool) bool {
+	lock.Lock()
+	defer lock.Unlock()
+	if quiescing == value {
+		return false
+	}
+	quiescing = value
+	close(changed)
+	changed = make(chan struct{})
+	return true
+}
+
+// Quiesceable wraps the sync of the controller to skip it while the cluster is torn down.
+func Quiesceable(controller string, sync factory.SyncFunc) factory.SyncFunc {
+	return func(ctx context.Context, syncCtx factory.SyncContext) error {
+		if Quiescing() {
+			skippedSyncs.WithLabelValues(controller).Inc()
+			klog.V(4).Infof("%s sync skipped, the cluster is torn down", controller)
+			return nil
+		}
+		return sync(ctx, syncCtx)
+	}
+}
+
+// NewQuiescingClient returns the operator client for the static pod controllers of library-go. It reports the
+// operator Unmanaged while the cluster is torn down, which stops the revision, installer and prune controllers
+// without a change to the spec.
+func NewQuiescingClient(operatorClient v1helpers.StaticPodOperatorClient) v1helpers.StaticPodOperatorClient {
+	return &quiescingClient{StaticPodOperatorClient: operatorClient}
+}
+
+type quiescingClient struct {
+	v1helpers.StaticPodOperatorClient
+}
+
+func (c *quiescingClient) GetOperatorState() (*operatorv1.OperatorSpec, *operatorv1.OperatorStatus, string, error) {
+	spec, status, resourceVersion, err := c.StaticPodOperatorClient.GetOperatorState()
+	if err != nil || !Quiescing() {
+		return spec, status, resourceVersion, err
+	}
+	// the spec may come from the lister, it must not be modified
+	spec = spec.DeepCopy()
+	spec.ManagementState = operatorv1.Unmanaged
+	return spec, status, resourceVersion, nil
+}
+
+func (c *quiescingClient) GetStaticPodOperatorState() (*operatorv1.StaticPodOperatorSpec, *operatorv1.StaticPodOperatorStatus, string, error) {
+	spec, status, resourceVersion, err := c.StaticPodOperatorClient.GetStaticPodOperatorState()
+	if err != nil || !Quiescing() {
+		return spec, status, resourceVersion, err
+	}
+	spec = spec.DeepCopy()
+	spec.ManagementState = operatorv1.Unmanaged
+	return spec, status, resourceVersion, nil
+}
+
+func (c *quiescingClient) GetStaticPodOperatorStateWithQuorum(ctx context.Context) (*operatorv1.StaticPodOperatorSpec, *operatorv1.StaticPodOperatorStatus, string, error) {
+	spec, status, resourceVersion, err := c.StaticPodOperatorClient.GetStaticPodOperatorStateWithQuorum(ctx)
+	if err != nil || !Quiescing() {
+		return spec, status, resourceVersion, err
+	}
+	spec = spec.DeepCopy()
+	spec.ManagementState = operatorv1.Unmanaged
+	return spec, status, resourceVersion, nil
+}
+
+type teardownController struct {
+	statusUpdater   statusbatcher.StatusUpdater
+	configMapLister corev1listers.ConfigMapLister
+}
+
+// NewTeardownController detects the teardown of the cluster from the teardown configmap and switches the operator into the quiesce mode: no new revisions, no
+// certificate rotation. Both would only fail noisily and write to etcd while the cluster goes away. The mode ends
+// when the signal is withdrawn.
+func NewTeardownController(
+	statusUpdater statusbatcher.StatusUpdater,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	configMapInformer := kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().ConfigMaps()
+	c := &teardownController{
+		statusUpdater:   statusUpdater,
+		configMapLister: configMapInformer.Lister(),
+	}
+	return factory.New().WithInformers(
+		configMapInformer.Informer(),
+	).ResyncEvery(operationprofile.Resync(time.Minute)).WithSync(syncmetrics.Timed("TeardownController", c.sync)).ToController("TeardownController", eventRecorder)
+}
+
+func (c *teardownController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	condition := operatorv1.OperatorCondition{
+		Type:   conditionType,
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+
+	_, err := c.configMapLister.ConfigMaps(operatorclient.OperatorNamespace).Get(ConfigMapName)
+	switch {
+	case err == nil:
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "TeardownConfigMap"
+		condition.Message = fmt.Sprintf("configmap %s/%s announces the teardown of the cluster", operatorclient.OperatorNamespace, ConfigMapName)
+	case !apierrors.IsNotFound(err):
+		return err
+	}
+
+	quiesce := condition.Status == operatorv1.ConditionTrue
+	if quiesce {
+		condition.Message += ", no new revisions are created and no certificates are rotated"
+	}
+	if setQuiescing(quiesce) {
+		if quiesce {
+			syncCtx.Recorder().Warningf("ClusterTeardownQuiesced", "the operator stops creating revisions and rotating certificates: %s", condition.Message)
+		} else {
+			syncCtx.Recorder().Eventf("ClusterTeardownWithdrawn", "the teardown signal is gone, the operator creates revisions and rotates certificates again")
+		}
+	}
+	return c.statusUpdater.UpdateStatus(ctx, v1helpers.UpdateConditionFn(condition))
+}
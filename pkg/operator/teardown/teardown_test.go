@@ -0,0 +1,140 @@
+package teardown
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+)
+
+func TestSync(t *testing.T) {
+	defer setQuiescing(false)
+
+	testCases := []struct {
+		name            string
+		configMap       bool
+		expectedStatus  operatorv1.ConditionStatus
+		expectedReason  string
+		expectedMessage string
+	}{
+		{
+			name:           "no teardown",
+			expectedStatus: operatorv1.ConditionFalse,
+			expectedReason: "AsExpected",
+		},
+		{
+			name:            "configmap",
+			configMap:       true,
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedReason:  "TeardownConfigMap",
+			expectedMessage: "configmap openshift-kube-controller-manager-operator/cluster-teardown announces the teardown of the cluster, no new revisions are created and no certificates are rotated",
+		},
+		{
+			name:           "withdrawn",
+			expectedStatus: operatorv1.ConditionFalse,
+			expectedReason: "AsExpected",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			if tc.configMap {
+				if err := indexer.Add(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: ConfigMapName}}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil)
+			c := &teardownController{
+				statusUpdater:   statusbatcher.NewDirectUpdater(operatorClient),
+				configMapLister: corev1listers.NewConfigMapLister(indexer),
+			}
+			recorder := events.NewInMemoryRecorder("test")
+			wasQuiescing := Quiescing()
+			if err := c.sync(context.TODO(), factory.NewSyncContext("test", recorder)); err != nil {
+				t.Fatal(err)
+			}
+
+			_, status, _, _ := operatorClient.GetOperatorState()
+			condition := v1helpers.FindOperatorCondition(status.Conditions, conditionType)
+			if condition == nil || condition.Status != tc.expectedStatus || condition.Reason != tc.expectedReason || condition.Message != tc.expectedMessage {
+				t.Fatalf("expected %s %s %q, got %v", tc.expectedStatus, tc.expectedReason, tc.expectedMessage, condition)
+			}
+			if quiescing := tc.expectedStatus == operatorv1.ConditionTrue; Quiescing() != quiescing {
+				t.Errorf("expected quiescing %v", quiescing)
+			}
+			reasons := []string{}
+			for _, event := range recorder.Events() {
+				reasons = append(reasons, event.Reason)
+			}
+			expectedEvents := ""
+			switch {
+			case !wasQuiescing && Quiescing():
+				expectedEvents = "ClusterTeardownQuiesced"
+			case wasQuiescing && !Quiescing():
+				expectedEvents = "ClusterTeardownWithdrawn"
+			}
+			if strings.Join(reasons, ",") != expectedEvents {
+				t.Errorf("expected the events %q, got %v", expectedEvents, reasons)
+			}
+		})
+	}
+}
+
+func TestQuiesce(t *testing.T) {
+	defer setQuiescing(false)
+
+	syncs := 0
+	sync := Quiesceable("TargetConfigController", func(context.Context, factory.SyncContext) error {
+		syncs++
+		return nil
+	})
+	client := NewQuiescingClient(v1helpers.NewFakeStaticPodOperatorClient(
+		&operatorv1.StaticPodOperatorSpec{OperatorSpec: operatorv1.OperatorSpec{ManagementState: operatorv1.Managed}},
+		&operatorv1.StaticPodOperatorStatus{}, nil, nil))
+	expect := func(expectedSyncs int, expectedState operatorv1.ManagementState) {
+		t.Helper()
+		if err := sync(context.TODO(), nil); err != nil {
+			t.Fatal(err)
+		}
+		if syncs != expectedSyncs {
+			t.Errorf("expected %d syncs, got %d", expectedSyncs, syncs)
+		}
+		spec, _, _, err := client.GetStaticPodOperatorState()
+		if err != nil {
+			t.Fatal(err)
+		}
+		operatorSpec, _, _, err := client.GetOperatorState()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if spec.ManagementState != expectedState || operatorSpec.ManagementState != expectedState {
+			t.Errorf("expected the operator %s, got %s and %s", expectedState, spec.ManagementState, operatorSpec.ManagementState)
+		}
+	}
+
+	expect(1, operatorv1.Managed)
+
+	changed := Changed()
+	setQuiescing(true)
+	select {
+	case <-changed:
+	default:
+		t.Fatal("expected a change to be signaled")
+	}
+	expect(1, operatorv1.Unmanaged)
+
+	setQuiescing(false)
+	expect(2, operatorv1.Managed)
+}
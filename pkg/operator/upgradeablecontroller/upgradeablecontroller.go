@@ -0,0 +1,190 @@
+package upgradeablecontroller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/util/cert"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operationprofile"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
+)
+
+const (
+	conditionType = "ConfigRisksUpgradeable"
+
+	// certificates are rotated well ahead of their expiry, one this close to it means rotation is stuck
+	certExpiryThreshold = 72 * time.Hour
+)
+
+// removedFlags are the kube-controller-manager flags which are gone in the next kube-controller-manager minor, keyed
+// by the minor removing them. Passing them through the unsupported config overrides makes the next
+// kube-controller-manager fail to start. Deprecated flags which still work are not listed, blocking the upgrade on
+// them would be wrong.
+var removedFlags = map[string]string{
+	"address":             "1.24",
+	"deleting-pods-burst": "1.24",
+	"deleting-pods-qps":   "1.24",
+	"horizontal-pod-autoscaler-downscale-delay":  "1.24",
+	"horizontal-pod-autoscaler-upscale-delay":    "1.24",
+	"horizontal-pod-autoscaler-use-rest-clients": "1.24",
+	"port":                 "1.24",
+	"register-retry-count": "1.24",
+}
+
+// certificate is a secret holding a certificate the operand depends on.
+type certificate struct {
+	namespace, name string
+}
+
+var certificates = []certificate{
+	{namespace: operatorclient.TargetNamespace, name: "csr-signer"},
+	{namespace: operatorclient.TargetNamespace, name: "serving-cert"},
+	{namespace: operatorclient.TargetNamespace, name: "cluster-policy-controller-serving-cert"},
+	{namespace: operatorclient.TargetNamespace, name: "cluster-policy-controller-client-cert-key"},
+	{namespace: operatorclient.GlobalMachineSpecifiedConfigNamespace, name: "kube-controller-manager-client-cert-key"},
+}
+
+// UpgradeableController sets Upgradeable=False when the configuration of kube-controller-manager makes an
+// upgrade risky, so that admins are warned before they start it.
+type UpgradeableController struct {
+	operatorClient v1helpers.OperatorClient
+	statusUpdater  statusbatcher.StatusUpdater
+	secretLister   corev1listers.SecretLister
+	now            func() time.Time
+}
+
+func NewUpgradeableController(
+	operatorClient v1helpers.OperatorClient,
+	statusUpdater statusbatcher.StatusUpdater,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &UpgradeableController{
+		operatorClient: operatorClient,
+		statusUpdater:  statusUpdater,
+		secretLister:   kubeInformersForNamespaces.SecretLister(),
+		now:            time.Now,
+	}
+
+	return factory.New().WithInformers(
+		operatorClient.Informer(),
+		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().Secrets().Informer(),
+		kubeInformersForNamespaces.InformersFor(operatorclient.GlobalMachineSpecifiedConfigNamespace).Core().V1().Secrets().Informer(),
+	).ResyncEvery(operationprofile.Resync(time.Minute)).WithSync(syncmetrics.Timed("UpgradeableController", c.sync)).ToController("UpgradeableController", eventRecorder)
+}
+
+func (c *UpgradeableController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	spec, _, _, err := c.operatorClient.GetOperatorState()
+	if err != nil {
+		return err
+	}
+
+	reasons := []string{}
+	messages := []string{}
+	if flags, err := RemovedFlagsInOverrides(spec.UnsupportedConfigOverrides.Raw); err != nil {
+		return err
+	} else if len(flags) > 0 {
+		reasons = append(reasons, "RemovedFlagsOverridden")
+		messages = append(messages, fmt.Sprintf("unsupportedConfigOverrides set the removed kube-controller-manager flags %s", strings.Join(flags, ", ")))
+	}
+
+	expiring, err := c.expiringCertificates()
+	if err != nil {
+		return err
+	}
+	if len(expiring) > 0 {
+		reasons = append(reasons, "CertificatesExpiringSoon")
+		messages = append(messages, expiring...)
+	}
+
+	condition := operatorv1.OperatorCondition{
+		Type:   conditionType,
+		Status: operatorv1.ConditionTrue,
+	}
+	if len(reasons) > 0 {
+		condition.Status = operatorv1.ConditionFalse
+		condition.Reason = strings.Join(reasons, "And")
+		condition.Message = strings.Join(messages, "\n")
+	}
+	return c.statusUpdater.UpdateStatus(ctx, v1helpers.UpdateConditionFn(condition))
+}
+
+// RemovedFlagsInOverrides returns the removed flags set through extendedArguments of the unsupported config overrides.
+func RemovedFlagsInOverrides(unsupportedConfigOverrides []byte) ([]string, error) {
+	if len(unsupportedConfigOverrides) == 0 {
+		return nil, nil
+	}
+	overrides := struct {
+		ExtendedArguments map[string][]string `json:"extendedArguments"`
+	}{}
+	if err := json.Unmarshal(unsupportedConfigOverrides, &overrides); err != nil {
+		return nil, fmt.Errorf("failed to read extendedArguments from unsupportedConfigOverrides: %v", err)
+	}
+
+	ret := []string{}
+	for flag := range removedFlags {
+		if _, ok := overrides.ExtendedArguments[flag]; ok {
+			ret = append(ret, flag)
+		}
+	}
+	sort.Strings(ret)
+	return ret, nil
+}
+
+// expiringCertificates describes the certificates which expire within certExpiryThreshold.
+func (c *UpgradeableController) expiringCertificates() ([]string, error) {
+	expiries, err := CertificateExpiries(c.secretLister)
+	if err != nil {
+		return nil, err
+	}
+	ret := []string{}
+	for secret, notAfter := range expiries {
+		if notAfter.Sub(c.now()) < certExpiryThreshold {
+			ret = append(ret, fmt.Sprintf("secret %s expires at %s", secret, notAfter.UTC().Format(time.RFC3339)))
+		}
+	}
+	sort.Strings(ret)
+	return ret, nil
+}
+
+// CertificateExpiries returns the earliest expiry of the certificates the operand depends on, keyed by
+// <namespace>/<name> of their secret. Missing and unparsable certificates are left out, they are reported by
+// the controllers creating them.
+func CertificateExpiries(secretLister corev1listers.SecretLister) (map[string]time.Time, error) {
+	ret := map[string]time.Time{}
+	for _, certificate := range certificates {
+		secret, err := secretLister.Secrets(certificate.namespace).Get(certificate.name)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		certs, err := cert.ParseCertsPEM(secret.Data["tls.crt"])
+		if err != nil {
+			continue
+		}
+		key := certificate.namespace + "/" + certificate.name
+		for _, crt := range certs {
+			if earliest, ok := ret[key]; !ok || crt.NotAfter.Before(earliest) {
+				ret[key] = crt.NotAfter
+			}
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,157 @@
+package upgradeablecontroller
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/blang/semver"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/crypto"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+)
+
+func TestSync(t *testing.T) {
+	ca, err := crypto.MakeSelfSignedCAConfigForDuration("test", 30*24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM, keyPEM, err := ca.GetPEMBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	notAfter := ca.Certs[0].NotAfter
+	signer := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "csr-signer"},
+		Data:       map[string][]byte{"tls.crt": certPEM, "tls.key": keyPEM},
+	}
+
+	testCases := []struct {
+		name            string
+		overrides       string
+		now             time.Time
+		expectedStatus  operatorv1.ConditionStatus
+		expectedReason  string
+		expectedMessage string
+	}{
+		{
+			name:           "no risks",
+			now:            notAfter.Add(-10 * 24 * time.Hour),
+			expectedStatus: operatorv1.ConditionTrue,
+		},
+		{
+			name:           "overrides of supported flags",
+			overrides:      `{"extendedArguments":{"node-monitor-grace-period":["50s"],"pod-eviction-timeout":["1m"],"enable-taint-manager":["true"]}}`,
+			now:            notAfter.Add(-10 * 24 * time.Hour),
+			expectedStatus: operatorv1.ConditionTrue,
+		},
+		{
+			name:            "overrides of removed flags",
+			overrides:       `{"extendedArguments":{"register-retry-count":["10"],"port":["0"]}}`,
+			now:             notAfter.Add(-10 * 24 * time.Hour),
+			expectedStatus:  operatorv1.ConditionFalse,
+			expectedReason:  "RemovedFlagsOverridden",
+			expectedMessage: "unsupportedConfigOverrides set the removed kube-controller-manager flags port, register-retry-count",
+		},
+		{
+			name:            "certificate expiring soon",
+			now:             notAfter.Add(-time.Hour),
+			expectedStatus:  operatorv1.ConditionFalse,
+			expectedReason:  "CertificatesExpiringSoon",
+			expectedMessage: "secret openshift-kube-controller-manager/csr-signer expires at " + notAfter.UTC().Format(time.RFC3339),
+		},
+		{
+			name:            "all risks",
+			overrides:       `{"extendedArguments":{"port":["0"]}}`,
+			now:             notAfter.Add(-time.Hour),
+			expectedStatus:  operatorv1.ConditionFalse,
+			expectedReason:  "RemovedFlagsOverriddenAndCertificatesExpiringSoon",
+			expectedMessage: "unsupportedConfigOverrides set the removed kube-controller-manager flags port\nsecret openshift-kube-controller-manager/csr-signer expires at " + notAfter.UTC().Format(time.RFC3339),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			if err := indexer.Add(signer); err != nil {
+				t.Fatal(err)
+			}
+			operatorClient := v1helpers.NewFakeOperatorClient(
+				&operatorv1.OperatorSpec{UnsupportedConfigOverrides: runtime.RawExtension{Raw: []byte(tc.overrides)}},
+				&operatorv1.OperatorStatus{},
+				nil,
+			)
+			c := &UpgradeableController{
+				operatorClient: operatorClient,
+				statusUpdater:  statusbatcher.NewDirectUpdater(operatorClient),
+				secretLister:   corev1listers.NewSecretLister(indexer),
+				now:            func() time.Time { return tc.now },
+			}
+			if err := c.sync(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))); err != nil {
+				t.Fatal(err)
+			}
+			_, status, _, _ := operatorClient.GetOperatorState()
+			condition := v1helpers.FindOperatorCondition(status.Conditions, conditionType)
+			if condition == nil || condition.Status != tc.expectedStatus || condition.Reason != tc.expectedReason || condition.Message != tc.expectedMessage {
+				t.Errorf("expected %s %s %q, got %v", tc.expectedStatus, tc.expectedReason, tc.expectedMessage, condition)
+			}
+		})
+	}
+}
+
+// vendoredKubeMinor is the kube minor removedFlags was reviewed against, the operand of the next release runs the
+// minor after it.
+const vendoredKubeMinor = "1.23"
+
+func TestRemovedFlags(t *testing.T) {
+	modules, err := os.Open("../../../vendor/modules.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer modules.Close()
+	var vendored *semver.Version
+	scanner := bufio.NewScanner(modules)
+	for scanner.Scan() {
+		if fields := strings.Fields(scanner.Text()); len(fields) == 3 && fields[0] == "#" && fields[1] == "k8s.io/api" {
+			// k8s.io/api v0.x.y is released with kube 1.x.y
+			staging, err := semver.ParseTolerant(fields[2])
+			if err != nil {
+				t.Fatal(err)
+			}
+			vendored = &semver.Version{Major: 1, Minor: staging.Minor}
+		}
+	}
+	if vendored == nil {
+		t.Fatal("k8s.io/api is not vendored")
+	}
+	if !vendored.Equals(semver.MustParse(vendoredKubeMinor + ".0")) {
+		t.Fatalf("the vendored kube is %d.%d, review removedFlags against the flags removed in kube-controller-manager 1.%d and update vendoredKubeMinor", vendored.Major, vendored.Minor, vendored.Minor+1)
+	}
+
+	next := semver.Version{Major: 1, Minor: vendored.Minor + 1}
+	for flag, removedIn := range removedFlags {
+		if semver.MustParse(removedIn + ".0").GT(next) {
+			t.Errorf("%s is removed in %s, it still works in kube-controller-manager 1.%d", flag, removedIn, next.Minor)
+		}
+	}
+	// deprecated, but still supported by the next kube-controller-manager
+	for _, flag := range []string{"enable-taint-manager", "pod-eviction-timeout", "experimental-cluster-signing-duration"} {
+		if _, ok := removedFlags[flag]; ok {
+			t.Errorf("%s still works in kube-controller-manager 1.%d", flag, next.Minor)
+		}
+	}
+}
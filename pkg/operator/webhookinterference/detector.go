@@ -0,0 +1,232 @@
+package webhookinterference
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+// Mutation is a write of the operator to the target namespace which did not persist as requested.
+type Mutation struct {
+	// Resource is the plural resource of the object, configmaps or pods.
+	Resource  string
+	Namespace string
+	Name      string
+	Operation admissionregistrationv1.OperationType
+	// Labels are the labels of the persisted object, the object selectors of the webhooks match them.
+	Labels map[string]string
+	// Fields are the parts of the object which differ from the request, like data.config.yaml.
+	Fields []string
+	Time   time.Time
+}
+
+// Detector compares the configmaps and pods the operator writes to the target namespace with what the API server
+// persists. The API server applies no defaults to the parts compared, a difference is the work of a mutating
+// admission webhook. Such a webhook makes every write look like a change, the revisions churn.
+type Detector struct {
+	now func() time.Time
+
+	lock      sync.Mutex
+	mutations map[string]Mutation
+}
+
+func NewDetector() *Detector {
+	return &Detector{now: time.Now, mutations: map[string]Mutation{}}
+}
+
+// Mutations returns the mutations seen since the given time, the latest one per object, and forgets the older ones.
+func (d *Detector) Mutations(since time.Time) []Mutation {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	ret := []Mutation{}
+	for key, mutation := range d.mutations {
+		if mutation.Time.Before(since) {
+			delete(d.mutations, key)
+			continue
+		}
+		ret = append(ret, mutation)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Resource != ret[j].Resource {
+			return ret[i].Resource < ret[j].Resource
+		}
+		return ret[i].Name < ret[j].Name
+	})
+	return ret
+}
+
+func (d *Detector) record(resource string, operation admissionregistrationv1.OperationType, persisted metav1.Object, fields []string) {
+	if len(fields) == 0 {
+		return
+	}
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.mutations[resource+"/"+persisted.GetNamespace()+"/"+persisted.GetName()] = Mutation{
+		Resource:  resource,
+		Namespace: persisted.GetNamespace(),
+		Name:      persisted.GetName(),
+		Operation: operation,
+		Labels:    persisted.GetLabels(),
+		Fields:    fields,
+		Time:      d.now(),
+	}
+}
+
+// WrapKubeClient returns a client reporting the mutations of its writes to the detector.
+func (d *Detector) WrapKubeClient(kubeClient kubernetes.Interface) kubernetes.Interface {
+	return &detectingKubeClient{Interface: kubeClient, detector: d}
+}
+
+type detectingKubeClient struct {
+	kubernetes.Interface
+	detector *Detector
+}
+
+func (c *detectingKubeClient) CoreV1() corev1client.CoreV1Interface {
+	return &detectingCoreV1{CoreV1Interface: c.Interface.CoreV1(), detector: c.detector}
+}
+
+type detectingCoreV1 struct {
+	corev1client.CoreV1Interface
+	detector *Detector
+}
+
+func (c *detectingCoreV1) ConfigMaps(namespace string) corev1client.ConfigMapInterface {
+	if namespace != operatorclient.TargetNamespace {
+		return c.CoreV1Interface.ConfigMaps(namespace)
+	}
+	return &detectingConfigMaps{ConfigMapInterface: c.CoreV1Interface.ConfigMaps(namespace), detector: c.detector}
+}
+
+func (c *detectingCoreV1) Pods(namespace string) corev1client.PodInterface {
+	if namespace != operatorclient.TargetNamespace {
+		return c.CoreV1Interface.Pods(namespace)
+	}
+	return &detectingPods{PodInterface: c.CoreV1Interface.Pods(namespace), detector: c.detector}
+}
+
+type detectingConfigMaps struct {
+	corev1client.ConfigMapInterface
+	detector *Detector
+}
+
+func (c *detectingConfigMaps) Create(ctx context.Context, configMap *corev1.ConfigMap, opts metav1.CreateOptions) (*corev1.ConfigMap, error) {
+	persisted, err := c.ConfigMapInterface.Create(ctx, configMap, opts)
+	if err == nil && len(opts.DryRun) == 0 {
+		c.detector.record("configmaps", admissionregistrationv1.Create, persisted, configMapChanges(configMap, persisted))
+	}
+	return persisted, err
+}
+
+func (c *detectingConfigMaps) Update(ctx context.Context, configMap *corev1.ConfigMap, opts metav1.UpdateOptions) (*corev1.ConfigMap, error) {
+	persisted, err := c.ConfigMapInterface.Update(ctx, configMap, opts)
+	if err == nil && len(opts.DryRun) == 0 {
+		c.detector.record("configmaps", admissionregistrationv1.Update, persisted, configMapChanges(configMap, persisted))
+	}
+	return persisted, err
+}
+
+type detectingPods struct {
+	corev1client.PodInterface
+	detector *Detector
+}
+
+func (c *detectingPods) Create(ctx context.Context, pod *corev1.Pod, opts metav1.CreateOptions) (*corev1.Pod, error) {
+	persisted, err := c.PodInterface.Create(ctx, pod, opts)
+	if err == nil && len(opts.DryRun) == 0 {
+		c.detector.record("pods", admissionregistrationv1.Create, persisted, podChanges(pod, persisted))
+	}
+	return persisted, err
+}
+
+// configMapChanges lists the data, labels and annotations of the request which did not persist as requested, and the
+// data which was added.
+func configMapChanges(requested, persisted *corev1.ConfigMap) []string {
+	changes := metadataChanges(requested, persisted)
+	for key, value := range requested.Data {
+		if persistedValue, ok := persisted.Data[key]; !ok || persistedValue != value {
+			changes = append(changes, "data."+key)
+		}
+	}
+	for key := range persisted.Data {
+		if _, ok := requested.Data[key]; !ok {
+			changes = append(changes, "data."+key)
+		}
+	}
+	for key, value := range requested.BinaryData {
+		if persistedValue, ok := persisted.BinaryData[key]; !ok || !reflect.DeepEqual(persistedValue, value) {
+			changes = append(changes, "binaryData."+key)
+		}
+	}
+	for key := range persisted.BinaryData {
+		if _, ok := requested.BinaryData[key]; !ok {
+			changes = append(changes, "binaryData."+key)
+		}
+	}
+	sort.Strings(changes)
+	return changes
+}
+
+// podChanges lists the labels and annotations of the request which did not persist as requested, and the containers,
+// volumes and images which differ. The API server defaults the other parts of the spec.
+func podChanges(requested, persisted *corev1.Pod) []string {
+	changes := metadataChanges(requested, persisted)
+	if !reflect.DeepEqual(containerImages(requested.Spec.InitContainers), containerImages(persisted.Spec.InitContainers)) {
+		changes = append(changes, "spec.initContainers")
+	}
+	if !reflect.DeepEqual(containerImages(requested.Spec.Containers), containerImages(persisted.Spec.Containers)) {
+		changes = append(changes, "spec.containers")
+	}
+	if !reflect.DeepEqual(volumeNames(requested.Spec.Volumes), volumeNames(persisted.Spec.Volumes)) {
+		changes = append(changes, "spec.volumes")
+	}
+	sort.Strings(changes)
+	return changes
+}
+
+func metadataChanges(requested, persisted metav1.Object) []string {
+	changes := []string{}
+	for key, value := range requested.GetLabels() {
+		if persistedValue, ok := persisted.GetLabels()[key]; !ok || persistedValue != value {
+			changes = append(changes, "metadata.labels."+key)
+		}
+	}
+	for key, value := range requested.GetAnnotations() {
+		if persistedValue, ok := persisted.GetAnnotations()[key]; !ok || persistedValue != value {
+			changes = append(changes, "metadata.annotations."+key)
+		}
+	}
+	return changes
+}
+
+func containerImages(containers []corev1.Container) map[string]string {
+	ret := map[string]string{}
+	for _, container := range containers {
+		ret[container.Name] = container.Image
+	}
+	return ret
+}
+
+func volumeNames(volumes []corev1.Volume) []string {
+	ret := []string{}
+	for _, volume := range volumes {
+		// the service account admission plugin of the API server mounts the token
+		if strings.HasPrefix(volume.Name, "kube-api-access-") {
+			continue
+		}
+		ret = append(ret, volume.Name)
+	}
+	sort.Strings(ret)
+	return ret
+}
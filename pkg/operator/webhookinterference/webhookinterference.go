@@ -0,0 +1,162 @@
+package webhookinterference
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	admissionregistrationv1listers "k8s.io/client-go/listers/admissionregistration/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/controllerpause"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operationprofile"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
+)
+
+const (
+	conditionType = "WebhookInterference"
+
+	// mutationTTL is how long a mutation is reported after it was last seen. The operator rewrites its configmaps
+	// every few minutes, a webhook still interfering is seen again long before.
+	mutationTTL = time.Hour
+)
+
+// WebhookInterferenceController reports the mutations of the writes of the operator seen by the Detector, with the
+// mutating webhook configurations whose rules and selectors match the mutated objects.
+type WebhookInterferenceController struct {
+	detector        *Detector
+	statusUpdater   statusbatcher.StatusUpdater
+	webhookLister   admissionregistrationv1listers.MutatingWebhookConfigurationLister
+	namespaceLister corev1listers.NamespaceLister
+}
+
+func NewWebhookInterferenceController(
+	detector *Detector,
+	statusUpdater statusbatcher.StatusUpdater,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	clusterInformers := kubeInformersForNamespaces.InformersFor("")
+	c := &WebhookInterferenceController{
+		detector:        detector,
+		statusUpdater:   statusUpdater,
+		webhookLister:   clusterInformers.Admissionregistration().V1().MutatingWebhookConfigurations().Lister(),
+		namespaceLister: clusterInformers.Core().V1().Namespaces().Lister(),
+	}
+
+	return factory.New().WithInformers(
+		clusterInformers.Admissionregistration().V1().MutatingWebhookConfigurations().Informer(),
+		clusterInformers.Core().V1().Namespaces().Informer(),
+	).ResyncEvery(operationprofile.Resync(time.Minute)).WithSync(syncmetrics.Timed("WebhookInterferenceController", controllerpause.Pausable("WebhookInterferenceController", c.sync))).ToController("WebhookInterferenceController", eventRecorder)
+}
+
+func (c *WebhookInterferenceController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	mutations := c.detector.Mutations(c.detector.now().Add(-mutationTTL))
+	condition := operatorv1.OperatorCondition{
+		Type:   conditionType,
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+	if len(mutations) == 0 {
+		return c.statusUpdater.UpdateStatus(ctx, v1helpers.UpdateConditionFn(condition))
+	}
+
+	configurations, err := c.webhookLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	namespace, err := c.namespaceLister.Get(operatorclient.TargetNamespace)
+	if err != nil {
+		return err
+	}
+	messages := []string{}
+	for _, mutation := range mutations {
+		culprits := matchingWebhooks(configurations, namespace.Labels, mutation)
+		culprit := "an unidentified mutating admission plugin"
+		if len(culprits) > 0 {
+			culprit = "mutatingwebhookconfiguration " + strings.Join(culprits, ", ")
+		}
+		messages = append(messages, fmt.Sprintf("%s %s/%s changed on %s (%s), likely by %s",
+			strings.TrimSuffix(mutation.Resource, "s"), mutation.Namespace, mutation.Name, strings.ToLower(string(mutation.Operation)), strings.Join(mutation.Fields, ", "), culprit))
+	}
+	condition.Status = operatorv1.ConditionTrue
+	condition.Reason = "MutatedByWebhook"
+	condition.Message = "objects written by the operator persisted with changes, every write then looks like a change and the revisions churn: " + strings.Join(messages, "; ")
+	return c.statusUpdater.UpdateStatus(ctx, v1helpers.UpdateConditionFn(condition))
+}
+
+// matchingWebhooks returns the <configuration>/<webhook> names of the webhooks whose rules and selectors match the
+// mutated object.
+func matchingWebhooks(configurations []*admissionregistrationv1.MutatingWebhookConfiguration, namespaceLabels map[string]string, mutation Mutation) []string {
+	ret := []string{}
+	for _, configuration := range configurations {
+		for _, webhook := range configuration.Webhooks {
+			if !rulesMatch(webhook.Rules, mutation) ||
+				!selectorMatches(webhook.NamespaceSelector, namespaceLabels) ||
+				!selectorMatches(webhook.ObjectSelector, mutation.Labels) {
+				continue
+			}
+			ret = append(ret, configuration.Name+"/"+webhook.Name)
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+func rulesMatch(rules []admissionregistrationv1.RuleWithOperations, mutation Mutation) bool {
+	for _, rule := range rules {
+		if containsOrWildcard(operationStrings(rule.Operations), string(mutation.Operation)) &&
+			containsOrWildcard(rule.APIGroups, "") &&
+			containsOrWildcard(rule.APIVersions, "v1") &&
+			(containsOrWildcard(rule.Resources, mutation.Resource) || contains(rule.Resources, "*/*")) {
+			return true
+		}
+	}
+	return false
+}
+
+func operationStrings(operations []admissionregistrationv1.OperationType) []string {
+	ret := []string{}
+	for _, operation := range operations {
+		ret = append(ret, string(operation))
+	}
+	return ret
+}
+
+func containsOrWildcard(values []string, value string) bool {
+	return contains(values, value) || contains(values, "*")
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// selectorMatches tells whether the labels match the selector of a webhook, a missing selector matches everything.
+func selectorMatches(selector *metav1.LabelSelector, objectLabels map[string]string) bool {
+	if selector == nil {
+		return true
+	}
+	parsed, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		// the API server rejects webhooks with invalid selectors, this is no culprit
+		return false
+	}
+	return parsed.Matches(labels.Set(objectLabels))
+}
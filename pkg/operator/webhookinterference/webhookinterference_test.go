@@ -0,0 +1,134 @@
+package webhookinterference
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	admissionregistrationv1listers "k8s.io/client-go/listers/admissionregistration/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+)
+
+func TestWebhookInterference(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	// a webhook injecting a proxy setting into every configmap named config
+	kubeClient.PrependReactor("create", "configmaps", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		configMap := action.(clienttesting.CreateAction).GetObject().(*corev1.ConfigMap)
+		if configMap.Name == "config" {
+			configMap.Data["proxy"] = "injected"
+		}
+		return false, nil, nil
+	})
+	detector := NewDetector()
+	client := detector.WrapKubeClient(kubeClient)
+	for _, name := range []string{"config", "recycler-config"} {
+		if _, err := client.CoreV1().ConfigMaps(operatorclient.TargetNamespace).Create(context.TODO(), &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: name, Labels: map[string]string{"app": "kube-controller-manager"}},
+			Data:       map[string]string{"config.yaml": "{}"},
+		}, metav1.CreateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	newIndexer := func() cache.Indexer {
+		return cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	}
+	namespaces, webhooks := newIndexer(), newIndexer()
+	if err := namespaces.Add(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: operatorclient.TargetNamespace, Labels: map[string]string{"openshift.io/run-level": "0"}}}); err != nil {
+		t.Fatal(err)
+	}
+	rules := []admissionregistrationv1.RuleWithOperations{{
+		Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create},
+		Rule:       admissionregistrationv1.Rule{APIGroups: []string{""}, APIVersions: []string{"v1"}, Resources: []string{"configmaps"}},
+	}}
+	for _, configuration := range []*admissionregistrationv1.MutatingWebhookConfiguration{
+		{ObjectMeta: metav1.ObjectMeta{Name: "proxy-injector"}, Webhooks: []admissionregistrationv1.MutatingWebhook{{Name: "inject.proxy.example.com", Rules: rules}}},
+		// scoped to other namespaces
+		{ObjectMeta: metav1.ObjectMeta{Name: "tenant-defaults"}, Webhooks: []admissionregistrationv1.MutatingWebhook{{
+			Name:              "defaults.tenant.example.com",
+			Rules:             rules,
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tenant": "true"}},
+		}}},
+		// for pods only
+		{ObjectMeta: metav1.ObjectMeta{Name: "sidecar-injector"}, Webhooks: []admissionregistrationv1.MutatingWebhook{{
+			Name: "inject.sidecar.example.com",
+			Rules: []admissionregistrationv1.RuleWithOperations{{
+				Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.OperationAll},
+				Rule:       admissionregistrationv1.Rule{APIGroups: []string{""}, APIVersions: []string{"v1"}, Resources: []string{"pods"}},
+			}},
+		}}},
+	} {
+		if err := webhooks.Add(configuration); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil)
+	c := &WebhookInterferenceController{
+		detector:        detector,
+		statusUpdater:   statusbatcher.NewDirectUpdater(operatorClient),
+		webhookLister:   admissionregistrationv1listers.NewMutatingWebhookConfigurationLister(webhooks),
+		namespaceLister: corev1listers.NewNamespaceLister(namespaces),
+	}
+	if err := c.sync(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))); err != nil {
+		t.Fatal(err)
+	}
+	condition := func() *operatorv1.OperatorCondition {
+		_, status, _, err := operatorClient.GetOperatorState()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v1helpers.FindOperatorCondition(status.Conditions, conditionType)
+	}
+	actual := condition()
+	if actual == nil || actual.Status != operatorv1.ConditionTrue {
+		t.Fatalf("expected %s True, got %v", conditionType, actual)
+	}
+	expected := "configmap openshift-kube-controller-manager/config changed on create (data.proxy), likely by mutatingwebhookconfiguration proxy-injector/inject.proxy.example.com"
+	if !strings.HasSuffix(actual.Message, expected) {
+		t.Errorf("expected the message to end with %q, got %q", expected, actual.Message)
+	}
+
+	// the mutation is forgotten once the webhook stopped interfering
+	detector.now = func() time.Time { return time.Now().Add(mutationTTL + time.Minute) }
+	if err := c.sync(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))); err != nil {
+		t.Fatal(err)
+	}
+	if actual := condition(); actual.Status != operatorv1.ConditionFalse {
+		t.Errorf("expected %s False after the mutation expired, got %v", conditionType, actual)
+	}
+}
+
+func TestPodChanges(t *testing.T) {
+	requested := &corev1.Pod{Spec: corev1.PodSpec{
+		Containers: []corev1.Container{{Name: "installer", Image: "operator"}},
+		Volumes:    []corev1.Volume{{Name: "kubelet-dir"}},
+	}}
+	defaulted := requested.DeepCopy()
+	defaulted.Spec.Volumes = append(defaulted.Spec.Volumes, corev1.Volume{Name: "kube-api-access-x7k2p"})
+	defaulted.Spec.Containers[0].ImagePullPolicy = corev1.PullIfNotPresent
+	if changes := podChanges(requested, defaulted); len(changes) > 0 {
+		t.Errorf("expected the defaults of the API server ignored, got %v", changes)
+	}
+	injected := defaulted.DeepCopy()
+	injected.Spec.Containers = append(injected.Spec.Containers, corev1.Container{Name: "sidecar", Image: "proxy"})
+	if changes := podChanges(requested, injected); len(changes) != 1 || changes[0] != "spec.containers" {
+		t.Errorf("expected the injected container reported, got %v", changes)
+	}
+}
@@ -0,0 +1,173 @@
+package zonetopologycontroller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/controllerpause"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operationprofile"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/syncmetrics"
+)
+
+const conditionType = "ZoneAwareEvictionUnavailable"
+
+// the node lifecycle controller reads the zone of a node from these labels only, the deprecated ones as a fallback
+var (
+	zoneLabels   = []string{corev1.LabelTopologyZone, corev1.LabelFailureDomainBetaZone}
+	regionLabels = []string{corev1.LabelTopologyRegion, corev1.LabelFailureDomainBetaRegion}
+
+	standardLabels = sets.NewString(append(append([]string{}, zoneLabels...), regionLabels...)...)
+)
+
+// ZoneTopologyController reports when the zone based eviction of the node lifecycle controller cannot tell the
+// failure domains of the cluster apart. The node lifecycle controller groups the nodes by their standard topology
+// labels and slows the evictions down in zones that are largely unhealthy. Nodes without those labels all fall
+// into a single zone, so the outage of a whole failure domain looks like the outage of part of the cluster.
+// kube-controller-manager has no flag to read other labels, custom topology labels on the nodes are only pointed
+// out for the nodes to be labeled with the standard ones.
+type ZoneTopologyController struct {
+	statusUpdater statusbatcher.StatusUpdater
+	nodeLister    corev1listers.NodeLister
+}
+
+func NewZoneTopologyController(
+	statusUpdater statusbatcher.StatusUpdater,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	nodeInformer := kubeInformersForNamespaces.InformersFor("").Core().V1().Nodes()
+	c := &ZoneTopologyController{
+		statusUpdater: statusUpdater,
+		nodeLister:    nodeInformer.Lister(),
+	}
+
+	return factory.New().WithInformers(
+		nodeInformer.Informer(),
+	).ResyncEvery(operationprofile.Resync(10*time.Minute)).WithSync(syncmetrics.Timed("ZoneTopologyController", controllerpause.Pausable("ZoneTopologyController", c.sync))).ToController("ZoneTopologyController", eventRecorder)
+}
+
+func (c *ZoneTopologyController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	nodes, err := c.nodeLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+
+	condition := operatorv1.OperatorCondition{
+		Type:   conditionType,
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+	zones := map[string]bool{}
+	unlabeled := []string{}
+	// customLabels counts the nodes carrying each topology label the node lifecycle controller does not read
+	customLabels := map[string]int{}
+	for _, node := range nodes {
+		if zone, found := zoneOf(node); found {
+			zones[zone] = true
+		} else {
+			unlabeled = append(unlabeled, node.Name)
+		}
+		for _, label := range customTopologyLabels(node) {
+			customLabels[label]++
+		}
+	}
+	// a cluster without any topology labels, like most bare metal and single zone clusters, is a single zone by
+	// design and nothing can be improved by labeling
+	if (len(unlabeled) == 0 || len(zones) == 0) && len(customLabels) == 0 {
+		switch {
+		case len(zones) == 0 && len(nodes) > 0:
+			condition.Message = "no node has a zone label, all nodes are in a single zone"
+		case len(zones) == 1:
+			condition.Message = "all nodes are in a single zone"
+		case len(zones) > 1:
+			condition.Message = fmt.Sprintf("the nodes are spread over %d zones", len(zones))
+		}
+		return c.statusUpdater.UpdateStatus(ctx, v1helpers.UpdateConditionFn(condition))
+	}
+
+	sort.Strings(unlabeled)
+	condition.Status = operatorv1.ConditionTrue
+	messages := []string{}
+	switch {
+	case len(zones) == 0:
+		condition.Reason = "NoZoneLabels"
+		messages = append(messages, fmt.Sprintf("no node has a %s label, the node lifecycle controller treats all %d nodes as a single zone", corev1.LabelTopologyZone, len(nodes)))
+	case len(unlabeled) > 0:
+		condition.Reason = "MissingZoneLabels"
+		messages = append(messages, fmt.Sprintf("%d of %d nodes have no %s label and are evicted as a zone of their own: %s",
+			len(unlabeled), len(nodes), corev1.LabelTopologyZone, truncatedList(unlabeled, 10)))
+	default:
+		condition.Reason = "CustomTopologyLabels"
+	}
+	if len(customLabels) > 0 {
+		custom := []string{}
+		for label, count := range customLabels {
+			custom = append(custom, fmt.Sprintf("%s on %d nodes", label, count))
+		}
+		sort.Strings(custom)
+		messages = append(messages, fmt.Sprintf("the nodes carry topology labels kube-controller-manager does not read (%s), label them with %s and %s instead",
+			strings.Join(custom, ", "), corev1.LabelTopologyZone, corev1.LabelTopologyRegion))
+	}
+	condition.Message = strings.Join(messages, "; ")
+	return c.statusUpdater.UpdateStatus(ctx, v1helpers.UpdateConditionFn(condition))
+}
+
+// zoneOf returns the zone key of the node the way the node lifecycle controller builds it, and whether the node has
+// a zone or region label at all.
+func zoneOf(node *corev1.Node) (string, bool) {
+	region, zone := firstLabel(node, regionLabels), firstLabel(node, zoneLabels)
+	if len(region) == 0 && len(zone) == 0 {
+		return "", false
+	}
+	return region + ":\x00:" + zone, true
+}
+
+func firstLabel(node *corev1.Node, keys []string) string {
+	for _, key := range keys {
+		if value := node.Labels[key]; len(value) > 0 {
+			return value
+		}
+	}
+	return ""
+}
+
+// customTopologyLabels returns the labels of the node which look like zone or region labels, but are not the ones
+// the node lifecycle controller reads.
+func customTopologyLabels(node *corev1.Node) []string {
+	ret := []string{}
+	for key := range node.Labels {
+		if standardLabels.Has(key) {
+			continue
+		}
+		name := key
+		if i := strings.LastIndex(key, "/"); i >= 0 {
+			name = key[i+1:]
+		}
+		switch strings.ToLower(name) {
+		case "zone", "region", "failure-domain", "failuredomain":
+			ret = append(ret, key)
+		}
+	}
+	return ret
+}
+
+func truncatedList(items []string, max int) string {
+	if len(items) <= max {
+		return strings.Join(items, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(items[:max], ", "), len(items)-max)
+}
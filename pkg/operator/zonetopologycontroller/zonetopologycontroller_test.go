@@ -0,0 +1,116 @@
+package zonetopologycontroller
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/statusbatcher"
+)
+
+func TestSync(t *testing.T) {
+	node := func(name string, labels map[string]string) *corev1.Node {
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	}
+	tests := []struct {
+		name            string
+		nodes           []*corev1.Node
+		expectedStatus  operatorv1.ConditionStatus
+		expectedReason  string
+		expectedMessage string
+	}{
+		{
+			name:           "no nodes",
+			expectedStatus: operatorv1.ConditionFalse,
+			expectedReason: "AsExpected",
+		},
+		{
+			name: "zones",
+			nodes: []*corev1.Node{
+				node("worker-a", map[string]string{corev1.LabelTopologyZone: "us-east-1a"}),
+				node("worker-b", map[string]string{corev1.LabelTopologyZone: "us-east-1b"}),
+				// the deprecated label is still read
+				node("worker-c", map[string]string{corev1.LabelFailureDomainBetaZone: "us-east-1c"}),
+			},
+			expectedStatus:  operatorv1.ConditionFalse,
+			expectedReason:  "AsExpected",
+			expectedMessage: "the nodes are spread over 3 zones",
+		},
+		{
+			name: "no zone labels",
+			nodes: []*corev1.Node{
+				node("master-0", map[string]string{}),
+				node("master-1", map[string]string{}),
+			},
+			expectedStatus:  operatorv1.ConditionFalse,
+			expectedReason:  "AsExpected",
+			expectedMessage: "no node has a zone label, all nodes are in a single zone",
+		},
+		{
+			name: "custom labels only",
+			nodes: []*corev1.Node{
+				node("worker-a", map[string]string{"topology.example.com/zone": "rack-1"}),
+				node("worker-b", map[string]string{"topology.example.com/zone": "rack-2", "example.com/Region": "dc-1"}),
+			},
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedReason:  "NoZoneLabels",
+			expectedMessage: "no node has a topology.kubernetes.io/zone label, the node lifecycle controller treats all 2 nodes as a single zone; the nodes carry topology labels kube-controller-manager does not read (example.com/Region on 1 nodes, topology.example.com/zone on 2 nodes), label them with topology.kubernetes.io/zone and topology.kubernetes.io/region instead",
+		},
+		{
+			name: "partially labeled",
+			nodes: []*corev1.Node{
+				node("worker-a", map[string]string{corev1.LabelTopologyZone: "us-east-1a"}),
+				node("worker-b", map[string]string{corev1.LabelTopologyRegion: "us-east-1"}),
+				node("worker-c", map[string]string{}),
+			},
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedReason:  "MissingZoneLabels",
+			expectedMessage: "1 of 3 nodes have no topology.kubernetes.io/zone label and are evicted as a zone of their own: worker-c",
+		},
+		{
+			name: "zones and custom labels",
+			nodes: []*corev1.Node{
+				node("worker-a", map[string]string{corev1.LabelTopologyZone: "us-east-1a", "example.com/rack-zone": "r1"}),
+				node("worker-b", map[string]string{corev1.LabelTopologyZone: "us-east-1b", "topology.example.com/zone": "r2"}),
+			},
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedReason:  "CustomTopologyLabels",
+			expectedMessage: "the nodes carry topology labels kube-controller-manager does not read (topology.example.com/zone on 1 nodes), label them with topology.kubernetes.io/zone and topology.kubernetes.io/region instead",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			for _, node := range test.nodes {
+				if err := indexer.Add(node); err != nil {
+					t.Fatal(err)
+				}
+			}
+			operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil)
+			c := &ZoneTopologyController{
+				statusUpdater: statusbatcher.NewDirectUpdater(operatorClient),
+				nodeLister:    corev1listers.NewNodeLister(indexer),
+			}
+			if err := c.sync(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))); err != nil {
+				t.Fatal(err)
+			}
+			_, status, _, err := operatorClient.GetOperatorState()
+			if err != nil {
+				t.Fatal(err)
+			}
+			condition := v1helpers.FindOperatorCondition(status.Conditions, conditionType)
+			if condition == nil || condition.Status != test.expectedStatus || condition.Reason != test.expectedReason || condition.Message != test.expectedMessage {
+				t.Errorf("expected %s %s %s with message %q, got %v", conditionType, test.expectedStatus, test.expectedReason, test.expectedMessage, condition)
+			}
+		})
+	}
+}
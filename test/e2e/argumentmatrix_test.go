@@ -0,0 +1,58 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/argumentmatrixcontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	test "github.com/openshift/cluster-kube-controller-manager-operator/test/library"
+)
+
+// TestArgumentMatrix expects all control plane nodes to settle at the same revision and to run kube-controller-manager
+// with the same arguments.
+func TestArgumentMatrix(t *testing.T) {
+	kubeConfig, err := test.NewClientConfigForTest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	kubeClient, err := kubernetes.NewForConfig(kubeConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	matrix := &argumentmatrixcontroller.Matrix{}
+	// a rollout still in progress is expected to finish
+	err = wait.PollImmediate(time.Second*5, time.Minute*10, func() (bool, error) {
+		configMap, err := kubeClient.CoreV1().ConfigMaps(operatorclient.OperatorNamespace).Get(context.TODO(), argumentmatrixcontroller.ConfigMapName, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		matrix = &argumentmatrixcontroller.Matrix{}
+		if err := json.Unmarshal([]byte(configMap.Data[argumentmatrixcontroller.MatrixKey]), matrix); err != nil {
+			return false, err
+		}
+		if len(matrix.Nodes) == 0 || len(matrix.Inconsistent) > 0 {
+			return false, nil
+		}
+		for _, node := range matrix.Nodes {
+			if node.Revision != matrix.Nodes[0].Revision || len(node.Arguments) == 0 {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		t.Fatalf("the control plane nodes do not run with consistent arguments: %v, last matrix: %+v", err, matrix)
+	}
+}